@@ -15,6 +15,13 @@ type unmarshaledDataKeyType int
 const (
 	rtpHeaderKey unmarshaledDataKeyType = iota
 	rtcpPacketsKey
+	invalidKey
+	ecnKey
+	frameTypeKey
+	labelsKey
+	codecKey
+	hopLimitKey
+	rttKey
 )
 
 var errInvalidType = errors.New("found value of invalid type in attributes map")
@@ -53,7 +60,9 @@ func (a Attributes) GetRTPHeader(raw []byte) (*rtp.Header, error) {
 
 // GetRTCPPackets gets the RTCP packets if present. If the packet slice is not
 // present, it will be unmarshalled from the raw byte slice and stored in the
-// attributes.
+// attributes. Packets of a compound packet which fail to parse, e.g. because
+// of an experimental type, are kept as *rtcp.RawPacket, so the remaining
+// packets can still be processed. Only invalid headers fail the whole batch.
 func (a Attributes) GetRTCPPackets(raw []byte) ([]rtcp.Packet, error) {
 	if val, ok := a[rtcpPacketsKey]; ok {
 		if packets, ok := val.([]rtcp.Packet); ok {
@@ -62,7 +71,7 @@ func (a Attributes) GetRTCPPackets(raw []byte) ([]rtcp.Packet, error) {
 
 		return nil, errInvalidType
 	}
-	pkts, err := rtcp.Unmarshal(raw)
+	pkts, err := unmarshalRTCP(raw)
 	if err != nil {
 		return nil, err
 	}
@@ -70,3 +79,55 @@ func (a Attributes) GetRTCPPackets(raw []byte) ([]rtcp.Packet, error) {
 
 	return pkts, nil
 }
+
+// unmarshalRTCP is rtcp.Unmarshal, but replaces the packets which fail to
+// parse with their raw bytes.
+func unmarshalRTCP(raw []byte) ([]rtcp.Packet, error) {
+	pkts, err := rtcp.Unmarshal(raw)
+	if err == nil || len(raw) == 0 {
+		return pkts, err
+	}
+
+	pkts = []rtcp.Packet{}
+	for len(raw) != 0 {
+		var header rtcp.Header
+		if headerErr := header.Unmarshal(raw); headerErr != nil {
+			return nil, err
+		}
+		size := (int(header.Length) + 1) * 4
+		if size > len(raw) {
+			return nil, err
+		}
+
+		if parsed, parseErr := rtcp.Unmarshal(raw[:size]); parseErr == nil {
+			pkts = append(pkts, parsed...)
+		} else {
+			rawPkt := rtcp.RawPacket(raw[:size])
+			pkts = append(pkts, &rawPkt)
+		}
+		raw = raw[size:]
+	}
+
+	return pkts, nil
+}
+
+// SetRTCPPackets replaces the RTCP packets stored in the attributes, e.g.
+// after an interceptor removed some of the packets of a received batch.
+func (a Attributes) SetRTCPPackets(pkts []rtcp.Packet) {
+	a[rtcpPacketsKey] = pkts
+}
+
+// MarkInvalid marks the RTP packet the attributes belong to as invalid, e.g.
+// because its payload failed a sanity check. Interceptors computing
+// statistics exclude invalid packets.
+func (a Attributes) MarkInvalid() {
+	a[invalidKey] = true
+}
+
+// IsInvalid returns whether the RTP packet the attributes belong to was marked
+// as invalid with MarkInvalid.
+func (a Attributes) IsInvalid() bool {
+	invalid, ok := a[invalidKey].(bool)
+
+	return ok && invalid
+}
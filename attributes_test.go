@@ -127,4 +127,42 @@ func TestAttributesGetRTCPPackets(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, []rtcp.Packet{sr}, packets)
 	})
+
+	t.Run("Set", func(t *testing.T) {
+		attributes := Attributes{}
+		pli := &rtcp.PictureLossIndication{SenderSSRC: 1, MediaSSRC: 2}
+		attributes.SetRTCPPackets([]rtcp.Packet{pli})
+		packets, err := attributes.GetRTCPPackets(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []rtcp.Packet{pli}, packets)
+	})
+
+	t.Run("Unparsable", func(t *testing.T) {
+		sr := &rtcp.SenderReport{SSRC: 1}
+		nack := &rtcp.TransportLayerNack{SenderSSRC: 1, MediaSSRC: 2, Nacks: []rtcp.NackPair{{PacketID: 3}}}
+		buf, err := rtcp.Marshal([]rtcp.Packet{sr, nack})
+		assert.NoError(t, err)
+
+		// A PLI without media SSRC, which the rtcp package fails to parse
+		truncated := []byte{0x81, 0xce, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01}
+		srSize := sr.MarshalSize()
+		buf = append(buf[:srSize], append(truncated, buf[srSize:]...)...)
+
+		packets, err := Attributes{}.GetRTCPPackets(buf)
+		assert.NoError(t, err)
+		rawPkt := rtcp.RawPacket(truncated)
+		assert.Equal(t, []rtcp.Packet{sr, &rawPkt, nack}, packets)
+
+		// Packets can't be skipped if their header is invalid
+		_, err = Attributes{}.GetRTCPPackets(append(buf, 0x00, 0x00, 0x00, 0x00))
+		assert.Error(t, err)
+	})
+}
+
+func TestAttributesInvalid(t *testing.T) {
+	attributes := Attributes{}
+	assert.False(t, attributes.IsInvalid())
+
+	attributes.MarkInvalid()
+	assert.True(t, attributes.IsInvalid())
 }
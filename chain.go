@@ -3,14 +3,24 @@
 
 package interceptor
 
+import "sync"
+
 // Chain is an interceptor that runs all child interceptors in order.
 type Chain struct {
 	interceptors []Interceptor
+
+	m             sync.Mutex
+	localStreams  map[uint32]*StreamInfo
+	remoteStreams map[uint32]*StreamInfo
 }
 
 // NewChain returns a new Chain interceptor.
 func NewChain(interceptors []Interceptor) *Chain {
-	return &Chain{interceptors: interceptors}
+	return &Chain{
+		interceptors:  interceptors,
+		localStreams:  map[uint32]*StreamInfo{},
+		remoteStreams: map[uint32]*StreamInfo{},
+	}
 }
 
 // BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
@@ -36,6 +46,7 @@ func (i *Chain) BindRTCPWriter(writer RTCPWriter) RTCPWriter {
 // BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
 // will be called once per rtp packet.
 func (i *Chain) BindLocalStream(ctx *StreamInfo, writer RTPWriter) RTPWriter {
+	i.bindStream(i.localStreams, ctx, true)
 	for _, interceptor := range i.interceptors {
 		writer = interceptor.BindLocalStream(ctx, writer)
 	}
@@ -45,6 +56,7 @@ func (i *Chain) BindLocalStream(ctx *StreamInfo, writer RTPWriter) RTPWriter {
 
 // UnbindLocalStream is called when the Stream is removed. It can be used to clean up any data related to that track.
 func (i *Chain) UnbindLocalStream(ctx *StreamInfo) {
+	i.bindStream(i.localStreams, ctx, false)
 	for _, interceptor := range i.interceptors {
 		interceptor.UnbindLocalStream(ctx)
 	}
@@ -54,6 +66,8 @@ func (i *Chain) UnbindLocalStream(ctx *StreamInfo) {
 // It is called once for per RemoteStream. The returned method
 // will be called once per rtp packet.
 func (i *Chain) BindRemoteStream(ctx *StreamInfo, reader RTPReader) RTPReader {
+	i.bindStream(i.remoteStreams, ctx, true)
+	reader = annotateCodec(ctx, reader)
 	for _, interceptor := range i.interceptors {
 		reader = interceptor.BindRemoteStream(ctx, reader)
 	}
@@ -63,6 +77,7 @@ func (i *Chain) BindRemoteStream(ctx *StreamInfo, reader RTPReader) RTPReader {
 
 // UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
 func (i *Chain) UnbindRemoteStream(ctx *StreamInfo) {
+	i.bindStream(i.remoteStreams, ctx, false)
 	for _, interceptor := range i.interceptors {
 		interceptor.UnbindRemoteStream(ctx)
 	}
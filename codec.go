@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Codec describes a codec negotiated for a payload type of a stream.
+type Codec struct {
+	PayloadType  uint8
+	MimeType     string
+	ClockRate    uint32
+	Channels     uint16
+	Fmtp         map[string]string
+	RTCPFeedback []RTCPFeedback
+}
+
+// SupportsFeedback returns whether the RTCP feedback of the given type and
+// parameter was negotiated for the codec.
+func (c Codec) SupportsFeedback(typ, parameter string) bool {
+	for _, fb := range c.RTCPFeedback {
+		if fb.Type == typ && fb.Parameter == parameter {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseFmtp parses a SDP fmtp line like "minptime=10;useinbandfec=1" into
+// its parameters. Parameters without a value, e.g. the redundant encodings of
+// RED, are stored with an empty value.
+func ParseFmtp(line string) map[string]string {
+	parameters := map[string]string{}
+	for _, parameter := range strings.Split(line, ";") {
+		parameter = strings.TrimSpace(parameter)
+		if parameter == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(parameter, "=")
+		parameters[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+
+	return parameters
+}
+
+// Codec returns the codec of the stream's PayloadType.
+func (s *StreamInfo) Codec() Codec {
+	if codec, ok := s.codec(s.PayloadType); ok {
+		return codec
+	}
+
+	return Codec{
+		PayloadType:  s.PayloadType,
+		MimeType:     s.MimeType,
+		ClockRate:    s.ClockRate,
+		Channels:     s.Channels,
+		Fmtp:         ParseFmtp(s.SDPFmtpLine),
+		RTCPFeedback: s.RTCPFeedback,
+	}
+}
+
+// CodecForPayloadType returns the codec negotiated for the given payload type.
+// It falls back to the stream's codec for its PayloadType, if the payload type
+// isn't listed in Codecs.
+func (s *StreamInfo) CodecForPayloadType(payloadType uint8) (Codec, bool) {
+	if codec, ok := s.codec(payloadType); ok {
+		return codec, true
+	}
+	if payloadType == s.PayloadType {
+		return s.Codec(), true
+	}
+
+	return Codec{}, false
+}
+
+func (s *StreamInfo) codec(payloadType uint8) (Codec, bool) {
+	for _, codec := range s.Codecs {
+		if codec.PayloadType == payloadType {
+			return codec, true
+		}
+	}
+
+	return Codec{}, false
+}
+
+// RetransmissionPayloadType returns the payload type of RFC 4588 retransmissions
+// of packets with the given payload type, from the apt parameter of the rtx
+// codecs in Codecs. If Codecs lists no rtx codecs, PayloadTypeRetransmission
+// is used for all payload types. It returns false if no retransmission payload
+// type was negotiated for the payload type.
+func (s *StreamInfo) RetransmissionPayloadType(payloadType uint8) (uint8, bool) {
+	hasRTX := false
+	for _, codec := range s.Codecs {
+		if !strings.HasSuffix(strings.ToLower(codec.MimeType), "/rtx") {
+			continue
+		}
+		hasRTX = true
+		apt, err := strconv.ParseUint(codec.Fmtp["apt"], 10, 8)
+		if err == nil && uint8(apt) == payloadType { //nolint:gosec // G115
+			return codec.PayloadType, true
+		}
+	}
+	if hasRTX {
+		return 0, false
+	}
+
+	return s.PayloadTypeRetransmission, s.PayloadTypeRetransmission != 0
+}
+
+// SupportsFeedback returns whether the RTCP feedback of the given type and
+// parameter was negotiated for the stream.
+func (s *StreamInfo) SupportsFeedback(typ, parameter string) bool {
+	return s.Codec().SupportsFeedback(typ, parameter)
+}
+
+// SupportsNack returns whether generic NACKs were negotiated for the stream.
+func (s *StreamInfo) SupportsNack() bool {
+	return s.SupportsFeedback("nack", "")
+}
+
+// SupportsPLI returns whether Picture Loss Indications were negotiated for the stream.
+func (s *StreamInfo) SupportsPLI() bool {
+	return s.SupportsFeedback("nack", "pli")
+}
+
+// SupportsFIR returns whether Full Intra Requests were negotiated for the stream.
+func (s *StreamInfo) SupportsFIR() bool {
+	return s.SupportsFeedback("ccm", "fir")
+}
+
+// SupportsTWCC returns whether transport wide congestion control feedback was
+// negotiated for the stream.
+func (s *StreamInfo) SupportsTWCC() bool {
+	return s.SupportsFeedback("transport-cc", "")
+}
+
+// HeaderExtensionID returns the ID of the RTP header extension with the given
+// URI, if it was negotiated for the stream.
+func (s *StreamInfo) HeaderExtensionID(uri string) (int, bool) {
+	for _, extension := range s.RTPHeaderExtensions {
+		if extension.URI == uri {
+			return extension.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+// SetCodec sets the codec the RTP packet the attributes belong to was encoded
+// with.
+func (a Attributes) SetCodec(codec Codec) {
+	a[codecKey] = codec
+}
+
+// GetCodec returns the codec of the RTP packet. The Chain sets it for every
+// received packet from the payload type and the codecs of the StreamInfo, so
+// payload aware interceptors don't need to map payload types themselves. It
+// returns false if the payload type wasn't negotiated.
+func (a Attributes) GetCodec() (Codec, bool) {
+	codec, ok := a[codecKey].(Codec)
+
+	return codec, ok
+}
+
+// annotateCodec returns a RTPReader setting the codec of every packet read
+// from reader.
+func annotateCodec(info *StreamInfo, reader RTPReader) RTPReader {
+	return RTPReaderFunc(func(b []byte, a Attributes) (int, Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+
+		if attr == nil {
+			attr = make(Attributes)
+		}
+		// Invalid packets are left to the interceptors to handle
+		if header, err := attr.GetRTPHeader(b[:n]); err == nil {
+			if codec, ok := info.CodecForPayloadType(header.PayloadType); ok {
+				attr.SetCodec(codec)
+			}
+		}
+
+		return n, attr, nil
+	})
+}
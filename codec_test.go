@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFmtp(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"minptime":     "10",
+		"useinbandfec": "1",
+	}, ParseFmtp("minptime=10; useinbandfec=1"))
+	assert.Equal(t, map[string]string{"111/111": ""}, ParseFmtp("111/111"))
+	assert.Empty(t, ParseFmtp(""))
+}
+
+func TestStreamInfoCodec(t *testing.T) {
+	info := &StreamInfo{
+		PayloadType:  96,
+		MimeType:     "video/VP8",
+		ClockRate:    90000,
+		SDPFmtpLine:  "max-fr=30",
+		RTCPFeedback: []RTCPFeedback{{Type: "nack"}, {Type: "nack", Parameter: "pli"}},
+		Codecs: []Codec{
+			{PayloadType: 111, MimeType: "audio/opus", ClockRate: 48000, Channels: 2},
+		},
+		RTPHeaderExtensions: []RTPHeaderExtension{{URI: "urn:test", ID: 3}},
+	}
+
+	assert.Equal(t, Codec{
+		PayloadType:  96,
+		MimeType:     "video/VP8",
+		ClockRate:    90000,
+		Fmtp:         map[string]string{"max-fr": "30"},
+		RTCPFeedback: info.RTCPFeedback,
+	}, info.Codec())
+
+	codec, ok := info.CodecForPayloadType(111)
+	assert.True(t, ok)
+	assert.Equal(t, "audio/opus", codec.MimeType)
+	assert.Equal(t, uint32(48000), info.ClockRateForPayloadType(111))
+
+	_, ok = info.CodecForPayloadType(0)
+	assert.False(t, ok)
+
+	assert.True(t, info.SupportsNack())
+	assert.True(t, info.SupportsPLI())
+	assert.False(t, info.SupportsFIR())
+	assert.False(t, info.SupportsTWCC())
+
+	id, ok := info.HeaderExtensionID("urn:test")
+	assert.True(t, ok)
+	assert.Equal(t, 3, id)
+	_, ok = info.HeaderExtensionID("urn:other")
+	assert.False(t, ok)
+}
+
+func TestStreamInfoRetransmissionPayloadType(t *testing.T) {
+	info := &StreamInfo{PayloadType: 96, PayloadTypeRetransmission: 97}
+	payloadType, ok := info.RetransmissionPayloadType(96)
+	assert.True(t, ok)
+	assert.Equal(t, uint8(97), payloadType)
+
+	info.Codecs = []Codec{
+		{PayloadType: 96, MimeType: "video/VP8"},
+		{PayloadType: 97, MimeType: "video/rtx", Fmtp: map[string]string{"apt": "96"}},
+		{PayloadType: 102, MimeType: "video/H264"},
+		{PayloadType: 103, MimeType: "video/RTX", Fmtp: map[string]string{"apt": "102"}},
+		{PayloadType: 104, MimeType: "video/AV1"},
+	}
+	payloadType, ok = info.RetransmissionPayloadType(102)
+	assert.True(t, ok)
+	assert.Equal(t, uint8(103), payloadType)
+	_, ok = info.RetransmissionPayloadType(104)
+	assert.False(t, ok)
+
+	_, ok = (&StreamInfo{PayloadType: 96}).RetransmissionPayloadType(96)
+	assert.False(t, ok)
+}
+
+func TestChainAnnotatesCodec(t *testing.T) {
+	info := &StreamInfo{
+		SSRC:        1,
+		PayloadType: 96,
+		MimeType:    "video/VP8",
+		ClockRate:   90000,
+		Codecs:      []Codec{{PayloadType: 111, MimeType: "audio/opus", ClockRate: 48000}},
+	}
+	for _, chain := range []Interceptor{NewChain(nil), &RecoveryChain{Chain: NewChain(nil)}} {
+		payloadType := uint8(0)
+		reader := chain.BindRemoteStream(info, RTPReaderFunc(func(b []byte, _ Attributes) (int, Attributes, error) {
+			n, err := (&rtp.Packet{Header: rtp.Header{Version: 2, PayloadType: payloadType}}).MarshalTo(b)
+
+			return n, nil, err
+		}))
+
+		buf := make([]byte, 1500)
+		for _, test := range []struct {
+			payloadType uint8
+			mimeType    string
+		}{{96, "video/VP8"}, {111, "audio/opus"}, {100, ""}} {
+			payloadType = test.payloadType
+			_, attr, err := reader.Read(buf, nil)
+			assert.NoError(t, err)
+			codec, ok := attr.GetCodec()
+			assert.Equal(t, test.mimeType != "", ok)
+			assert.Equal(t, test.mimeType, codec.MimeType)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"github.com/pion/rtcp"
+)
+
+// CompoundRTCP returns pkts starting with a report, as remotes not supporting
+// reduced-size RTCP (RFC 5506) require. The packets are ordered with
+// OrderReportsFirst, and an empty receiver report sent from reportSSRC is
+// prepended if there is none. The result is only a compound packet as defined
+// by RFC 3550 section 6.1 if pkts contain a SDES packet with the CNAME of the
+// endpoint, e.g. the one written with every report of the report interceptors,
+// as CompoundRTCP doesn't know the CNAME to add one.
+func CompoundRTCP(pkts []rtcp.Packet, reportSSRC uint32) []rtcp.Packet {
+	if len(pkts) == 0 {
+		return pkts
+	}
+
+	ordered := OrderReportsFirst(pkts)
+	switch ordered[0].(type) {
+	case *rtcp.SenderReport, *rtcp.ReceiverReport:
+		return ordered
+	default:
+		return append([]rtcp.Packet{&rtcp.ReceiverReport{SSRC: reportSSRC}}, ordered...)
+	}
+}
+
+// OrderReportsFirst moves sender and receiver reports to the front, followed
+// by source descriptions, keeping the relative order of all other packets, as
+// RFC 3550 section 6.1 requires for compound packets.
+func OrderReportsFirst(pkts []rtcp.Packet) []rtcp.Packet {
+	ordered := make([]rtcp.Packet, 0, len(pkts))
+	for _, pkt := range pkts {
+		switch pkt.(type) {
+		case *rtcp.SenderReport, *rtcp.ReceiverReport:
+			ordered = append(ordered, pkt)
+		}
+	}
+	for _, pkt := range pkts {
+		if _, ok := pkt.(*rtcp.SourceDescription); ok {
+			ordered = append(ordered, pkt)
+		}
+	}
+	for _, pkt := range pkts {
+		switch pkt.(type) {
+		case *rtcp.SenderReport, *rtcp.ReceiverReport, *rtcp.SourceDescription:
+		default:
+			ordered = append(ordered, pkt)
+		}
+	}
+
+	return ordered
+}
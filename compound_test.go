@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompoundRTCP(t *testing.T) {
+	pli := &rtcp.PictureLossIndication{MediaSSRC: 1}
+	nack := &rtcp.TransportLayerNack{MediaSSRC: 1}
+	sr := &rtcp.SenderReport{SSRC: 2}
+
+	assert.Empty(t, CompoundRTCP(nil, 3))
+	assert.Equal(t, []rtcp.Packet{&rtcp.ReceiverReport{SSRC: 3}, pli, nack}, CompoundRTCP([]rtcp.Packet{pli, nack}, 3))
+	assert.Equal(t, []rtcp.Packet{sr, pli, nack}, CompoundRTCP([]rtcp.Packet{pli, sr, nack}, 3))
+
+	// Source descriptions follow the reports
+	sdes := &rtcp.SourceDescription{Chunks: []rtcp.SourceDescriptionChunk{{
+		Source: 2,
+		Items:  []rtcp.SourceDescriptionItem{{Type: rtcp.SDESCNAME, Text: "cname"}},
+	}}}
+	assert.Equal(t, []rtcp.Packet{sr, sdes, pli, nack}, CompoundRTCP([]rtcp.Packet{pli, sdes, nack, sr}, 3))
+	assert.Equal(t, []rtcp.Packet{&rtcp.ReceiverReport{SSRC: 3}, sdes, pli}, CompoundRTCP([]rtcp.Packet{pli, sdes}, 3))
+	assert.Equal(t, []rtcp.Packet{sdes, pli}, OrderReportsFirst([]rtcp.Packet{pli, sdes}))
+}
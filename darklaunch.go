@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+var errNilDarkLaunchFilter = errors.New("dark launch filter is nil")
+
+// DarkLaunchFilter returns whether a RTCP packet written by a dark launched
+// interceptor is suppressed.
+type DarkLaunchFilter func(pkt rtcp.Packet) bool
+
+// DarkLaunchCallback is called with every suppressed packet and the id of the
+// PeerConnection it was generated for.
+type DarkLaunchCallback func(id string, pkt rtcp.Packet)
+
+// DarkLaunchOption configures a DarkLaunchFactory.
+type DarkLaunchOption func(*DarkLaunchFactory) error
+
+// DarkLaunchLog sets the logger the suppressed packets are logged to.
+func DarkLaunchLog(log logging.LeveledLogger) DarkLaunchOption {
+	return func(f *DarkLaunchFactory) error {
+		f.log = log
+
+		return nil
+	}
+}
+
+// DarkLaunchOnPacket sets a callback which is called with every suppressed
+// packet, e.g. to compare the feedback of a new interceptor with the one
+// actually sent.
+func DarkLaunchOnPacket(cb DarkLaunchCallback) DarkLaunchOption {
+	return func(f *DarkLaunchFactory) error {
+		f.onPacket = cb
+
+		return nil
+	}
+}
+
+// DarkLaunchFactory is a Factory running the interceptors of another Factory
+// in dark launch mode: the RTCP packets they write which are selected by the
+// filter, e.g. the feedback of a new RFC 8888 interceptor, are logged and
+// counted, but not sent, so the interceptor can be validated in production
+// before its feedback is actually used. All other packets are written as
+// usual. As interceptors also write the packets of the interceptors
+// registered after them, the filter should only select the packet types the
+// dark launched interceptor generates.
+type DarkLaunchFactory struct {
+	factory    Factory
+	filter     DarkLaunchFilter
+	log        logging.LeveledLogger
+	onPacket   DarkLaunchCallback
+	suppressed atomic.Uint64
+}
+
+// NewDarkLaunchFactory returns a new DarkLaunchFactory wrapping factory. The
+// filter must not be nil.
+func NewDarkLaunchFactory(
+	factory Factory, filter DarkLaunchFilter, opts ...DarkLaunchOption,
+) (*DarkLaunchFactory, error) {
+	if filter == nil {
+		return nil, errNilDarkLaunchFilter
+	}
+
+	darkLaunch := &DarkLaunchFactory{
+		factory:  factory,
+		filter:   filter,
+		log:      logging.NewDefaultLoggerFactory().NewLogger("interceptor_darklaunch"),
+		onPacket: nil,
+	}
+	for _, opt := range opts {
+		if err := opt(darkLaunch); err != nil {
+			return nil, err
+		}
+	}
+
+	return darkLaunch, nil
+}
+
+// NewInterceptor constructs an interceptor of the wrapped factory in dark
+// launch mode.
+func (f *DarkLaunchFactory) NewInterceptor(id string) (Interceptor, error) {
+	i, err := f.factory.NewInterceptor(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &darkLaunchInterceptor{Interceptor: i, factory: f, id: id}, nil
+}
+
+// Suppressed returns the number of packets suppressed by the interceptors of
+// all PeerConnections.
+func (f *DarkLaunchFactory) Suppressed() uint64 {
+	return f.suppressed.Load()
+}
+
+// darkLaunchInterceptor suppresses the packets selected by the filter of its
+// factory which the wrapped interceptor writes.
+type darkLaunchInterceptor struct {
+	Interceptor
+	factory *DarkLaunchFactory
+	id      string
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (i *darkLaunchInterceptor) BindRTCPWriter(writer RTCPWriter) RTCPWriter {
+	return i.Interceptor.BindRTCPWriter(RTCPWriterFunc(func(pkts []rtcp.Packet, attributes Attributes) (int, error) {
+		sent := make([]rtcp.Packet, 0, len(pkts))
+		for _, pkt := range pkts {
+			if !i.factory.filter(pkt) {
+				sent = append(sent, pkt)
+
+				continue
+			}
+
+			i.factory.suppressed.Add(1)
+			i.factory.log.Debugf("dark launch, not sending %T", pkt)
+			if i.factory.onPacket != nil {
+				i.factory.onPacket(i.id, pkt)
+			}
+		}
+		if len(sent) == 0 {
+			return 0, nil
+		}
+
+		return writer.Write(sent, attributes)
+	}))
+}
+
+// PreBindLocalStream pre-binds a LocalStream if the wrapped interceptor
+// implements PreBinder.
+func (i *darkLaunchInterceptor) PreBindLocalStream(info *StreamInfo) {
+	if binder, ok := i.Interceptor.(PreBinder); ok {
+		binder.PreBindLocalStream(info)
+	}
+}
+
+// PreBindRemoteStream pre-binds a RemoteStream if the wrapped interceptor
+// implements PreBinder.
+func (i *darkLaunchInterceptor) PreBindRemoteStream(info *StreamInfo) {
+	if binder, ok := i.Interceptor.(PreBinder); ok {
+		binder.PreBindRemoteStream(info)
+	}
+}
+
+// SetEventBus passes bus to the wrapped interceptor if it implements
+// EventPublisher.
+func (i *darkLaunchInterceptor) SetEventBus(bus *EventBus) {
+	if publisher, ok := i.Interceptor.(EventPublisher); ok {
+		publisher.SetEventBus(bus)
+	}
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// feedbackInterceptor keeps the RTCP writer it was bound to, to write
+// feedback like a generating interceptor.
+type feedbackInterceptor struct {
+	NoOp
+	writer   RTCPWriter
+	eventBus *EventBus
+}
+
+func (f *feedbackInterceptor) BindRTCPWriter(writer RTCPWriter) RTCPWriter {
+	f.writer = writer
+
+	return writer
+}
+
+func (f *feedbackInterceptor) SetEventBus(bus *EventBus) {
+	f.eventBus = bus
+}
+
+type feedbackFactory struct {
+	interceptor *feedbackInterceptor
+}
+
+func (f *feedbackFactory) NewInterceptor(_ string) (Interceptor, error) {
+	return f.interceptor, nil
+}
+
+func TestDarkLaunchFactory(t *testing.T) {
+	inner := &feedbackInterceptor{}
+	suppressed := []rtcp.Packet{}
+	factory, err := NewDarkLaunchFactory(
+		&feedbackFactory{interceptor: inner},
+		func(pkt rtcp.Packet) bool {
+			_, ok := pkt.(*rtcp.CCFeedbackReport)
+
+			return ok
+		},
+		DarkLaunchOnPacket(func(id string, pkt rtcp.Packet) {
+			assert.Equal(t, "pc", id)
+			suppressed = append(suppressed, pkt)
+		}),
+	)
+	require.NoError(t, err)
+
+	i, err := factory.NewInterceptor("pc")
+	require.NoError(t, err)
+
+	written := [][]rtcp.Packet{}
+	writer := i.BindRTCPWriter(RTCPWriterFunc(func(pkts []rtcp.Packet, _ Attributes) (int, error) {
+		written = append(written, pkts)
+
+		return 0, nil
+	}))
+
+	// Packets of other interceptors pass
+	rr := &rtcp.ReceiverReport{SSRC: 1}
+	_, err = writer.Write([]rtcp.Packet{rr}, nil)
+	require.NoError(t, err)
+
+	// The feedback of the wrapped interceptor is suppressed
+	ccfb := &rtcp.CCFeedbackReport{SenderSSRC: 1}
+	_, err = inner.writer.Write([]rtcp.Packet{ccfb}, nil)
+	require.NoError(t, err)
+	_, err = inner.writer.Write([]rtcp.Packet{rr, ccfb}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]rtcp.Packet{{rr}, {rr}}, written)
+	assert.Equal(t, []rtcp.Packet{ccfb, ccfb}, suppressed)
+	assert.Equal(t, uint64(2), factory.Suppressed())
+
+	// Optional interfaces of the wrapped interceptor are still reachable
+	chain := NewChain([]Interceptor{i})
+	bus := NewEventBus()
+	chain.SetEventBus(bus)
+	assert.Equal(t, bus, inner.eventBus)
+}
+
+func TestDarkLaunchFactory_NilFilter(t *testing.T) {
+	_, err := NewDarkLaunchFactory(&feedbackFactory{}, nil)
+	assert.ErrorIs(t, err, errNilDarkLaunchFilter)
+}
@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+// ECN is a Explicit Congestion Notification codepoint of the IP header as
+// defined in RFC 3168.
+type ECN uint8
+
+const (
+	// ECNNotECT marks a packet as not ECN capable.
+	ECNNotECT ECN = 0b00
+	// ECNECT1 marks a packet as ECN capable with ECT(1), which identifies L4S
+	// traffic as described in RFC 9331.
+	ECNECT1 ECN = 0b01
+	// ECNECT0 marks a packet as ECN capable with ECT(0).
+	ECNECT0 ECN = 0b10
+	// ECNCE marks a packet as having experienced congestion.
+	ECNCE ECN = 0b11
+)
+
+// SetECN requests the transport to send the RTP packet the attributes belong
+// to with the ECN codepoint. It is set by interceptors, e.g. the pacer of a
+// congestion controller, and read by the transport, which is responsible for
+// setting the codepoint of the IP header.
+func (a Attributes) SetECN(ecn ECN) {
+	a[ecnKey] = ecn
+}
+
+// GetECN returns the ECN codepoint requested with SetECN, and whether one was
+// requested.
+func (a Attributes) GetECN() (ECN, bool) {
+	ecn, ok := a[ecnKey].(ECN)
+
+	return ecn, ok
+}
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributesECN(t *testing.T) {
+	attributes := Attributes{}
+	_, ok := attributes.GetECN()
+	assert.False(t, ok)
+
+	attributes.SetECN(ECNECT1)
+	ecn, ok := attributes.GetECN()
+	assert.True(t, ok)
+	assert.Equal(t, ECNECT1, ecn)
+}
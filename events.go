@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is published by interceptors on an EventBus. The events published by
+// the interceptors of this module are the types below, e.g. EstimateChanged.
+type Event interface{}
+
+// EstimateChanged is published when the bandwidth estimate of the connection
+// changed, e.g. when a REMB message was received or the target bitrate of a
+// cc interceptor changed.
+type EstimateChanged struct {
+	// Bitrate is the estimate in bits per second.
+	Bitrate int
+	// SSRCs are the streams the estimate applies to, if it is limited to some.
+	SSRCs []uint32
+}
+
+// KeyframeRequested is published when a keyframe of a stream was requested,
+// e.g. with a PLI sent for a remote stream.
+type KeyframeRequested struct {
+	SSRC uint32
+}
+
+// StreamStalled is published while no packets of a remote stream arrived for
+// longer than expected.
+type StreamStalled struct {
+	SSRC uint32
+	// Duration is the time since the last packet of the stream.
+	Duration time.Duration
+}
+
+// PacketDiscarded is published when a received RTP packet was discarded by
+// an interceptor instead of being passed on, e.g. by a jitter buffer as it
+// arrived after its playout time.
+type PacketDiscarded struct {
+	SSRC           uint32
+	SequenceNumber uint16
+}
+
+// NACKStorm is published when most packets NACKed by the remote weren't
+// available for retransmission, e.g. as it NACKs far more packets than were
+// lost, or as the retransmission buffer is too small.
+type NACKStorm struct {
+	// SSRC is the stream of the last missing packet.
+	SSRC uint32
+	// Hits and Misses are the number of NACKed packets which were retransmitted
+	// or not found.
+	Hits   uint64
+	Misses uint64
+}
+
+// ProbeResult is published when a bandwidth probe finished, e.g. an increase
+// phase of the gcc delay based estimator.
+type ProbeResult struct {
+	// Bitrate is the bitrate in bits per second the probe was sent with.
+	Bitrate int
+	// Success is set if the probe was received without signs of congestion.
+	Success bool
+}
+
+// EventHandler receives the events of an EventBus. It is called from the
+// goroutine publishing the event, often the read or write path of a stream,
+// and must return quickly.
+type EventHandler func(event Event)
+
+// EventBus distributes the events published by the interceptors of a
+// connection to the subscribed handlers, as an alternative to the callback
+// options of every interceptor. It is passed to the interceptors with
+// SetEventBus of the Chain. The zero value is not usable, use NewEventBus.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers []*eventSubscriber
+}
+
+// eventSubscriber identifies a subscription, handlers can't be compared.
+type eventSubscriber struct {
+	handler EventHandler
+}
+
+// NewEventBus returns a new EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe adds a handler for all events published on the bus. The returned
+// function removes it again.
+func (b *EventBus) Subscribe(handler EventHandler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber := &eventSubscriber{handler: handler}
+	b.subscribers = append(b.subscribers, subscriber)
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		for k, s := range b.subscribers {
+			if s == subscriber {
+				b.subscribers = append(b.subscribers[:k:k], b.subscribers[k+1:]...)
+
+				break
+			}
+		}
+	}
+}
+
+// Publish calls the subscribed handlers with event, in the order they
+// subscribed. Publishing on a nil EventBus does nothing, so interceptors don't
+// need to check whether a bus was set.
+func (b *EventBus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		s.handler(event)
+	}
+}
+
+// EventPublisher is implemented by interceptors which publish events.
+type EventPublisher interface {
+	// SetEventBus sets the bus to publish events on. It should be called before
+	// streams are bound, events are dropped until it was called.
+	SetEventBus(bus *EventBus)
+}
+
+// SetEventBus passes bus to all interceptors of the Chain which implement
+// EventPublisher.
+func (i *Chain) SetEventBus(bus *EventBus) {
+	for _, interceptor := range i.interceptors {
+		if publisher, ok := interceptor.(EventPublisher); ok {
+			publisher.SetEventBus(bus)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type eventPublisher struct {
+	NoOp
+	bus *EventBus
+}
+
+func (p *eventPublisher) SetEventBus(bus *EventBus) {
+	p.bus = bus
+}
+
+func TestEventBus(t *testing.T) {
+	bus := NewEventBus()
+
+	var first, second []Event
+	order := []int{}
+	unsubscribe := bus.Subscribe(func(event Event) {
+		first = append(first, event)
+		order = append(order, 1)
+	})
+	bus.Subscribe(func(event Event) {
+		second = append(second, event)
+		order = append(order, 2)
+	})
+
+	bus.Publish(KeyframeRequested{SSRC: 1})
+	unsubscribe()
+	bus.Publish(EstimateChanged{Bitrate: 1000})
+
+	assert.Equal(t, []Event{KeyframeRequested{SSRC: 1}}, first)
+	assert.Equal(t, []Event{KeyframeRequested{SSRC: 1}, EstimateChanged{Bitrate: 1000}}, second)
+	assert.Equal(t, []int{1, 2, 2}, order, "handlers are called in subscription order")
+
+	// Unsubscribing twice is a no-op
+	unsubscribe()
+
+	var nilBus *EventBus
+	assert.NotPanics(t, func() {
+		nilBus.Publish(KeyframeRequested{SSRC: 1})
+	})
+}
+
+func TestChainSetEventBus(t *testing.T) {
+	publisher := &eventPublisher{}
+	chain := NewChain([]Interceptor{&NoOp{}, publisher})
+
+	bus := NewEventBus()
+	chain.SetEventBus(bus)
+	assert.Same(t, bus, publisher.bus)
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+// FrameType describes the importance of the video frame a RTP packet belongs
+// to for decoding the rest of the stream.
+type FrameType uint8
+
+const (
+	// FrameTypeUnknown is used when the frame type wasn't set.
+	FrameTypeUnknown FrameType = iota
+	// FrameTypeKey marks a keyframe, which can be decoded on its own.
+	FrameTypeKey
+	// FrameTypeDelta marks a frame which depends on previous frames and may
+	// be referenced by later ones.
+	FrameTypeDelta
+	// FrameTypeDiscardable marks a frame which isn't referenced by any other
+	// frame, e.g. a B-frame or a frame of the highest temporal layer.
+	FrameTypeDiscardable
+)
+
+// SetFrameType sets the type of the frame the RTP packet the attributes belong
+// to is part of. It is set by the application or packetizer when writing the
+// packet and used by interceptors to prioritize packets, e.g. when deciding
+// whether a lost packet is retransmitted.
+func (a Attributes) SetFrameType(frameType FrameType) {
+	a[frameTypeKey] = frameType
+}
+
+// GetFrameType returns the frame type set with SetFrameType, or
+// FrameTypeUnknown if it wasn't set.
+func (a Attributes) GetFrameType() FrameType {
+	frameType, _ := a[frameTypeKey].(FrameType)
+
+	return frameType
+}
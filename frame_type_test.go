@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributesFrameType(t *testing.T) {
+	attributes := Attributes{}
+	assert.Equal(t, FrameTypeUnknown, attributes.GetFrameType())
+
+	attributes.SetFrameType(FrameTypeKey)
+	assert.Equal(t, FrameTypeKey, attributes.GetFrameType())
+}
@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+// HopLimit is the TTL of the IPv4 header or the hop limit of the IPv6 header
+// a packet was received with.
+type HopLimit struct {
+	Value uint8
+	IPv6  bool
+}
+
+// SetHopLimit stores the TTL or hop limit a received RTP packet arrived with.
+// It is set by the transport, which has access to the IP header, and read by
+// interceptors, e.g. for RTCP XR statistics summary blocks.
+func (a Attributes) SetHopLimit(hopLimit HopLimit) {
+	a[hopLimitKey] = hopLimit
+}
+
+// GetHopLimit returns the hop limit stored with SetHopLimit, and whether one
+// was stored.
+func (a Attributes) GetHopLimit() (HopLimit, bool) {
+	hopLimit, ok := a[hopLimitKey].(HopLimit)
+
+	return hopLimit, ok
+}
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributesHopLimit(t *testing.T) {
+	attributes := Attributes{}
+	_, ok := attributes.GetHopLimit()
+	assert.False(t, ok)
+
+	attributes.SetHopLimit(HopLimit{Value: 64, IPv6: true})
+	hopLimit, ok := attributes.GetHopLimit()
+	assert.True(t, ok)
+	assert.Equal(t, HopLimit{Value: 64, IPv6: true}, hopLimit)
+}
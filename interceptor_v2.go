@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"context"
+	"io"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// InterceptorV2 is the context aware variant of Interceptor. Every read and
+// write takes a context.Context, which allows cancellation and deadlines to
+// be propagated through the chain. Use FromV1 and ToV1 to mix it with
+// existing interceptors.
+type InterceptorV2 interface {
+	// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+	// change in the future. The returned method will be called once per packet batch.
+	BindRTCPReader(reader RTCPReaderV2) RTCPReaderV2
+
+	// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+	// will be called once per packet batch.
+	BindRTCPWriter(writer RTCPWriterV2) RTCPWriterV2
+
+	// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
+	// will be called once per rtp packet.
+	BindLocalStream(info *StreamInfo, writer RTPWriterV2) RTPWriterV2
+
+	// UnbindLocalStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+	UnbindLocalStream(info *StreamInfo)
+
+	// BindRemoteStream lets you modify any incoming RTP packets.
+	// It is called once for per RemoteStream. The returned method
+	// will be called once per rtp packet.
+	BindRemoteStream(info *StreamInfo, reader RTPReaderV2) RTPReaderV2
+
+	// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+	UnbindRemoteStream(info *StreamInfo)
+
+	io.Closer
+}
+
+// RTPWriterV2 is used by InterceptorV2.BindLocalStream.
+type RTPWriterV2 interface {
+	// Write a rtp packet
+	Write(ctx context.Context, header *rtp.Header, payload []byte, attributes Attributes) (int, error)
+}
+
+// RTPReaderV2 is used by InterceptorV2.BindRemoteStream.
+type RTPReaderV2 interface {
+	// Read a rtp packet
+	Read(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error)
+}
+
+// RTCPWriterV2 is used by InterceptorV2.BindRTCPWriter.
+type RTCPWriterV2 interface {
+	// Write a batch of rtcp packets
+	Write(ctx context.Context, pkts []rtcp.Packet, attributes Attributes) (int, error)
+}
+
+// RTCPReaderV2 is used by InterceptorV2.BindRTCPReader.
+type RTCPReaderV2 interface {
+	// Read a batch of rtcp packets
+	Read(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error)
+}
+
+// RTPWriterV2Func is an adapter for RTPWriterV2 interface.
+type RTPWriterV2Func func(ctx context.Context, header *rtp.Header, payload []byte, attributes Attributes) (int, error)
+
+// RTPReaderV2Func is an adapter for RTPReaderV2 interface.
+type RTPReaderV2Func func(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error)
+
+// RTCPWriterV2Func is an adapter for RTCPWriterV2 interface.
+type RTCPWriterV2Func func(ctx context.Context, pkts []rtcp.Packet, attributes Attributes) (int, error)
+
+// RTCPReaderV2Func is an adapter for RTCPReaderV2 interface.
+type RTCPReaderV2Func func(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error)
+
+// Write a rtp packet.
+func (f RTPWriterV2Func) Write(
+	ctx context.Context, header *rtp.Header, payload []byte, attributes Attributes,
+) (int, error) {
+	return f(ctx, header, payload, attributes)
+}
+
+// Read a rtp packet.
+func (f RTPReaderV2Func) Read(ctx context.Context, b []byte, a Attributes) (int, Attributes, error) {
+	return f(ctx, b, a)
+}
+
+// Write a batch of rtcp packets.
+func (f RTCPWriterV2Func) Write(ctx context.Context, pkts []rtcp.Packet, attributes Attributes) (int, error) {
+	return f(ctx, pkts, attributes)
+}
+
+// Read a batch of rtcp packets.
+func (f RTCPReaderV2Func) Read(ctx context.Context, b []byte, a Attributes) (int, Attributes, error) {
+	return f(ctx, b, a)
+}
+
+type contextKeyType int
+
+const contextKey contextKeyType = iota
+
+// Context returns the context stored in the attributes by a V1 adapter, or
+// context.Background if there is none.
+func (a Attributes) Context() context.Context {
+	if ctx, ok := a[contextKey].(context.Context); ok {
+		return ctx
+	}
+
+	return context.Background()
+}
+
+// withContext returns a copy of the attributes with ctx stored in them. The
+// attributes of the caller aren't modified, as they may be reused for other
+// calls, e.g. with a different context.
+func withContext(attributes Attributes, ctx context.Context) Attributes {
+	copied := make(Attributes, len(attributes)+1)
+	for key, val := range attributes {
+		copied[key] = val
+	}
+	copied[contextKey] = ctx
+
+	return copied
+}
+
+// FromV1 adapts an Interceptor to the InterceptorV2 interface. The context of
+// a call is carried through the wrapped interceptor in the attributes, and a
+// call with a context that is already done fails without reaching it.
+func FromV1(i Interceptor) InterceptorV2 {
+	if v1, ok := i.(*v2Adapter); ok {
+		return v1.interceptor
+	}
+
+	return &v1Adapter{interceptor: i}
+}
+
+// ToV1 adapts an InterceptorV2 to the Interceptor interface, so it can be used
+// wherever an Interceptor is expected. The context passed to it is taken from
+// the attributes, see Attributes.Context.
+func ToV1(i InterceptorV2) Interceptor {
+	if v2, ok := i.(*v1Adapter); ok {
+		return v2.interceptor
+	}
+
+	return &v2Adapter{interceptor: i}
+}
+
+type v1Adapter struct {
+	interceptor Interceptor
+}
+
+func (a *v1Adapter) BindRTCPReader(reader RTCPReaderV2) RTCPReaderV2 {
+	bound := a.interceptor.BindRTCPReader(RTCPReaderFunc(
+		func(b []byte, attributes Attributes) (int, Attributes, error) {
+			return reader.Read(attributes.Context(), b, attributes)
+		},
+	))
+
+	return RTCPReaderV2Func(func(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error) {
+		if err := ctx.Err(); err != nil {
+			return 0, attributes, err
+		}
+
+		return bound.Read(b, withContext(attributes, ctx))
+	})
+}
+
+func (a *v1Adapter) BindRTCPWriter(writer RTCPWriterV2) RTCPWriterV2 {
+	bound := a.interceptor.BindRTCPWriter(RTCPWriterFunc(
+		func(pkts []rtcp.Packet, attributes Attributes) (int, error) {
+			return writer.Write(attributes.Context(), pkts, attributes)
+		},
+	))
+
+	return RTCPWriterV2Func(func(ctx context.Context, pkts []rtcp.Packet, attributes Attributes) (int, error) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		return bound.Write(pkts, withContext(attributes, ctx))
+	})
+}
+
+func (a *v1Adapter) BindLocalStream(info *StreamInfo, writer RTPWriterV2) RTPWriterV2 {
+	bound := a.interceptor.BindLocalStream(info, RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes Attributes) (int, error) {
+			return writer.Write(attributes.Context(), header, payload, attributes)
+		},
+	))
+
+	return RTPWriterV2Func(func(
+		ctx context.Context, header *rtp.Header, payload []byte, attributes Attributes,
+	) (int, error) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		return bound.Write(header, payload, withContext(attributes, ctx))
+	})
+}
+
+func (a *v1Adapter) UnbindLocalStream(info *StreamInfo) {
+	a.interceptor.UnbindLocalStream(info)
+}
+
+func (a *v1Adapter) BindRemoteStream(info *StreamInfo, reader RTPReaderV2) RTPReaderV2 {
+	bound := a.interceptor.BindRemoteStream(info, RTPReaderFunc(
+		func(b []byte, attributes Attributes) (int, Attributes, error) {
+			return reader.Read(attributes.Context(), b, attributes)
+		},
+	))
+
+	return RTPReaderV2Func(func(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error) {
+		if err := ctx.Err(); err != nil {
+			return 0, attributes, err
+		}
+
+		return bound.Read(b, withContext(attributes, ctx))
+	})
+}
+
+func (a *v1Adapter) UnbindRemoteStream(info *StreamInfo) {
+	a.interceptor.UnbindRemoteStream(info)
+}
+
+func (a *v1Adapter) Close() error {
+	return a.interceptor.Close()
+}
+
+type v2Adapter struct {
+	interceptor InterceptorV2
+}
+
+func (a *v2Adapter) BindRTCPReader(reader RTCPReader) RTCPReader {
+	bound := a.interceptor.BindRTCPReader(RTCPReaderV2Func(
+		func(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error) {
+			return reader.Read(b, withContext(attributes, ctx))
+		},
+	))
+
+	return RTCPReaderFunc(func(b []byte, attributes Attributes) (int, Attributes, error) {
+		return bound.Read(attributes.Context(), b, attributes)
+	})
+}
+
+func (a *v2Adapter) BindRTCPWriter(writer RTCPWriter) RTCPWriter {
+	bound := a.interceptor.BindRTCPWriter(RTCPWriterV2Func(
+		func(ctx context.Context, pkts []rtcp.Packet, attributes Attributes) (int, error) {
+			return writer.Write(pkts, withContext(attributes, ctx))
+		},
+	))
+
+	return RTCPWriterFunc(func(pkts []rtcp.Packet, attributes Attributes) (int, error) {
+		return bound.Write(attributes.Context(), pkts, attributes)
+	})
+}
+
+func (a *v2Adapter) BindLocalStream(info *StreamInfo, writer RTPWriter) RTPWriter {
+	bound := a.interceptor.BindLocalStream(info, RTPWriterV2Func(
+		func(ctx context.Context, header *rtp.Header, payload []byte, attributes Attributes) (int, error) {
+			return writer.Write(header, payload, withContext(attributes, ctx))
+		},
+	))
+
+	return RTPWriterFunc(func(header *rtp.Header, payload []byte, attributes Attributes) (int, error) {
+		return bound.Write(attributes.Context(), header, payload, attributes)
+	})
+}
+
+func (a *v2Adapter) UnbindLocalStream(info *StreamInfo) {
+	a.interceptor.UnbindLocalStream(info)
+}
+
+func (a *v2Adapter) BindRemoteStream(info *StreamInfo, reader RTPReader) RTPReader {
+	bound := a.interceptor.BindRemoteStream(info, RTPReaderV2Func(
+		func(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error) {
+			return reader.Read(b, withContext(attributes, ctx))
+		},
+	))
+
+	return RTPReaderFunc(func(b []byte, attributes Attributes) (int, Attributes, error) {
+		return bound.Read(attributes.Context(), b, attributes)
+	})
+}
+
+func (a *v2Adapter) UnbindRemoteStream(info *StreamInfo) {
+	a.interceptor.UnbindRemoteStream(info)
+}
+
+func (a *v2Adapter) Close() error {
+	return a.interceptor.Close()
+}
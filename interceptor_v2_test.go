@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+// countingInterceptor is a v1 interceptor counting the packets passing it.
+type countingInterceptor struct {
+	NoOp
+	rtp, rtcp int
+}
+
+func (c *countingInterceptor) BindRTCPWriter(writer RTCPWriter) RTCPWriter {
+	return RTCPWriterFunc(func(pkts []rtcp.Packet, attributes Attributes) (int, error) {
+		c.rtcp++
+
+		return writer.Write(pkts, attributes)
+	})
+}
+
+func (c *countingInterceptor) BindRemoteStream(_ *StreamInfo, reader RTPReader) RTPReader {
+	return RTPReaderFunc(func(b []byte, attributes Attributes) (int, Attributes, error) {
+		c.rtp++
+
+		return reader.Read(b, attributes)
+	})
+}
+
+func TestFromV1(t *testing.T) {
+	counter := &countingInterceptor{}
+	v2 := FromV1(counter)
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	t.Run("context is propagated", func(t *testing.T) {
+		reader := v2.BindRemoteStream(&StreamInfo{}, RTPReaderV2Func(
+			func(ctx context.Context, b []byte, attributes Attributes) (int, Attributes, error) {
+				assert.Equal(t, "value", ctx.Value(ctxKey{}))
+
+				return len(b), attributes, nil
+			},
+		))
+		n, _, err := reader.Read(ctx, make([]byte, 10), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, n)
+		assert.Equal(t, 1, counter.rtp)
+
+		writer := v2.BindRTCPWriter(RTCPWriterV2Func(
+			func(ctx context.Context, pkts []rtcp.Packet, _ Attributes) (int, error) {
+				assert.Equal(t, "value", ctx.Value(ctxKey{}))
+
+				return len(pkts), nil
+			},
+		))
+		n, err = writer.Write(ctx, []rtcp.Packet{&rtcp.PictureLossIndication{}}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, 1, counter.rtcp)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		writer := v2.BindRTCPWriter(RTCPWriterV2Func(
+			func(context.Context, []rtcp.Packet, Attributes) (int, error) {
+				assert.Fail(t, "canceled write reached writer")
+
+				return 0, nil
+			},
+		))
+		_, err := writer.Write(canceled, []rtcp.Packet{&rtcp.PictureLossIndication{}}, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, counter.rtcp)
+	})
+
+	assert.Same(t, counter, ToV1(v2))
+	assert.NoError(t, v2.Close())
+}
+
+// deadlineInterceptor is a v2 interceptor rejecting writes without deadline.
+type deadlineInterceptor struct {
+	InterceptorV2
+}
+
+func (d *deadlineInterceptor) BindLocalStream(info *StreamInfo, writer RTPWriterV2) RTPWriterV2 {
+	return RTPWriterV2Func(func(
+		ctx context.Context, header *rtp.Header, payload []byte, attributes Attributes,
+	) (int, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return 0, context.DeadlineExceeded
+		}
+
+		return writer.Write(ctx, header, payload, attributes)
+	})
+}
+
+func TestToV1(t *testing.T) {
+	v1 := ToV1(&deadlineInterceptor{InterceptorV2: FromV1(&NoOp{})})
+	chain := NewChain([]Interceptor{&NoOp{}, v1})
+
+	var written Attributes
+	writer := chain.BindLocalStream(&StreamInfo{}, RTPWriterFunc(
+		func(_ *rtp.Header, payload []byte, attributes Attributes) (int, error) {
+			written = attributes
+
+			return len(payload), nil
+		},
+	))
+
+	_, err := writer.Write(&rtp.Header{}, []byte{1}, Attributes{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	n, err := writer.Write(&rtp.Header{}, []byte{1}, withContext(nil, ctx))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, ctx, written.Context())
+
+	assert.Equal(t, context.Background(), Attributes(nil).Context())
+	assert.NoError(t, v1.Close())
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The attributes of the caller are not modified
+	attributes := Attributes{"key": "value"}
+	withCtx := withContext(attributes, ctx)
+	assert.Equal(t, ctx, withCtx.Context())
+	assert.Equal(t, "value", withCtx.Get("key"))
+	assert.Equal(t, Attributes{"key": "value"}, attributes)
+	assert.Equal(t, context.Background(), attributes.Context())
+}
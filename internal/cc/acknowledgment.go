@@ -19,6 +19,10 @@ type Acknowledgment struct {
 	Departure      time.Time
 	Arrival        time.Time
 	ECN            rtcp.ECN
+
+	// Metadata is the opaque value attached to the packet with
+	// MetadataAttributesKey when it was sent.
+	Metadata interface{}
 }
 
 func (a Acknowledgment) String() string {
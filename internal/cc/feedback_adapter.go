@@ -10,7 +10,7 @@ import (
 	"time"
 
 	"github.com/pion/interceptor"
-	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
@@ -19,6 +19,12 @@ import (
 // so we don't need to reparse.
 const TwccExtensionAttributesKey = iota
 
+type metadataAttributesKeyType int
+
+// MetadataAttributesKey identifies opaque per packet metadata in the attribute
+// collection, which is preserved and returned with the Acknowledgment.
+const MetadataAttributesKey metadataAttributesKeyType = 0
+
 var (
 	errMissingTWCCExtension = errors.New("missing transport layer cc header extension")
 	errInvalidFeedback      = errors.New("invalid feedback")
@@ -36,7 +42,7 @@ func NewFeedbackAdapter() *FeedbackAdapter {
 	return &FeedbackAdapter{history: newFeedbackHistory(250)}
 }
 
-func (f *FeedbackAdapter) onSentRFC8888(ts time.Time, header *rtp.Header, size int) error {
+func (f *FeedbackAdapter) onSentRFC8888(ts time.Time, header *rtp.Header, size int, metadata interface{}) error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
@@ -47,12 +53,15 @@ func (f *FeedbackAdapter) onSentRFC8888(ts time.Time, header *rtp.Header, size i
 		Departure:      ts,
 		Arrival:        time.Time{},
 		ECN:            0,
+		Metadata:       metadata,
 	})
 
 	return nil
 }
 
-func (f *FeedbackAdapter) onSentTWCC(ts time.Time, extID uint8, header *rtp.Header, size int) error {
+func (f *FeedbackAdapter) onSentTWCC(
+	ts time.Time, extID uint8, header *rtp.Header, size int, metadata interface{},
+) error {
 	sequenceNumber := header.GetExtension(extID)
 	var tccExt rtp.TransportCCExtension
 	err := tccExt.Unmarshal(sequenceNumber)
@@ -69,6 +78,7 @@ func (f *FeedbackAdapter) onSentTWCC(ts time.Time, extID uint8, header *rtp.Head
 		Departure:      ts,
 		Arrival:        time.Time{},
 		ECN:            0,
+		Metadata:       metadata,
 	})
 
 	return nil
@@ -77,13 +87,14 @@ func (f *FeedbackAdapter) onSentTWCC(ts time.Time, extID uint8, header *rtp.Head
 // OnSent records that and when an outgoing packet was sent for later mapping to
 // acknowledgments.
 func (f *FeedbackAdapter) OnSent(ts time.Time, header *rtp.Header, size int, attributes interceptor.Attributes) error {
+	metadata := attributes.Get(MetadataAttributesKey)
 	hdrExtensionID := attributes.Get(TwccExtensionAttributesKey)
 	id, ok := hdrExtensionID.(uint8)
 	if ok && hdrExtensionID != 0 {
-		return f.onSentTWCC(ts, id, header, size)
+		return f.onSentTWCC(ts, id, header, size, metadata)
 	}
 
-	return f.onSentRFC8888(ts, header, size)
+	return f.onSentRFC8888(ts, header, size, metadata)
 }
 
 func (f *FeedbackAdapter) unpackRunLengthChunk(
@@ -1006,4 +1006,40 @@ func TestFeedbackAdapterTWCC(t *testing.T) {
 			assert.Empty(t, packets)
 		})
 	})
+
+	t.Run("preservesMetadata", func(t *testing.T) {
+		adapter := NewFeedbackAdapter()
+
+		t0 := time.Time{}
+		for i := uint16(0); i < 2; i++ {
+			pkt := getPacketWithTransportCCExt(t, i)
+			assert.NoError(t, adapter.OnSent(t0, &pkt.Header, 1200, interceptor.Attributes{
+				TwccExtensionAttributesKey: hdrExtID,
+				MetadataAttributesKey:      fmt.Sprintf("frame-%v", i),
+			}))
+		}
+
+		results, err := adapter.OnTransportCCFeedback(t0, &rtcp.TransportLayerCC{
+			BaseSequenceNumber: 0,
+			PacketStatusCount:  2,
+			PacketChunks: []rtcp.PacketStatusChunk{
+				&rtcp.StatusVectorChunk{
+					SymbolSize: rtcp.TypeTCCSymbolSizeTwoBit,
+					SymbolList: []uint16{
+						rtcp.TypeTCCPacketReceivedSmallDelta,
+						rtcp.TypeTCCPacketNotReceived,
+					},
+				},
+			},
+			RecvDeltas: []*rtcp.RecvDelta{
+				{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 4},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(results))
+		assert.Equal(t, "frame-0", results[0].Metadata)
+		assert.False(t, results[0].Arrival.IsZero())
+		assert.Equal(t, "frame-1", results[1].Metadata)
+		assert.True(t, results[1].Arrival.IsZero())
+	})
 }
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package clockdrift estimates the drift between the RTP clock of a sender and
+// the local clock.
+package clockdrift
+
+import (
+	"time"
+)
+
+const (
+	// interval is the time span of which the minimum transit time is kept.
+	interval = time.Second
+	// maxSamples limits the history to the last hour.
+	maxSamples = 3600
+	// minSamples is the history needed before drift is estimated.
+	minSamples = 30
+)
+
+// sample is the minimum transit time of an interval.
+type sample struct {
+	index   int64
+	arrival float64
+	transit float64
+}
+
+// Estimator estimates the long-term drift between a RTP clock and the local
+// clock from the arrival times of packets. Network delay varies far more than
+// clocks drift apart, so only the minimum transit time, the arrival time minus
+// the RTP time, of each interval is used. The slope of a line fitted to these
+// minimums is the drift.
+type Estimator struct {
+	clockRate float64
+
+	started       bool
+	firstArrival  time.Time
+	lastTimestamp uint32
+	// timestamp is the unwrapped timestamp of the last packet, relative to
+	// the first one
+	timestamp int64
+
+	samples []sample
+	drift   float64
+}
+
+// NewEstimator returns a new Estimator for a RTP clock of the clock rate.
+func NewEstimator(clockRate uint32) *Estimator {
+	return &Estimator{
+		clockRate: float64(clockRate),
+		samples:   make([]sample, 0, minSamples),
+	}
+}
+
+// Update adds a packet received at arrival.
+func (e *Estimator) Update(arrival time.Time, timestamp uint32) {
+	if e.clockRate == 0 {
+		return
+	}
+	if !e.started {
+		e.started = true
+		e.firstArrival = arrival
+		e.lastTimestamp = timestamp
+	}
+
+	e.timestamp += int64(int32(timestamp - e.lastTimestamp)) //nolint:gosec // G115
+	e.lastTimestamp = timestamp
+
+	elapsed := arrival.Sub(e.firstArrival)
+	current := sample{
+		index:   int64(elapsed / interval),
+		arrival: elapsed.Seconds(),
+		transit: elapsed.Seconds() - float64(e.timestamp)/e.clockRate,
+	}
+
+	if last := len(e.samples) - 1; last >= 0 && e.samples[last].index >= current.index {
+		// Reordered packets may belong to an earlier interval
+		for i := last; i >= 0 && e.samples[i].index >= current.index; i-- {
+			if e.samples[i].index == current.index && current.transit < e.samples[i].transit {
+				e.samples[i] = current
+			}
+		}
+
+		return
+	}
+
+	// The fit is only updated once per interval, the minimum of the
+	// current interval isn't final yet
+	e.drift = e.fit()
+	e.samples = append(e.samples, current)
+	if len(e.samples) > maxSamples {
+		e.samples = e.samples[len(e.samples)-maxSamples:]
+	}
+}
+
+// Drift returns the drift in parts per million, positive if the sender's clock
+// runs faster than the local one, or 0 until enough packets were received.
+func (e *Estimator) Drift() float64 {
+	return e.drift
+}
+
+func (e *Estimator) fit() float64 {
+	if len(e.samples) < minSamples {
+		return 0
+	}
+
+	// Least squares fit of transit over arrival
+	n := float64(len(e.samples))
+	var sumX, sumY float64
+	for _, s := range e.samples {
+		sumX += s.arrival
+		sumY += s.transit
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var covariance, variance float64
+	for _, s := range e.samples {
+		covariance += (s.arrival - meanX) * (s.transit - meanY)
+		variance += (s.arrival - meanX) * (s.arrival - meanX)
+	}
+	if variance == 0 {
+		return 0
+	}
+
+	// A faster sender clock makes the transit time shrink
+	return -covariance / variance * 1e6
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package clockdrift
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimator(t *testing.T) {
+	for _, drift := range []float64{-200, 0, 50, 1000} {
+		estimator := NewEstimator(48000)
+		start := time.Unix(1000, 0)
+		// Start close to the wrap around of the timestamp
+		timestamp := uint32(0xFFFF0000)
+		rng := rand.New(rand.NewSource(1)) //nolint:gosec
+
+		assert.Zero(t, estimator.Drift())
+
+		// 20ms packets for 10 minutes, with up to 50ms of network jitter
+		for i := 0; i < 30000; i++ {
+			sent := time.Duration(float64(i) * 20 * float64(time.Millisecond) / (1 + drift/1e6))
+			jitter := time.Duration(rng.Int63n(int64(50 * time.Millisecond)))
+			estimator.Update(start.Add(sent+jitter), timestamp+uint32(i*960)) //nolint:gosec // G115
+		}
+
+		assert.InDelta(t, drift, estimator.Drift(), 1, "drift %v", drift)
+	}
+}
+
+func TestEstimator_NotEnoughSamples(t *testing.T) {
+	estimator := NewEstimator(90000)
+	start := time.Unix(1000, 0)
+	for i := 0; i < 10; i++ {
+		estimator.Update(start.Add(time.Duration(i)*time.Second), uint32(i*90000)) //nolint:gosec // G115
+	}
+	assert.Zero(t, estimator.Drift())
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package sequencenumber
+
+// Unroller extends 16 bit sequence numbers to 64 bit sequence numbers whose
+// higher bits count the cycles of the 16 bit sequence numbers, like the
+// extended highest sequence number of RFC 3550 section 6.4.1. The first
+// sequence number is in cycle 0. Unlike Unwrapper, which unwraps relative to
+// the previous sequence number, it unrolls relative to the highest one, so
+// reordered packets don't change the result for later ones.
+type Unroller struct {
+	started bool
+	highest uint64
+}
+
+// Unroll returns the extended sequence number of seq, and updates the highest
+// extended sequence number if seq is newer. Sequence numbers older than the
+// first one which would be before cycle 0 can't be unrolled, ok is false then.
+func (u *Unroller) Unroll(seq uint16) (extended uint64, ok bool) {
+	if !u.started {
+		u.started = true
+		u.highest = uint64(seq)
+
+		return u.highest, true
+	}
+
+	extended, ok = u.Extend(seq)
+	if ok && extended > u.highest {
+		u.highest = extended
+	}
+
+	return extended, ok
+}
+
+// Extend returns the extended sequence number of seq closest to the highest
+// one, without updating it. Sequence numbers newer than the highest one by
+// less than half a cycle are extended into the future.
+func (u *Unroller) Extend(seq uint16) (extended uint64, ok bool) {
+	if !u.started {
+		return uint64(seq), true
+	}
+
+	last := uint16(u.highest) //nolint:gosec // G115
+	if isNewer(seq, last) {
+		return u.highest + uint64(seq-last), true
+	}
+
+	behind := uint64(last - seq)
+	if behind > u.highest {
+		return 0, false
+	}
+
+	return u.highest - behind, true
+}
+
+// Highest returns the highest extended sequence number, or 0 if no sequence
+// number was unrolled yet.
+func (u *Unroller) Highest() uint64 {
+	return u.highest
+}
+
+// Started returns whether a sequence number was unrolled.
+func (u *Unroller) Started() bool {
+	return u.started
+}
+
+// Reset forgets all sequence numbers, the next one is in cycle 0 again.
+func (u *Unroller) Reset() {
+	*u = Unroller{}
+}
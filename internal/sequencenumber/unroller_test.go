@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package sequencenumber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnroller(t *testing.T) {
+	unroll := func(u *Unroller, seq uint16) uint64 {
+		extended, ok := u.Unroll(seq)
+		assert.True(t, ok)
+
+		return extended
+	}
+
+	u := &Unroller{}
+	assert.False(t, u.Started())
+	assert.Equal(t, uint64(65530), unroll(u, 65530))
+	assert.True(t, u.Started())
+	assert.Equal(t, uint64(65536+2), unroll(u, 2))
+	// Reordered packets are unrolled relative to the highest one
+	assert.Equal(t, uint64(65535), unroll(u, 65535))
+	assert.Equal(t, uint64(65536+3), unroll(u, 3))
+	assert.Equal(t, uint64(65536+3), u.Highest())
+
+	extended, ok := u.Extend(1)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(65536+1), extended)
+	assert.Equal(t, uint64(65536+3), u.Highest())
+
+	// Many cycles
+	seq := uint16(3)
+	for i := 0; i < 10*65536/1000; i++ {
+		seq += 1000
+		unroll(u, seq)
+	}
+	assert.Equal(t, uint64(65536+3+655*1000), u.Highest())
+
+	u.Reset()
+	assert.Equal(t, uint64(10), unroll(u, 10))
+	// Packets before cycle 0 can't be unrolled
+	_, ok = u.Unroll(65535)
+	assert.False(t, ok)
+	_, ok = u.Extend(65535)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(10), u.Highest())
+	assert.Equal(t, uint64(5), unroll(u, 5))
+}
@@ -1,7 +1,7 @@
 // SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
 // SPDX-License-Identifier: MIT
 
-// Package sequencenumber provides a sequence number unwrapper
+// Package sequencenumber provides sequence number unwrappers
 package sequencenumber
 
 const (
@@ -130,7 +130,7 @@ func NewMockStream(info *interceptor.StreamInfo, i interceptor.Interceptor) *Moc
 				return
 			}
 
-			pkts, err := rtcp.Unmarshal(buf[:i])
+			pkts, err := interceptor.Attributes{}.GetRTCPPackets(buf[:i])
 			if err != nil {
 				mockStream.rtcpInModified <- RTCPWithError{Err: err}
 
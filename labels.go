@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"sort"
+	"strings"
+)
+
+// Labels are key/value pairs identifying the tenant of a stream, e.g. the
+// conference and user it belongs to. They are attached to the Attributes of
+// the StreamInfo before the stream is bound, and then included in the metrics
+// and dumps of the stream, so they don't need to be mapped back from the SSRC.
+type Labels map[string]string
+
+// String returns the labels as space separated key=value pairs, sorted by key.
+func (l Labels) String() string {
+	keys := make([]string, 0, len(l))
+	for key := range l {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+l[key])
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// SetLabels sets the labels of the stream, see Labels. It must be called on
+// the Attributes of the StreamInfo before binding the stream.
+func (a Attributes) SetLabels(labels Labels) {
+	a[labelsKey] = labels
+}
+
+// GetLabels returns the labels set with SetLabels, or nil if none were set.
+func (a Attributes) GetLabels() Labels {
+	labels, _ := a[labelsKey].(Labels)
+
+	return labels
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributesLabels(t *testing.T) {
+	attributes := Attributes{}
+	assert.Nil(t, attributes.GetLabels())
+
+	attributes.SetLabels(Labels{"user": "bob", "conference": "42"})
+	assert.Equal(t, Labels{"user": "bob", "conference": "42"}, attributes.GetLabels())
+	assert.Equal(t, "conference=42 user=bob", attributes.GetLabels().String())
+
+	var nilAttributes Attributes
+	assert.Nil(t, nilAttributes.GetLabels())
+}
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// LatencyChain is a Chain measuring how long every interceptor delays the RTP
+// packets written to its LocalStreams, to verify that the chain meets a
+// processing budget. Packets are timestamped when they enter the chain and
+// every time they are passed on to the next interceptor, so the latency of an
+// interceptor includes the time packets are queued by it, e.g. by a pacer.
+// Packets written by the interceptors themselves, like retransmissions, are
+// not measured.
+type LatencyChain struct {
+	*Chain
+	histograms []*latencyHistogram
+}
+
+// LatencyReport is the latency of the packets passing an interceptor of a
+// LatencyChain. The percentiles are upper bounds, with an error of at most
+// 12.5%.
+type LatencyReport struct {
+	Interceptor Interceptor
+	Packets     uint64
+	P50         time.Duration
+	P90         time.Duration
+	P99         time.Duration
+	P999        time.Duration
+	Max         time.Duration
+}
+
+// NewLatencyChain returns a new LatencyChain of interceptors.
+func NewLatencyChain(interceptors []Interceptor) *LatencyChain {
+	histograms := make([]*latencyHistogram, len(interceptors))
+	for i := range histograms {
+		histograms[i] = &latencyHistogram{}
+	}
+
+	return &LatencyChain{
+		Chain:      NewChain(interceptors),
+		histograms: histograms,
+	}
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
+// will be called once per rtp packet.
+func (i *LatencyChain) BindLocalStream(ctx *StreamInfo, writer RTPWriter) RTPWriter {
+	i.bindStream(i.localStreams, ctx, true)
+	for k, interceptor := range i.interceptors {
+		writer = interceptor.BindLocalStream(ctx, i.exit(i.histograms[k], writer))
+	}
+
+	return RTPWriterFunc(func(header *rtp.Header, payload []byte, attributes Attributes) (int, error) {
+		if attributes == nil {
+			attributes = make(Attributes)
+		}
+		attributes[latencyKey] = &latencyStamp{last: time.Now()}
+
+		return writer.Write(header, payload, attributes)
+	})
+}
+
+// exit records the time packets took from the previous interceptor to writer.
+func (i *LatencyChain) exit(histogram *latencyHistogram, writer RTPWriter) RTPWriter {
+	return RTPWriterFunc(func(header *rtp.Header, payload []byte, attributes Attributes) (int, error) {
+		if stamp, ok := attributes[latencyKey].(*latencyStamp); ok {
+			now := time.Now()
+			histogram.record(now.Sub(stamp.last))
+			stamp.last = now
+		}
+
+		return writer.Write(header, payload, attributes)
+	})
+}
+
+// Latency returns the latency of every interceptor, in the order they were
+// passed to NewLatencyChain.
+func (i *LatencyChain) Latency() []LatencyReport {
+	reports := make([]LatencyReport, len(i.interceptors))
+	for k, interceptor := range i.interceptors {
+		histogram := i.histograms[k]
+		reports[k] = LatencyReport{
+			Interceptor: interceptor,
+			Packets:     histogram.count.Load(),
+			P50:         histogram.percentile(0.5),
+			P90:         histogram.percentile(0.9),
+			P99:         histogram.percentile(0.99),
+			P999:        histogram.percentile(0.999),
+			Max:         time.Duration(histogram.max.Load()),
+		}
+	}
+
+	return reports
+}
+
+type latencyKeyType int
+
+const latencyKey latencyKeyType = iota
+
+type latencyStamp struct {
+	last time.Time
+}
+
+// latencySubBuckets is the number of buckets per power of two of a
+// latencyHistogram.
+const latencySubBuckets = 8
+
+// latencyHistogram counts latencies in nanoseconds in log-linear buckets,
+// without locking so it can be updated by concurrent writers.
+type latencyHistogram struct {
+	buckets [62 * latencySubBuckets]atomic.Uint64
+	count   atomic.Uint64
+	max     atomic.Int64
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.buckets[latencyBucket(uint64(d))].Add(1) //nolint:gosec // G115
+	h.count.Add(1)
+	for {
+		largest := h.max.Load()
+		if int64(d) <= largest || h.max.CompareAndSwap(largest, int64(d)) {
+			return
+		}
+	}
+}
+
+// percentile returns the upper bound of the bucket of the latency below which
+// the fraction q of all latencies are, capped by the maximum latency.
+func (h *latencyHistogram) percentile(q float64) time.Duration {
+	count := h.count.Load()
+	if count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(count)))
+	seen := uint64(0)
+	for bucket := range h.buckets {
+		seen += h.buckets[bucket].Load()
+		if seen >= rank {
+			largest := time.Duration(h.max.Load())
+			if upper := time.Duration(latencyBucketUpperBound(bucket)); upper < largest { //nolint:gosec // G115
+				return upper
+			}
+
+			return largest
+		}
+	}
+
+	return time.Duration(h.max.Load())
+}
+
+// latencyBucket returns the bucket of the value v. Values below
+// latencySubBuckets have a bucket of their own, larger ones are split into
+// latencySubBuckets buckets per power of two.
+func latencyBucket(v uint64) int {
+	if v < latencySubBuckets {
+		return int(v)
+	}
+	shift := bits.Len64(v) - 4
+
+	return (shift+1)*latencySubBuckets + int(v>>shift) - latencySubBuckets //nolint:gosec // G115
+}
+
+// latencyBucketUpperBound returns the largest value of the bucket.
+func latencyBucketUpperBound(bucket int) uint64 {
+	if bucket < latencySubBuckets {
+		return uint64(bucket) //nolint:gosec // G115
+	}
+	shift := bucket/latencySubBuckets - 1
+	mantissa := uint64(bucket%latencySubBuckets + latencySubBuckets) //nolint:gosec // G115
+
+	return (mantissa+1)<<shift - 1
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+type delayInterceptor struct {
+	NoOp
+	delay time.Duration
+}
+
+func (d *delayInterceptor) BindLocalStream(_ *StreamInfo, writer RTPWriter) RTPWriter {
+	return RTPWriterFunc(func(header *rtp.Header, payload []byte, attributes Attributes) (int, error) {
+		time.Sleep(d.delay)
+
+		return writer.Write(header, payload, attributes)
+	})
+}
+
+func TestLatencyChain(t *testing.T) {
+	slow := &delayInterceptor{delay: 2 * time.Millisecond}
+	fast := &delayInterceptor{}
+	chain := NewLatencyChain([]Interceptor{fast, slow})
+
+	written := 0
+	writer := chain.BindLocalStream(&StreamInfo{SSRC: 1}, RTPWriterFunc(
+		func(*rtp.Header, []byte, Attributes) (int, error) {
+			written++
+
+			return 0, nil
+		},
+	))
+	for n := 0; n < 10; n++ {
+		_, err := writer.Write(&rtp.Header{SSRC: 1}, nil, nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 10, written)
+
+	reports := chain.Latency()
+	assert.Len(t, reports, 2)
+	assert.Equal(t, fast, reports[0].Interceptor)
+	assert.Equal(t, slow, reports[1].Interceptor)
+	for _, report := range reports {
+		assert.Equal(t, uint64(10), report.Packets)
+		assert.LessOrEqual(t, report.P50, report.P99)
+		assert.LessOrEqual(t, report.P999, report.Max)
+	}
+	assert.GreaterOrEqual(t, reports[1].P50, 2*time.Millisecond)
+	assert.Less(t, reports[0].P50, 2*time.Millisecond)
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	for _, v := range []uint64{0, 7, 8, 15, 16, 1000, 123456789, 1 << 40} {
+		bucket := latencyBucket(v)
+		assert.LessOrEqual(t, v, latencyBucketUpperBound(bucket))
+		if bucket > 0 {
+			assert.Greater(t, v, latencyBucketUpperBound(bucket-1))
+		}
+	}
+	assert.Equal(t, 495, latencyBucket(1<<64-1))
+
+	histogram := &latencyHistogram{}
+	assert.Equal(t, time.Duration(0), histogram.percentile(0.5))
+	for d := time.Duration(1); d <= 100; d++ {
+		histogram.record(d * time.Microsecond)
+	}
+	// Buckets are at most 12.5% wide
+	assert.InDelta(t, 50*time.Microsecond, histogram.percentile(0.5), float64(50*time.Microsecond/8))
+	assert.InDelta(t, 99*time.Microsecond, histogram.percentile(0.99), float64(99*time.Microsecond/8))
+	assert.Equal(t, 100*time.Microsecond, histogram.percentile(1))
+}
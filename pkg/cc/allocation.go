@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+// maxAllocationLoss caps the loss retransmissions are allocated for, so heavy
+// loss doesn't starve the media, as retransmissions would be lost as well.
+const maxAllocationLoss = 0.5
+
+// Allocation is a target bitrate split between the media payload and the
+// overhead protecting it, in bits per second. The sum of the bitrates doesn't
+// exceed the target.
+type Allocation struct {
+	Media          int
+	FEC            int
+	Retransmission int
+}
+
+// Allocate splits the target bitrate into the media bitrate and the bitrates
+// expected for FEC and retransmissions. fecOverhead is the bitrate of the
+// repair packets relative to the media bitrate, e.g. 0.25 for one repair
+// packet per four media packets, and loss is the fraction of lost packets.
+// Every lost media packet is expected to be retransmitted, including lost
+// retransmissions, while repair packets are not. The loss is capped at 50%.
+func Allocate(target int, loss, fecOverhead float64) Allocation {
+	if target <= 0 {
+		return Allocation{}
+	}
+	if fecOverhead < 0 {
+		fecOverhead = 0
+	}
+	if loss < 0 {
+		loss = 0
+	} else if loss > maxAllocationLoss {
+		loss = maxAllocationLoss
+	}
+
+	retransmissions := loss / (1 - loss)
+	media := int(float64(target) / (1 + fecOverhead + retransmissions))
+	fec := int(float64(media) * fecOverhead)
+
+	return Allocation{
+		Media:          media,
+		FEC:            fec,
+		Retransmission: target - media - fec,
+	}
+}
+
+// AllocateEstimate splits the current target bitrate of estimator with
+// Allocate, at the average loss the estimator reports in its stats, like the
+// GCC estimator. The loss of estimators not reporting it is taken to be 0.
+func AllocateEstimate(estimator BandwidthEstimator, fecOverhead float64) Allocation {
+	loss, _ := estimator.GetStats()["averageLoss"].(float64)
+
+	return Allocate(estimator.GetTargetBitrate(), loss, fecOverhead)
+}
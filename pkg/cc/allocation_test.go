@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocate(t *testing.T) {
+	assert.Equal(t, Allocation{Media: 1_000_000}, Allocate(1_000_000, 0, 0))
+	assert.Equal(t, Allocation{Media: 800_000, FEC: 200_000}, Allocate(1_000_000, 0, 0.25))
+
+	// 20% loss needs a quarter of the media bitrate for retransmissions
+	assert.Equal(t, Allocation{Media: 666_666, FEC: 166_666, Retransmission: 166_668}, Allocate(1_000_000, 0.2, 0.25))
+
+	// The loss is capped, invalid values are ignored
+	assert.Equal(t, Allocate(1_000_000, 0.5, 0), Allocate(1_000_000, 0.9, -1))
+	assert.Equal(t, Allocation{Media: 500_000, Retransmission: 500_000}, Allocate(1_000_000, 0.9, 0))
+	assert.Equal(t, Allocation{}, Allocate(0, 0.1, 0.1))
+
+	for _, loss := range []float64{0, 0.01, 0.1, 0.3} {
+		a := Allocate(1_234_567, loss, 0.1)
+		assert.Equal(t, 1_234_567, a.Media+a.FEC+a.Retransmission)
+	}
+}
+
+func TestAllocateEstimate(t *testing.T) {
+	estimator := &statsEstimator{
+		fakeEstimator: fakeEstimator{bitrate: 1_000_000},
+		stats:         map[string]interface{}{"averageLoss": 0.2},
+	}
+	assert.Equal(t, Allocate(1_000_000, 0.2, 0.25), AllocateEstimate(estimator, 0.25))
+
+	// Estimators without loss stats only split off the FEC
+	assert.Equal(t, Allocation{Media: 800_000, FEC: 200_000}, AllocateEstimate(&fakeEstimator{bitrate: 1_000_000}, 0.25))
+}
+
+type statsEstimator struct {
+	fakeEstimator
+	stats map[string]interface{}
+}
+
+func (s *statsEstimator) GetStats() map[string]interface{} {
+	return s.stats
+}
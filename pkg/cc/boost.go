@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	errInvalidBoost = errors.New("boost headroom, budget and window must be positive and the budget within the window")
+
+	errInvalidBoostDuration = errors.New("boost duration must be positive")
+
+	// ErrBoostHeadroomExhausted is returned by RequestBoost when the active
+	// boosts already use all of the headroom above the target bitrate.
+	ErrBoostHeadroomExhausted = errors.New("boost headroom exhausted")
+	// ErrBoostBudgetExhausted is returned by RequestBoost when the boosts
+	// granted recently used up the boost budget.
+	ErrBoostBudgetExhausted = errors.New("boost budget exhausted")
+)
+
+// Booster is implemented by the BandwidthEstimator passed to the
+// NewPeerConnectionCallback of interceptors created with Boost.
+type Booster interface {
+	// RequestBoost requests to raise the target bitrate by bitrate for
+	// duration, e.g. to encode a scene change or a new screen share page
+	// without dropping quality. The boost granted may be smaller than the
+	// requested one, it is limited by the headroom above the target bitrate
+	// and by the remaining boost budget. The duration must be positive.
+	RequestBoost(bitrate int, duration time.Duration) (*BoostToken, error)
+}
+
+// Boost allows the application to temporarily raise the target bitrate with
+// Booster.RequestBoost. The boosts active at the same time raise the target by
+// at most the fraction headroom of the estimate, and all boosts of the
+// interceptor last at most budget within every window, so an application
+// can't starve competing traffic by requesting boosts continuously. The
+// boosted target is still capped by a SharedLimit.
+func Boost(headroom float64, budget, window time.Duration) Option {
+	return func(c *Interceptor) error {
+		if headroom <= 0 || budget <= 0 || window < budget {
+			return errInvalidBoost
+		}
+		c.boost = &boostConfig{headroom: headroom, budget: budget, window: window}
+
+		return nil
+	}
+}
+
+type boostConfig struct {
+	headroom float64
+	budget   time.Duration
+	window   time.Duration
+}
+
+// BoostToken is a boost granted by Booster.RequestBoost. It ends when it
+// expires or is released, whichever comes first.
+type BoostToken struct {
+	estimator *boostedEstimator
+	bitrate   int
+	expires   time.Time
+	timer     *time.Timer
+}
+
+// Bitrate returns the granted raise of the target bitrate in bits per second.
+func (t *BoostToken) Bitrate() int {
+	return t.bitrate
+}
+
+// Expires returns when the boost expires.
+func (t *BoostToken) Expires() time.Time {
+	return t.expires
+}
+
+// Release ends the boost before it expires. The unused time is refunded to
+// the boost budget.
+func (t *BoostToken) Release() {
+	t.timer.Stop()
+	t.estimator.end(t, true)
+}
+
+// limitedBooster is the estimator of a SharedLimit wrapping a boostedEstimator,
+// so the application can still request boosts.
+type limitedBooster struct {
+	BandwidthEstimator
+	Booster
+}
+
+// boostedEstimator is a BandwidthEstimator whose target can be raised by
+// boosts.
+type boostedEstimator struct {
+	BandwidthEstimator
+	config boostConfig
+	now    func() time.Time
+
+	m        sync.Mutex
+	active   map[*BoostToken]struct{}
+	budget   time.Duration
+	refilled time.Time
+	onChange func(int)
+	reported int
+}
+
+func newBoostedEstimator(estimator BandwidthEstimator, config boostConfig) *boostedEstimator {
+	boosted := &boostedEstimator{
+		BandwidthEstimator: estimator,
+		config:             config,
+		now:                time.Now,
+		active:             map[*BoostToken]struct{}{},
+		budget:             config.budget,
+		refilled:           time.Time{},
+		onChange:           nil,
+		reported:           estimator.GetTargetBitrate(),
+	}
+	estimator.OnTargetBitrateChange(func(int) {
+		boosted.update()
+	})
+
+	return boosted
+}
+
+func (e *boostedEstimator) RequestBoost(bitrate int, duration time.Duration) (*BoostToken, error) {
+	if duration <= 0 {
+		return nil, errInvalidBoostDuration
+	}
+
+	e.m.Lock()
+	now := e.now()
+	e.refill(now)
+	if available := e.headroom() - e.boosted(); bitrate > available {
+		bitrate = available
+	}
+	if bitrate <= 0 {
+		e.m.Unlock()
+
+		return nil, ErrBoostHeadroomExhausted
+	}
+	if e.budget <= 0 {
+		e.m.Unlock()
+
+		return nil, ErrBoostBudgetExhausted
+	}
+	if duration > e.budget {
+		duration = e.budget
+	}
+	e.budget -= duration
+
+	token := &BoostToken{
+		estimator: e,
+		bitrate:   bitrate,
+		expires:   now.Add(duration),
+	}
+	e.active[token] = struct{}{}
+	token.timer = time.AfterFunc(duration, func() {
+		e.end(token, false)
+	})
+	e.m.Unlock()
+	e.update()
+
+	return token, nil
+}
+
+// end removes the boost of the token, refunding its remaining time to the
+// budget if it was released early.
+func (e *boostedEstimator) end(token *BoostToken, refund bool) {
+	e.m.Lock()
+	if _, ok := e.active[token]; !ok {
+		e.m.Unlock()
+
+		return
+	}
+	delete(e.active, token)
+	if now := e.now(); refund && token.expires.After(now) {
+		e.refill(now)
+		e.budget += token.expires.Sub(now)
+		if e.budget > e.config.budget {
+			e.budget = e.config.budget
+		}
+	}
+	e.m.Unlock()
+	e.update()
+}
+
+// refill adds the budget regained since the last refill, which is the budget
+// per window. It must be called with e.m held.
+func (e *boostedEstimator) refill(now time.Time) {
+	if !e.refilled.IsZero() {
+		elapsed := now.Sub(e.refilled)
+		e.budget += time.Duration(float64(elapsed) * float64(e.config.budget) / float64(e.config.window))
+		if e.budget > e.config.budget {
+			e.budget = e.config.budget
+		}
+	}
+	e.refilled = now
+}
+
+// headroom must be called with e.m held.
+func (e *boostedEstimator) headroom() int {
+	return int(float64(e.BandwidthEstimator.GetTargetBitrate()) * e.config.headroom)
+}
+
+// boosted must be called with e.m held.
+func (e *boostedEstimator) boosted() int {
+	boosted := 0
+	for token := range e.active {
+		boosted += token.bitrate
+	}
+
+	return boosted
+}
+
+// target must be called with e.m held. The boosts are capped by the headroom,
+// as the estimate may have dropped since they were granted.
+func (e *boostedEstimator) target() int {
+	boosted := e.boosted()
+	if headroom := e.headroom(); boosted > headroom {
+		boosted = headroom
+	}
+
+	return e.BandwidthEstimator.GetTargetBitrate() + boosted
+}
+
+// update notifies the callback if the boosted target changed.
+func (e *boostedEstimator) update() {
+	e.m.Lock()
+	bitrate := e.target()
+	changed := bitrate != e.reported
+	e.reported = bitrate
+	onChange := e.onChange
+	e.m.Unlock()
+
+	if changed && onChange != nil {
+		onChange(bitrate)
+	}
+}
+
+func (e *boostedEstimator) GetTargetBitrate() int {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return e.target()
+}
+
+func (e *boostedEstimator) OnTargetBitrateChange(f func(bitrate int)) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.onChange = f
+}
+
+func (e *boostedEstimator) Close() error {
+	e.m.Lock()
+	for token := range e.active {
+		token.timer.Stop()
+		delete(e.active, token)
+	}
+	e.m.Unlock()
+
+	return e.BandwidthEstimator.Close()
+}
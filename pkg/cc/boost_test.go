@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoost(t *testing.T) {
+	raw := &fakeEstimator{bitrate: 1_000_000}
+	factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+		return raw, nil
+	}, Boost(0.5, time.Hour, 10*time.Hour))
+	require.NoError(t, err)
+
+	var estimator BandwidthEstimator
+	factory.OnNewPeerConnection(func(_ string, e BandwidthEstimator) {
+		estimator = e
+	})
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	booster, ok := estimator.(Booster)
+	require.True(t, ok)
+	now := time.Now()
+	publishing, ok := estimator.(*publishingBooster)
+	require.True(t, ok)
+	boosted, ok := publishing.Booster.(*boostedEstimator)
+	require.True(t, ok)
+	boosted.now = func() time.Time { return now }
+
+	changes := make(chan int, 10)
+	estimator.OnTargetBitrateChange(func(bitrate int) {
+		changes <- bitrate
+	})
+
+	// A boost must last.
+	for _, duration := range []time.Duration{0, -time.Minute} {
+		_, err = booster.RequestBoost(100_000, duration)
+		assert.ErrorIs(t, err, errInvalidBoostDuration)
+	}
+
+	// The boost is capped by the headroom of 50% of the estimate.
+	first, err := booster.RequestBoost(300_000, 20*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 300_000, first.Bitrate())
+	assert.Equal(t, 1_300_000, <-changes)
+	second, err := booster.RequestBoost(300_000, 10*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 200_000, second.Bitrate())
+	assert.Equal(t, now.Add(40*time.Minute), second.Expires())
+	assert.Equal(t, 1_500_000, <-changes)
+	_, err = booster.RequestBoost(100_000, time.Minute)
+	assert.ErrorIs(t, err, ErrBoostHeadroomExhausted)
+
+	// Boosts exceeding the headroom of a lower estimate are capped.
+	raw.setTargetBitrate(800_000)
+	assert.Equal(t, 1_200_000, <-changes)
+	assert.Equal(t, 1_200_000, estimator.GetTargetBitrate())
+
+	// Releasing a boost early refunds the rest of its duration.
+	first.Release()
+	assert.Equal(t, 1_000_000, <-changes)
+	third, err := booster.RequestBoost(100_000, 10*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(20*time.Minute), third.Expires())
+	assert.Equal(t, 1_100_000, <-changes)
+	_, err = booster.RequestBoost(100_000, time.Minute)
+	assert.ErrorIs(t, err, ErrBoostBudgetExhausted)
+
+	// The budget is refilled by an hour every ten hours.
+	now = now.Add(10 * time.Minute)
+	second.Release()
+	assert.Equal(t, 900_000, <-changes)
+	fourth, err := booster.RequestBoost(100_000, 10*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(31*time.Minute), fourth.Expires())
+	assert.Equal(t, 1_000_000, <-changes)
+	fourth.Release()
+	assert.Equal(t, 900_000, <-changes)
+
+	// Boosts end when they expire.
+	fifth, err := booster.RequestBoost(100_000, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, 1_000_000, <-changes)
+	select {
+	case bitrate := <-changes:
+		assert.Equal(t, 900_000, bitrate)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "boost didn't expire")
+	}
+	fifth.Release()
+	third.Release()
+	assert.Equal(t, 800_000, <-changes)
+	assert.Empty(t, changes)
+}
+
+func TestBoost_SharedLimit(t *testing.T) {
+	limiter, err := NewSharedLimiter(1_000_000)
+	require.NoError(t, err)
+
+	raw := &fakeEstimator{bitrate: 800_000}
+	factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+		return raw, nil
+	}, SharedLimit(limiter), Boost(0.5, time.Hour, 10*time.Hour))
+	require.NoError(t, err)
+
+	var estimator BandwidthEstimator
+	factory.OnNewPeerConnection(func(_ string, e BandwidthEstimator) {
+		estimator = e
+	})
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	changes := make(chan int, 10)
+	estimator.OnTargetBitrateChange(func(bitrate int) {
+		changes <- bitrate
+	})
+
+	// The boost is granted, but the boosted target is capped by the limit
+	booster, ok := estimator.(Booster)
+	require.True(t, ok)
+	token, err := booster.RequestBoost(300_000, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 300_000, token.Bitrate())
+	assert.Equal(t, 1_000_000, <-changes)
+	assert.Equal(t, 1_000_000, estimator.GetTargetBitrate())
+
+	token.Release()
+	assert.Equal(t, 800_000, <-changes)
+	assert.Empty(t, changes)
+}
+
+func TestBoost_Invalid(t *testing.T) {
+	for _, opt := range []Option{
+		Boost(0, time.Second, time.Second),
+		Boost(0.5, 0, time.Second),
+		Boost(0.5, 2*time.Second, time.Second),
+	} {
+		factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+			return &fakeEstimator{}, nil
+		}, opt)
+		require.NoError(t, err)
+
+		_, err = factory.NewInterceptor("")
+		assert.ErrorIs(t, err, errInvalidBoost)
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFrameDropperWindow        = 500 * time.Millisecond
+	defaultFrameDropperMaxQueueDelay = 200 * time.Millisecond
+)
+
+var errInvalidFrameDropperWindow = errors.New("frame dropper window must be positive")
+
+// FrameDropperOption configures a FrameDropper.
+type FrameDropperOption func(*FrameDropper) error
+
+// FrameDropperWindow sets the burst window of the FrameDropper. Frames are
+// dropped once the encoder output exceeds the target bitrate by more than the
+// amount of data that can be sent during window.
+func FrameDropperWindow(window time.Duration) FrameDropperOption {
+	return func(d *FrameDropper) error {
+		if window <= 0 {
+			return errInvalidFrameDropperWindow
+		}
+		d.window = window
+
+		return nil
+	}
+}
+
+// FrameDropperMaxQueueDelay sets the maximum time it may take the pacer to
+// send its queue at the target bitrate, before new frames are dropped. A value
+// <= 0 disables the check.
+func FrameDropperMaxQueueDelay(delay time.Duration) FrameDropperOption {
+	return func(d *FrameDropper) error {
+		d.maxQueueDelay = delay
+
+		return nil
+	}
+}
+
+// FrameDropper advises an application whether to send or drop an encoded
+// frame, given the current target bitrate and the state of the pacer queue.
+// It implements a leaky bucket similar to the frame dropper of libwebrtc: the
+// size of every sent frame is added to the bucket which leaks at the target
+// bitrate. Frames which would overflow the bucket should be dropped.
+type FrameDropper struct {
+	window        time.Duration
+	maxQueueDelay time.Duration
+
+	lock        sync.Mutex
+	accumulator float64
+	lastUpdate  time.Time
+}
+
+// NewFrameDropper creates a new FrameDropper.
+func NewFrameDropper(opts ...FrameDropperOption) (*FrameDropper, error) {
+	dropper := &FrameDropper{
+		window:        defaultFrameDropperWindow,
+		maxQueueDelay: defaultFrameDropperMaxQueueDelay,
+		lock:          sync.Mutex{},
+		accumulator:   0,
+		lastUpdate:    time.Time{},
+	}
+	for _, opt := range opts {
+		if err := opt(dropper); err != nil {
+			return nil, err
+		}
+	}
+
+	return dropper, nil
+}
+
+// ShouldDrop returns true if the frame of frameSize bytes should be dropped.
+// targetBitrate is the current target bitrate in bits per second, e.g. from
+// BandwidthEstimator.GetTargetBitrate, and queuedBytes the number of bytes
+// currently waiting in the pacer. Frames that are not dropped are accounted
+// as sent.
+func (d *FrameDropper) ShouldDrop(now time.Time, targetBitrate, queuedBytes, frameSize int) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if targetBitrate <= 0 {
+		return true
+	}
+
+	if !d.lastUpdate.IsZero() && now.After(d.lastUpdate) {
+		d.accumulator -= now.Sub(d.lastUpdate).Seconds() * float64(targetBitrate)
+		if d.accumulator < 0 {
+			d.accumulator = 0
+		}
+	}
+	d.lastUpdate = now
+
+	if d.maxQueueDelay > 0 {
+		queueDelay := time.Duration(float64(queuedBytes*8) / float64(targetBitrate) * float64(time.Second))
+		if queueDelay > d.maxQueueDelay {
+			return true
+		}
+	}
+
+	frameBits := float64(frameSize * 8)
+	// Always allow a single frame to pass an empty bucket, otherwise frames
+	// larger than the bucket would never be sent.
+	if d.accumulator > 0 && d.accumulator+frameBits > d.window.Seconds()*float64(targetBitrate) {
+		return true
+	}
+	d.accumulator += frameBits
+
+	return false
+}
+
+// Reset empties the leaky bucket, e.g. after a key frame was requested.
+func (d *FrameDropper) Reset() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.accumulator = 0
+	d.lastUpdate = time.Time{}
+}
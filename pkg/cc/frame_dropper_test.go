@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameDropper(t *testing.T) {
+	t0 := time.Time{}.Add(time.Hour)
+
+	t.Run("sendsFramesWithinBudget", func(t *testing.T) {
+		dropper, err := NewFrameDropper()
+		assert.NoError(t, err)
+
+		// 30 fps at 1 Mbps leaves ~4166 bytes per frame
+		for i := 0; i < 100; i++ {
+			now := t0.Add(time.Duration(i) * time.Second / 30)
+			assert.False(t, dropper.ShouldDrop(now, 1_000_000, 0, 4000))
+		}
+	})
+
+	t.Run("dropsFramesExceedingBudget", func(t *testing.T) {
+		dropper, err := NewFrameDropper()
+		assert.NoError(t, err)
+
+		dropped := 0
+		for i := 0; i < 300; i++ {
+			now := t0.Add(time.Duration(i) * time.Second / 30)
+			if dropper.ShouldDrop(now, 1_000_000, 0, 8000) {
+				dropped++
+			}
+		}
+		// The encoder produces twice the target bitrate, roughly half the
+		// frames have to be dropped once the burst window is used up.
+		assert.InDelta(t, 140, dropped, 10)
+	})
+
+	t.Run("allowsLargeFrameOnEmptyBucket", func(t *testing.T) {
+		dropper, err := NewFrameDropper(FrameDropperWindow(100 * time.Millisecond))
+		assert.NoError(t, err)
+
+		assert.False(t, dropper.ShouldDrop(t0, 100_000, 0, 10_000))
+		assert.True(t, dropper.ShouldDrop(t0.Add(time.Millisecond), 100_000, 0, 1000))
+		dropper.Reset()
+		assert.False(t, dropper.ShouldDrop(t0.Add(2*time.Millisecond), 100_000, 0, 1000))
+	})
+
+	t.Run("dropsOnLongPacerQueue", func(t *testing.T) {
+		dropper, err := NewFrameDropper(FrameDropperMaxQueueDelay(100 * time.Millisecond))
+		assert.NoError(t, err)
+
+		// 25000 bytes take 200ms at 1 Mbps
+		assert.True(t, dropper.ShouldDrop(t0, 1_000_000, 25_000, 1000))
+		assert.False(t, dropper.ShouldDrop(t0, 1_000_000, 10_000, 1000))
+	})
+
+	t.Run("rejectsInvalidWindow", func(t *testing.T) {
+		_, err := NewFrameDropper(FrameDropperWindow(0))
+		assert.ErrorIs(t, err, errInvalidFrameDropperWindow)
+	})
+}
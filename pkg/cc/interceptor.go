@@ -6,14 +6,71 @@
 package cc
 
 import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/pkg/gcc"
+	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 )
 
+var errInvalidREMBInterval = errors.New("REMB mirror interval must be positive")
+
 // Option can be used to set initial options on CC interceptors.
 type Option func(*Interceptor) error
 
+// MirrorREMB makes the interceptor send the current send-side estimate to the
+// remote as REMB, which some SFUs use as a hint. A REMB for the local streams
+// is sent at most once per interval and only if the estimate changed.
+func MirrorREMB(interval time.Duration) Option {
+	return func(c *Interceptor) error {
+		if interval <= 0 {
+			return errInvalidREMBInterval
+		}
+		c.rembInterval = interval
+
+		return nil
+	}
+}
+
+// SharedLimit makes the interceptor share the limit of the SharedLimiter with
+// the other interceptors registered with it. The BandwidthEstimator passed to
+// the NewPeerConnectionCallback reports the scaled target bitrate.
+func SharedLimit(limiter *SharedLimiter) Option {
+	return func(c *Interceptor) error {
+		c.limiter = limiter
+
+		return nil
+	}
+}
+
+// ShadowCallback is called with the target bitrates of the active and the
+// shadow estimator whenever the shadow target changes.
+type ShadowCallback func(active, shadow int)
+
+// Shadow runs a second BandwidthEstimator created by factory on the same
+// feedback and sent packets, e.g. to evaluate a new algorithm on live traffic.
+// Its estimate is logged and passed to cb, which may be nil, but never used as
+// the target. The shadow must not pace, e.g. a gcc.SendSideBWE has to use a
+// gcc.NoOpPacer.
+func Shadow(factory BandwidthEstimatorFactory, cb ShadowCallback) Option {
+	return func(c *Interceptor) error {
+		shadow, err := factory()
+		if err != nil {
+			return err
+		}
+		c.shadow = shadow
+		c.onShadowChange = cb
+
+		return nil
+	}
+}
+
 // BandwidthEstimatorFactory creates new BandwidthEstimators.
 type BandwidthEstimatorFactory func() (BandwidthEstimator, error)
 
@@ -68,10 +125,18 @@ func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor,
 		return nil, err
 	}
 	interceptorInstance := &Interceptor{
-		NoOp:      interceptor.NoOp{},
-		estimator: bwe,
-		feedback:  make(chan []rtcp.Packet),
-		close:     make(chan struct{}),
+		NoOp:         interceptor.NoOp{},
+		log:          logging.NewDefaultLoggerFactory().NewLogger("cc_interceptor"),
+		estimator:    bwe,
+		base:         bwe,
+		feedback:     make(chan []rtcp.Packet),
+		close:        make(chan struct{}),
+		rembInterval: 0,
+		senderSSRC:   rand.Uint32(), // #nosec
+		limiter:      nil,
+		shadow:       nil,
+		boost:        nil,
+		ssrcs:        map[uint32]struct{}{},
 	}
 
 	for _, opt := range f.opts {
@@ -80,6 +145,31 @@ func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor,
 		}
 	}
 
+	if interceptorInstance.shadow != nil {
+		interceptorInstance.shadow.OnTargetBitrateChange(interceptorInstance.onShadowTargetBitrateChange)
+	}
+
+	// The limiter is applied after the booster, so boosts can't exceed the
+	// shared limit
+	var booster Booster
+	if interceptorInstance.boost != nil {
+		boosted := newBoostedEstimator(interceptorInstance.estimator, *interceptorInstance.boost)
+		booster = boosted
+		interceptorInstance.estimator = boosted
+	}
+
+	if interceptorInstance.limiter != nil {
+		interceptorInstance.estimator = interceptorInstance.limiter.register(interceptorInstance.estimator)
+		if booster != nil {
+			interceptorInstance.estimator = &limitedBooster{
+				BandwidthEstimator: interceptorInstance.estimator,
+				Booster:            booster,
+			}
+		}
+	}
+
+	interceptorInstance.estimator = newPublishingEstimator(interceptorInstance.estimator, &interceptorInstance.events)
+
 	if f.addPeerConnection != nil {
 		f.addPeerConnection(id, interceptorInstance.estimator)
 	}
@@ -90,9 +180,97 @@ func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor,
 // Interceptor implements Google Congestion Control.
 type Interceptor struct {
 	interceptor.NoOp
+	log       logging.LeveledLogger
 	estimator BandwidthEstimator
-	feedback  chan []rtcp.Packet
-	close     chan struct{}
+	// base is the estimator before it was wrapped, e.g. by the limiter.
+	base     BandwidthEstimator
+	feedback chan []rtcp.Packet
+	close    chan struct{}
+
+	rembInterval time.Duration
+	senderSSRC   uint32
+	limiter      *SharedLimiter
+
+	shadow         BandwidthEstimator
+	onShadowChange ShadowCallback
+
+	boost *boostConfig
+
+	events atomic.Pointer[interceptor.EventBus]
+
+	m     sync.Mutex
+	wg    sync.WaitGroup
+	ssrcs map[uint32]struct{}
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once
+// per PeerConnection. The returned method will be called once per packet
+// batch.
+func (c *Interceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.rembInterval == 0 || c.isClosed() {
+		return writer
+	}
+
+	c.wg.Add(1)
+	go c.loop(writer)
+
+	return writer
+}
+
+func (c *Interceptor) isClosed() bool {
+	select {
+	case <-c.close:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Interceptor) loop(writer interceptor.RTCPWriter) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.rembInterval)
+	defer ticker.Stop()
+
+	lastBitrate := 0
+	for {
+		select {
+		case <-ticker.C:
+			bitrate := c.estimator.GetTargetBitrate()
+			ssrcs := c.localSSRCs()
+			if bitrate == lastBitrate || len(ssrcs) == 0 {
+				continue
+			}
+			lastBitrate = bitrate
+
+			remb := &rtcp.ReceiverEstimatedMaximumBitrate{
+				SenderSSRC: c.senderSSRC,
+				Bitrate:    float32(bitrate),
+				SSRCs:      ssrcs,
+			}
+			if _, err := writer.Write([]rtcp.Packet{remb}, interceptor.Attributes{}); err != nil {
+				c.log.Warnf("failed sending REMB: %+v", err)
+			}
+		case <-c.close:
+			return
+		}
+	}
+}
+
+func (c *Interceptor) localSSRCs() []uint32 {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	ssrcs := make([]uint32, 0, len(c.ssrcs))
+	for ssrc := range c.ssrcs {
+		ssrcs = append(ssrcs, ssrc)
+	}
+	sort.Slice(ssrcs, func(i, j int) bool { return ssrcs[i] < ssrcs[j] })
+
+	return ssrcs
 }
 
 // BindRTCPReader lets you modify any incoming RTCP packets. It is called once
@@ -119,6 +297,11 @@ func (c *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.
 		if err = c.estimator.WriteRTCP(pkts, attr); err != nil {
 			return 0, nil, err
 		}
+		if c.shadow != nil {
+			if err := c.shadow.WriteRTCP(pkts, attr); err != nil {
+				c.log.Warnf("shadow estimator failed to process RTCP: %+v", err)
+			}
+		}
 
 		return i, attr, nil
 	})
@@ -129,10 +312,61 @@ func (c *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.
 func (c *Interceptor) BindLocalStream(
 	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
 ) interceptor.RTPWriter {
+	c.m.Lock()
+	c.ssrcs[info.SSRC] = struct{}{}
+	c.m.Unlock()
+
+	if c.shadow != nil {
+		// The shadow sees the packets after they were paced by the active estimator
+		writer = c.shadow.AddStream(info, writer)
+	}
+
 	return c.estimator.AddStream(info, writer)
 }
 
+// SetEventBus sets the bus an interceptor.EstimateChanged event is published
+// on for every change of the target bitrate. It is passed to the
+// BandwidthEstimator as well if it implements interceptor.EventPublisher, e.g.
+// to publish the interceptor.ProbeResult events of gcc.SendSideBWE.
+func (c *Interceptor) SetEventBus(bus *interceptor.EventBus) {
+	c.events.Store(bus)
+	if publisher, ok := c.base.(interceptor.EventPublisher); ok {
+		publisher.SetEventBus(bus)
+	}
+}
+
+func (c *Interceptor) onShadowTargetBitrateChange(shadow int) {
+	active := c.estimator.GetTargetBitrate()
+	c.log.Infof("shadow target bitrate %d, active target bitrate %d", shadow, active)
+	if c.onShadowChange != nil {
+		c.onShadowChange(active, shadow)
+	}
+}
+
+// UnbindLocalStream removes the stream from mirrored REMBs.
+func (c *Interceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	delete(c.ssrcs, info.SSRC)
+}
+
 // Close closes the interceptor and the associated bandwidth estimator.
 func (c *Interceptor) Close() error {
+	c.m.Lock()
+	if !c.isClosed() {
+		close(c.close)
+	}
+	c.m.Unlock()
+
+	// The REMB loop must be done with the estimator before it is closed
+	c.wg.Wait()
+
+	if c.shadow != nil {
+		if err := c.shadow.Close(); err != nil {
+			return err
+		}
+	}
+
 	return c.estimator.Close()
 }
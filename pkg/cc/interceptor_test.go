@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEstimator struct {
+	bitrate  int64
+	feedback int64
+	onChange func(int)
+}
+
+func (f *fakeEstimator) setTargetBitrate(bitrate int) {
+	atomic.StoreInt64(&f.bitrate, int64(bitrate))
+	if f.onChange != nil {
+		f.onChange(bitrate)
+	}
+}
+
+func (f *fakeEstimator) AddStream(_ *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
+	return writer
+}
+
+func (f *fakeEstimator) WriteRTCP([]rtcp.Packet, interceptor.Attributes) error {
+	atomic.AddInt64(&f.feedback, 1)
+
+	return nil
+}
+
+func (f *fakeEstimator) GetTargetBitrate() int {
+	return int(atomic.LoadInt64(&f.bitrate))
+}
+
+func (f *fakeEstimator) OnTargetBitrateChange(cb func(bitrate int)) {
+	f.onChange = cb
+}
+
+func (f *fakeEstimator) GetStats() map[string]interface{} {
+	return nil
+}
+
+func (f *fakeEstimator) Close() error {
+	return nil
+}
+
+func TestInterceptor_MirrorREMB(t *testing.T) {
+	estimator := &fakeEstimator{bitrate: 500_000}
+	factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+		return estimator, nil
+	}, MirrorREMB(10*time.Millisecond))
+	require.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	readREMB := func() *rtcp.ReceiverEstimatedMaximumBitrate {
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			require.Len(t, pkts, 1)
+			remb, ok := pkts[0].(*rtcp.ReceiverEstimatedMaximumBitrate)
+			require.True(t, ok)
+
+			return remb
+		case <-time.After(time.Second):
+			assert.FailNow(t, "no REMB written")
+		}
+
+		return nil
+	}
+
+	remb := readREMB()
+	assert.Equal(t, float32(500_000), remb.Bitrate)
+	assert.Equal(t, []uint32{123}, remb.SSRCs)
+
+	// An unchanged estimate isn't sent again
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.FailNow(t, "unexpected REMB", "%v", pkts)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	atomic.StoreInt64(&estimator.bitrate, 300_000)
+	assert.Equal(t, float32(300_000), readREMB().Bitrate)
+}
+
+func TestInterceptor_MirrorREMBInvalidInterval(t *testing.T) {
+	factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+		return &fakeEstimator{}, nil
+	}, MirrorREMB(0))
+	require.NoError(t, err)
+
+	_, err = factory.NewInterceptor("")
+	assert.ErrorIs(t, err, errInvalidREMBInterval)
+}
+
+func TestInterceptor_Shadow(t *testing.T) {
+	active := &fakeEstimator{bitrate: 500_000}
+	shadow := &fakeEstimator{bitrate: 300_000}
+	changes := make(chan [2]int, 1)
+	factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+		return active, nil
+	}, Shadow(func() (BandwidthEstimator, error) {
+		return shadow, nil
+	}, func(active, shadow int) {
+		changes <- [2]int{active, shadow}
+	}))
+	require.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTCP([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 123}})
+	select {
+	case r := <-stream.ReadRTCP():
+		require.NoError(t, r.Err)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "receiver rtcp packets not found")
+	}
+	assert.Equal(t, int64(1), atomic.LoadInt64(&active.feedback))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&shadow.feedback))
+
+	require.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123}}))
+	select {
+	case <-stream.WrittenRTP():
+	case <-time.After(time.Second):
+		assert.FailNow(t, "rtp packet not written")
+	}
+
+	shadow.setTargetBitrate(400_000)
+	assert.Equal(t, [2]int{500_000, 400_000}, <-changes)
+	assert.Equal(t, 500_000, active.GetTargetBitrate())
+}
+
+func TestInterceptor_EstimateChanged(t *testing.T) {
+	for _, boost := range []bool{false, true} {
+		estimator := &fakeEstimator{bitrate: 500_000}
+		opts := []Option{}
+		if boost {
+			opts = append(opts, Boost(0.2, time.Second, 10*time.Second))
+		}
+		factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+			return estimator, nil
+		}, opts...)
+		require.NoError(t, err)
+		var bwe BandwidthEstimator
+		factory.OnNewPeerConnection(func(_ string, e BandwidthEstimator) {
+			bwe = e
+		})
+
+		i, err := factory.NewInterceptor("")
+		require.NoError(t, err)
+
+		bus := interceptor.NewEventBus()
+		var events []interceptor.Event
+		bus.Subscribe(func(event interceptor.Event) {
+			events = append(events, event)
+		})
+		publisher, ok := i.(interceptor.EventPublisher)
+		require.True(t, ok)
+		publisher.SetEventBus(bus)
+
+		// The callback of the application is still called
+		var changes []int
+		bwe.OnTargetBitrateChange(func(bitrate int) {
+			changes = append(changes, bitrate)
+		})
+		_, ok = bwe.(Booster)
+		assert.Equal(t, boost, ok)
+
+		estimator.setTargetBitrate(400_000)
+		assert.Equal(t, []interceptor.Event{interceptor.EstimateChanged{Bitrate: 400_000}}, events)
+		assert.Equal(t, []int{400_000}, changes)
+		assert.NoError(t, i.Close())
+	}
+}
+
+type publishingFakeEstimator struct {
+	fakeEstimator
+	bus *interceptor.EventBus
+}
+
+func (f *publishingFakeEstimator) SetEventBus(bus *interceptor.EventBus) {
+	f.bus = bus
+}
+
+func TestInterceptor_EstimatorEventBus(t *testing.T) {
+	estimator := &publishingFakeEstimator{fakeEstimator: fakeEstimator{bitrate: 500_000}}
+	factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+		return estimator, nil
+	}, Boost(0.2, time.Second, 10*time.Second))
+	require.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	// The bus is passed to the estimator, even though it is wrapped
+	bus := interceptor.NewEventBus()
+	publisher, ok := i.(interceptor.EventPublisher)
+	require.True(t, ok)
+	publisher.SetEventBus(bus)
+	assert.Same(t, bus, estimator.bus)
+}
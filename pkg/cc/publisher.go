@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pion/interceptor"
+)
+
+// publishingEstimator publishes an interceptor.EstimateChanged event for every
+// change of the target bitrate of the wrapped BandwidthEstimator, in addition
+// to calling the callback set with OnTargetBitrateChange.
+type publishingEstimator struct {
+	BandwidthEstimator
+	events *atomic.Pointer[interceptor.EventBus]
+
+	m        sync.Mutex
+	onChange func(bitrate int)
+}
+
+// publishingBooster is a publishingEstimator of a BandwidthEstimator which is
+// a Booster, so the application can still request boosts.
+type publishingBooster struct {
+	*publishingEstimator
+	Booster
+}
+
+func newPublishingEstimator(
+	estimator BandwidthEstimator, events *atomic.Pointer[interceptor.EventBus],
+) BandwidthEstimator {
+	publishing := &publishingEstimator{
+		BandwidthEstimator: estimator,
+		events:             events,
+		onChange:           nil,
+	}
+	estimator.OnTargetBitrateChange(publishing.changed)
+	if booster, ok := estimator.(Booster); ok {
+		return &publishingBooster{publishingEstimator: publishing, Booster: booster}
+	}
+
+	return publishing
+}
+
+func (e *publishingEstimator) changed(bitrate int) {
+	e.events.Load().Publish(interceptor.EstimateChanged{Bitrate: bitrate})
+
+	e.m.Lock()
+	onChange := e.onChange
+	e.m.Unlock()
+	if onChange != nil {
+		onChange(bitrate)
+	}
+}
+
+func (e *publishingEstimator) OnTargetBitrateChange(f func(bitrate int)) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.onChange = f
+}
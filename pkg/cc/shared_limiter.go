@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"errors"
+	"sync"
+)
+
+var errInvalidSharedLimit = errors.New("shared limit must be positive")
+
+// SharedLimiter enforces a cap on the sum of the target bitrates of several
+// CC interceptors, e.g. of all PeerConnections in a container with limited
+// egress. While the estimates exceed the limit, the target of every estimator
+// is scaled down proportionally. It only affects the targets reported to the
+// application, which has to adapt its encoders accordingly.
+type SharedLimiter struct {
+	m       sync.Mutex
+	limit   int
+	members map[*limitedEstimator]struct{}
+}
+
+// NewSharedLimiter returns a SharedLimiter with a limit in bits per second.
+// It is passed to the CC interceptor factories with SharedLimit.
+func NewSharedLimiter(limit int) (*SharedLimiter, error) {
+	if limit <= 0 {
+		return nil, errInvalidSharedLimit
+	}
+
+	return &SharedLimiter{
+		m:       sync.Mutex{},
+		limit:   limit,
+		members: map[*limitedEstimator]struct{}{},
+	}, nil
+}
+
+// SetLimit changes the limit in bits per second.
+func (l *SharedLimiter) SetLimit(limit int) error {
+	if limit <= 0 {
+		return errInvalidSharedLimit
+	}
+
+	l.m.Lock()
+	l.limit = limit
+	l.m.Unlock()
+	l.update()
+
+	return nil
+}
+
+func (l *SharedLimiter) register(estimator BandwidthEstimator) BandwidthEstimator {
+	member := &limitedEstimator{
+		BandwidthEstimator: estimator,
+		limiter:            l,
+		onChange:           nil,
+		reported:           0,
+	}
+	estimator.OnTargetBitrateChange(func(int) {
+		l.update()
+	})
+
+	l.m.Lock()
+	l.members[member] = struct{}{}
+	member.reported = l.scaled(member, l.total())
+	l.m.Unlock()
+	l.update()
+
+	return member
+}
+
+func (l *SharedLimiter) unregister(member *limitedEstimator) {
+	l.m.Lock()
+	delete(l.members, member)
+	l.m.Unlock()
+	l.update()
+}
+
+// total must be called with l.m held.
+func (l *SharedLimiter) total() int {
+	total := 0
+	for member := range l.members {
+		total += member.BandwidthEstimator.GetTargetBitrate()
+	}
+
+	return total
+}
+
+// scaled must be called with l.m held.
+func (l *SharedLimiter) scaled(member *limitedEstimator, total int) int {
+	bitrate := member.BandwidthEstimator.GetTargetBitrate()
+	if total <= l.limit {
+		return bitrate
+	}
+
+	return int(int64(bitrate) * int64(l.limit) / int64(total))
+}
+
+// update notifies the members whose scaled target changed.
+func (l *SharedLimiter) update() {
+	type change struct {
+		cb      func(int)
+		bitrate int
+	}
+
+	l.m.Lock()
+	total := l.total()
+	changes := []change{}
+	for member := range l.members {
+		bitrate := l.scaled(member, total)
+		if bitrate == member.reported {
+			continue
+		}
+		member.reported = bitrate
+		if member.onChange != nil {
+			changes = append(changes, change{cb: member.onChange, bitrate: bitrate})
+		}
+	}
+	l.m.Unlock()
+
+	for _, c := range changes {
+		c.cb(c.bitrate)
+	}
+}
+
+// limitedEstimator is a BandwidthEstimator whose target is scaled by a
+// SharedLimiter.
+type limitedEstimator struct {
+	BandwidthEstimator
+	limiter  *SharedLimiter
+	onChange func(int)
+	reported int
+}
+
+func (e *limitedEstimator) GetTargetBitrate() int {
+	e.limiter.m.Lock()
+	defer e.limiter.m.Unlock()
+
+	return e.limiter.scaled(e, e.limiter.total())
+}
+
+func (e *limitedEstimator) OnTargetBitrateChange(f func(bitrate int)) {
+	e.limiter.m.Lock()
+	defer e.limiter.m.Unlock()
+
+	e.onChange = f
+}
+
+func (e *limitedEstimator) Close() error {
+	e.limiter.unregister(e)
+
+	return e.BandwidthEstimator.Close()
+}
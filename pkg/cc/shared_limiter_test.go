@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedLimiter(t *testing.T) {
+	limiter, err := NewSharedLimiter(1_000_000)
+	require.NoError(t, err)
+
+	newEstimator := func(bitrate int) (*fakeEstimator, BandwidthEstimator, chan int) {
+		raw := &fakeEstimator{bitrate: int64(bitrate)}
+		factory, err := NewInterceptor(func() (BandwidthEstimator, error) {
+			return raw, nil
+		}, SharedLimit(limiter))
+		require.NoError(t, err)
+
+		var estimator BandwidthEstimator
+		factory.OnNewPeerConnection(func(_ string, e BandwidthEstimator) {
+			estimator = e
+		})
+		i, err := factory.NewInterceptor("")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			assert.NoError(t, i.Close())
+		})
+
+		changes := make(chan int, 10)
+		estimator.OnTargetBitrateChange(func(bitrate int) {
+			changes <- bitrate
+		})
+
+		return raw, estimator, changes
+	}
+
+	raw1, estimator1, changes1 := newEstimator(400_000)
+	assert.Equal(t, 400_000, estimator1.GetTargetBitrate())
+
+	// The sum exceeds the limit, so both targets are scaled by 2/3
+	raw2, estimator2, changes2 := newEstimator(1_100_000)
+	assert.Equal(t, 266_666, estimator1.GetTargetBitrate())
+	assert.Equal(t, 733_333, estimator2.GetTargetBitrate())
+	assert.Equal(t, 266_666, <-changes1)
+
+	raw2.setTargetBitrate(100_000)
+	assert.Equal(t, 400_000, <-changes1)
+	assert.Equal(t, 100_000, <-changes2)
+
+	raw1.setTargetBitrate(1_900_000)
+	assert.Equal(t, 950_000, <-changes1)
+	assert.Equal(t, 50_000, <-changes2)
+
+	require.NoError(t, limiter.SetLimit(4_000_000))
+	assert.Equal(t, 1_900_000, <-changes1)
+	assert.Equal(t, 100_000, <-changes2)
+
+	require.NoError(t, estimator2.Close())
+	assert.Equal(t, 1_900_000, estimator1.GetTargetBitrate())
+	assert.Empty(t, changes1)
+}
+
+func TestNewSharedLimiter_Invalid(t *testing.T) {
+	_, err := NewSharedLimiter(0)
+	assert.ErrorIs(t, err, errInvalidSharedLimit)
+
+	limiter, err := NewSharedLimiter(1)
+	require.NoError(t, err)
+	assert.ErrorIs(t, limiter.SetLimit(-1), errInvalidSharedLimit)
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"errors"
+	"sync"
+)
+
+const defaultSimulcastHysteresis = 0.2
+
+var (
+	errNoSimulcastLayers          = errors.New("simulcast policy needs at least one layer")
+	errInvalidSimulcastLayer      = errors.New("simulcast layer bitrates must be positive and min not exceed max")
+	errInvalidSimulcastHysteresis = errors.New("simulcast hysteresis must not be negative")
+)
+
+// SimulcastLayer describes a simulcast layer of a publisher.
+type SimulcastLayer struct {
+	RID string
+	// MinBitrate is the bitrate needed to send the layer in useful quality,
+	// MaxBitrate the bitrate it is encoded with at most.
+	MinBitrate int
+	MaxBitrate int
+}
+
+// LayerRecommendation is the recommendation of a SimulcastPolicy for a
+// layer. Disabled layers have a TargetBitrate of 0.
+type LayerRecommendation struct {
+	RID           string
+	Enabled       bool
+	TargetBitrate int
+}
+
+// SimulcastPolicyOption configures a SimulcastPolicy.
+type SimulcastPolicyOption func(*SimulcastPolicy) error
+
+// SimulcastHysteresis sets how much the bitrate must exceed the minimum
+// bitrate of a disabled layer, as a fraction of it, before the layer is
+// enabled, so layers don't flap when the estimate is close to their minimum.
+// The default is 0.2.
+func SimulcastHysteresis(hysteresis float64) SimulcastPolicyOption {
+	return func(p *SimulcastPolicy) error {
+		if hysteresis < 0 {
+			return errInvalidSimulcastHysteresis
+		}
+		p.hysteresis = hysteresis
+
+		return nil
+	}
+}
+
+// SimulcastPolicy turns the media bitrate of an Allocation into
+// recommendations for the simulcast layers of a publisher: which layers to
+// enable, and the target bitrate of each one. Layers are enabled from the
+// lowest one up, while the bitrate covers the minimum bitrates of all enabled
+// layers, and the bitrate left is given to the lower layers first, up to
+// their maximum bitrates. The lowest layer is never disabled, it gets the
+// whole bitrate if it is below its minimum.
+type SimulcastPolicy struct {
+	layers     []SimulcastLayer
+	hysteresis float64
+
+	m       sync.Mutex
+	enabled int
+}
+
+// NewSimulcastPolicy returns a SimulcastPolicy for the layers, ordered from
+// the lowest to the highest one.
+func NewSimulcastPolicy(layers []SimulcastLayer, opts ...SimulcastPolicyOption) (*SimulcastPolicy, error) {
+	if len(layers) == 0 {
+		return nil, errNoSimulcastLayers
+	}
+	for _, layer := range layers {
+		if layer.MinBitrate <= 0 || layer.MaxBitrate < layer.MinBitrate {
+			return nil, errInvalidSimulcastLayer
+		}
+	}
+
+	policy := &SimulcastPolicy{
+		layers:     append([]SimulcastLayer{}, layers...),
+		hysteresis: defaultSimulcastHysteresis,
+		m:          sync.Mutex{},
+		enabled:    1,
+	}
+	for _, opt := range opts {
+		if err := opt(policy); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+// Recommend returns the recommendations for all layers, in the order they were
+// passed to NewSimulcastPolicy, for the media bitrate of the allocation. The
+// layers enabled by the previous call are kept as long as the bitrate covers
+// their minimum bitrates.
+func (p *SimulcastPolicy) Recommend(allocation Allocation) []LayerRecommendation {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	bitrate := allocation.Media
+	required := 0
+	enabled := 0
+	for k, layer := range p.layers {
+		required += layer.MinBitrate
+		threshold := required
+		if k >= p.enabled {
+			threshold += int(float64(layer.MinBitrate) * p.hysteresis)
+		}
+		if k > 0 && bitrate < threshold {
+			break
+		}
+		enabled++
+	}
+	p.enabled = enabled
+
+	recommendations := make([]LayerRecommendation, len(p.layers))
+	left := bitrate
+	for k, layer := range p.layers {
+		recommendations[k] = LayerRecommendation{RID: layer.RID, Enabled: k < enabled, TargetBitrate: 0}
+		if k < enabled {
+			recommendations[k].TargetBitrate = minInt(layer.MinBitrate, left)
+			left -= recommendations[k].TargetBitrate
+		}
+	}
+	for k := 0; k < enabled && left > 0; k++ {
+		extra := minInt(p.layers[k].MaxBitrate-recommendations[k].TargetBitrate, left)
+		recommendations[k].TargetBitrate += extra
+		left -= extra
+	}
+
+	return recommendations
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
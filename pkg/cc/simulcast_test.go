@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package cc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulcastPolicy(t *testing.T) {
+	policy, err := NewSimulcastPolicy([]SimulcastLayer{
+		{RID: "q", MinBitrate: 100_000, MaxBitrate: 200_000},
+		{RID: "h", MinBitrate: 300_000, MaxBitrate: 600_000},
+		{RID: "f", MinBitrate: 1_000_000, MaxBitrate: 2_000_000},
+	})
+	require.NoError(t, err)
+
+	recommend := func(media int) []LayerRecommendation {
+		return policy.Recommend(Allocation{Media: media})
+	}
+
+	// The lowest layer is always enabled
+	assert.Equal(t, []LayerRecommendation{
+		{RID: "q", Enabled: true, TargetBitrate: 50_000},
+		{RID: "h"},
+		{RID: "f"},
+	}, recommend(50_000))
+
+	// The minimum of the next layer is covered, but not the hysteresis
+	assert.Equal(t, []LayerRecommendation{
+		{RID: "q", Enabled: true, TargetBitrate: 200_000},
+		{RID: "h"},
+		{RID: "f"},
+	}, recommend(450_000))
+
+	assert.Equal(t, []LayerRecommendation{
+		{RID: "q", Enabled: true, TargetBitrate: 200_000},
+		{RID: "h", Enabled: true, TargetBitrate: 300_000},
+		{RID: "f"},
+	}, recommend(500_000))
+
+	// Enabled layers stay enabled down to their minimum bitrate
+	assert.Equal(t, []LayerRecommendation{
+		{RID: "q", Enabled: true, TargetBitrate: 150_000},
+		{RID: "h", Enabled: true, TargetBitrate: 300_000},
+		{RID: "f"},
+	}, recommend(450_000))
+
+	assert.Equal(t, []LayerRecommendation{
+		{RID: "q", Enabled: true, TargetBitrate: 200_000},
+		{RID: "h", Enabled: true, TargetBitrate: 600_000},
+		{RID: "f", Enabled: true, TargetBitrate: 1_700_000},
+	}, recommend(2_500_000))
+
+	// Dropping below the minimum of a layer disables it and all above it
+	assert.Equal(t, []LayerRecommendation{
+		{RID: "q", Enabled: true, TargetBitrate: 200_000},
+		{RID: "h"},
+		{RID: "f"},
+	}, recommend(350_000))
+}
+
+func TestNewSimulcastPolicy_Invalid(t *testing.T) {
+	_, err := NewSimulcastPolicy(nil)
+	assert.ErrorIs(t, err, errNoSimulcastLayers)
+
+	_, err = NewSimulcastPolicy([]SimulcastLayer{{RID: "q", MinBitrate: 200_000, MaxBitrate: 100_000}})
+	assert.ErrorIs(t, err, errInvalidSimulcastLayer)
+
+	_, err = NewSimulcastPolicy([]SimulcastLayer{{RID: "q", MinBitrate: 100_000, MaxBitrate: 100_000}},
+		SimulcastHysteresis(-1))
+	assert.ErrorIs(t, err, errInvalidSimulcastHysteresis)
+}
@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package concealment provides an interceptor hinting the application at lost
+// audio packets as soon as the loss is detected, so packet loss concealment
+// can be prepared before the decoder reaches the gap.
+package concealment
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+// LossRun describes consecutive lost packets of an audio stream.
+type LossRun struct {
+	SSRC uint32
+	// FirstSequenceNumber is the sequence number of the first lost packet.
+	FirstSequenceNumber uint16
+	// Packets is the number of lost packets. It is estimated from the packet
+	// duration while the run is ongoing, and 0 if a run reported as ongoing
+	// ended without loss, e.g. because the sender paused for DTX.
+	Packets int
+	// Duration is the duration of the missing audio.
+	Duration time.Duration
+	// Ongoing is set while no packet after the run arrived yet. The run is
+	// reported again once it ended.
+	Ongoing bool
+	// RecentRuns and RecentPackets are the number of ended loss runs and their
+	// lost packets within the window, see Window.
+	RecentRuns    int
+	RecentPackets int
+}
+
+// LossCallback receives the loss runs of audio streams. It is called from the
+// read path of the stream and the loop detecting stalls, and should return
+// quickly.
+type LossCallback func(run LossRun)
+
+// InterceptorFactory is a interceptor.Factory for a concealment Interceptor.
+type InterceptorFactory struct {
+	opts []Option
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{opts: opts}, nil
+}
+
+// NewInterceptor constructs a new Interceptor.
+func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	i := &Interceptor{
+		NoOp:         interceptor.NoOp{},
+		onLoss:       nil,
+		stallTimeout: 60 * time.Millisecond,
+		window:       time.Second,
+		now:          time.Now,
+		log:          logging.NewDefaultLoggerFactory().NewLogger("concealment"),
+		streams:      map[uint32]*stream{},
+		close:        make(chan struct{}),
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// Interceptor detects runs of lost packets on received audio streams. A run
+// is reported when the packet following it arrives, and, if no packet arrived
+// for the stall timeout, already while it is ongoing.
+type Interceptor struct {
+	interceptor.NoOp
+	onLoss       LossCallback
+	stallTimeout time.Duration
+	window       time.Duration
+	now          func() time.Time
+	log          logging.LeveledLogger
+	events       atomic.Pointer[interceptor.EventBus]
+
+	m       sync.Mutex
+	wg      sync.WaitGroup
+	streams map[uint32]*stream
+	started bool
+	close   chan struct{}
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream. The returned method
+// will be called once per rtp packet.
+func (i *Interceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	if (i.onLoss == nil && i.events.Load() == nil) || !strings.HasPrefix(strings.ToLower(info.MimeType), "audio/") {
+		return reader
+	}
+
+	s := &stream{ssrc: info.SSRC, clockRate: info.ClockRate}
+	i.m.Lock()
+	i.streams[info.SSRC] = s
+	if !i.started && i.stallTimeout > 0 && !i.isClosed() {
+		i.started = true
+		i.wg.Add(1)
+		go i.loop()
+	}
+	i.m.Unlock()
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		header, err := attr.GetRTPHeader(b[:n])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if header.SSRC == info.SSRC {
+			i.receive(s, header)
+		}
+
+		return n, attr, nil
+	})
+}
+
+func (i *Interceptor) receive(s *stream, header *rtp.Header) {
+	i.m.Lock()
+	run, ok := s.receive(i.now(), header.SequenceNumber, header.Timestamp, i.window)
+	i.m.Unlock()
+
+	if ok {
+		i.report(run)
+	}
+}
+
+func (i *Interceptor) report(run LossRun) {
+	i.log.Debugf("loss run of ssrc %d: %d packets from %d, ongoing: %v",
+		run.SSRC, run.Packets, run.FirstSequenceNumber, run.Ongoing)
+	if i.onLoss != nil {
+		i.onLoss(run)
+	}
+}
+
+// SetEventBus sets the bus an interceptor.StreamStalled event is published on
+// when a stream received no packets for the stall timeout. It must be called
+// before the streams are bound, streams are only tracked if a LossCallback or
+// an EventBus was set.
+func (i *Interceptor) SetEventBus(bus *interceptor.EventBus) {
+	i.events.Store(bus)
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (i *Interceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	delete(i.streams, info.SSRC)
+}
+
+// Close closes the interceptor.
+func (i *Interceptor) Close() error {
+	defer i.wg.Wait()
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if !i.isClosed() {
+		close(i.close)
+	}
+
+	return nil
+}
+
+func (i *Interceptor) isClosed() bool {
+	select {
+	case <-i.close:
+		return true
+	default:
+		return false
+	}
+}
+
+func (i *Interceptor) loop() {
+	defer i.wg.Done()
+
+	interval := i.stallTimeout / 4
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, run := range i.stalls() {
+				i.report(run)
+				i.events.Load().Publish(interceptor.StreamStalled{SSRC: run.SSRC, Duration: run.Duration})
+			}
+		case <-i.close:
+			return
+		}
+	}
+}
+
+func (i *Interceptor) stalls() []LossRun {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	var runs []LossRun
+	now := i.now()
+	for _, s := range i.streams {
+		if run, ok := s.stall(now, i.stallTimeout, i.window); ok {
+			runs = append(runs, run)
+		}
+	}
+
+	return runs
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package concealment
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type runRecorder struct {
+	m    sync.Mutex
+	runs []LossRun
+}
+
+func (r *runRecorder) add(run LossRun) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.runs = append(r.runs, run)
+}
+
+func (r *runRecorder) get() []LossRun {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return append([]LossRun{}, r.runs...)
+}
+
+func TestInterceptor(t *testing.T) {
+	mt := &test.MockTime{}
+	start := time.Unix(1000, 0)
+	mt.SetNow(start)
+	recorder := &runRecorder{}
+
+	f, err := NewInterceptor(OnLoss(recorder.add), StallTimeout(50*time.Millisecond), Now(mt.Now))
+	require.NoError(t, err)
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1, MimeType: "audio/opus", ClockRate: 48000}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	receive := func(seq uint16, at time.Duration) {
+		mt.SetNow(start.Add(at))
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{
+			SSRC:           1,
+			SequenceNumber: seq,
+			Timestamp:      uint32(seq) * 960,
+		}})
+		<-stream.ReadRTP()
+	}
+
+	receive(10, 0)
+	receive(11, 20*time.Millisecond)
+	receive(14, 60*time.Millisecond)
+	assert.Equal(t, []LossRun{{
+		SSRC:                1,
+		FirstSequenceNumber: 12,
+		Packets:             2,
+		Duration:            40 * time.Millisecond,
+		Ongoing:             false,
+		RecentRuns:          1,
+		RecentPackets:       2,
+	}}, recorder.get())
+
+	// No packet for 100ms
+	mt.SetNow(start.Add(160 * time.Millisecond))
+	require.Eventually(t, func() bool {
+		return len(recorder.get()) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, LossRun{
+		SSRC:                1,
+		FirstSequenceNumber: 15,
+		Packets:             5,
+		Duration:            100 * time.Millisecond,
+		Ongoing:             true,
+		RecentRuns:          1,
+		RecentPackets:       2,
+	}, recorder.get()[1])
+
+	receive(21, 200*time.Millisecond)
+	assert.Equal(t, LossRun{
+		SSRC:                1,
+		FirstSequenceNumber: 15,
+		Packets:             6,
+		Duration:            120 * time.Millisecond,
+		Ongoing:             false,
+		RecentRuns:          2,
+		RecentPackets:       8,
+	}, recorder.get()[2])
+
+	// The first run left the window, the stall may have been reported before
+	receive(23, 1100*time.Millisecond)
+	runs := recorder.get()
+	last := runs[len(runs)-1]
+	assert.False(t, last.Ongoing)
+	assert.Equal(t, 2, last.RecentRuns)
+	assert.Equal(t, 7, last.RecentPackets)
+}
+
+func TestInterceptor_StallWithoutLoss(t *testing.T) {
+	mt := &test.MockTime{}
+	start := time.Unix(1000, 0)
+	mt.SetNow(start)
+	recorder := &runRecorder{}
+
+	f, err := NewInterceptor(OnLoss(recorder.add), StallTimeout(50*time.Millisecond), Now(mt.Now))
+	require.NoError(t, err)
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1, MimeType: "audio/opus", ClockRate: 48000}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: 1}})
+	<-stream.ReadRTP()
+
+	mt.SetNow(start.Add(400 * time.Millisecond))
+	require.Eventually(t, func() bool {
+		return len(recorder.get()) == 1
+	}, time.Second, time.Millisecond)
+	assert.True(t, recorder.get()[0].Ongoing)
+
+	// DTX
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: 2, Timestamp: 400 * 48}})
+	<-stream.ReadRTP()
+	runs := recorder.get()
+	require.Len(t, runs, 2)
+	assert.False(t, runs[1].Ongoing)
+	assert.Zero(t, runs[1].Packets)
+	assert.Zero(t, runs[1].RecentRuns)
+}
+
+func TestInterceptor_IgnoresVideo(t *testing.T) {
+	recorder := &runRecorder{}
+	f, err := NewInterceptor(OnLoss(recorder.add))
+	require.NoError(t, err)
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1, MimeType: "video/VP8", ClockRate: 90000}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	for _, seq := range []uint16{1, 5} {
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: seq}})
+		<-stream.ReadRTP()
+	}
+	assert.Empty(t, recorder.get())
+}
+
+func TestInterceptor_InvalidOptions(t *testing.T) {
+	for _, item := range []struct {
+		opt Option
+		err error
+	}{
+		{StallTimeout(-time.Second), errInvalidStallTimeout},
+		{Window(0), errInvalidWindow},
+	} {
+		f, err := NewInterceptor(item.opt)
+		require.NoError(t, err)
+
+		_, err = f.NewInterceptor("")
+		assert.ErrorIs(t, err, item.err)
+	}
+}
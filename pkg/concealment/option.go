@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package concealment
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pion/logging"
+)
+
+var (
+	errInvalidStallTimeout = errors.New("stall timeout must not be negative")
+	errInvalidWindow       = errors.New("window must be positive")
+)
+
+// Option can be used to configure the Interceptor.
+type Option func(*Interceptor) error
+
+// OnLoss sets the callback receiving the loss runs of the audio streams.
+func OnLoss(cb LossCallback) Option {
+	return func(i *Interceptor) error {
+		i.onLoss = cb
+
+		return nil
+	}
+}
+
+// StallTimeout sets after how long without packets an ongoing loss run is
+// reported, before the next packet reveals its length. Streams using DTX
+// report ongoing runs during silence, a timeout of 0 disables them.
+func StallTimeout(timeout time.Duration) Option {
+	return func(i *Interceptor) error {
+		if timeout < 0 {
+			return errInvalidStallTimeout
+		}
+		i.stallTimeout = timeout
+
+		return nil
+	}
+}
+
+// Window sets the time span covered by the recent loss of a LossRun.
+func Window(window time.Duration) Option {
+	return func(i *Interceptor) error {
+		if window <= 0 {
+			return errInvalidWindow
+		}
+		i.window = window
+
+		return nil
+	}
+}
+
+// Now sets an alternative for the time.Now function.
+func Now(now func() time.Time) Option {
+	return func(i *Interceptor) error {
+		i.now = now
+
+		return nil
+	}
+}
+
+// Log sets a logger for the interceptor.
+func Log(log logging.LeveledLogger) Option {
+	return func(i *Interceptor) error {
+		i.log = log
+
+		return nil
+	}
+}
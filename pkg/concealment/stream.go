@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package concealment
+
+import (
+	"time"
+)
+
+// defaultPacketDuration is assumed until the duration of the stream's
+// packets is known.
+const defaultPacketDuration = 20 * time.Millisecond
+
+type lossRecord struct {
+	at      time.Time
+	packets int
+}
+
+type stream struct {
+	ssrc      uint32
+	clockRate uint32
+
+	started        bool
+	lastSeq        uint16
+	lastTimestamp  uint32
+	lastArrival    time.Time
+	packetDuration time.Duration
+
+	// stalled is set once an ongoing run was reported
+	stalled bool
+	recent  []lossRecord
+}
+
+// receive processes a received packet and returns the loss run it ended, if
+// any.
+func (s *stream) receive(now time.Time, seq uint16, timestamp uint32, window time.Duration) (LossRun, bool) {
+	if !s.started {
+		s.started = true
+		s.lastSeq, s.lastTimestamp, s.lastArrival = seq, timestamp, now
+
+		return LossRun{}, false
+	}
+
+	diff := seq - s.lastSeq
+	if diff == 0 || diff >= 1<<15 {
+		// Duplicates and late packets are too late for the decoder anyway
+		return LossRun{}, false
+	}
+
+	if s.clockRate != 0 && timestamp != s.lastTimestamp {
+		perPacket := uint64(timestamp-s.lastTimestamp) * uint64(time.Second) / uint64(s.clockRate) / uint64(diff)
+		s.packetDuration = time.Duration(perPacket) //nolint:gosec // G115
+	}
+	stalled := s.stalled
+	first := s.lastSeq + 1
+	s.lastSeq, s.lastTimestamp, s.lastArrival, s.stalled = seq, timestamp, now, false
+
+	lost := int(diff) - 1
+	if lost == 0 {
+		if !stalled {
+			return LossRun{}, false
+		}
+
+		// The stall wasn't caused by loss, e.g. DTX
+		return s.run(now, first, 0, 0, false, window), true
+	}
+
+	return s.run(now, first, lost, time.Duration(lost)*s.duration(), false, window), true
+}
+
+// stall returns an ongoing loss run, if no packet arrived for longer than
+// timeout and the run wasn't reported yet.
+func (s *stream) stall(now time.Time, timeout time.Duration, window time.Duration) (LossRun, bool) {
+	elapsed := now.Sub(s.lastArrival)
+	if !s.started || s.stalled || elapsed <= timeout {
+		return LossRun{}, false
+	}
+	s.stalled = true
+
+	lost := int(elapsed / s.duration())
+	if lost == 0 {
+		lost = 1
+	}
+
+	return s.run(now, s.lastSeq+1, lost, elapsed, true, window), true
+}
+
+func (s *stream) duration() time.Duration {
+	if s.packetDuration <= 0 {
+		return defaultPacketDuration
+	}
+
+	return s.packetDuration
+}
+
+func (s *stream) run(now time.Time, first uint16, lost int, duration time.Duration, ongoing bool,
+	window time.Duration,
+) LossRun {
+	// An ongoing run is recorded once it ended
+	if !ongoing && lost > 0 {
+		s.recent = append(s.recent, lossRecord{at: now, packets: lost})
+	}
+	for len(s.recent) > 0 && now.Sub(s.recent[0].at) > window {
+		s.recent = s.recent[1:]
+	}
+
+	run := LossRun{
+		SSRC:                s.ssrc,
+		FirstSequenceNumber: first,
+		Packets:             lost,
+		Duration:            duration,
+		Ongoing:             ongoing,
+		RecentRuns:          len(s.recent),
+		RecentPackets:       0,
+	}
+	for _, record := range s.recent {
+		run.RecentPackets += record.packets
+	}
+
+	return run
+}
@@ -0,0 +1,293 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/intervalpli"
+	"github.com/pion/interceptor/pkg/nack"
+	"github.com/pion/interceptor/pkg/report"
+	"github.com/pion/interceptor/pkg/rfc8888"
+	"github.com/pion/interceptor/pkg/rtcpaggregator"
+	"github.com/pion/interceptor/pkg/twcc"
+)
+
+// BuildFunc constructs an interceptor factory from its options.
+type BuildFunc func(options Options) (interceptor.Factory, error)
+
+// Builder constructs registries from a Config. It maps the type names used in
+// the config to the functions building the corresponding interceptors.
+type Builder struct {
+	types map[string]BuildFunc
+}
+
+// NewBuilder returns a Builder which knows the interceptors of this module
+// that can be configured without code: nack_generator, nack_responder,
+// report_sender, report_receiver, twcc_sender, twcc_header_extension,
+// rfc8888_sender, interval_pli and rtcp_aggregator.
+func NewBuilder() *Builder {
+	b := &Builder{
+		types: map[string]BuildFunc{},
+	}
+	b.Register("nack_generator", buildNackGenerator)
+	b.Register("nack_responder", buildNackResponder)
+	b.Register("report_sender", buildReportSender)
+	b.Register("report_receiver", buildReportReceiver)
+	b.Register("twcc_sender", buildTWCCSender)
+	b.Register("twcc_header_extension", buildTWCCHeaderExtension)
+	b.Register("rfc8888_sender", buildRFC8888Sender)
+	b.Register("interval_pli", buildIntervalPLI)
+	b.Register("rtcp_aggregator", buildRTCPAggregator)
+
+	return b
+}
+
+// Register adds or replaces the BuildFunc for an interceptor type.
+func (b *Builder) Register(name string, fn BuildFunc) {
+	b.types[name] = fn
+}
+
+// Types returns the sorted names of all registered interceptor types.
+func (b *Builder) Types() []string {
+	names := make([]string, 0, len(b.types))
+	for name := range b.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Build validates the config and constructs a Registry containing all
+// configured interceptors in order.
+func (b *Builder) Build(cfg *Config) (*interceptor.Registry, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	registry := &interceptor.Registry{}
+	for _, entry := range cfg.Interceptors {
+		factories, err := b.buildEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Type, err)
+		}
+		for _, f := range factories {
+			registry.Add(f)
+		}
+	}
+
+	return registry, nil
+}
+
+func (b *Builder) buildEntry(entry InterceptorConfig) ([]interceptor.Factory, error) {
+	fn, ok := b.types[entry.Type]
+	if !ok {
+		return nil, errUnknownType
+	}
+
+	kinds := entry.Kinds
+	if len(kinds) == 0 {
+		kinds = []Kind{KindAudio, KindVideo}
+	}
+
+	var factories []interceptor.Factory
+	baseKinds := []Kind{}
+	for _, kind := range kinds {
+		override, ok := entry.Overrides[kind]
+		if !ok {
+			baseKinds = append(baseKinds, kind)
+
+			continue
+		}
+
+		f, err := fn(entry.Options.merge(override))
+		if err != nil {
+			return nil, err
+		}
+		factories = append(factories, &kindFactory{factory: f, kinds: []Kind{kind}})
+	}
+
+	if len(baseKinds) == 0 {
+		return factories, nil
+	}
+
+	f, err := fn(entry.Options)
+	if err != nil {
+		return nil, err
+	}
+	if len(entry.Kinds) == 0 && len(factories) == 0 {
+		return []interceptor.Factory{f}, nil
+	}
+
+	return append([]interceptor.Factory{&kindFactory{factory: f, kinds: baseKinds}}, factories...), nil
+}
+
+func buildNackGenerator(options Options) (interceptor.Factory, error) {
+	var o struct {
+		Size              *uint16   `json:"size"`
+		SkipLastN         *uint16   `json:"skip_last_n"`
+		MaxNacksPerPacket *uint16   `json:"max_nacks_per_packet"`
+		Interval          *Duration `json:"interval"`
+	}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	opts := []nack.GeneratorOption{}
+	if o.Size != nil {
+		opts = append(opts, nack.GeneratorSize(*o.Size))
+	}
+	if o.SkipLastN != nil {
+		opts = append(opts, nack.GeneratorSkipLastN(*o.SkipLastN))
+	}
+	if o.MaxNacksPerPacket != nil {
+		opts = append(opts, nack.GeneratorMaxNacksPerPacket(*o.MaxNacksPerPacket))
+	}
+	if o.Interval != nil {
+		opts = append(opts, nack.GeneratorInterval(time.Duration(*o.Interval)))
+	}
+
+	return nack.NewGeneratorInterceptor(opts...)
+}
+
+func buildNackResponder(options Options) (interceptor.Factory, error) {
+	var o struct {
+		Size        *uint16 `json:"size"`
+		DisableCopy bool    `json:"disable_copy"`
+	}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	opts := []nack.ResponderOption{}
+	if o.Size != nil {
+		opts = append(opts, nack.ResponderSize(*o.Size))
+	}
+	if o.DisableCopy {
+		opts = append(opts, nack.DisableCopy())
+	}
+
+	return nack.NewResponderInterceptor(opts...)
+}
+
+func buildReportSender(options Options) (interceptor.Factory, error) {
+	var o struct {
+		Interval        *Duration `json:"interval"`
+		UseLatestPacket bool      `json:"use_latest_packet"`
+	}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	opts := []report.SenderOption{}
+	if o.Interval != nil {
+		opts = append(opts, report.SenderInterval(time.Duration(*o.Interval)))
+	}
+	if o.UseLatestPacket {
+		opts = append(opts, report.SenderUseLatestPacket())
+	}
+
+	return report.NewSenderInterceptor(opts...)
+}
+
+func buildReportReceiver(options Options) (interceptor.Factory, error) {
+	var o struct {
+		Interval      *Duration `json:"interval"`
+		ReferenceTime bool      `json:"reference_time"`
+	}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	opts := []report.ReceiverOption{}
+	if o.Interval != nil {
+		opts = append(opts, report.ReceiverInterval(time.Duration(*o.Interval)))
+	}
+	if o.ReferenceTime {
+		opts = append(opts, report.ReceiverReferenceTime())
+	}
+
+	return report.NewReceiverInterceptor(opts...)
+}
+
+func buildTWCCSender(options Options) (interceptor.Factory, error) {
+	var o struct {
+		Interval *Duration `json:"interval"`
+	}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	opts := []twcc.Option{}
+	if o.Interval != nil {
+		opts = append(opts, twcc.SendInterval(time.Duration(*o.Interval)))
+	}
+
+	return twcc.NewSenderInterceptor(opts...)
+}
+
+func buildTWCCHeaderExtension(options Options) (interceptor.Factory, error) {
+	var o struct{}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	return twcc.NewHeaderExtensionInterceptor()
+}
+
+func buildRFC8888Sender(options Options) (interceptor.Factory, error) {
+	var o struct {
+		Interval *Duration `json:"interval"`
+	}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	opts := []rfc8888.Option{}
+	if o.Interval != nil {
+		opts = append(opts, rfc8888.SendInterval(time.Duration(*o.Interval)))
+	}
+
+	return rfc8888.NewSenderInterceptor(opts...)
+}
+
+func buildIntervalPLI(options Options) (interceptor.Factory, error) {
+	var o struct {
+		Interval *Duration `json:"interval"`
+	}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	opts := []intervalpli.GeneratorOption{}
+	if o.Interval != nil {
+		opts = append(opts, intervalpli.GeneratorInterval(time.Duration(*o.Interval)))
+	}
+
+	return intervalpli.NewReceiverInterceptor(opts...)
+}
+
+func buildRTCPAggregator(options Options) (interceptor.Factory, error) {
+	var o struct {
+		Window  *Duration `json:"window"`
+		MaxSize *int      `json:"max_size"`
+	}
+	if err := options.Decode(&o); err != nil {
+		return nil, err
+	}
+
+	opts := []rtcpaggregator.Option{}
+	if o.Window != nil {
+		opts = append(opts, rtcpaggregator.Window(time.Duration(*o.Window)))
+	}
+	if o.MaxSize != nil {
+		opts = append(opts, rtcpaggregator.MaxSize(*o.MaxSize))
+	}
+
+	return rtcpaggregator.NewInterceptor(opts...)
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package config builds an interceptor.Registry from a declarative description.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errMissingType   = errors.New("interceptor entry without type")
+	errUnknownType   = errors.New("unknown interceptor type")
+	errUnknownKind   = errors.New("unknown stream kind")
+	errInvalidOption = errors.New("invalid interceptor options")
+)
+
+// Kind is the media kind of a stream, derived from the prefix of its MimeType.
+type Kind string
+
+// Supported stream kinds.
+const (
+	KindAudio Kind = "audio"
+	KindVideo Kind = "video"
+)
+
+func (k Kind) valid() bool {
+	return k == KindAudio || k == KindVideo
+}
+
+// Config is the declarative description of an interceptor chain. The
+// interceptors are added to the registry in the order they are listed.
+type Config struct {
+	Interceptors []InterceptorConfig `json:"interceptors"`
+}
+
+// InterceptorConfig describes a single interceptor of the chain.
+type InterceptorConfig struct {
+	// Type is the name the interceptor was registered with on the Builder.
+	Type string `json:"type"`
+
+	// Options are passed to the BuildFunc of the interceptor type.
+	Options Options `json:"options,omitempty"`
+
+	// Kinds restricts the interceptor to streams of the given kinds. If empty,
+	// the interceptor is bound to all streams.
+	Kinds []Kind `json:"kinds,omitempty"`
+
+	// Overrides holds options which replace the base options for streams of a
+	// specific kind. A separate interceptor is built for every override.
+	Overrides map[Kind]Options `json:"overrides,omitempty"`
+}
+
+// Options are the settings of a single interceptor, as decoded from JSON.
+type Options map[string]interface{}
+
+// Decode stores the options in the struct pointed to by v. Unknown options
+// are rejected.
+func (o Options) Decode(v interface{}) error {
+	if len(o) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidOption, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidOption, err)
+	}
+
+	return nil
+}
+
+// merge returns a copy of o with the values of override applied on top.
+func (o Options) merge(override Options) Options {
+	merged := make(Options, len(o)+len(override))
+	for k, v := range o {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// Duration is a time.Duration which is encoded as a string like "100ms".
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// ParseJSON parses a Config from its JSON encoding.
+func ParseJSON(data []byte) (*Config, error) {
+	cfg := &Config{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the config for entries without type and unknown kinds.
+// Option values are only checked when the registry is built.
+func (c *Config) Validate() error {
+	for i, entry := range c.Interceptors {
+		if entry.Type == "" {
+			return fmt.Errorf("%w: entry %d", errMissingType, i)
+		}
+		for _, kind := range entry.Kinds {
+			if !kind.valid() {
+				return fmt.Errorf("%w: %q", errUnknownKind, kind)
+			}
+		}
+		for kind := range entry.Overrides {
+			if !kind.valid() {
+				return fmt.Errorf("%w: %q", errUnknownKind, kind)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingInterceptor struct {
+	interceptor.NoOp
+	label string
+	bound *[]string
+}
+
+func (r *recordingInterceptor) BindLocalStream(
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	*r.bound = append(*r.bound, r.label+":"+info.MimeType)
+
+	return writer
+}
+
+type recordingFactory struct {
+	label string
+	bound *[]string
+}
+
+func (f *recordingFactory) NewInterceptor(string) (interceptor.Interceptor, error) {
+	return &recordingInterceptor{label: f.label, bound: f.bound}, nil
+}
+
+func TestParseJSON(t *testing.T) {
+	cfg, err := ParseJSON([]byte(`{
+		"interceptors": [
+			{"type": "nack_generator", "options": {"size": 512, "interval": "50ms"}},
+			{"type": "nack_responder", "kinds": ["video"]},
+			{"type": "report_receiver", "overrides": {"audio": {"interval": "5s"}}}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Len(t, cfg.Interceptors, 3)
+	assert.Equal(t, "nack_generator", cfg.Interceptors[0].Type)
+	assert.Equal(t, []Kind{KindVideo}, cfg.Interceptors[1].Kinds)
+	assert.Equal(t, Options{"interval": "5s"}, cfg.Interceptors[2].Overrides[KindAudio])
+
+	registry, err := NewBuilder().Build(cfg)
+	require.NoError(t, err)
+	i, err := registry.Build("")
+	require.NoError(t, err)
+	assert.NoError(t, i.Close())
+
+	_, err = ParseJSON([]byte(`{"interceptor": []}`))
+	assert.Error(t, err)
+}
+
+func TestOptions_Decode(t *testing.T) {
+	var o struct {
+		Size     uint16   `json:"size"`
+		Interval Duration `json:"interval"`
+	}
+	require.NoError(t, Options{"size": 64, "interval": "1.5s"}.Decode(&o))
+	assert.Equal(t, uint16(64), o.Size)
+	assert.Equal(t, Duration(1500*time.Millisecond), o.Interval)
+
+	assert.ErrorIs(t, Options{"unknown": true}.Decode(&o), errInvalidOption)
+	assert.ErrorIs(t, Options{"interval": "soon"}.Decode(&o), errInvalidOption)
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	builder := NewBuilder()
+
+	for name, cfg := range map[string]struct {
+		cfg *Config
+		err error
+	}{
+		"missing type": {
+			cfg: &Config{Interceptors: []InterceptorConfig{{}}},
+			err: errMissingType,
+		},
+		"unknown type": {
+			cfg: &Config{Interceptors: []InterceptorConfig{{Type: "foo"}}},
+			err: errUnknownType,
+		},
+		"unknown kind": {
+			cfg: &Config{Interceptors: []InterceptorConfig{{Type: "nack_generator", Kinds: []Kind{"data"}}}},
+			err: errUnknownKind,
+		},
+		"unknown override kind": {
+			cfg: &Config{Interceptors: []InterceptorConfig{{
+				Type:      "nack_generator",
+				Overrides: map[Kind]Options{"data": {}},
+			}}},
+			err: errUnknownKind,
+		},
+		"invalid option": {
+			cfg: &Config{Interceptors: []InterceptorConfig{{
+				Type:    "nack_generator",
+				Options: Options{"size": "large"},
+			}}},
+			err: errInvalidOption,
+		},
+		"invalid override option": {
+			cfg: &Config{Interceptors: []InterceptorConfig{{
+				Type:      "twcc_sender",
+				Overrides: map[Kind]Options{KindVideo: {"window": "1s"}},
+			}}},
+			err: errInvalidOption,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := builder.Build(cfg.cfg)
+			assert.ErrorIs(t, err, cfg.err)
+		})
+	}
+}
+
+func TestBuilder_Kinds(t *testing.T) {
+	var bound []string
+	builder := NewBuilder()
+	builder.Register("recorder", func(options Options) (interceptor.Factory, error) {
+		var o struct {
+			Label string `json:"label"`
+		}
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+
+		return &recordingFactory{label: o.Label, bound: &bound}, nil
+	})
+	assert.Contains(t, builder.Types(), "recorder")
+
+	registry, err := builder.Build(&Config{Interceptors: []InterceptorConfig{
+		{Type: "recorder", Options: Options{"label": "all"}},
+		{Type: "recorder", Options: Options{"label": "video"}, Kinds: []Kind{KindVideo}},
+		{
+			Type:      "recorder",
+			Options:   Options{"label": "base"},
+			Overrides: map[Kind]Options{KindAudio: {"label": "audio"}},
+		},
+	}})
+	require.NoError(t, err)
+
+	i, err := registry.Build("")
+	require.NoError(t, err)
+
+	i.BindLocalStream(&interceptor.StreamInfo{MimeType: "audio/opus"}, nil)
+	i.BindLocalStream(&interceptor.StreamInfo{MimeType: "video/VP8"}, nil)
+	assert.ElementsMatch(t, []string{
+		"all:audio/opus", "audio:audio/opus",
+		"all:video/VP8", "video:video/VP8", "base:video/VP8",
+	}, bound)
+}
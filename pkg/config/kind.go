@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"strings"
+
+	"github.com/pion/interceptor"
+)
+
+// kindFactory wraps a factory so that its interceptors are only bound to
+// streams of the given kinds. RTCP is passed to the interceptor unchanged.
+type kindFactory struct {
+	factory interceptor.Factory
+	kinds   []Kind
+}
+
+func (f *kindFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	i, err := f.factory.NewInterceptor(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kindInterceptor{Interceptor: i, kinds: f.kinds}, nil
+}
+
+type kindInterceptor struct {
+	interceptor.Interceptor
+	kinds []Kind
+}
+
+func (k *kindInterceptor) matches(info *interceptor.StreamInfo) bool {
+	kind, _, _ := strings.Cut(strings.ToLower(info.MimeType), "/")
+	for _, want := range k.kinds {
+		if Kind(kind) == want {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *kindInterceptor) BindLocalStream(
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	if !k.matches(info) {
+		return writer
+	}
+
+	return k.Interceptor.BindLocalStream(info, writer)
+}
+
+func (k *kindInterceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	if k.matches(info) {
+		k.Interceptor.UnbindLocalStream(info)
+	}
+}
+
+func (k *kindInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	if !k.matches(info) {
+		return reader
+	}
+
+	return k.Interceptor.BindRemoteStream(info, reader)
+}
+
+func (k *kindInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	if k.matches(info) {
+		k.Interceptor.UnbindRemoteStream(info)
+	}
+}
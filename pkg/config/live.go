@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// liveInterceptor is an interceptor created by a Watcher. It forwards to a
+// chain built from the active config, which is replaced by a chain built from
+// the new config on every change.
+type liveInterceptor struct {
+	interceptor.NoOp
+	id      string
+	watcher *Watcher
+
+	// m serializes binding, unbinding, rebuilding and closing.
+	m             sync.Mutex
+	closed        bool
+	bus           *interceptor.EventBus
+	rtcpReaders   []interceptor.RTCPReader
+	rtcpWriters   []interceptor.RTCPWriter
+	localStreams  map[*interceptor.StreamInfo]interceptor.RTPWriter
+	remoteStreams map[*interceptor.StreamInfo]interceptor.RTPReader
+
+	current atomic.Pointer[generation]
+}
+
+// generation is a chain built from a single config, bound to all streams of
+// a liveInterceptor. It is never modified once it is active, binding a
+// stream activates a copy.
+type generation struct {
+	chain         interceptor.Interceptor
+	rtcpReaders   []interceptor.RTCPReader
+	rtcpWriters   []interceptor.RTCPWriter
+	localStreams  map[*interceptor.StreamInfo]interceptor.RTPWriter
+	remoteStreams map[*interceptor.StreamInfo]interceptor.RTPReader
+}
+
+func newLiveInterceptor(id string, watcher *Watcher, chain interceptor.Interceptor) *liveInterceptor {
+	live := &liveInterceptor{
+		id:            id,
+		watcher:       watcher,
+		localStreams:  map[*interceptor.StreamInfo]interceptor.RTPWriter{},
+		remoteStreams: map[*interceptor.StreamInfo]interceptor.RTPReader{},
+	}
+	live.current.Store(&generation{
+		chain:         chain,
+		localStreams:  map[*interceptor.StreamInfo]interceptor.RTPWriter{},
+		remoteStreams: map[*interceptor.StreamInfo]interceptor.RTPReader{},
+	})
+
+	return live
+}
+
+func (g *generation) clone() *generation {
+	clone := &generation{
+		chain:         g.chain,
+		rtcpReaders:   append([]interceptor.RTCPReader{}, g.rtcpReaders...),
+		rtcpWriters:   append([]interceptor.RTCPWriter{}, g.rtcpWriters...),
+		localStreams:  make(map[*interceptor.StreamInfo]interceptor.RTPWriter, len(g.localStreams)),
+		remoteStreams: make(map[*interceptor.StreamInfo]interceptor.RTPReader, len(g.remoteStreams)),
+	}
+	for info, writer := range g.localStreams {
+		clone.localStreams[info] = writer
+	}
+	for info, reader := range g.remoteStreams {
+		clone.remoteStreams[info] = reader
+	}
+
+	return clone
+}
+
+// rebuild binds everything that is bound to l to chain. The returned
+// generation is activated by swap. It must be called with l.m held.
+func (l *liveInterceptor) rebuild(chain interceptor.Interceptor) *generation {
+	if publisher, ok := chain.(interceptor.EventPublisher); ok && l.bus != nil {
+		publisher.SetEventBus(l.bus)
+	}
+
+	next := &generation{
+		chain:         chain,
+		localStreams:  make(map[*interceptor.StreamInfo]interceptor.RTPWriter, len(l.localStreams)),
+		remoteStreams: make(map[*interceptor.StreamInfo]interceptor.RTPReader, len(l.remoteStreams)),
+	}
+	for _, reader := range l.rtcpReaders {
+		next.rtcpReaders = append(next.rtcpReaders, chain.BindRTCPReader(reader))
+	}
+	for _, writer := range l.rtcpWriters {
+		next.rtcpWriters = append(next.rtcpWriters, chain.BindRTCPWriter(writer))
+	}
+	for info, writer := range l.localStreams {
+		next.localStreams[info] = chain.BindLocalStream(info, writer)
+	}
+	for info, reader := range l.remoteStreams {
+		next.remoteStreams[info] = chain.BindRemoteStream(info, reader)
+	}
+
+	return next
+}
+
+// swap activates next and returns the chain it replaced. It must be called
+// with l.m held.
+func (l *liveInterceptor) swap(next *generation) interceptor.Interceptor {
+	return l.current.Swap(next).chain
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (l *liveInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	index := len(l.rtcpReaders)
+	l.rtcpReaders = append(l.rtcpReaders, reader)
+	next := l.current.Load().clone()
+	next.rtcpReaders = append(next.rtcpReaders, next.chain.BindRTCPReader(reader))
+	l.current.Store(next)
+
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		return l.current.Load().rtcpReaders[index].Read(b, a)
+	})
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (l *liveInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	index := len(l.rtcpWriters)
+	l.rtcpWriters = append(l.rtcpWriters, writer)
+	next := l.current.Load().clone()
+	next.rtcpWriters = append(next.rtcpWriters, next.chain.BindRTCPWriter(writer))
+	l.current.Store(next)
+
+	return interceptor.RTCPWriterFunc(func(pkts []rtcp.Packet, attributes interceptor.Attributes) (int, error) {
+		return l.current.Load().rtcpWriters[index].Write(pkts, attributes)
+	})
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream.
+// The returned method will be called once per rtp packet.
+func (l *liveInterceptor) BindLocalStream(
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.localStreams[info] = writer
+	next := l.current.Load().clone()
+	next.localStreams[info] = next.chain.BindLocalStream(info, writer)
+	l.current.Store(next)
+
+	return interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			bound, ok := l.current.Load().localStreams[info]
+			if !ok {
+				// The stream was unbound
+				bound = writer
+			}
+
+			return bound.Write(header, payload, attributes)
+		},
+	)
+}
+
+// UnbindLocalStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (l *liveInterceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	delete(l.localStreams, info)
+	next := l.current.Load().clone()
+	delete(next.localStreams, info)
+	l.current.Store(next)
+	next.chain.UnbindLocalStream(info)
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+// The returned method will be called once per rtp packet.
+func (l *liveInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.remoteStreams[info] = reader
+	next := l.current.Load().clone()
+	next.remoteStreams[info] = next.chain.BindRemoteStream(info, reader)
+	l.current.Store(next)
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		bound, ok := l.current.Load().remoteStreams[info]
+		if !ok {
+			// The stream was unbound
+			bound = reader
+		}
+
+		return bound.Read(b, a)
+	})
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (l *liveInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	delete(l.remoteStreams, info)
+	next := l.current.Load().clone()
+	delete(next.remoteStreams, info)
+	l.current.Store(next)
+	next.chain.UnbindRemoteStream(info)
+}
+
+// PreBindLocalStream pre-binds a LocalStream on the active chain. The
+// pre-bound state is lost if the config changes before the stream is bound.
+func (l *liveInterceptor) PreBindLocalStream(info *interceptor.StreamInfo) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if binder, ok := l.current.Load().chain.(interceptor.PreBinder); ok {
+		binder.PreBindLocalStream(info)
+	}
+}
+
+// PreBindRemoteStream pre-binds a RemoteStream on the active chain. The
+// pre-bound state is lost if the config changes before the stream is bound.
+func (l *liveInterceptor) PreBindRemoteStream(info *interceptor.StreamInfo) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if binder, ok := l.current.Load().chain.(interceptor.PreBinder); ok {
+		binder.PreBindRemoteStream(info)
+	}
+}
+
+// SetEventBus passes bus to the active chain and all chains built from later
+// configs.
+func (l *liveInterceptor) SetEventBus(bus *interceptor.EventBus) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.bus = bus
+	if publisher, ok := l.current.Load().chain.(interceptor.EventPublisher); ok {
+		publisher.SetEventBus(bus)
+	}
+}
+
+// Close closes the active chain. The interceptor is not rebuilt anymore.
+func (l *liveInterceptor) Close() error {
+	l.watcher.removeLive(l)
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	return l.current.Load().chain.Close()
+}
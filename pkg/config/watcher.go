@@ -0,0 +1,299 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+)
+
+// Source returns the current encoded config. It is polled by the Watcher.
+type Source func() ([]byte, error)
+
+// FileSource returns a Source which reads the config from a file.
+func FileSource(path string) Source {
+	return func() ([]byte, error) {
+		return os.ReadFile(path) //nolint:gosec // G304
+	}
+}
+
+// ChangeHook is called by the Watcher when a new config was loaded and
+// successfully built, before it is applied, e.g. to update state outside of
+// the interceptors. If a hook returns an error, the change is rolled back.
+// Hooks must not call into the Watcher.
+type ChangeHook func(cfg *Config, registry *interceptor.Registry) error
+
+// WatcherOption can be used to configure a Watcher.
+type WatcherOption func(w *Watcher) error
+
+// WatcherInterval sets how often the Source is polled for changes. An
+// interval of zero disables polling, changes are then only applied by Reload.
+func WatcherInterval(interval time.Duration) WatcherOption {
+	return func(w *Watcher) error {
+		w.interval = interval
+
+		return nil
+	}
+}
+
+// WatcherLog sets a logger for the watcher.
+func WatcherLog(log logging.LeveledLogger) WatcherOption {
+	return func(w *Watcher) error {
+		w.log = log
+
+		return nil
+	}
+}
+
+// WatcherParser sets the function decoding the data returned by the Source,
+// ParseJSON by default.
+func WatcherParser(parse func([]byte) (*Config, error)) WatcherOption {
+	return func(w *Watcher) error {
+		w.parse = parse
+
+		return nil
+	}
+}
+
+// Watcher keeps an interceptor configuration up to date with a Source. It is
+// an interceptor.Factory: every interceptor it creates runs a chain built from
+// the active config. When the config changes, the chains of all interceptors
+// which are not closed yet are rebuilt from the new config and bound to the
+// same streams, then each interceptor switches to its new chain with a single
+// swap, so a chain never mixes options of two config versions. The state of
+// the replaced chains, like buffered packets and statistics, is not carried
+// over.
+//
+// A config which fails to parse, validate or build, or which is rejected by
+// a hook, is not applied and the previous config stays active.
+type Watcher struct {
+	builder  *Builder
+	source   Source
+	parse    func([]byte) (*Config, error)
+	interval time.Duration
+	log      logging.LeveledLogger
+
+	m        sync.Mutex
+	raw      []byte
+	config   *Config
+	registry *interceptor.Registry
+	hooks    []ChangeHook
+	live     map[*liveInterceptor]struct{}
+
+	wg    sync.WaitGroup
+	close chan struct{}
+}
+
+// NewWatcher loads the initial config from source and starts watching it for
+// changes. It fails if the initial config can't be applied.
+func NewWatcher(builder *Builder, source Source, opts ...WatcherOption) (*Watcher, error) {
+	watcher := &Watcher{
+		builder:  builder,
+		source:   source,
+		parse:    ParseJSON,
+		interval: time.Second,
+		log:      logging.NewDefaultLoggerFactory().NewLogger("config_watcher"),
+		raw:      nil,
+		config:   nil,
+		registry: nil,
+		hooks:    nil,
+		live:     map[*liveInterceptor]struct{}{},
+		close:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		if err := opt(watcher); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := watcher.Reload(); err != nil {
+		return nil, err
+	}
+
+	if watcher.interval > 0 {
+		watcher.wg.Add(1)
+		go watcher.loop()
+	}
+
+	return watcher, nil
+}
+
+// OnChange adds a hook which is called for every config change.
+func (w *Watcher) OnChange(hook ChangeHook) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.hooks = append(w.hooks, hook)
+}
+
+// Config returns the active config.
+func (w *Watcher) Config() *Config {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	return w.config
+}
+
+// NewInterceptor builds an interceptor from the active config, which is
+// rebuilt from every new config until it is closed.
+func (w *Watcher) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	chain, err := w.registry.Build(id)
+	if err != nil {
+		return nil, err
+	}
+	live := newLiveInterceptor(id, w, chain)
+	w.live[live] = struct{}{}
+
+	return live, nil
+}
+
+func (w *Watcher) removeLive(live *liveInterceptor) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	delete(w.live, live)
+}
+
+// Reload reads the Source and applies the config if it changed. It reports
+// whether a new config was applied.
+func (w *Watcher) Reload() (bool, error) {
+	raw, err := w.source()
+	if err != nil {
+		return false, err
+	}
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.config != nil && bytes.Equal(raw, w.raw) {
+		return false, nil
+	}
+
+	cfg, err := w.parse(raw)
+	if err != nil {
+		return false, err
+	}
+	registry, err := w.builder.Build(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	// The interceptors can't be bound or closed until the change is applied
+	for live := range w.live {
+		live.m.Lock()
+		defer live.m.Unlock()
+	}
+	next, err := w.rebuildLive(registry)
+	if err != nil {
+		return false, err
+	}
+
+	for i, hook := range w.hooks {
+		if err := hook(cfg, registry); err != nil {
+			w.rollback(w.hooks[:i])
+			closeGenerations(next)
+
+			return false, fmt.Errorf("config change rejected: %w", err)
+		}
+	}
+
+	w.raw = raw
+	w.config = cfg
+	w.registry = registry
+	for live, gen := range next {
+		if err := live.swap(gen).Close(); err != nil {
+			w.log.Warnf("failed to close interceptor replaced by config change: %v", err)
+		}
+	}
+
+	return true, nil
+}
+
+// rebuildLive builds the chains of all live interceptors from registry. It
+// must be called with w.m and the locks of all live interceptors held.
+func (w *Watcher) rebuildLive(registry *interceptor.Registry) (map[*liveInterceptor]*generation, error) {
+	next := make(map[*liveInterceptor]*generation, len(w.live))
+	for live := range w.live {
+		chain, err := registry.Build(live.id)
+		if err != nil {
+			closeGenerations(next)
+
+			return nil, err
+		}
+		next[live] = live.rebuild(chain)
+	}
+
+	return next, nil
+}
+
+// closeGenerations closes the chains of generations which were not applied.
+func closeGenerations(generations map[*liveInterceptor]*generation) {
+	for _, gen := range generations {
+		_ = gen.chain.Close()
+	}
+}
+
+// rollback re-applies the active config to the hooks which already accepted
+// a change that was rejected by a later hook.
+func (w *Watcher) rollback(hooks []ChangeHook) {
+	if w.config == nil {
+		return
+	}
+
+	for _, hook := range hooks {
+		if err := hook(w.config, w.registry); err != nil {
+			w.log.Errorf("failed to roll back config change: %v", err)
+		}
+	}
+}
+
+// Close stops watching the Source.
+func (w *Watcher) Close() error {
+	defer w.wg.Wait()
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if !w.isClosed() {
+		close(w.close)
+	}
+
+	return nil
+}
+
+func (w *Watcher) isClosed() bool {
+	select {
+	case <-w.close:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *Watcher) loop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := w.Reload(); err != nil {
+				w.log.Warnf("failed to apply config: %v", err)
+			}
+		case <-w.close:
+			return
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		data = []byte(`{"interceptors": [{"type": "nack_generator", "options": {"interval": "100ms"}}]}`)
+	)
+	source := func() ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return data, nil
+	}
+	setData := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		data = []byte(s)
+	}
+
+	watcher, err := NewWatcher(NewBuilder(), source, WatcherInterval(0))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, watcher.Close())
+	}()
+
+	assert.Equal(t, Options{"interval": "100ms"}, watcher.Config().Interceptors[0].Options)
+
+	changed, err := watcher.Reload()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	var applied []*Config
+	watcher.OnChange(func(cfg *Config, _ *interceptor.Registry) error {
+		applied = append(applied, cfg)
+
+		return nil
+	})
+
+	t.Run("invalid config is not applied", func(t *testing.T) {
+		previous := watcher.Config()
+		for _, s := range []string{
+			`{"interceptors": [`,
+			`{"interceptors": [{"type": "unknown"}]}`,
+			`{"interceptors": [{"type": "nack_generator", "options": {"interval": 5}}]}`,
+		} {
+			setData(s)
+			changed, err := watcher.Reload()
+			assert.Error(t, err)
+			assert.False(t, changed)
+			assert.Same(t, previous, watcher.Config())
+		}
+		assert.Empty(t, applied)
+	})
+
+	t.Run("valid config is applied", func(t *testing.T) {
+		setData(`{"interceptors": [{"type": "nack_generator", "options": {"interval": "50ms"}}]}`)
+		changed, err := watcher.Reload()
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, Options{"interval": "50ms"}, watcher.Config().Interceptors[0].Options)
+		require.Len(t, applied, 1)
+		assert.Same(t, watcher.Config(), applied[0])
+
+		i, err := watcher.NewInterceptor("")
+		require.NoError(t, err)
+		assert.NoError(t, i.Close())
+	})
+
+	t.Run("rejected change is rolled back", func(t *testing.T) {
+		errReject := errors.New("reject")
+		watcher.OnChange(func(*Config, *interceptor.Registry) error {
+			return errReject
+		})
+
+		previous := watcher.Config()
+		applied = nil
+		setData(`{"interceptors": [{"type": "nack_generator", "options": {"interval": "10ms"}}]}`)
+		changed, err := watcher.Reload()
+		assert.ErrorIs(t, err, errReject)
+		assert.False(t, changed)
+		assert.Same(t, previous, watcher.Config())
+
+		require.Len(t, applied, 2)
+		assert.Equal(t, Options{"interval": "10ms"}, applied[0].Interceptors[0].Options)
+		assert.Same(t, previous, applied[1])
+	})
+}
+
+func TestWatcher_FileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interceptors.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"interceptors": []}`), 0o600))
+
+	_, err := NewWatcher(NewBuilder(), FileSource(filepath.Join(t.TempDir(), "missing.json")))
+	assert.Error(t, err)
+
+	watcher, err := NewWatcher(NewBuilder(), FileSource(path), WatcherInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, watcher.Close())
+	}()
+	assert.Empty(t, watcher.Config().Interceptors)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"interceptors": [{"type": "twcc_sender"}]}`), 0o600))
+	assert.Eventually(t, func() bool {
+		return len(watcher.Config().Interceptors) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+// payloadTypeInterceptor sets the payload type of sent packets to its option.
+type payloadTypeInterceptor struct {
+	interceptor.NoOp
+	payloadType uint8
+	closed      *int
+}
+
+func (p *payloadTypeInterceptor) BindLocalStream(
+	_ *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	return interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			header.PayloadType = p.payloadType
+
+			return writer.Write(header, payload, attributes)
+		},
+	)
+}
+
+func (p *payloadTypeInterceptor) Close() error {
+	*p.closed++
+
+	return nil
+}
+
+type payloadTypeFactory struct {
+	payloadType uint8
+	closed      *int
+}
+
+func (f *payloadTypeFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	if f.payloadType == 0 {
+		return nil, errInvalidOption
+	}
+
+	return &payloadTypeInterceptor{payloadType: f.payloadType, closed: f.closed}, nil
+}
+
+func TestWatcher_LiveInterceptors(t *testing.T) {
+	closed := 0
+	builder := NewBuilder()
+	builder.Register("payload_type", func(options Options) (interceptor.Factory, error) {
+		var o struct {
+			PayloadType uint8 `json:"payload_type"`
+		}
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+
+		return &payloadTypeFactory{payloadType: o.PayloadType, closed: &closed}, nil
+	})
+
+	data := []byte(`{"interceptors": [{"type": "payload_type", "options": {"payload_type": 96}}]}`)
+	watcher, err := NewWatcher(builder, func() ([]byte, error) {
+		return data, nil
+	}, WatcherInterval(0))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, watcher.Close())
+	}()
+
+	i, err := watcher.NewInterceptor("")
+	require.NoError(t, err)
+
+	var written []uint8
+	writer := i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+			written = append(written, header.PayloadType)
+
+			return 0, nil
+		},
+	))
+	_, err = writer.Write(&rtp.Header{}, nil, nil)
+	require.NoError(t, err)
+
+	// The running chain is rebuilt with the new option and the old one closed
+	data = []byte(`{"interceptors": [{"type": "payload_type", "options": {"payload_type": 97}}]}`)
+	changed, err := watcher.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 1, closed)
+	_, err = writer.Write(&rtp.Header{}, nil, nil)
+	require.NoError(t, err)
+
+	// A config which fails to build an interceptor is not applied
+	data = []byte(`{"interceptors": [{"type": "payload_type", "options": {"payload_type": 0}}]}`)
+	changed, err = watcher.Reload()
+	assert.ErrorIs(t, err, errInvalidOption)
+	assert.False(t, changed)
+	_, err = writer.Write(&rtp.Header{}, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint8{96, 97, 97}, written)
+
+	// Closed interceptors are not rebuilt anymore
+	require.NoError(t, i.Close())
+	assert.Equal(t, 2, closed)
+	data = []byte(`{"interceptors": [{"type": "payload_type", "options": {"payload_type": 98}}]}`)
+	changed, err = watcher.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 2, closed)
+}
@@ -0,0 +1,374 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package feedbackaggregator merges the keyframe requests and NACKs that an
+// SFU receives from its subscribers into a deduplicated and rate limited
+// feedback stream towards the publishers.
+package feedbackaggregator
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+// Aggregator collects the feedback of all subscriber interceptors and sends
+// it to the publisher interceptor receiving the stream. PLI and FIR are both
+// forwarded as PLI.
+type Aggregator struct {
+	log              logging.LeveledLogger
+	interval         time.Duration
+	keyframeInterval time.Duration
+	nackWindow       time.Duration
+	mapSSRC          func(ssrc uint32) (uint32, bool)
+
+	m                sync.Mutex
+	publishers       map[uint32]*PublisherInterceptor
+	pendingKeyframes map[uint32]struct{}
+	lastKeyframe     map[uint32]time.Time
+	pendingNacks     map[uint32]map[uint16]struct{}
+	nacked           map[nackKey]time.Time
+
+	wg    sync.WaitGroup
+	close chan struct{}
+}
+
+type nackKey struct {
+	ssrc uint32
+	seq  uint16
+}
+
+// New returns a new Aggregator, which sends the collected feedback until it's
+// closed.
+func New(opts ...Option) (*Aggregator, error) {
+	aggregator := &Aggregator{
+		log:              logging.NewDefaultLoggerFactory().NewLogger("feedback_aggregator"),
+		interval:         20 * time.Millisecond,
+		keyframeInterval: 500 * time.Millisecond,
+		nackWindow:       100 * time.Millisecond,
+		mapSSRC: func(ssrc uint32) (uint32, bool) {
+			return ssrc, true
+		},
+		publishers:       map[uint32]*PublisherInterceptor{},
+		pendingKeyframes: map[uint32]struct{}{},
+		lastKeyframe:     map[uint32]time.Time{},
+		pendingNacks:     map[uint32]map[uint16]struct{}{},
+		nacked:           map[nackKey]time.Time{},
+		close:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		if err := opt(aggregator); err != nil {
+			return nil, err
+		}
+	}
+
+	aggregator.wg.Add(1)
+	go aggregator.loop()
+
+	return aggregator, nil
+}
+
+// SubscriberFactory returns an interceptor.Factory for the PeerConnections of
+// subscribers. Its interceptors pass the feedback they read to the Aggregator.
+func (a *Aggregator) SubscriberFactory() interceptor.Factory {
+	return &subscriberFactory{aggregator: a}
+}
+
+// PublisherFactory returns an interceptor.Factory for the PeerConnections of
+// publishers. Its interceptors send the aggregated feedback for their remote
+// streams.
+func (a *Aggregator) PublisherFactory() interceptor.Factory {
+	return &publisherFactory{aggregator: a}
+}
+
+// Close stops sending feedback.
+func (a *Aggregator) Close() error {
+	defer a.wg.Wait()
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if !a.isClosed() {
+		close(a.close)
+	}
+
+	return nil
+}
+
+func (a *Aggregator) isClosed() bool {
+	select {
+	case <-a.close:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *Aggregator) loop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			a.flush(now)
+		case <-a.close:
+			return
+		}
+	}
+}
+
+func (a *Aggregator) onFeedback(pkts []rtcp.Packet) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	for _, pkt := range pkts {
+		switch pkt := pkt.(type) {
+		case *rtcp.PictureLossIndication:
+			a.addKeyframeRequest(pkt.MediaSSRC)
+		case *rtcp.FullIntraRequest:
+			for _, entry := range pkt.FIR {
+				a.addKeyframeRequest(entry.SSRC)
+			}
+		case *rtcp.TransportLayerNack:
+			ssrc, ok := a.mapSSRC(pkt.MediaSSRC)
+			if !ok {
+				continue
+			}
+			pending, ok := a.pendingNacks[ssrc]
+			if !ok {
+				pending = map[uint16]struct{}{}
+				a.pendingNacks[ssrc] = pending
+			}
+			for _, pair := range pkt.Nacks {
+				pair.Range(func(seq uint16) bool {
+					pending[seq] = struct{}{}
+
+					return true
+				})
+			}
+		}
+	}
+}
+
+// addKeyframeRequest must be called with a.m held.
+func (a *Aggregator) addKeyframeRequest(ssrc uint32) {
+	if mapped, ok := a.mapSSRC(ssrc); ok {
+		a.pendingKeyframes[mapped] = struct{}{}
+	}
+}
+
+// flush sends the pending feedback which is allowed to be sent at now.
+func (a *Aggregator) flush(now time.Time) {
+	a.m.Lock()
+	batches := map[*PublisherInterceptor][]rtcp.Packet{}
+
+	for ssrc := range a.pendingKeyframes {
+		publisher, ok := a.publishers[ssrc]
+		if !ok {
+			delete(a.pendingKeyframes, ssrc)
+
+			continue
+		}
+		if last, ok := a.lastKeyframe[ssrc]; ok && now.Sub(last) < a.keyframeInterval {
+			continue
+		}
+		a.lastKeyframe[ssrc] = now
+		delete(a.pendingKeyframes, ssrc)
+		batches[publisher] = append(batches[publisher], &rtcp.PictureLossIndication{
+			SenderSSRC: publisher.senderSSRC,
+			MediaSSRC:  ssrc,
+		})
+	}
+
+	for key, last := range a.nacked {
+		if now.Sub(last) >= a.nackWindow {
+			delete(a.nacked, key)
+		}
+	}
+	for ssrc, pending := range a.pendingNacks {
+		delete(a.pendingNacks, ssrc)
+		publisher, ok := a.publishers[ssrc]
+		if !ok {
+			continue
+		}
+
+		seqs := []uint16{}
+		for seq := range pending {
+			key := nackKey{ssrc: ssrc, seq: seq}
+			if _, ok := a.nacked[key]; ok {
+				continue
+			}
+			a.nacked[key] = now
+			seqs = append(seqs, seq)
+		}
+		if len(seqs) == 0 {
+			continue
+		}
+		batches[publisher] = append(batches[publisher], &rtcp.TransportLayerNack{
+			SenderSSRC: publisher.senderSSRC,
+			MediaSSRC:  ssrc,
+			Nacks:      rtcp.NackPairsFromSequenceNumbers(sortSequenceNumbers(seqs)),
+		})
+	}
+	a.m.Unlock()
+
+	for publisher, pkts := range batches {
+		writer := publisher.rtcpWriter()
+		if writer == nil {
+			continue
+		}
+		if _, err := writer.Write(pkts, interceptor.Attributes{}); err != nil {
+			a.log.Warnf("failed sending aggregated feedback: %+v", err)
+		}
+	}
+}
+
+// sortSequenceNumbers sorts seqs in ascending order, starting after the largest
+// gap so that sequence numbers wrapping around stay in sending order.
+func sortSequenceNumbers(seqs []uint16) []uint16 {
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	start, largestGap := 0, seqs[0]-seqs[len(seqs)-1]
+	for i := 1; i < len(seqs); i++ {
+		if gap := seqs[i] - seqs[i-1]; gap > largestGap {
+			start, largestGap = i, gap
+		}
+	}
+
+	return append(seqs[start:], seqs[:start]...)
+}
+
+type subscriberFactory struct {
+	aggregator *Aggregator
+}
+
+func (f *subscriberFactory) NewInterceptor(string) (interceptor.Interceptor, error) {
+	return &SubscriberInterceptor{aggregator: f.aggregator}, nil
+}
+
+// SubscriberInterceptor passes the keyframe requests and NACKs of a subscriber
+// to the Aggregator. The feedback is not removed from the RTCP it reads.
+type SubscriberInterceptor struct {
+	interceptor.NoOp
+	aggregator *Aggregator
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (s *SubscriberInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		pkts, err := attr.GetRTCPPackets(b[:i])
+		if err != nil {
+			return 0, nil, err
+		}
+		s.aggregator.onFeedback(pkts)
+
+		return i, attr, nil
+	})
+}
+
+type publisherFactory struct {
+	aggregator *Aggregator
+}
+
+func (f *publisherFactory) NewInterceptor(string) (interceptor.Interceptor, error) {
+	return &PublisherInterceptor{
+		aggregator: f.aggregator,
+		senderSSRC: rand.Uint32(), // #nosec
+		ssrcs:      map[uint32]struct{}{},
+	}, nil
+}
+
+// PublisherInterceptor sends the aggregated feedback for the streams it
+// receives from a publisher.
+type PublisherInterceptor struct {
+	interceptor.NoOp
+	aggregator *Aggregator
+	senderSSRC uint32
+
+	m      sync.Mutex
+	writer interceptor.RTCPWriter
+	ssrcs  map[uint32]struct{}
+}
+
+func (p *PublisherInterceptor) rtcpWriter() interceptor.RTCPWriter {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.writer
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (p *PublisherInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.writer = writer
+
+	return writer
+}
+
+// BindRemoteStream registers the stream, so feedback for it is sent by this interceptor.
+func (p *PublisherInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	p.m.Lock()
+	p.ssrcs[info.SSRC] = struct{}{}
+	p.m.Unlock()
+
+	p.aggregator.m.Lock()
+	p.aggregator.publishers[info.SSRC] = p
+	p.aggregator.m.Unlock()
+
+	return reader
+}
+
+// UnbindRemoteStream stops sending feedback for the stream.
+func (p *PublisherInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	p.m.Lock()
+	delete(p.ssrcs, info.SSRC)
+	p.m.Unlock()
+
+	p.aggregator.removePublisher(p, info.SSRC)
+}
+
+// Close stops sending feedback for all streams of the interceptor.
+func (p *PublisherInterceptor) Close() error {
+	p.m.Lock()
+	ssrcs := p.ssrcs
+	p.ssrcs = map[uint32]struct{}{}
+	p.m.Unlock()
+
+	for ssrc := range ssrcs {
+		p.aggregator.removePublisher(p, ssrc)
+	}
+
+	return nil
+}
+
+func (a *Aggregator) removePublisher(p *PublisherInterceptor, ssrc uint32) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if a.publishers[ssrc] == p {
+		delete(a.publishers, ssrc)
+		delete(a.lastKeyframe, ssrc)
+	}
+}
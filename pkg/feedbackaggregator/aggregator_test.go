@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package feedbackaggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAggregator(t *testing.T, opts ...Option) (*Aggregator, *test.MockStream, []*test.MockStream) {
+	t.Helper()
+
+	aggregator, err := New(append([]Option{Interval(time.Hour)}, opts...)...)
+	require.NoError(t, err)
+
+	publisher, err := aggregator.PublisherFactory().NewInterceptor("publisher")
+	require.NoError(t, err)
+	publisherStream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1}, publisher)
+
+	subscribers := []*test.MockStream{}
+	for i := 0; i < 2; i++ {
+		subscriber, err := aggregator.SubscriberFactory().NewInterceptor("subscriber")
+		require.NoError(t, err)
+		subscribers = append(subscribers, test.NewMockStream(&interceptor.StreamInfo{SSRC: 1}, subscriber))
+	}
+
+	t.Cleanup(func() {
+		for _, stream := range subscribers {
+			assert.NoError(t, stream.Close())
+		}
+		assert.NoError(t, publisherStream.Close())
+		assert.NoError(t, aggregator.Close())
+	})
+
+	return aggregator, publisherStream, subscribers
+}
+
+func receiveFeedback(t *testing.T, stream *test.MockStream, pkts []rtcp.Packet) {
+	t.Helper()
+
+	stream.ReceiveRTCP(pkts)
+	select {
+	case r := <-stream.ReadRTCP():
+		assert.NoError(t, r.Err)
+		assert.Equal(t, pkts, r.Packets)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "feedback not passed through")
+	}
+}
+
+func TestAggregator_Keyframes(t *testing.T) {
+	aggregator, publisher, subscribers := newTestAggregator(t)
+
+	receiveFeedback(t, subscribers[0], []rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 1}})
+	receiveFeedback(t, subscribers[1], []rtcp.Packet{&rtcp.FullIntraRequest{
+		FIR: []rtcp.FIREntry{{SSRC: 1}},
+	}})
+
+	now := time.Now()
+	aggregator.flush(now)
+
+	select {
+	case pkts := <-publisher.WrittenRTCP():
+		require.Len(t, pkts, 1)
+		pli, ok := pkts[0].(*rtcp.PictureLossIndication)
+		require.True(t, ok)
+		assert.Equal(t, uint32(1), pli.MediaSSRC)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no keyframe request written")
+	}
+
+	// Within the keyframe interval the request is held back
+	receiveFeedback(t, subscribers[0], []rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 1}})
+	aggregator.flush(now.Add(100 * time.Millisecond))
+	select {
+	case pkts := <-publisher.WrittenRTCP():
+		assert.FailNow(t, "unexpected feedback", "%v", pkts)
+	default:
+	}
+
+	aggregator.flush(now.Add(500 * time.Millisecond))
+	select {
+	case pkts := <-publisher.WrittenRTCP():
+		require.Len(t, pkts, 1)
+		_, ok := pkts[0].(*rtcp.PictureLossIndication)
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no keyframe request written")
+	}
+}
+
+func TestAggregator_Nacks(t *testing.T) {
+	aggregator, publisher, subscribers := newTestAggregator(t)
+
+	receiveFeedback(t, subscribers[0], []rtcp.Packet{&rtcp.TransportLayerNack{
+		MediaSSRC: 1,
+		Nacks:     rtcp.NackPairsFromSequenceNumbers([]uint16{10, 11}),
+	}})
+	receiveFeedback(t, subscribers[1], []rtcp.Packet{&rtcp.TransportLayerNack{
+		MediaSSRC: 1,
+		Nacks:     rtcp.NackPairsFromSequenceNumbers([]uint16{11, 12}),
+	}})
+
+	now := time.Now()
+	aggregator.flush(now)
+
+	select {
+	case pkts := <-publisher.WrittenRTCP():
+		require.Len(t, pkts, 1)
+		nack, ok := pkts[0].(*rtcp.TransportLayerNack)
+		require.True(t, ok)
+		assert.Equal(t, uint32(1), nack.MediaSSRC)
+		assert.Equal(t, rtcp.NackPairsFromSequenceNumbers([]uint16{10, 11, 12}), nack.Nacks)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no nack written")
+	}
+
+	// Already NACKed sequence numbers are dropped within the window
+	receiveFeedback(t, subscribers[0], []rtcp.Packet{&rtcp.TransportLayerNack{
+		MediaSSRC: 1,
+		Nacks:     rtcp.NackPairsFromSequenceNumbers([]uint16{12, 13}),
+	}})
+	aggregator.flush(now.Add(50 * time.Millisecond))
+
+	select {
+	case pkts := <-publisher.WrittenRTCP():
+		require.Len(t, pkts, 1)
+		nack, ok := pkts[0].(*rtcp.TransportLayerNack)
+		require.True(t, ok)
+		assert.Equal(t, rtcp.NackPairsFromSequenceNumbers([]uint16{13}), nack.Nacks)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no nack written")
+	}
+}
+
+func TestAggregator_SSRCMapper(t *testing.T) {
+	aggregator, publisher, subscribers := newTestAggregator(t, SSRCMapper(func(ssrc uint32) (uint32, bool) {
+		if ssrc == 100 {
+			return 1, true
+		}
+
+		return 0, false
+	}))
+
+	receiveFeedback(t, subscribers[0], []rtcp.Packet{
+		&rtcp.PictureLossIndication{MediaSSRC: 2},
+		&rtcp.PictureLossIndication{MediaSSRC: 100},
+	})
+	aggregator.flush(time.Now())
+
+	select {
+	case pkts := <-publisher.WrittenRTCP():
+		require.Len(t, pkts, 1)
+		pli, ok := pkts[0].(*rtcp.PictureLossIndication)
+		require.True(t, ok)
+		assert.Equal(t, uint32(1), pli.MediaSSRC)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no keyframe request written")
+	}
+}
+
+func TestAggregator_InvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		_, err := New(Interval(interval))
+		assert.ErrorIs(t, err, errInvalidInterval)
+	}
+}
+
+func TestSortSequenceNumbers(t *testing.T) {
+	assert.Equal(t, []uint16{1, 2, 5}, sortSequenceNumbers([]uint16{5, 1, 2}))
+	assert.Equal(t, []uint16{65534, 65535, 0, 1}, sortSequenceNumbers([]uint16{0, 65535, 1, 65534}))
+	assert.Equal(t, []uint16{7}, sortSequenceNumbers([]uint16{7}))
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package feedbackaggregator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pion/logging"
+)
+
+var errInvalidInterval = errors.New("feedback interval must be positive")
+
+// Option can be used to configure the Aggregator.
+type Option func(a *Aggregator) error
+
+// Log sets a logger for the aggregator.
+func Log(log logging.LeveledLogger) Option {
+	return func(a *Aggregator) error {
+		a.log = log
+
+		return nil
+	}
+}
+
+// Interval sets how often the collected feedback is sent to the publishers.
+func Interval(interval time.Duration) Option {
+	return func(a *Aggregator) error {
+		if interval <= 0 {
+			return errInvalidInterval
+		}
+		a.interval = interval
+
+		return nil
+	}
+}
+
+// KeyframeInterval sets the minimum time between two keyframe requests sent
+// for the same publisher stream. Requests arriving in between are merged and
+// sent when the interval has passed.
+func KeyframeInterval(interval time.Duration) Option {
+	return func(a *Aggregator) error {
+		a.keyframeInterval = interval
+
+		return nil
+	}
+}
+
+// NackWindow sets for how long a sequence number which was NACKed towards a
+// publisher is not NACKed again, no matter how many subscribers request it.
+func NackWindow(window time.Duration) Option {
+	return func(a *Aggregator) error {
+		a.nackWindow = window
+
+		return nil
+	}
+}
+
+// SSRCMapper sets the function mapping the SSRC of a stream sent to a
+// subscriber to the SSRC of the publisher stream it is forwarded from. The
+// feedback of streams for which it returns false is ignored. By default
+// the SSRCs are forwarded unchanged.
+func SSRCMapper(mapper func(ssrc uint32) (uint32, bool)) Option {
+	return func(a *Aggregator) error {
+		a.mapSSRC = mapper
+
+		return nil
+	}
+}
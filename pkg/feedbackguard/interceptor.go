@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package feedbackguard provides an interceptor ignoring received RTCP
+// feedback for streams which aren't sent, so a confused or malicious remote
+// on a multiplexed transport can't trigger retransmissions, keyframes or
+// bitrate changes of other streams.
+package feedbackguard
+
+import (
+	"sync"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+// Rejected holds the number of rejected feedback packets by type.
+type Rejected struct {
+	NACK uint64
+	PLI  uint64
+	FIR  uint64
+	REMB uint64
+	TWCC uint64
+}
+
+// NewPeerConnectionCallback receives a new Interceptor for every PeerConnection.
+type NewPeerConnectionCallback func(id string, i *Interceptor)
+
+// InterceptorFactory is a interceptor.Factory for a feedback guard Interceptor.
+type InterceptorFactory struct {
+	opts              []Option
+	addPeerConnection NewPeerConnectionCallback
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{
+		opts:              opts,
+		addPeerConnection: nil,
+	}, nil
+}
+
+// OnNewPeerConnection sets the callback that is called when a new
+// PeerConnection is created.
+func (f *InterceptorFactory) OnNewPeerConnection(cb NewPeerConnectionCallback) {
+	f.addPeerConnection = cb
+}
+
+// NewInterceptor constructs a new Interceptor.
+func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	i := &Interceptor{
+		NoOp:     interceptor.NoOp{},
+		log:      logging.NewDefaultLoggerFactory().NewLogger("feedbackguard"),
+		ssrcs:    map[uint32]struct{}{},
+		rejected: Rejected{},
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.addPeerConnection != nil {
+		f.addPeerConnection(id, i)
+	}
+
+	return i, nil
+}
+
+// Interceptor removes NACK, PLI, FIR, REMB and TWCC feedback whose media SSRC
+// doesn't belong to a bound local stream from received RTCP. It must be
+// registered before the interceptors acting on feedback, e.g. the nack
+// responder and the cc interceptor. TWCC feedback without a media SSRC is
+// accepted, since it refers to the whole transport.
+type Interceptor struct {
+	interceptor.NoOp
+	log logging.LeveledLogger
+
+	m        sync.Mutex
+	ssrcs    map[uint32]struct{}
+	rejected Rejected
+}
+
+// Rejected returns the number of rejected feedback packets.
+func (i *Interceptor) Rejected() Rejected {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	return i.rejected
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (i *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		for {
+			n, attr, err := reader.Read(b, a)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			if attr == nil {
+				attr = make(interceptor.Attributes)
+			}
+			pkts, err := attr.GetRTCPPackets(b[:n])
+			if err != nil {
+				return 0, nil, err
+			}
+
+			accepted := i.filter(pkts)
+			switch len(accepted) {
+			case len(pkts):
+				return n, attr, nil
+			case 0:
+				// The attributes of the dropped batch must not leak into the next one
+				a = interceptor.Attributes{}
+
+				continue
+			}
+
+			raw, err := rtcp.Marshal(accepted)
+			if err != nil {
+				return 0, nil, err
+			}
+			attr.SetRTCPPackets(accepted)
+
+			return copy(b, raw), attr, nil
+		}
+	})
+}
+
+func (i *Interceptor) filter(pkts []rtcp.Packet) []rtcp.Packet {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	accepted := make([]rtcp.Packet, 0, len(pkts))
+	for _, pkt := range pkts {
+		if i.accept(pkt) {
+			accepted = append(accepted, pkt)
+
+			continue
+		}
+		i.log.Debugf("rejecting feedback for unknown stream: %T %v", pkt, pkt.DestinationSSRC())
+	}
+
+	return accepted
+}
+
+// accept must be called with i.m held.
+func (i *Interceptor) accept(pkt rtcp.Packet) bool {
+	switch pkt := pkt.(type) {
+	case *rtcp.TransportLayerNack:
+		return count(i.bound(pkt.MediaSSRC), &i.rejected.NACK)
+	case *rtcp.PictureLossIndication:
+		return count(i.bound(pkt.MediaSSRC), &i.rejected.PLI)
+	case *rtcp.FullIntraRequest:
+		ssrcs := make([]uint32, 0, len(pkt.FIR))
+		for _, entry := range pkt.FIR {
+			ssrcs = append(ssrcs, entry.SSRC)
+		}
+
+		return count(i.bound(ssrcs...), &i.rejected.FIR)
+	case *rtcp.ReceiverEstimatedMaximumBitrate:
+		return count(i.bound(pkt.SSRCs...), &i.rejected.REMB)
+	case *rtcp.TransportLayerCC:
+		return count(pkt.MediaSSRC == 0 || i.bound(pkt.MediaSSRC), &i.rejected.TWCC)
+	default:
+		return true
+	}
+}
+
+func count(accepted bool, rejected *uint64) bool {
+	if !accepted {
+		*rejected++
+	}
+
+	return accepted
+}
+
+// bound returns whether any of the SSRCs belongs to a bound local stream.
+func (i *Interceptor) bound(ssrcs ...uint32) bool {
+	for _, ssrc := range ssrcs {
+		if _, ok := i.ssrcs[ssrc]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream.
+// The returned method will be called once per rtp packet.
+func (i *Interceptor) BindLocalStream(
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	i.ssrcs[info.SSRC] = struct{}{}
+
+	return writer
+}
+
+// UnbindLocalStream is called when the Stream is removed. Feedback for the
+// stream is rejected afterwards.
+func (i *Interceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	delete(i.ssrcs, info.SSRC)
+}
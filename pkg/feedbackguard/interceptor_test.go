@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package feedbackguard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptor(t *testing.T) {
+	factory, err := NewInterceptor()
+	require.NoError(t, err)
+
+	var guard *Interceptor
+	factory.OnNewPeerConnection(func(_ string, i *Interceptor) {
+		guard = i
+	})
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	readRTCP := func() []rtcp.Packet {
+		select {
+		case r := <-stream.ReadRTCP():
+			assert.NoError(t, r.Err)
+
+			return r.Packets
+		case <-time.After(time.Second):
+			assert.FailNow(t, "receiver rtcp packets not found")
+		}
+
+		return nil
+	}
+
+	sr := &rtcp.SenderReport{SSRC: 456}
+	pli := &rtcp.PictureLossIndication{SenderSSRC: 456, MediaSSRC: 123}
+	stream.ReceiveRTCP([]rtcp.Packet{
+		sr,
+		pli,
+		&rtcp.PictureLossIndication{SenderSSRC: 456, MediaSSRC: 789},
+		&rtcp.TransportLayerNack{SenderSSRC: 456, MediaSSRC: 789, Nacks: []rtcp.NackPair{{PacketID: 1}}},
+		&rtcp.ReceiverEstimatedMaximumBitrate{SenderSSRC: 456, Bitrate: 1000, SSRCs: []uint32{789}},
+	})
+	assert.Equal(t, []rtcp.Packet{sr, pli}, readRTCP())
+
+	// A batch of only rejected feedback is dropped
+	twcc := &rtcp.TransportLayerCC{
+		Header:       rtcp.Header{Count: rtcp.FormatTCC, Type: rtcp.TypeTransportSpecificFeedback, Length: 4},
+		SenderSSRC:   456,
+		PacketChunks: []rtcp.PacketStatusChunk{},
+		RecvDeltas:   []*rtcp.RecvDelta{},
+	}
+	stream.ReceiveRTCP([]rtcp.Packet{&rtcp.FullIntraRequest{
+		SenderSSRC: 456,
+		FIR:        []rtcp.FIREntry{{SSRC: 789}},
+	}})
+	stream.ReceiveRTCP([]rtcp.Packet{twcc})
+	pkts := readRTCP()
+	require.Len(t, pkts, 1)
+	assert.IsType(t, &rtcp.TransportLayerCC{}, pkts[0])
+
+	assert.Equal(t, Rejected{NACK: 1, PLI: 1, FIR: 1, REMB: 1}, guard.Rejected())
+
+	i.UnbindLocalStream(&interceptor.StreamInfo{SSRC: 123})
+	stream.ReceiveRTCP([]rtcp.Packet{pli})
+	stream.ReceiveRTCP([]rtcp.Packet{sr})
+	assert.Equal(t, []rtcp.Packet{sr}, readRTCP())
+	assert.Equal(t, uint64(2), guard.Rejected().PLI)
+}
@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package feedbackguard
+
+import (
+	"github.com/pion/logging"
+)
+
+// Option can be used to configure the Interceptor.
+type Option func(*Interceptor) error
+
+// Log sets a logger for the interceptor.
+func Log(log logging.LeveledLogger) Option {
+	return func(i *Interceptor) error {
+		i.log = log
+
+		return nil
+	}
+}
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"sync"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+)
+
+// FecDecoderInterceptor recovers lost media packets of remote streams from the FlexFEC-03 packets protecting them.
+// FEC packets sent with the SSRCForwardErrorCorrection of a media stream are read from their own remote stream, which
+// must be bound after the media stream. Without a SSRCForwardErrorCorrection, FEC packets are read from the media
+// stream itself, identified by the PayloadTypeForwardErrorCorrection, and aren't returned by its reader. Recovered
+// packets are returned by the reader of the media stream before the next packet is read.
+type FecDecoderInterceptor struct {
+	interceptor.NoOp
+	onRecoveryFailure RecoveryFailureCallback
+
+	m sync.Mutex
+	// fecStreams are the decoders of the media streams with a separate FEC
+	// stream, by the SSRC of the FEC stream.
+	fecStreams map[uint32]*decoderStream
+}
+
+// decoderStream is the decoder of a media stream, shared by the readers of
+// the media and the FEC stream.
+type decoderStream struct {
+	m         sync.Mutex
+	decoder   *FlexDecoder03
+	recovered []rtp.Packet
+}
+
+func (s *decoderStream) addMediaPacket(packet rtp.Packet) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.recovered = append(s.recovered, s.decoder.AddMediaPacket(packet)...)
+}
+
+func (s *decoderStream) addFecPacket(packet rtp.Packet) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.recovered = append(s.recovered, s.decoder.AddFecPacket(packet)...)
+}
+
+func (s *decoderStream) nextRecovered() (rtp.Packet, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if len(s.recovered) == 0 {
+		return rtp.Packet{}, false
+	}
+	packet := s.recovered[0]
+	s.recovered = s.recovered[1:]
+
+	return packet, true
+}
+
+// FecDecoderOption can be used to set initial options on Fec decoder interceptors.
+type FecDecoderOption func(d *FecDecoderInterceptor) error
+
+// OnRecoveryFailure sets a callback which is called with the lost media packets that couldn't be recovered from the
+// FEC packets protecting them, as soon as the packets following them arrived. It complements the in-band recovery,
+// e.g. to request the affected frames over a data channel. The callback is called from the goroutine reading the
+// media or the FEC stream and mustn't block.
+func OnRecoveryFailure(cb RecoveryFailureCallback) FecDecoderOption {
+	return func(d *FecDecoderInterceptor) error {
+		d.onRecoveryFailure = cb
+
+		return nil
+	}
+}
+
+// FecDecoderInterceptorFactory creates new FecDecoderInterceptors.
+type FecDecoderInterceptorFactory struct {
+	opts []FecDecoderOption
+}
+
+// NewFecDecoderInterceptor returns a new Fec decoder interceptor factory.
+func NewFecDecoderInterceptor(opts ...FecDecoderOption) (*FecDecoderInterceptorFactory, error) {
+	return &FecDecoderInterceptorFactory{opts: opts}, nil
+}
+
+// NewInterceptor constructs a new FecDecoderInterceptor.
+func (r *FecDecoderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	i := &FecDecoderInterceptor{
+		fecStreams: map[uint32]*decoderStream{},
+	}
+	for _, opt := range r.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream. The returned
+// method will be called once per rtp packet.
+func (r *FecDecoderInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	r.m.Lock()
+	fecStream, ok := r.fecStreams[info.SSRC]
+	r.m.Unlock()
+	if ok {
+		return r.bindFecStream(fecStream, reader)
+	}
+	if info.SSRCForwardErrorCorrection == 0 && info.PayloadTypeForwardErrorCorrection == 0 {
+		return reader
+	}
+
+	stream := &decoderStream{decoder: NewFlexDecoder03(info.SSRC, r.onRecoveryFailure)}
+	if info.SSRCForwardErrorCorrection != 0 {
+		r.m.Lock()
+		r.fecStreams[info.SSRCForwardErrorCorrection] = stream
+		r.m.Unlock()
+	}
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		for {
+			// Return the packets recovered since the previous read before reading the next one
+			if packet, ok := stream.nextRecovered(); ok {
+				n, err := packet.MarshalTo(b)
+				if a == nil {
+					a = interceptor.Attributes{}
+				}
+
+				return n, a, err
+			}
+
+			n, attr, err := reader.Read(b, a)
+			if err != nil {
+				return n, attr, err
+			}
+			packet := rtp.Packet{}
+			if err := packet.Unmarshal(append([]byte{}, b[:n]...)); err != nil {
+				return n, attr, nil
+			}
+			if info.SSRCForwardErrorCorrection == 0 && packet.PayloadType == info.PayloadTypeForwardErrorCorrection {
+				stream.addFecPacket(packet)
+
+				continue
+			}
+			if packet.SSRC == info.SSRC {
+				stream.addMediaPacket(packet)
+			}
+
+			return n, attr, nil
+		}
+	})
+}
+
+// bindFecStream passes the packets of a FEC stream to the decoder of its media
+// stream, they are still returned by the reader.
+func (r *FecDecoderInterceptor) bindFecStream(
+	stream *decoderStream, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+		packet := rtp.Packet{}
+		if err := packet.Unmarshal(append([]byte{}, b[:n]...)); err == nil {
+			stream.addFecPacket(packet)
+		}
+
+		return n, attr, nil
+	})
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (r *FecDecoderInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	if info.SSRCForwardErrorCorrection == 0 {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.fecStreams, info.SSRCForwardErrorCorrection)
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFecDecoderInterceptor(t *testing.T) {
+	info := &interceptor.StreamInfo{
+		SSRC:                              1,
+		SSRCForwardErrorCorrection:        2,
+		PayloadType:                       96,
+		PayloadTypeForwardErrorCorrection: 49,
+	}
+
+	encoderFactory, err := NewFecInterceptor()
+	require.NoError(t, err)
+	encoder, err := encoderFactory.NewInterceptor("")
+	require.NoError(t, err)
+	sender := test.NewMockStream(info, encoder)
+	defer func() {
+		assert.NoError(t, sender.Close())
+	}()
+
+	failures := make(chan RecoveryFailure, 10)
+	decoderFactory, err := NewFecDecoderInterceptor(OnRecoveryFailure(func(failure RecoveryFailure) {
+		failures <- failure
+	}))
+	require.NoError(t, err)
+	decoder, err := decoderFactory.NewInterceptor("")
+	require.NoError(t, err)
+	receiver := test.NewMockStream(info, decoder)
+	defer func() {
+		assert.NoError(t, receiver.Close())
+	}()
+	// The FEC packets are received on their own stream
+	fecReceiver := test.NewMockStream(&interceptor.StreamInfo{SSRC: 2, PayloadType: 49}, decoder)
+	defer func() {
+		assert.NoError(t, fecReceiver.Close())
+	}()
+
+	// Three groups of 5 media packets of two frames each, every group is
+	// followed by 2 FEC packets
+	media := map[uint16]*rtp.Packet{}
+	for seq := uint16(0); seq < 15; seq++ {
+		media[seq] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         seq%2 == 1,
+				PayloadType:    96,
+				SequenceNumber: seq,
+				Timestamp:      3000 * uint32(seq/2),
+				SSRC:           1,
+			},
+			Payload: bytes.Repeat([]byte{byte(seq)}, 10+int(seq)),
+		}
+		require.NoError(t, sender.WriteRTP(media[seq]))
+	}
+
+	// The payloads are checked before the next packet is received, as the mock
+	// stream reuses its read buffer
+	readSequenceNumbers := []uint16{}
+	readPacket := func(checkPayload bool) {
+		select {
+		case read := <-receiver.ReadRTP():
+			require.NoError(t, read.Err)
+			expected := media[read.Packet.SequenceNumber]
+			assert.Equal(t, expected.Marker, read.Packet.Marker)
+			assert.Equal(t, expected.PayloadType, read.Packet.PayloadType)
+			assert.Equal(t, expected.Timestamp, read.Packet.Timestamp)
+			assert.Equal(t, expected.SSRC, read.Packet.SSRC)
+			if checkPayload {
+				assert.Equal(t, expected.Payload, read.Packet.Payload)
+			}
+			readSequenceNumbers = append(readSequenceNumbers, read.Packet.SequenceNumber)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "missing packet")
+		}
+	}
+
+	// Packet 1 is the only lost one of the second FEC packet of the first
+	// group, packets 5 and 7 are both lost ones of the first FEC packet of the
+	// second group
+	var fec int
+	for i := 0; i < 15+3*2; i++ {
+		packet := <-sender.WrittenRTP()
+		if packet.SSRC == 2 {
+			assert.Equal(t, uint8(49), packet.PayloadType)
+			fec++
+			fecReceiver.ReceiveRTP(packet)
+			select {
+			case read := <-fecReceiver.ReadRTP():
+				require.NoError(t, read.Err)
+			case <-time.After(time.Second):
+				assert.FailNow(t, "missing FEC packet")
+			}
+
+			// The failure is reported as soon as the FEC packet arrives
+			if fec == 3 {
+				select {
+				case failure := <-failures:
+					assert.Equal(t, RecoveryFailure{
+						SSRC:            1,
+						SequenceNumbers: []uint16{5, 7},
+						FirstTimestamp:  6000,
+						LastTimestamp:   12000,
+					}, failure)
+				case <-time.After(time.Second):
+					assert.FailNow(t, "missing recovery failure")
+				}
+			}
+
+			continue
+		}
+		if seq := packet.SequenceNumber; seq == 1 || seq == 5 || seq == 7 {
+			continue
+		}
+		receiver.ReceiveRTP(packet)
+		// The recovered packet is returned right after the next packet was
+		// read, overwriting its payload
+		readPacket(packet.SequenceNumber != 6)
+		if packet.SequenceNumber == 6 {
+			readPacket(true)
+		}
+	}
+	assert.Equal(t, 6, fec)
+
+	assert.Equal(t, []uint16{0, 2, 3, 4, 6, 1, 8, 9, 10, 11, 12, 13, 14}, readSequenceNumbers)
+	assert.Empty(t, failures)
+}
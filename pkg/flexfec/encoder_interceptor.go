@@ -50,7 +50,7 @@ func (r *FecInterceptor) BindLocalStream(
 ) interceptor.RTPWriter {
 	// Chromium supports version flexfec-03 of existing draft, this is the one we will configure by default
 	// although we should support configuring the latest (flexfec-20) as well.
-	r.flexFecEncoder = NewFlexEncoder03(info.PayloadType, info.SSRC)
+	r.flexFecEncoder = NewFlexEncoder03(info.PayloadTypeForwardErrorCorrection, info.SSRCForwardErrorCorrection)
 
 	return interceptor.RTPWriterFunc(
 		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
@@ -0,0 +1,319 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package flexfec
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/pion/rtp"
+)
+
+// mediaHistorySize is the number of media packets kept to recover lost ones,
+// enough for the packets protected by two FEC packet groups.
+const mediaHistorySize = 2 * MaxMediaPackets
+
+// RecoveryFailure describes media packets which were lost and couldn't be
+// recovered from the FEC packets protecting them, so the application can
+// recover the affected frames by other means, e.g. by requesting them over a
+// data channel.
+type RecoveryFailure struct {
+	SSRC uint32
+	// SequenceNumbers are the sequence numbers of the lost packets, in order.
+	SequenceNumbers []uint16
+	// FirstTimestamp and LastTimestamp bound the RTP timestamps of the frames
+	// the lost packets belong to. They are the timestamps of the packets
+	// received right before and after the lost ones. If there is no such
+	// packet on one side, the timestamp of the other side is used.
+	FirstTimestamp uint32
+	LastTimestamp  uint32
+}
+
+// RecoveryFailureCallback is called with every RecoveryFailure.
+type RecoveryFailureCallback func(failure RecoveryFailure)
+
+// fecPacket03 is a FlexFEC-03 packet protecting the media packets of a single
+// SSRC.
+type fecPacket03 struct {
+	// recovery holds the P, X, CC, M and PT, the length and the timestamp
+	// recovery fields.
+	recovery  [8]byte
+	snBase    uint16
+	protected []uint16
+	repair    []byte
+}
+
+// FlexDecoder03 implements the Fec decoding mechanism for the "Flex" variant of FlexFec, recovering the lost media
+// packets of a single SSRC from the FEC packets generated by FlexEncoder03. A FEC packet is used once a media packet
+// following all packets it protects was added: the single lost packet it protects is recovered, if more were lost
+// the failure is reported right away.
+type FlexDecoder03 struct {
+	ssrc      uint32
+	onFailure RecoveryFailureCallback
+
+	media   map[uint16][]byte
+	order   []uint16
+	started bool
+	highest uint16
+
+	// pending are the FEC packets protecting media packets newer than highest.
+	pending []*fecPacket03
+}
+
+// NewFlexDecoder03 returns a new FlexDecoder03 for the media packets of ssrc. onFailure is called with the media
+// packets which can't be recovered from the FEC packets protecting them, it may be nil.
+func NewFlexDecoder03(ssrc uint32, onFailure RecoveryFailureCallback) *FlexDecoder03 {
+	return &FlexDecoder03{
+		ssrc:      ssrc,
+		onFailure: onFailure,
+		media:     map[uint16][]byte{},
+	}
+}
+
+// AddMediaPacket adds a received media packet and returns the packets recovered from the FEC packets protecting the
+// packets before it.
+func (flex *FlexDecoder03) AddMediaPacket(mediaPacket rtp.Packet) []rtp.Packet {
+	raw, err := mediaPacket.Marshal()
+	if err != nil {
+		return nil
+	}
+	flex.store(mediaPacket.SequenceNumber, raw)
+	if !flex.started || isNewer(mediaPacket.SequenceNumber, flex.highest) {
+		flex.started = true
+		flex.highest = mediaPacket.SequenceNumber
+	}
+
+	return flex.recoverPending()
+}
+
+// AddFecPacket adds a received FEC packet and returns the media packets recovered with it, if the packets it protects
+// were already added.
+func (flex *FlexDecoder03) AddFecPacket(fecPacket rtp.Packet) []rtp.Packet {
+	fec, ok := flex.parseFecPacket03(fecPacket.Payload)
+	if !ok {
+		return nil
+	}
+	flex.pending = append(flex.pending, fec)
+	if len(flex.pending) > int(MaxFecPackets) {
+		flex.pending = flex.pending[1:]
+	}
+
+	return flex.recoverPending()
+}
+
+// isNewer returns whether the sequence number a follows b.
+func isNewer(a, b uint16) bool {
+	return a != b && a-b < 1<<15
+}
+
+// ready returns whether all media packets protected by fec which weren't lost
+// were added.
+func (flex *FlexDecoder03) ready(fec *fecPacket03) bool {
+	if len(fec.protected) == 0 {
+		return true
+	}
+
+	return flex.started && !isNewer(fec.protected[len(fec.protected)-1], flex.highest)
+}
+
+func (flex *FlexDecoder03) parseFecPacket03(payload []byte) (*fecPacket03, bool) {
+	// Only FEC packets protecting a single SSRC are supported, as generated by FlexEncoder03.
+	if len(payload) < BaseFec03HeaderSize || payload[8] != 1 ||
+		binary.BigEndian.Uint32(payload[12:16]) != flex.ssrc {
+		return nil, false
+	}
+
+	fec := &fecPacket03{snBase: binary.BigEndian.Uint16(payload[16:18])}
+	copy(fec.recovery[:], payload[:8])
+
+	// The k bit of a mask is set if it is the last one.
+	mask1 := binary.BigEndian.Uint16(payload[18:20])
+	fec.addProtected(uint64(mask1), 15, 0)
+	headerSize := BaseFec03HeaderSize
+	if mask1&0x8000 == 0 {
+		if len(payload) < headerSize+4 {
+			return nil, false
+		}
+		mask2 := binary.BigEndian.Uint32(payload[20:24])
+		fec.addProtected(uint64(mask2), 31, 15)
+		headerSize += 4
+		if mask2&0x80000000 == 0 {
+			if len(payload) < headerSize+8 {
+				return nil, false
+			}
+			fec.addProtected(binary.BigEndian.Uint64(payload[24:32]), 63, 46)
+			headerSize += 8
+		}
+	}
+	fec.repair = payload[headerSize:]
+
+	return fec, true
+}
+
+// addProtected adds the sequence numbers of the bits set in the lowest size
+// bits of mask, the most significant one protecting the packet offset
+// packets after the SN base.
+func (f *fecPacket03) addProtected(mask uint64, size, offset uint16) {
+	for i := uint16(0); i < size; i++ {
+		if mask&(1<<(size-1-i)) != 0 {
+			f.protected = append(f.protected, f.snBase+offset+i)
+		}
+	}
+}
+
+func (flex *FlexDecoder03) store(sequenceNumber uint16, raw []byte) {
+	if _, ok := flex.media[sequenceNumber]; ok {
+		return
+	}
+	flex.media[sequenceNumber] = raw
+	flex.order = append(flex.order, sequenceNumber)
+	if len(flex.order) > int(mediaHistorySize) {
+		delete(flex.media, flex.order[0])
+		flex.order = flex.order[1:]
+	}
+}
+
+// recoverPending recovers the media packets which are the only missing ones
+// protected by a ready FEC packet, until no more can be recovered. The media
+// packets still missing afterwards are reported.
+func (flex *FlexDecoder03) recoverPending() []rtp.Packet {
+	var ready, pending []*fecPacket03
+	for _, fec := range flex.pending {
+		if flex.ready(fec) {
+			ready = append(ready, fec)
+		} else {
+			pending = append(pending, fec)
+		}
+	}
+	flex.pending = pending
+
+	var recovered []rtp.Packet
+	for progress := true; progress; {
+		progress = false
+		for _, fec := range ready {
+			missing := flex.missing(fec.protected)
+			if len(missing) != 1 {
+				continue
+			}
+			if packet, ok := flex.recover(fec, missing[0]); ok {
+				recovered = append(recovered, packet)
+				progress = true
+			}
+		}
+	}
+
+	lost := map[uint16]bool{}
+	for _, fec := range ready {
+		for _, sequenceNumber := range flex.missing(fec.protected) {
+			lost[sequenceNumber] = true
+		}
+	}
+	if len(lost) > 0 {
+		flex.reportFailure(lost)
+	}
+
+	return recovered
+}
+
+func (flex *FlexDecoder03) missing(protected []uint16) []uint16 {
+	var missing []uint16
+	for _, sequenceNumber := range protected {
+		if _, ok := flex.media[sequenceNumber]; !ok {
+			missing = append(missing, sequenceNumber)
+		}
+	}
+
+	return missing
+}
+
+// recover XORs the FEC packet with all other media packets it protects, which
+// results in the missing one.
+func (flex *FlexDecoder03) recover(fec *fecPacket03, missing uint16) (rtp.Packet, bool) {
+	recovery := fec.recovery
+	repair := append([]byte{}, fec.repair...)
+	for _, sequenceNumber := range fec.protected {
+		if sequenceNumber == missing {
+			continue
+		}
+		raw := flex.media[sequenceNumber]
+		body := raw[BaseRTPHeaderSize:]
+		if len(body) > len(repair) {
+			return rtp.Packet{}, false
+		}
+
+		recovery[0] ^= raw[0]
+		recovery[1] ^= raw[1]
+		recovery[2] ^= uint8(len(body) >> 8) //nolint:gosec // G115
+		recovery[3] ^= uint8(len(body))      //nolint:gosec // G115
+		for i := 4; i < 8; i++ {
+			recovery[i] ^= raw[i]
+		}
+		for i := range body {
+			repair[i] ^= body[i]
+		}
+	}
+
+	length := int(binary.BigEndian.Uint16(recovery[2:4]))
+	if length > len(repair) {
+		return rtp.Packet{}, false
+	}
+	raw := make([]byte, BaseRTPHeaderSize+length)
+	raw[0] = 0b10000000 | recovery[0]&0b00111111
+	raw[1] = recovery[1]
+	binary.BigEndian.PutUint16(raw[2:4], missing)
+	copy(raw[4:8], recovery[4:8])
+	binary.BigEndian.PutUint32(raw[8:12], flex.ssrc)
+	copy(raw[BaseRTPHeaderSize:], repair)
+
+	packet := rtp.Packet{}
+	if err := packet.Unmarshal(raw); err != nil {
+		return rtp.Packet{}, false
+	}
+	flex.store(missing, raw)
+
+	return packet, true
+}
+
+// reportFailure reports the lost media packets.
+func (flex *FlexDecoder03) reportFailure(lost map[uint16]bool) {
+	if flex.onFailure == nil {
+		return
+	}
+
+	sequenceNumbers := make([]uint16, 0, len(lost))
+	for sequenceNumber := range lost {
+		sequenceNumbers = append(sequenceNumbers, sequenceNumber)
+	}
+	// All lost packets precede the highest one
+	sort.Slice(sequenceNumbers, func(i, j int) bool {
+		return flex.highest-sequenceNumbers[i] > flex.highest-sequenceNumbers[j]
+	})
+
+	first, firstOK := flex.nearestTimestamp(sequenceNumbers[0], ^uint16(0))
+	last, lastOK := flex.nearestTimestamp(sequenceNumbers[len(sequenceNumbers)-1], 1)
+	if !firstOK {
+		first = last
+	}
+	if !lastOK {
+		last = first
+	}
+	flex.onFailure(RecoveryFailure{
+		SSRC:            flex.ssrc,
+		SequenceNumbers: sequenceNumbers,
+		FirstTimestamp:  first,
+		LastTimestamp:   last,
+	})
+}
+
+// nearestTimestamp returns the timestamp of the nearest stored media packet
+// from sequenceNumber in the direction of step.
+func (flex *FlexDecoder03) nearestTimestamp(sequenceNumber, step uint16) (uint32, bool) {
+	for i := uint16(1); i <= uint16(mediaHistorySize); i++ {
+		if raw, ok := flex.media[sequenceNumber+i*step]; ok {
+			return binary.BigEndian.Uint32(raw[4:8]), true
+		}
+	}
+
+	return 0, false
+}
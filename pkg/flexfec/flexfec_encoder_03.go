@@ -200,21 +200,27 @@ func (flex *FlexEncoder03) encodeFlexFecHeader(
 	return flexFecHeader
 }
 
+// encodeFlexFecRepairPayload XORs everything following the fixed RTP header of the media packets: the CSRCs, the
+// header extension, the payload and the padding.
 func (flex *FlexEncoder03) encodeFlexFecRepairPayload(mediaPackets *util.MediaPacketIterator) []byte {
-	flexFecPayload := make([]byte, len(mediaPackets.First().Payload))
+	flexFecPayload := make([]byte, 0)
 
 	for mediaPackets.HasNext() {
-		mediaPacketPayload := mediaPackets.Next().Payload
+		mediaPacket, err := mediaPackets.Next().Marshal()
+		if err != nil {
+			return nil
+		}
+		mediaPacketBody := mediaPacket[BaseRTPHeaderSize:]
 
-		if len(flexFecPayload) < len(mediaPacketPayload) {
+		if len(flexFecPayload) < len(mediaPacketBody) {
 			// Expected FEC packet payload is bigger that what we can currently store,
 			// we need to resize.
-			flexFecPayloadTmp := make([]byte, len(mediaPacketPayload))
+			flexFecPayloadTmp := make([]byte, len(mediaPacketBody))
 			copy(flexFecPayloadTmp, flexFecPayload)
 			flexFecPayload = flexFecPayloadTmp
 		}
-		for byteIndex := 0; byteIndex < len(mediaPacketPayload); byteIndex++ {
-			flexFecPayload[byteIndex] ^= mediaPacketPayload[byteIndex]
+		for byteIndex := 0; byteIndex < len(mediaPacketBody); byteIndex++ {
+			flexFecPayload[byteIndex] ^= mediaPacketBody[byteIndex]
 		}
 	}
 
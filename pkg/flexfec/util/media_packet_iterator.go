@@ -40,7 +40,7 @@ func (m *MediaPacketIterator) Next() *rtp.Packet {
 	if m.nextIndex == len(m.coveredIndices) {
 		return nil
 	}
-	packet := m.mediaPackets[m.nextIndex]
+	packet := m.mediaPackets[m.coveredIndices[m.nextIndex]]
 	m.nextIndex++
 
 	return &packet
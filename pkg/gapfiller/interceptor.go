@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package gapfiller provides an interceptor which keeps the sequence numbers
+// of sent RTP packets continuous when packets are dropped before they are
+// sent, e.g. by a forwarding policy or an SVC layer filter of a relay. The
+// remote jitter buffers and NACK generators then don't wait for or request
+// packets which are never sent.
+package gapfiller
+
+import (
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+// paddingSize is the size of the padding of padding-only packets, the
+// smallest valid one holding only the padding count.
+const paddingSize = 1
+
+// InterceptorFactory is a interceptor.Factory for a gap filler Interceptor.
+type InterceptorFactory struct {
+	opts []Option
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{opts}, nil
+}
+
+// NewInterceptor constructs a new gap filler Interceptor.
+func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	i := &Interceptor{
+		NoOp:          interceptor.NoOp{},
+		log:           logging.NewDefaultLoggerFactory().NewLogger("gapfiller"),
+		paddingMaxGap: 0,
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// Interceptor rewrites the sequence numbers of the packets of local streams
+// to close the gaps left by packets dropped by the interceptors registered
+// after it, or the application. Retransmissions are sent with the sequence
+// number the packet was first sent with, and old packets of a closed gap are
+// dropped. It must be registered after the interceptors keeping sent packets,
+// like the NACK responder, so they see the rewritten sequence numbers.
+type Interceptor struct {
+	interceptor.NoOp
+	log           logging.LeveledLogger
+	paddingMaxGap uint16
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
+// will be called once per rtp packet.
+func (i *Interceptor) BindLocalStream(_ *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
+	s := &stream{}
+
+	return interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			seq, padding, ok := s.next(header, i.paddingMaxGap)
+			if !ok {
+				i.log.Debugf("dropping packet %d of ssrc %d sent after its gap was closed",
+					header.SequenceNumber, header.SSRC)
+
+				return 0, nil
+			}
+
+			for k := range padding {
+				pkt := make([]byte, paddingSize)
+				pkt[paddingSize-1] = paddingSize
+				if _, err := writer.Write(&padding[k], pkt, interceptor.Attributes{}); err != nil {
+					return 0, err
+				}
+			}
+
+			if seq == header.SequenceNumber {
+				return writer.Write(header, payload, attributes)
+			}
+
+			// The header may be reused by the caller, e.g. for retransmissions
+			rewritten := header.Clone()
+			rewritten.SequenceNumber = seq
+
+			return writer.Write(&rewritten, payload, attributes)
+		},
+	)
+}
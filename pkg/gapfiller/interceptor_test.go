@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gapfiller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type written struct {
+	seq       uint16
+	timestamp uint32
+	padding   bool
+}
+
+func writeAndCheck(t *testing.T, stream *test.MockStream, seqs []uint16, expected []written) {
+	t.Helper()
+
+	for _, seq := range seqs {
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: seq, Timestamp: uint32(seq) * 10, PayloadType: 96, SSRC: 123},
+			Payload: []byte{0x01},
+		}))
+	}
+
+	for _, e := range expected {
+		select {
+		case p := <-stream.WrittenRTP():
+			assert.Equal(t, e.seq, p.SequenceNumber)
+			assert.Equal(t, e.timestamp, p.Timestamp)
+			assert.Equal(t, e.padding, p.Padding)
+			assert.Equal(t, uint8(96), p.PayloadType)
+			assert.Equal(t, uint32(123), p.SSRC)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "written rtp packet not found")
+		}
+	}
+
+	select {
+	case p := <-stream.WrittenRTP():
+		assert.Failf(t, "unexpected packet", "sequence number %d", p.SequenceNumber)
+	default:
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	t.Run("renumber", func(t *testing.T) {
+		factory, err := NewInterceptor()
+		require.NoError(t, err)
+		i, err := factory.NewInterceptor("")
+		require.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		writeAndCheck(t, stream, []uint16{65534, 65535, 2, 3, 65535, 0, 6}, []written{
+			{seq: 65534, timestamp: 655340},
+			{seq: 65535, timestamp: 655350},
+			{seq: 0, timestamp: 20},
+			{seq: 1, timestamp: 30},
+			// Retransmission of 65535, while 0 was dropped
+			{seq: 65535, timestamp: 655350},
+			{seq: 2, timestamp: 60},
+		})
+	})
+
+	t.Run("padding", func(t *testing.T) {
+		factory, err := NewInterceptor(Padding(2))
+		require.NoError(t, err)
+		i, err := factory.NewInterceptor("")
+		require.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		writeAndCheck(t, stream, []uint16{10, 13, 17, 18}, []written{
+			{seq: 10, timestamp: 100},
+			{seq: 11, timestamp: 100, padding: true},
+			{seq: 12, timestamp: 100, padding: true},
+			{seq: 13, timestamp: 130},
+			// The gap of 3 packets is too large to be padded
+			{seq: 14, timestamp: 170},
+			{seq: 15, timestamp: 180},
+		})
+	})
+}
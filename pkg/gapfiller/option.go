@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gapfiller
+
+import (
+	"github.com/pion/logging"
+)
+
+// Option can be used to configure the gap filler Interceptor.
+type Option func(i *Interceptor) error
+
+// Log sets a logger for the interceptor.
+func Log(log logging.LeveledLogger) Option {
+	return func(i *Interceptor) error {
+		i.log = log
+
+		return nil
+	}
+}
+
+// Padding fills gaps of up to maxGap packets with padding-only packets
+// instead of renumbering the following packets, so the packet rate seen by
+// the remote side is preserved. The padding packets have the sequence numbers
+// of the dropped packets, and the payload type and timestamp of the packet
+// before the gap. Larger gaps are still closed by renumbering.
+func Padding(maxGap uint16) Option {
+	return func(i *Interceptor) error {
+		i.paddingMaxGap = maxGap
+
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gapfiller
+
+import (
+	"github.com/pion/rtp"
+)
+
+// historySize is the number of sequence numbers whose rewritten numbers are
+// kept, to send retransmissions with the sequence number of the original.
+const historySize = 1024
+
+type mapping struct {
+	in    uint16
+	out   uint16
+	valid bool
+}
+
+// stream rewrites the sequence numbers of a local stream. It is only used
+// from the writer of the stream, which is not called concurrently.
+type stream struct {
+	started       bool
+	lastIn        uint16
+	lastTimestamp uint32
+	lastPT        uint8
+	offset        uint16
+	history       [historySize]mapping
+}
+
+// next returns the sequence number to send a packet with, and the headers of
+// the padding packets filling the gap before it. ok is false for old packets
+// whose sequence number is unknown, which are dropped.
+func (s *stream) next(header *rtp.Header, paddingMaxGap uint16) (seq uint16, padding []rtp.Header, ok bool) {
+	in := header.SequenceNumber
+	if !s.started {
+		s.started = true
+		s.update(header)
+
+		return s.record(in), nil, true
+	}
+
+	diff := in - s.lastIn
+	if diff == 0 || diff >= 1<<15 {
+		// Retransmissions and reordered packets keep the number they were
+		// first sent with
+		m := s.history[in%historySize]
+		if !m.valid || m.in != in {
+			return 0, nil, false
+		}
+
+		return m.out, nil, true
+	}
+
+	if gap := diff - 1; gap > 0 {
+		if gap <= paddingMaxGap {
+			padding = make([]rtp.Header, 0, gap)
+			for k := uint16(1); k <= gap; k++ {
+				padding = append(padding, rtp.Header{
+					Version:        2,
+					Padding:        true,
+					PayloadType:    s.lastPT,
+					SequenceNumber: s.record(s.lastIn + k),
+					Timestamp:      s.lastTimestamp,
+					SSRC:           header.SSRC,
+				})
+			}
+		} else {
+			s.offset += gap
+		}
+	}
+	s.update(header)
+
+	return s.record(in), padding, true
+}
+
+func (s *stream) update(header *rtp.Header) {
+	s.lastIn = header.SequenceNumber
+	s.lastTimestamp = header.Timestamp
+	s.lastPT = header.PayloadType
+}
+
+func (s *stream) record(in uint16) uint16 {
+	out := in - s.offset
+	s.history[in%historySize] = mapping{in: in, out: out, valid: true}
+
+	return out
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"sync"
+	"time"
+)
+
+type ackedPacket struct {
+	size    int
+	arrival time.Time
+}
+
+// AckedBitrateEstimator computes the bitrate acknowledged by the congestion
+// control feedback, the bytes which arrived at the remote within a sliding
+// window of arrival times. It is the bitrate actually delivered, as opposed
+// to the target bitrate of the SendSideBWE, e.g. to be displayed by
+// applications. It can be fed with the feedback of SendSideBWE.OnPacketFeedback.
+type AckedBitrateEstimator struct {
+	window time.Duration
+
+	m        sync.Mutex
+	history  []ackedPacket
+	sum      int
+	bitrate  int
+	started  bool
+	onChange func(bitrate int)
+}
+
+// NewAckedBitrateEstimator returns a new AckedBitrateEstimator averaging over
+// window. The SendSideBWE uses a window of 500 ms.
+func NewAckedBitrateEstimator(window time.Duration) *AckedBitrateEstimator {
+	return &AckedBitrateEstimator{
+		window: window,
+	}
+}
+
+// OnAckedBitrate sets the callback that is called with the acknowledged
+// bitrate in bits per second after every update acknowledging packets. It is
+// called synchronously from Update and should return quickly.
+func (e *AckedBitrateEstimator) OnAckedBitrate(f func(bitrate int)) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.onChange = f
+}
+
+// Update adds the packets which arrived according to the feedback. The
+// feedback must be passed in the order it was received.
+func (e *AckedBitrateEstimator) Update(feedback []PacketFeedback) {
+	e.m.Lock()
+	updated := false
+	for _, f := range feedback {
+		if f.Lost() {
+			continue
+		}
+		e.add(f.Size, f.Arrival)
+		updated = true
+	}
+	bitrate, onChange := e.bitrate, e.onChange
+	e.m.Unlock()
+
+	if updated && onChange != nil {
+		onChange(bitrate)
+	}
+}
+
+// Bitrate returns the last acknowledged bitrate in bits per second, and
+// whether any packet was acknowledged yet.
+func (e *AckedBitrateEstimator) Bitrate() (int, bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return e.bitrate, e.started
+}
+
+// add adds a packet which arrived and returns the new bitrate. It must be
+// called with e.m held.
+func (e *AckedBitrateEstimator) add(size int, arrival time.Time) int {
+	e.history = append(e.history, ackedPacket{size: size, arrival: arrival})
+	e.sum += size
+
+	if !e.started {
+		e.started = true
+		// Don't know any timeframe here, only arrival of last packet,
+		// which is by definition in the window that ends with the last
+		// arrival time
+		e.bitrate = size * 8
+
+		return e.bitrate
+	}
+
+	del := 0
+	deadline := arrival.Add(-e.window)
+	for _, packet := range e.history {
+		if !packet.arrival.Before(deadline) {
+			break
+		}
+		del++
+		e.sum -= packet.size
+	}
+	e.history = e.history[del:]
+	if len(e.history) == 0 {
+		e.bitrate = 0
+
+		return e.bitrate
+	}
+	dt := arrival.Sub(e.history[0].arrival)
+	e.bitrate = int(float64(8*e.sum) / dt.Seconds())
+
+	return e.bitrate
+}
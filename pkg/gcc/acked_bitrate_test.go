@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAckedBitrateEstimator(t *testing.T) {
+	estimator := NewAckedBitrateEstimator(500 * time.Millisecond)
+	_, ok := estimator.Bitrate()
+	assert.False(t, ok)
+
+	updates := []int{}
+	estimator.OnAckedBitrate(func(bitrate int) {
+		updates = append(updates, bitrate)
+	})
+
+	// Lost packets don't update the bitrate
+	t0 := time.Now()
+	estimator.Update([]PacketFeedback{{Size: 1200}})
+	assert.Empty(t, updates)
+
+	// The callback is called once per update
+	feedback := []PacketFeedback{}
+	for i := 0; i < 10; i++ {
+		feedback = append(feedback, PacketFeedback{Size: 1200, Arrival: t0.Add(time.Duration(i) * 100 * time.Millisecond)})
+	}
+	estimator.Update(feedback[:2])
+	estimator.Update(feedback[2:])
+	assert.Equal(t, []int{192_000, 115_200}, updates)
+
+	bitrate, ok := estimator.Bitrate()
+	assert.True(t, ok)
+	assert.Equal(t, 115_200, bitrate)
+}
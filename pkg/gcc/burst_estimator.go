@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+const defaultBurstMinPackets = 5
+
+var errInvalidBurstMinPackets = errors.New("burst estimator needs at least two packets")
+
+// BurstEstimatorOption configures a BurstEstimator.
+type BurstEstimatorOption func(*BurstEstimator) error
+
+// BurstEstimatorMinPackets sets the minimum number of packets a burst must
+// have to be used for the estimate.
+func BurstEstimatorMinPackets(n int) BurstEstimatorOption {
+	return func(e *BurstEstimator) error {
+		if n < 2 {
+			return errInvalidBurstMinPackets
+		}
+		e.minPackets = n
+
+		return nil
+	}
+}
+
+// BurstEstimator derives an initial receive side bandwidth estimate from the
+// dispersion of the first packet burst of a stream. All packets sharing an
+// RTP timestamp belong to the same frame and are sent back to back, so the
+// rate at which they arrive approximates the bottleneck capacity. This can be
+// used to seed REMB or other estimates right after joining, before enough
+// regular feedback has accumulated, see remb.ReceiverBurstEstimate.
+type BurstEstimator struct {
+	minPackets int
+
+	lock         sync.Mutex
+	started      bool
+	timestamp    uint32
+	firstArrival time.Time
+	lastArrival  time.Time
+	packets      int
+	bytes        int
+	estimate     int
+	done         bool
+}
+
+// NewBurstEstimator creates a new BurstEstimator.
+func NewBurstEstimator(opts ...BurstEstimatorOption) (*BurstEstimator, error) {
+	estimator := &BurstEstimator{
+		minPackets: defaultBurstMinPackets,
+	}
+	for _, opt := range opts {
+		if err := opt(estimator); err != nil {
+			return nil, err
+		}
+	}
+
+	return estimator, nil
+}
+
+// OnPacket adds an incoming packet of size bytes which arrived at arrival. It
+// returns the estimate in bits per second once a suitable burst was found.
+func (e *BurstEstimator) OnPacket(arrival time.Time, header *rtp.Header, size int) (int, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.done {
+		return e.estimate, true
+	}
+
+	if e.started && header.Timestamp != e.timestamp {
+		if e.finish() {
+			return e.estimate, true
+		}
+		e.started = false
+	}
+
+	if !e.started {
+		e.started = true
+		e.timestamp = header.Timestamp
+		e.firstArrival = arrival
+		e.lastArrival = arrival
+		e.packets = 1
+		// The first packet only marks the start of the burst, its size is
+		// not part of the dispersion.
+		e.bytes = 0
+
+		return 0, false
+	}
+
+	e.packets++
+	e.bytes += size
+	if arrival.After(e.lastArrival) {
+		e.lastArrival = arrival
+	}
+
+	if header.Marker && e.finish() {
+		return e.estimate, true
+	}
+
+	return 0, false
+}
+
+// Estimate returns the estimate in bits per second, if available.
+func (e *BurstEstimator) Estimate() (int, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.estimate, e.done
+}
+
+func (e *BurstEstimator) finish() bool {
+	if e.packets < e.minPackets {
+		return false
+	}
+	dispersion := e.lastArrival.Sub(e.firstArrival)
+	if dispersion <= 0 {
+		return false
+	}
+	e.estimate = int(float64(e.bytes*8) / dispersion.Seconds())
+	e.done = true
+
+	return true
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBurstEstimator(t *testing.T) {
+	t0 := time.Time{}.Add(time.Hour)
+
+	t.Run("estimatesFromDispersion", func(t *testing.T) {
+		estimator, err := NewBurstEstimator()
+		assert.NoError(t, err)
+
+		// 1250 bytes every millisecond is 10 Mbps
+		for i := 0; i < 10; i++ {
+			estimate, ok := estimator.OnPacket(t0.Add(time.Duration(i)*time.Millisecond), &rtp.Header{
+				Timestamp: 1000,
+				Marker:    i == 9,
+			}, 1250)
+			if i < 9 {
+				assert.False(t, ok)
+			} else {
+				assert.True(t, ok)
+				assert.Equal(t, 10_000_000, estimate)
+			}
+		}
+
+		// Later packets don't change the estimate
+		estimate, ok := estimator.OnPacket(t0.Add(time.Second), &rtp.Header{Timestamp: 2000}, 100)
+		assert.True(t, ok)
+		assert.Equal(t, 10_000_000, estimate)
+	})
+
+	t.Run("skipsSmallBursts", func(t *testing.T) {
+		estimator, err := NewBurstEstimator(BurstEstimatorMinPackets(3))
+		assert.NoError(t, err)
+
+		_, ok := estimator.OnPacket(t0, &rtp.Header{Timestamp: 1000}, 1000)
+		assert.False(t, ok)
+		_, ok = estimator.OnPacket(t0.Add(time.Millisecond), &rtp.Header{Timestamp: 1000, Marker: true}, 1000)
+		assert.False(t, ok)
+
+		for i := 0; i < 3; i++ {
+			_, ok = estimator.OnPacket(t0.Add(time.Duration(10+i)*time.Millisecond), &rtp.Header{Timestamp: 2000}, 1000)
+			assert.False(t, ok)
+		}
+		// The next frame completes the burst
+		estimate, ok := estimator.OnPacket(t0.Add(50*time.Millisecond), &rtp.Header{Timestamp: 3000}, 1000)
+		assert.True(t, ok)
+		assert.Equal(t, 8_000_000, estimate)
+
+		estimate, ok = estimator.Estimate()
+		assert.True(t, ok)
+		assert.Equal(t, 8_000_000, estimate)
+	})
+
+	t.Run("rejectsInvalidMinPackets", func(t *testing.T) {
+		_, err := NewBurstEstimator(BurstEstimatorMinPackets(1))
+		assert.ErrorIs(t, err, errInvalidBurstMinPackets)
+	})
+}
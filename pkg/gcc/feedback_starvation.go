@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"errors"
+	"time"
+)
+
+var errInvalidFeedbackTimeout = errors.New("feedback timeout and ramp down step must be positive, the factor in (0, 1)")
+
+// feedbackTimeout is the schedule of SendSideBWEFeedbackTimeout.
+type feedbackTimeout struct {
+	freeze time.Duration
+	step   time.Duration
+	factor float64
+}
+
+// SendSideBWEFeedbackTimeout handles the starvation of congestion control
+// feedback, e.g. as the path died or the remote stopped sending it. Once no
+// feedback arrived for freeze after a previous one, the estimate is
+// considered stale: the target bitrate is multiplied by factor right away and
+// every step after, down to the minimum bitrate. When feedback arrives again,
+// the estimate restarts from the lowered target. The starvation is checked
+// every step, and its start and end are passed to the callback of
+// OnFeedbackStarvation.
+func SendSideBWEFeedbackTimeout(freeze, step time.Duration, factor float64) Option {
+	return func(e *SendSideBWE) error {
+		if freeze <= 0 || step <= 0 || factor <= 0 || factor >= 1 {
+			return errInvalidFeedbackTimeout
+		}
+		e.feedbackTimeout = &feedbackTimeout{freeze: freeze, step: step, factor: factor}
+
+		return nil
+	}
+}
+
+// OnFeedbackStarvation sets the callback that is called with true when the
+// feedback starved as configured with SendSideBWEFeedbackTimeout, and with
+// false when feedback arrives again.
+func (e *SendSideBWE) OnFeedbackStarvation(f func(starved bool)) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.onFeedbackStarvation = f
+}
+
+// onFeedback records that feedback arrived at now.
+func (e *SendSideBWE) onFeedback(now time.Time) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.lastFeedback = now
+	if e.starved {
+		e.starved = false
+		if e.onFeedbackStarvation != nil {
+			go e.onFeedbackStarvation(false)
+		}
+	}
+}
+
+func (e *SendSideBWE) feedbackTimeoutLoop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.feedbackTimeout.step)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			e.checkFeedbackTimeout(now)
+		case <-e.close:
+			return
+		}
+	}
+}
+
+// checkFeedbackTimeout lowers the target bitrate while the feedback starved.
+func (e *SendSideBWE) checkFeedbackTimeout(now time.Time) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.lastFeedback.IsZero() || now.Sub(e.lastFeedback) < e.feedbackTimeout.freeze {
+		return
+	}
+	if !e.starved {
+		e.starved = true
+		if e.onFeedbackStarvation != nil {
+			go e.onFeedbackStarvation(true)
+		}
+	}
+
+	bitrate := maxInt(e.minBitrate, int(float64(e.latestBitrate)*e.feedbackTimeout.factor))
+	if bitrate == e.latestBitrate {
+		return
+	}
+	e.latestBitrate = bitrate
+	e.pacer.SetTargetBitrate(bitrate)
+	// The delay based estimate is stale, the loss based one limits the target
+	// until it increased again.
+	e.lossController.limitBitrate(bitrate)
+	if e.onTargetBitrateChange != nil {
+		go e.onTargetBitrateChange(bitrate)
+	}
+}
@@ -37,6 +37,17 @@ type LeakyBucketPacer struct {
 	queue *list.List
 	done  chan struct{}
 
+	// maxQueueBytes is the queue size above which frames are dropped, 0
+	// disables dropping. The other fields are guarded by qLock.
+	maxQueueBytes int
+	queuedBytes   int
+	// inProgress holds the timestamp of the frame of every SSRC whose first
+	// packets were sent, but not its last one.
+	inProgress map[uint32]uint32
+	// dropping holds the timestamp of the frame of every SSRC which was
+	// dropped before all its packets were queued.
+	dropping map[uint32]uint32
+
 	ssrcToWriter map[uint32]interceptor.RTPWriter
 	writerLock   sync.RWMutex
 
@@ -53,6 +64,8 @@ func NewLeakyBucketPacer(initialBitrate int) *LeakyBucketPacer {
 		qLock:          sync.RWMutex{},
 		queue:          list.New(),
 		done:           make(chan struct{}),
+		inProgress:     map[uint32]uint32{},
+		dropping:       map[uint32]uint32{},
 		ssrcToWriter:   map[uint32]interceptor.RTPWriter{},
 		pool:           &sync.Pool{},
 	}
@@ -84,6 +97,20 @@ func (p *LeakyBucketPacer) SetTargetBitrate(rate int) {
 	p.targetBitrate = int(p.f * float64(rate))
 }
 
+// SetMaxQueueSize sets the number of queued bytes above which the pacer drops
+// queued frames, to avoid sending stale media under congestion. Frames are
+// only dropped as a whole, identified by the SSRC and timestamp of their
+// packets and ended by the marker bit, so no undecodable partial frames are
+// sent. Frames are dropped oldest first, discardable frames before delta
+// frames and frames of unknown type, see interceptor.SetFrameType. Key frames
+// and frames which are partially sent already are never dropped. The receiver
+// sees the packets of dropped frames as lost. A size of 0 disables dropping.
+func (p *LeakyBucketPacer) SetMaxQueueSize(bytes int) {
+	p.qLock.Lock()
+	defer p.qLock.Unlock()
+	p.maxQueueBytes = bytes
+}
+
 func (p *LeakyBucketPacer) getTargetBitrate() int {
 	p.targetBitrateLock.Lock()
 	defer p.targetBitrateLock.Unlock()
@@ -99,16 +126,33 @@ func (p *LeakyBucketPacer) Write(header *rtp.Header, payload []byte, attributes
 		return 0, errLeakyBucketPacerPoolCastFailed
 	}
 
+	p.qLock.Lock()
+	if ts, ok := p.dropping[header.SSRC]; ok {
+		if ts == header.Timestamp {
+			if header.Marker {
+				delete(p.dropping, header.SSRC)
+			}
+			p.qLock.Unlock()
+			p.pool.Put(buf)
+
+			return header.MarshalSize() + len(payload), nil
+		}
+		delete(p.dropping, header.SSRC)
+	}
+
 	copy(*buf, payload)
 	hdr := header.Clone()
 
-	p.qLock.Lock()
 	p.queue.PushBack(&item{
 		header:     &hdr,
 		payload:    buf,
 		size:       len(payload),
 		attributes: attributes,
 	})
+	p.queuedBytes += len(payload)
+	if p.maxQueueBytes > 0 && p.queuedBytes > p.maxQueueBytes {
+		p.dropFrames()
+	}
 	p.qLock.Unlock()
 
 	return header.MarshalSize() + len(payload), nil
@@ -130,12 +174,15 @@ func (p *LeakyBucketPacer) Run() {
 			for p.queue.Len() != 0 && budget > 0 {
 				p.log.Infof("budget=%v, len(queue)=%v, targetBitrate=%v", budget, p.queue.Len(), p.getTargetBitrate())
 				next, ok := p.queue.Remove(p.queue.Front()).(*item)
-				p.qLock.Unlock()
 				if !ok {
+					p.qLock.Unlock()
 					p.log.Warnf("failed to access leaky bucket pacer queue, cast failed")
+					p.qLock.Lock()
 
 					continue
 				}
+				p.dequeued(next)
+				p.qLock.Unlock()
 
 				p.writerLock.RLock()
 				writer, ok := p.ssrcToWriter[next.header.SSRC]
@@ -163,6 +210,85 @@ func (p *LeakyBucketPacer) Run() {
 	}
 }
 
+// dequeued updates the state of the queue after next was removed. It must be
+// called with qLock held.
+func (p *LeakyBucketPacer) dequeued(next *item) {
+	p.queuedBytes -= next.size
+	if next.header.Marker {
+		delete(p.inProgress, next.header.SSRC)
+	} else {
+		p.inProgress[next.header.SSRC] = next.header.Timestamp
+	}
+}
+
+type queuedFrame struct {
+	ssrc      uint32
+	timestamp uint32
+	frameType interceptor.FrameType
+	elements  []*list.Element
+	complete  bool
+}
+
+// dropFrames drops whole frames until the queue size is below the maximum. It
+// must be called with qLock held.
+func (p *LeakyBucketPacer) dropFrames() {
+	type frameKey struct {
+		ssrc      uint32
+		timestamp uint32
+	}
+	frames := []*queuedFrame{}
+	byKey := map[frameKey]*queuedFrame{}
+	for e := p.queue.Front(); e != nil; e = e.Next() {
+		it, ok := e.Value.(*item)
+		if !ok {
+			continue
+		}
+		key := frameKey{ssrc: it.header.SSRC, timestamp: it.header.Timestamp}
+		frame, ok := byKey[key]
+		if !ok {
+			frame = &queuedFrame{ssrc: key.ssrc, timestamp: key.timestamp}
+			byKey[key] = frame
+			frames = append(frames, frame)
+		}
+		if frameType := it.attributes.GetFrameType(); frameType != interceptor.FrameTypeUnknown {
+			frame.frameType = frameType
+		}
+		frame.elements = append(frame.elements, e)
+		frame.complete = frame.complete || it.header.Marker
+	}
+
+	for _, frameType := range []interceptor.FrameType{
+		interceptor.FrameTypeDiscardable, interceptor.FrameTypeDelta, interceptor.FrameTypeUnknown,
+	} {
+		for _, frame := range frames {
+			if p.queuedBytes <= p.maxQueueBytes {
+				return
+			}
+			if frame.frameType != frameType {
+				continue
+			}
+			if ts, ok := p.inProgress[frame.ssrc]; ok && ts == frame.timestamp {
+				continue
+			}
+			p.dropFrame(frame)
+		}
+	}
+}
+
+func (p *LeakyBucketPacer) dropFrame(frame *queuedFrame) {
+	for _, e := range frame.elements {
+		if it, ok := p.queue.Remove(e).(*item); ok {
+			p.queuedBytes -= it.size
+			p.pool.Put(it.payload)
+		}
+	}
+	if !frame.complete {
+		p.dropping[frame.ssrc] = frame.timestamp
+	}
+	p.log.Debugf("dropped frame with timestamp %d of ssrc %d, %d bytes queued",
+		frame.timestamp, frame.ssrc, p.queuedBytes)
+}
+
 // Close closes the LeakyBucketPacer.
 func (p *LeakyBucketPacer) Close() error {
 	close(p.done)
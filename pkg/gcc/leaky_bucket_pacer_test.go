@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeakyBucketPacer_DropFrames(t *testing.T) {
+	// Without bitrate nothing is sent, so the queue only shrinks by drops
+	pacer := NewLeakyBucketPacer(0)
+	defer func() {
+		assert.NoError(t, pacer.Close())
+	}()
+	pacer.SetMaxQueueSize(250)
+
+	payload := make([]byte, 100)
+	write := func(timestamp uint32, marker bool, frameType interceptor.FrameType) {
+		attributes := interceptor.Attributes{}
+		attributes.SetFrameType(frameType)
+		_, err := pacer.Write(&rtp.Header{SSRC: 1, Timestamp: timestamp, Marker: marker}, payload, attributes)
+		assert.NoError(t, err)
+	}
+	queued := func() []uint32 {
+		pacer.qLock.Lock()
+		defer pacer.qLock.Unlock()
+
+		timestamps := []uint32{}
+		for e := pacer.queue.Front(); e != nil; e = e.Next() {
+			it, ok := e.Value.(*item)
+			assert.True(t, ok)
+			timestamps = append(timestamps, it.header.Timestamp)
+		}
+
+		return timestamps
+	}
+
+	write(1, false, interceptor.FrameTypeKey)
+	write(1, true, interceptor.FrameTypeKey)
+	assert.Equal(t, []uint32{1, 1}, queued())
+
+	// The delta frame overflows the queue while it's queued, so its remaining
+	// packets are dropped as well.
+	write(2, false, interceptor.FrameTypeDelta)
+	assert.Equal(t, []uint32{1, 1}, queued())
+	write(2, true, interceptor.FrameTypeUnknown)
+	assert.Equal(t, []uint32{1, 1}, queued())
+
+	// Discardable frames are dropped before older delta frames
+	pacer.SetMaxQueueSize(350)
+	write(3, true, interceptor.FrameTypeDelta)
+	write(4, true, interceptor.FrameTypeDiscardable)
+	assert.Equal(t, []uint32{1, 1, 3}, queued())
+
+	// Key frames and frames which are partially sent are never dropped
+	pacer.qLock.Lock()
+	pacer.inProgress[1] = 3
+	pacer.qLock.Unlock()
+	write(5, true, interceptor.FrameTypeKey)
+	assert.Equal(t, []uint32{1, 1, 3, 5}, queued())
+
+	pacer.qLock.Lock()
+	assert.Equal(t, 400, pacer.queuedBytes)
+	pacer.qLock.Unlock()
+}
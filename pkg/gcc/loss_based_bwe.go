@@ -22,6 +22,10 @@ const (
 
 	decreaseLossThreshold = 0.1
 	decreaseTimeThreshold = 200 * time.Millisecond
+
+	// reportLossMaxAge is how long the loss of a receiver report is fused
+	// into the loss of the feedback, a few regular report intervals.
+	reportLossMaxAge = 5 * time.Second
 )
 
 // LossStats contains internal statistics of the loss based controller.
@@ -39,6 +43,17 @@ type lossBasedBandwidthEstimator struct {
 	lastLossUpdate time.Time
 	lastIncrease   time.Time
 	lastDecrease   time.Time
+	// fastBackoff makes the first decrease drop the bitrate by the full loss
+	// ratio, it is used when starting close to the maximum bitrate.
+	fastBackoff bool
+	// reportWeight is the weight of the loss of receiver reports fused into
+	// the loss of the feedback, and stallTimeout the time without feedback
+	// after which the loss of receiver reports is used alone.
+	reportWeight   float64
+	stallTimeout   time.Duration
+	reportLoss     float64
+	lastReportLoss time.Time
+	lastFeedback   time.Time
 	log            logging.LeveledLogger
 }
 
@@ -52,6 +67,12 @@ func newLossBasedBWE(initialBitrate int) *lossBasedBandwidthEstimator {
 		lastLossUpdate: time.Time{},
 		lastIncrease:   time.Time{},
 		lastDecrease:   time.Time{},
+		fastBackoff:    false,
+		reportWeight:   0,
+		stallTimeout:   0,
+		reportLoss:     0,
+		lastReportLoss: time.Time{},
+		lastFeedback:   time.Time{},
 		log:            logging.NewDefaultLoggerFactory().NewLogger("gcc_loss_controller"),
 	}
 }
@@ -71,6 +92,14 @@ func (e *lossBasedBandwidthEstimator) getEstimate(wantedRate int) LossStats {
 	}
 }
 
+// limitBitrate lowers the estimate to at most bitrate.
+func (e *lossBasedBandwidthEstimator) limitBitrate(bitrate int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.bitrate = minInt(e.bitrate, bitrate)
+}
+
 func (e *lossBasedBandwidthEstimator) updateLossEstimate(results []cc.Acknowledgment) {
 	if len(results) == 0 {
 		return
@@ -87,13 +116,46 @@ func (e *lossBasedBandwidthEstimator) updateLossEstimate(results []cc.Acknowledg
 	defer e.lock.Unlock()
 
 	lossRatio := float64(packetsLost) / float64(len(results))
+	e.lastFeedback = time.Now()
+	if e.reportWeight > 0 && !e.lastReportLoss.IsZero() && time.Since(e.lastReportLoss) < reportLossMaxAge {
+		lossRatio = (1-e.reportWeight)*lossRatio + e.reportWeight*e.reportLoss
+	}
+	e.update(lossRatio, true)
+}
+
+// updateReportLoss adds the fraction of packets lost reported by a receiver
+// report, in units of 1/256. While feedback is received it is fused into the
+// loss of the feedback. Once the feedback stalled, it is used alone as a
+// safety net, which only decreases the bitrate as receiver reports are too
+// infrequent and coarse to probe for more. It returns whether the bitrate
+// changed.
+func (e *lossBasedBandwidthEstimator) updateReportLoss(fractionLost uint8) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.reportWeight <= 0 {
+		return false
+	}
+	e.reportLoss = float64(fractionLost) / 256
+	e.lastReportLoss = time.Now()
+	if !e.lastFeedback.IsZero() && time.Since(e.lastFeedback) < e.stallTimeout {
+		return false
+	}
+	bitrate := e.bitrate
+	e.update(e.reportLoss, false)
+
+	return e.bitrate != bitrate
+}
+
+// update must be called with e.lock held.
+func (e *lossBasedBandwidthEstimator) update(lossRatio float64, allowIncrease bool) {
 	e.averageLoss = e.average(time.Since(e.lastLossUpdate), e.averageLoss, lossRatio)
 	e.lastLossUpdate = time.Now()
 
 	increaseLoss := math.Max(e.averageLoss, lossRatio)
 	decreaseLoss := math.Min(e.averageLoss, lossRatio)
 
-	if increaseLoss < increaseLossThreshold && time.Since(e.lastIncrease) > increaseTimeThreshold {
+	if allowIncrease && increaseLoss < increaseLossThreshold && time.Since(e.lastIncrease) > increaseTimeThreshold {
 		e.log.Infof(
 			"loss controller increasing; averageLoss: %v, decreaseLoss: %v, increaseLoss: %v",
 			e.averageLoss, decreaseLoss, increaseLoss,
@@ -106,7 +168,12 @@ func (e *lossBasedBandwidthEstimator) updateLossEstimate(results []cc.Acknowledg
 			e.averageLoss, decreaseLoss, increaseLoss,
 		)
 		e.lastDecrease = time.Now()
-		e.bitrate = clampInt(int(float64(e.bitrate)*(1-0.5*decreaseLoss)), e.minBitrate, e.maxBitrate)
+		factor := 0.5
+		if e.fastBackoff {
+			factor = 1.0
+			e.fastBackoff = false
+		}
+		e.bitrate = clampInt(int(float64(e.bitrate)*(1-factor*decreaseLoss)), e.minBitrate, e.maxBitrate)
 	}
 }
 
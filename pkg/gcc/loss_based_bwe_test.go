@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLossBasedBWE_FastBackoff(t *testing.T) {
+	acks := []cc.Acknowledgment{
+		{Arrival: time.Now()},
+		{Arrival: time.Time{}},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		bwe := newLossBasedBWE(1_000_000)
+		bwe.updateLossEstimate(acks)
+		assert.Equal(t, 750_000, bwe.getEstimate(1_000_000).TargetBitrate)
+	})
+
+	t.Run("fastBackoff", func(t *testing.T) {
+		bwe := newLossBasedBWE(1_000_000)
+		bwe.fastBackoff = true
+		bwe.updateLossEstimate(acks)
+		assert.Equal(t, 500_000, bwe.getEstimate(1_000_000).TargetBitrate)
+		assert.False(t, bwe.fastBackoff)
+	})
+}
+
+func TestLossBasedBWE_ReportLoss(t *testing.T) {
+	t.Run("fused with feedback", func(t *testing.T) {
+		bwe := newLossBasedBWE(1_000_000)
+		bwe.reportWeight = 0.5
+		bwe.stallTimeout = time.Second
+		assert.True(t, bwe.updateReportLoss(128))
+		bwe.lastDecrease = time.Time{}
+
+		// Half of the 50% loss of the report is fused into the lossless feedback
+		bwe.updateLossEstimate([]cc.Acknowledgment{{Arrival: time.Now()}})
+		assert.Equal(t, 656_250, bwe.getEstimate(1_000_000).TargetBitrate)
+
+		// Reports don't update the estimate while feedback is received
+		bwe.lastDecrease = time.Time{}
+		assert.False(t, bwe.updateReportLoss(255))
+	})
+
+	t.Run("stalled feedback", func(t *testing.T) {
+		bwe := newLossBasedBWE(1_000_000)
+		bwe.reportWeight = 0.5
+		bwe.stallTimeout = time.Second
+		assert.True(t, bwe.updateReportLoss(128))
+		assert.Equal(t, 750_000, bwe.getEstimate(1_000_000).TargetBitrate)
+
+		// Lossless reports don't increase the estimate
+		bwe.lastIncrease = time.Time{}
+		assert.False(t, bwe.updateReportLoss(0))
+		assert.Equal(t, 750_000, bwe.getEstimate(1_000_000).TargetBitrate)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		bwe := newLossBasedBWE(1_000_000)
+		assert.False(t, bwe.updateReportLoss(128))
+		assert.Equal(t, 1_000_000, bwe.getEstimate(1_000_000).TargetBitrate)
+	})
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+
+	"github.com/pion/interceptor/internal/cc"
+)
+
+// PacketMetadataAttributesKey can be used to attach opaque metadata to an
+// outgoing RTP packet via its Attributes. The metadata is preserved and
+// returned in the PacketFeedback of the packet, which allows applications to
+// map network feedback back to frames.
+const PacketMetadataAttributesKey = cc.MetadataAttributesKey
+
+// PacketFeedback is the feedback received for a single sent packet.
+type PacketFeedback struct {
+	// SequenceNumber is the transport wide sequence number for TWCC feedback or
+	// the RTP sequence number for RFC 8888 feedback.
+	SequenceNumber uint16
+	// SSRC is only set for RFC 8888 feedback.
+	SSRC      uint32
+	Size      int
+	Departure time.Time
+	// Arrival is the zero time if the packet was reported as lost.
+	Arrival  time.Time
+	Metadata interface{}
+}
+
+// Lost returns true if the packet was reported as not received.
+func (f PacketFeedback) Lost() bool {
+	return f.Arrival.IsZero()
+}
+
+func packetFeedbackFromAcks(acks []cc.Acknowledgment) []PacketFeedback {
+	feedback := make([]PacketFeedback, 0, len(acks))
+	for _, ack := range acks {
+		// Acknowledgments of packets we don't know about carry no information.
+		if ack.Departure.IsZero() {
+			continue
+		}
+		feedback = append(feedback, PacketFeedback{
+			SequenceNumber: ack.SequenceNumber,
+			SSRC:           ack.SSRC,
+			Size:           ack.Size,
+			Departure:      ack.Departure,
+			Arrival:        ack.Arrival,
+			Metadata:       ack.Metadata,
+		})
+	}
+
+	return feedback
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"math"
+	"time"
+)
+
+// rateShaper smooths the target bitrate reported to the application. Decreases
+// are applied immediately, while increases are capped to a maximum relative
+// growth per second, similar to the ramp-up behavior of libwebrtc.
+type rateShaper struct {
+	// maxIncreasePerSecond is the maximum relative increase per second, e.g.
+	// 0.08 allows the output to grow by at most 8% per second. A value <= 0
+	// disables shaping.
+	maxIncreasePerSecond float64
+
+	init       bool
+	output     float64
+	lastUpdate time.Time
+}
+
+func newRateShaper(maxIncreasePerSecond float64) *rateShaper {
+	return &rateShaper{
+		maxIncreasePerSecond: maxIncreasePerSecond,
+		init:                 false,
+		output:               0.0,
+		lastUpdate:           time.Time{},
+	}
+}
+
+// update feeds a new raw estimate into the shaper and returns the shaped
+// output.
+func (s *rateShaper) update(now time.Time, estimate int) int {
+	if s.maxIncreasePerSecond <= 0 {
+		return estimate
+	}
+	if !s.init || float64(estimate) <= s.output {
+		s.init = true
+		s.output = float64(estimate)
+		s.lastUpdate = now
+
+		return estimate
+	}
+
+	elapsed := math.Max(now.Sub(s.lastUpdate).Seconds(), 0)
+	s.output = math.Min(float64(estimate), s.output*math.Pow(1+s.maxIncreasePerSecond, elapsed))
+	s.lastUpdate = now
+
+	return int(s.output)
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateShaper(t *testing.T) {
+	t0 := time.Time{}.Add(time.Hour)
+
+	t.Run("disabled", func(t *testing.T) {
+		shaper := newRateShaper(0)
+		assert.Equal(t, 100_000, shaper.update(t0, 100_000))
+		assert.Equal(t, 1_000_000, shaper.update(t0.Add(time.Millisecond), 1_000_000))
+		assert.Equal(t, 10_000, shaper.update(t0.Add(2*time.Millisecond), 10_000))
+	})
+
+	t.Run("capsIncrease", func(t *testing.T) {
+		shaper := newRateShaper(0.1)
+		assert.Equal(t, 100_000, shaper.update(t0, 100_000))
+		assert.Equal(t, 110_000, shaper.update(t0.Add(time.Second), 1_000_000))
+		assert.Equal(t, 121_000, shaper.update(t0.Add(2*time.Second), 1_000_000))
+		assert.Equal(t, 125_000, shaper.update(t0.Add(3*time.Second), 125_000))
+	})
+
+	t.Run("accumulatesSmallSteps", func(t *testing.T) {
+		shaper := newRateShaper(0.08)
+		assert.Equal(t, 10_000, shaper.update(t0, 10_000))
+		out := 0
+		for i := 1; i <= 1000; i++ {
+			out = shaper.update(t0.Add(time.Duration(i)*time.Millisecond), 1_000_000)
+		}
+		assert.InDelta(t, 10_800, out, 10)
+	})
+
+	t.Run("immediateDecrease", func(t *testing.T) {
+		shaper := newRateShaper(0.1)
+		assert.Equal(t, 100_000, shaper.update(t0, 100_000))
+		assert.Equal(t, 50_000, shaper.update(t0.Add(time.Millisecond), 50_000))
+		assert.Equal(t, 55_000, shaper.update(t0.Add(time.Second+time.Millisecond), 80_000))
+	})
+}
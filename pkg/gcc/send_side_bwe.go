@@ -4,14 +4,17 @@
 package gcc
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/cc"
-	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
@@ -26,6 +29,11 @@ const (
 // ErrSendSideBWEClosed is raised when SendSideBWE.WriteRTCP is called after SendSideBWE.Close.
 var ErrSendSideBWEClosed = errors.New("SendSideBwe closed")
 
+var (
+	errInvalidFastStartFraction = errors.New("fast start fraction must be in (0, 1]")
+	errInvalidReportLoss        = errors.New("report loss weight must be in (0, 1] and the stall timeout positive")
+)
+
 // Pacer is the interface implemented by packet pacers.
 type Pacer interface {
 	interceptor.RTPWriter
@@ -46,17 +54,34 @@ type SendSideBWE struct {
 	lossController  *lossBasedBandwidthEstimator
 	delayController *delayController
 	feedbackAdapter *cc.FeedbackAdapter
+	rateShaper      *rateShaper
 
 	onTargetBitrateChange func(bitrate int)
+	onPacketFeedback      func([]PacketFeedback)
 
 	lock          sync.Mutex
 	latestStats   Stats
 	latestBitrate int
 	minBitrate    int
 	maxBitrate    int
+	fastStart     float64
+	reportWeight  float64
+	stallTimeout  time.Duration
+	trace         *json.Encoder
+	ecn           *interceptor.ECN
+
+	feedbackTimeout      *feedbackTimeout
+	lastFeedback         time.Time
+	starved              bool
+	onFeedbackStarvation func(starved bool)
+
+	events       atomic.Pointer[interceptor.EventBus]
+	probing      bool
+	probeBitrate int
 
 	close     chan struct{}
 	closeLock sync.RWMutex
+	wg        sync.WaitGroup
 }
 
 // Option configures a bandwidth estimator.
@@ -89,6 +114,54 @@ func SendSideBWEMinBitrate(rate int) Option {
 	}
 }
 
+// SendSideBWEMaxIncreaseRate sets the maximum relative increase per second of
+// the target bitrate reported to the application, e.g. 0.08 for 8% per second.
+// Decreases are always applied immediately. A value <= 0 disables shaping.
+func SendSideBWEMaxIncreaseRate(rate float64) Option {
+	return func(e *SendSideBWE) error {
+		e.rateShaper = newRateShaper(rate)
+
+		return nil
+	}
+}
+
+// SendSideBWEFastStart enables an aggressive startup mode, in which the
+// estimator starts at fraction of the maximum bitrate instead of the initial
+// bitrate, and backs off by the full loss ratio on the first loss based
+// decrease. It is meant for links which are known to be good, e.g. screen
+// sharing to a LAN peer, where the default conservative start wastes the first
+// seconds of a session.
+func SendSideBWEFastStart(fraction float64) Option {
+	return func(e *SendSideBWE) error {
+		if fraction <= 0 || fraction > 1 {
+			return errInvalidFastStartFraction
+		}
+		e.fastStart = fraction
+
+		return nil
+	}
+}
+
+// SendSideBWEReceiverReportLoss makes the loss based controller also use the
+// fraction lost of received receiver and sender reports, instead of only the
+// loss of TWCC or RFC 8888 feedback. While feedback is received, the loss of
+// the last report is fused into the loss of every feedback with weight. Once
+// no feedback was received for stallTimeout, e.g. as the feedback is dropped
+// by middleboxes, every report updates the estimate with its loss alone. The
+// reports then only decrease the bitrate, as they are too infrequent to probe
+// for more.
+func SendSideBWEReceiverReportLoss(weight float64, stallTimeout time.Duration) Option {
+	return func(e *SendSideBWE) error {
+		if weight <= 0 || weight > 1 || stallTimeout <= 0 {
+			return errInvalidReportLoss
+		}
+		e.reportWeight = weight
+		e.stallTimeout = stallTimeout
+
+		return nil
+	}
+}
+
 // SendSideBWEPacer sets the pacing algorithm to use.
 func SendSideBWEPacer(p Pacer) Option {
 	return func(e *SendSideBWE) error {
@@ -98,6 +171,31 @@ func SendSideBWEPacer(p Pacer) Option {
 	}
 }
 
+// SendSideBWETrace writes a trace of the estimator to w, with a JSON object
+// per line for every update of the delay based estimate. It holds the arrival
+// delta and the trendline of the delay, the detector and controller state and
+// the resulting target bitrates, so the behavior of different versions on the
+// same network trace can be plotted and compared. Tracing stops on the first
+// write error.
+func SendSideBWETrace(w io.Writer) Option {
+	return func(e *SendSideBWE) error {
+		e.trace = json.NewEncoder(w)
+
+		return nil
+	}
+}
+
+// SendSideBWEECN requests the transport to send all packets with the ECN
+// codepoint, e.g. interceptor.ECNECT1 for L4S experiments, unless a codepoint
+// was already set on the attributes of a packet.
+func SendSideBWEECN(ecn interceptor.ECN) Option {
+	return func(e *SendSideBWE) error {
+		e.ecn = &ecn
+
+		return nil
+	}
+}
+
 // NewSendSideBWE creates a new sender side bandwidth estimator.
 func NewSendSideBWE(opts ...Option) (*SendSideBWE, error) {
 	send := &SendSideBWE{
@@ -105,12 +203,20 @@ func NewSendSideBWE(opts ...Option) (*SendSideBWE, error) {
 		lossController:        nil,
 		delayController:       nil,
 		feedbackAdapter:       cc.NewFeedbackAdapter(),
+		rateShaper:            newRateShaper(0),
 		onTargetBitrateChange: nil,
+		onPacketFeedback:      nil,
 		lock:                  sync.Mutex{},
 		latestStats:           Stats{},
 		latestBitrate:         latestBitrate,
 		minBitrate:            minBitrate,
 		maxBitrate:            maxBitrate,
+		fastStart:             0,
+		reportWeight:          0,
+		stallTimeout:          0,
+		trace:                 nil,
+		ecn:                   nil,
+		feedbackTimeout:       nil,
 		close:                 make(chan struct{}),
 	}
 	for _, opt := range opts {
@@ -118,10 +224,16 @@ func NewSendSideBWE(opts ...Option) (*SendSideBWE, error) {
 			return nil, err
 		}
 	}
+	if send.fastStart > 0 {
+		send.latestBitrate = int(send.fastStart * float64(send.maxBitrate))
+	}
 	if send.pacer == nil {
 		send.pacer = NewLeakyBucketPacer(send.latestBitrate)
 	}
 	send.lossController = newLossBasedBWE(send.latestBitrate)
+	send.lossController.fastBackoff = send.fastStart > 0
+	send.lossController.reportWeight = send.reportWeight
+	send.lossController.stallTimeout = send.stallTimeout
 	send.delayController = newDelayController(delayControllerConfig{
 		nowFn:          time.Now,
 		initialBitrate: send.latestBitrate,
@@ -131,6 +243,11 @@ func NewSendSideBWE(opts ...Option) (*SendSideBWE, error) {
 
 	send.delayController.onUpdate(send.onDelayUpdate)
 
+	if send.feedbackTimeout != nil {
+		send.wg.Add(1)
+		go send.feedbackTimeoutLoop()
+	}
+
 	return send, nil
 }
 
@@ -147,12 +264,15 @@ func (e *SendSideBWE) AddStream(info *interceptor.StreamInfo, writer interceptor
 
 	e.pacer.AddStream(info.SSRC, interceptor.RTPWriterFunc(
 		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			if attributes == nil && (hdrExtID != 0 || e.ecn != nil) {
+				attributes = make(interceptor.Attributes)
+			}
 			if hdrExtID != 0 {
-				if attributes == nil {
-					attributes = make(interceptor.Attributes)
-				}
 				attributes.Set(cc.TwccExtensionAttributesKey, hdrExtID)
 			}
+			if _, ok := attributes.GetECN(); e.ecn != nil && !ok {
+				attributes.SetECN(*e.ecn)
+			}
 			if err := e.feedbackAdapter.OnSent(time.Now(), header, len(payload), attributes); err != nil {
 				return 0, err
 			}
@@ -164,10 +284,12 @@ func (e *SendSideBWE) AddStream(info *interceptor.StreamInfo, writer interceptor
 	return e.pacer
 }
 
-// WriteRTCP adds some RTCP feedback to the bandwidth estimator.
+// WriteRTCP adds some RTCP feedback to the bandwidth estimator. The RTT
+// measured from congestion control feedback is stored in attributes, see
+// interceptor.Attributes.SetRTT.
 //
 //nolint:cyclop
-func (e *SendSideBWE) WriteRTCP(pkts []rtcp.Packet, _ interceptor.Attributes) error {
+func (e *SendSideBWE) WriteRTCP(pkts []rtcp.Packet, attributes interceptor.Attributes) error {
 	now := time.Now()
 	e.closeLock.RLock()
 	defer e.closeLock.RUnlock()
@@ -199,6 +321,14 @@ func (e *SendSideBWE) WriteRTCP(pkts []rtcp.Packet, _ interceptor.Attributes) er
 		case *rtcp.CCFeedbackReport:
 			acks = e.feedbackAdapter.OnRFC8888Feedback(now, fb)
 			feedbackSentTime = ntp.ToTime(uint64(fb.ReportTimestamp) << 16)
+		case *rtcp.ReceiverReport:
+			e.updateReportLoss(fb.Reports)
+
+			continue
+		case *rtcp.SenderReport:
+			e.updateReportLoss(fb.Reports)
+
+			continue
 		default:
 			continue
 		}
@@ -212,8 +342,20 @@ func (e *SendSideBWE) WriteRTCP(pkts []rtcp.Packet, _ interceptor.Attributes) er
 			rtt := now.Sub(ack.Departure) - pendingTime
 			feedbackMinRTT = time.Duration(minInt(int(rtt), int(feedbackMinRTT)))
 		}
+		if len(acks) > 0 {
+			e.onFeedback(now)
+		}
 		if feedbackMinRTT < math.MaxInt {
 			e.delayController.updateRTT(feedbackMinRTT)
+			if attributes != nil {
+				attributes.SetRTT(feedbackMinRTT)
+			}
+		}
+
+		if e.onPacketFeedback != nil {
+			if feedback := packetFeedbackFromAcks(acks); len(feedback) > 0 {
+				e.onPacketFeedback(feedback)
+			}
 		}
 
 		e.lossController.updateLossEstimate(acks)
@@ -223,6 +365,32 @@ func (e *SendSideBWE) WriteRTCP(pkts []rtcp.Packet, _ interceptor.Attributes) er
 	return nil
 }
 
+// updateReportLoss passes the highest fraction lost of the reception reports
+// to the loss based controller, and updates the target if it changed, as no
+// delay based update may follow while the feedback stalled.
+func (e *SendSideBWE) updateReportLoss(reports []rtcp.ReceptionReport) {
+	if e.reportWeight <= 0 || len(reports) == 0 {
+		return
+	}
+	fractionLost := uint8(0)
+	for _, report := range reports {
+		if report.FractionLost > fractionLost {
+			fractionLost = report.FractionLost
+		}
+	}
+	if !e.lossController.updateReportLoss(fractionLost) {
+		return
+	}
+
+	e.lock.Lock()
+	delayStats := e.latestStats.DelayStats
+	if delayStats.TargetBitrate == 0 {
+		delayStats.TargetBitrate = e.latestBitrate
+	}
+	e.lock.Unlock()
+	e.onDelayUpdate(delayStats)
+}
+
 // GetTargetBitrate returns the current target bitrate in bits per second.
 func (e *SendSideBWE) GetTargetBitrate() int {
 	e.lock.Lock()
@@ -254,6 +422,13 @@ func (e *SendSideBWE) OnTargetBitrateChange(f func(bitrate int)) {
 	e.onTargetBitrateChange = f
 }
 
+// OnPacketFeedback sets the callback that is called with the per packet
+// results of every received congestion control feedback report. The callback
+// is called synchronously from WriteRTCP and should return quickly.
+func (e *SendSideBWE) OnPacketFeedback(f func([]PacketFeedback)) {
+	e.onPacketFeedback = f
+}
+
 // isClosed returns true if SendSideBWE is closed.
 func (e *SendSideBWE) isClosed() bool {
 	select {
@@ -273,17 +448,65 @@ func (e *SendSideBWE) Close() error {
 		return err
 	}
 	close(e.close)
+	e.wg.Wait()
 
 	return e.pacer.Close()
 }
 
+// SetEventBus sets the bus an interceptor.ProbeResult event is published on
+// for every increase phase of the delay based estimator, which probes for more
+// bandwidth. The probe fails when overuse ends the phase, with the highest
+// delay based target bitrate reached, and succeeds when it reaches the max
+// bitrate.
+func (e *SendSideBWE) SetEventBus(bus *interceptor.EventBus) {
+	e.events.Store(bus)
+}
+
 func (e *SendSideBWE) onDelayUpdate(delayStats DelayStats) {
 	e.lock.Lock()
-	defer e.lock.Unlock()
+	probeResult, probeFinished := e.updateProbe(delayStats)
+	e.updateTarget(delayStats)
+	e.lock.Unlock()
 
+	if probeFinished {
+		e.events.Load().Publish(probeResult)
+	}
+}
+
+// updateProbe tracks the increase phases of the delay based estimator and
+// returns the result of the phase which finished with delayStats, if any. It
+// must be called with e.lock held.
+func (e *SendSideBWE) updateProbe(delayStats DelayStats) (interceptor.ProbeResult, bool) {
+	switch {
+	case delayStats.State == stateIncrease && delayStats.TargetBitrate >= e.maxBitrate:
+		if !e.probing {
+			return interceptor.ProbeResult{}, false
+		}
+		e.probing = false
+
+		return interceptor.ProbeResult{Bitrate: delayStats.TargetBitrate, Success: true}, true
+	case delayStats.State == stateIncrease:
+		if !e.probing || delayStats.TargetBitrate > e.probeBitrate {
+			e.probeBitrate = delayStats.TargetBitrate
+		}
+		e.probing = true
+
+		return interceptor.ProbeResult{}, false
+	case delayStats.State == stateDecrease && e.probing:
+		e.probing = false
+
+		return interceptor.ProbeResult{Bitrate: e.probeBitrate, Success: false}, true
+	default:
+		return interceptor.ProbeResult{}, false
+	}
+}
+
+// updateTarget must be called with e.lock held.
+func (e *SendSideBWE) updateTarget(delayStats DelayStats) {
 	lossStats := e.lossController.getEstimate(delayStats.TargetBitrate)
 	bitrateChanged := false
 	bitrate := minInt(delayStats.TargetBitrate, lossStats.TargetBitrate)
+	bitrate = e.rateShaper.update(time.Now(), bitrate)
 	if bitrate != e.latestBitrate {
 		bitrateChanged = true
 		e.latestBitrate = bitrate
@@ -298,4 +521,49 @@ func (e *SendSideBWE) onDelayUpdate(delayStats DelayStats) {
 		LossStats:  lossStats,
 		DelayStats: delayStats,
 	}
+	e.writeTrace(time.Now())
+}
+
+// traceRecord is a line of the trace written by SendSideBWETrace. Durations
+// are in milliseconds.
+type traceRecord struct {
+	Time               int64   `json:"time"`
+	ReceiveDelta       float64 `json:"receiveDelta"`
+	Measurement        float64 `json:"measurement"`
+	Trendline          float64 `json:"trendline"`
+	Threshold          float64 `json:"threshold"`
+	Usage              string  `json:"usage"`
+	State              string  `json:"state"`
+	DelayTargetBitrate int     `json:"delayTargetBitrate"`
+	AverageLoss        float64 `json:"averageLoss"`
+	LossTargetBitrate  int     `json:"lossTargetBitrate"`
+	TargetBitrate      int     `json:"targetBitrate"`
+}
+
+// writeTrace must be called with e.lock held.
+func (e *SendSideBWE) writeTrace(now time.Time) {
+	if e.trace == nil {
+		return
+	}
+
+	err := e.trace.Encode(traceRecord{
+		Time:               now.UnixMilli(),
+		ReceiveDelta:       milliseconds(e.latestStats.LastReceiveDelta),
+		Measurement:        milliseconds(e.latestStats.Measurement),
+		Trendline:          milliseconds(e.latestStats.Estimate),
+		Threshold:          milliseconds(e.latestStats.Threshold),
+		Usage:              e.latestStats.Usage.String(),
+		State:              e.latestStats.State.String(),
+		DelayTargetBitrate: e.latestStats.DelayStats.TargetBitrate,
+		AverageLoss:        e.latestStats.AverageLoss,
+		LossTargetBitrate:  e.latestStats.LossStats.TargetBitrate,
+		TargetBitrate:      e.latestBitrate,
+	})
+	if err != nil {
+		e.trace = nil
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000.0
 }
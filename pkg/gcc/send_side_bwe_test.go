@@ -4,9 +4,12 @@
 package gcc
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/pkg/twcc"
@@ -111,6 +114,257 @@ func TestSendSideBWE_ErrorOnWriteRTCPAtClosedState(t *testing.T) {
 	require.Equal(t, bwe.isClosed(), true)
 }
 
+func TestSendSideBWE_FastStart(t *testing.T) {
+	bwe, err := NewSendSideBWE(
+		SendSideBWEPacer(NewNoOpPacer()),
+		SendSideBWEFastStart(0.8),
+		SendSideBWEMaxBitrate(10_000_000),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 8_000_000, bwe.GetTargetBitrate())
+	require.True(t, bwe.lossController.fastBackoff)
+	require.NoError(t, bwe.Close())
+
+	_, err = NewSendSideBWE(SendSideBWEFastStart(1.5))
+	require.ErrorIs(t, err, errInvalidFastStartFraction)
+}
+
+func TestSendSideBWE_ReceiverReportLoss(t *testing.T) {
+	bwe, err := NewSendSideBWE(
+		SendSideBWEPacer(NewNoOpPacer()),
+		SendSideBWEInitialBitrate(1_000_000),
+		SendSideBWEReceiverReportLoss(0.5, time.Second),
+	)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, bwe.Close())
+	}()
+
+	// Without any feedback the loss of the reports decreases the target
+	require.NoError(t, bwe.WriteRTCP([]rtcp.Packet{&rtcp.ReceiverReport{
+		Reports: []rtcp.ReceptionReport{{SSRC: 1, FractionLost: 13}, {SSRC: 2, FractionLost: 128}},
+	}}, nil))
+	require.Equal(t, 750_000, bwe.GetTargetBitrate())
+
+	_, err = NewSendSideBWE(SendSideBWEReceiverReportLoss(0, time.Second))
+	require.ErrorIs(t, err, errInvalidReportLoss)
+	_, err = NewSendSideBWE(SendSideBWEReceiverReportLoss(0.5, 0))
+	require.ErrorIs(t, err, errInvalidReportLoss)
+}
+
+func TestSendSideBWE_FeedbackTimeout(t *testing.T) {
+	bwe, err := NewSendSideBWE(
+		SendSideBWEPacer(NewNoOpPacer()),
+		SendSideBWEInitialBitrate(1_000_000),
+		SendSideBWEFeedbackTimeout(time.Second, time.Hour, 0.5),
+	)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, bwe.Close())
+	}()
+
+	starvation := make(chan bool, 2)
+	bwe.OnFeedbackStarvation(func(starved bool) {
+		starvation <- starved
+	})
+	writer := bwe.AddStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+			return 0, nil
+		},
+	))
+	feedback := func(seq uint16) {
+		_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: seq}, make([]byte, 100), nil)
+		require.NoError(t, err)
+		require.NoError(t, bwe.WriteRTCP([]rtcp.Packet{&rtcp.CCFeedbackReport{
+			ReportBlocks: []rtcp.CCFeedbackReportBlock{{
+				MediaSSRC:     1,
+				BeginSequence: seq,
+				MetricBlocks:  []rtcp.CCFeedbackMetricBlock{{Received: true}},
+			}},
+		}}, nil))
+	}
+
+	// Starvation is only detected after the first feedback
+	now := time.Now()
+	bwe.checkFeedbackTimeout(now.Add(time.Hour))
+	require.Equal(t, 1_000_000, bwe.GetTargetBitrate())
+
+	feedback(0)
+	bwe.lock.Lock()
+	lastFeedback := bwe.lastFeedback
+	bwe.lock.Unlock()
+	target := bwe.GetTargetBitrate()
+
+	// The estimate is frozen until the timeout and ramped down afterwards
+	bwe.checkFeedbackTimeout(lastFeedback.Add(500 * time.Millisecond))
+	require.Equal(t, target, bwe.GetTargetBitrate())
+	bwe.checkFeedbackTimeout(lastFeedback.Add(time.Second))
+	require.Equal(t, target/2, bwe.GetTargetBitrate())
+	require.True(t, <-starvation)
+	bwe.checkFeedbackTimeout(lastFeedback.Add(2 * time.Second))
+	require.Equal(t, target/4, bwe.GetTargetBitrate())
+
+	// The estimate restarts from the lowered target with the next feedback
+	feedback(1)
+	require.False(t, <-starvation)
+	require.LessOrEqual(t, bwe.GetTargetBitrate(), target/4)
+
+	for _, opt := range []Option{
+		SendSideBWEFeedbackTimeout(0, time.Second, 0.5),
+		SendSideBWEFeedbackTimeout(time.Second, 0, 0.5),
+		SendSideBWEFeedbackTimeout(time.Second, time.Second, 1),
+	} {
+		_, err = NewSendSideBWE(opt)
+		require.ErrorIs(t, err, errInvalidFeedbackTimeout)
+	}
+}
+
+func TestSendSideBWE_Trace(t *testing.T) {
+	trace := &bytes.Buffer{}
+	bwe, err := NewSendSideBWE(SendSideBWEPacer(NewNoOpPacer()), SendSideBWETrace(trace))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, bwe.Close())
+	}()
+
+	bwe.onDelayUpdate(DelayStats{
+		Measurement:      2 * time.Millisecond,
+		Estimate:         1500 * time.Microsecond,
+		Threshold:        6 * time.Millisecond,
+		LastReceiveDelta: 20 * time.Millisecond,
+		Usage:            usageNormal,
+		State:            stateHold,
+		TargetBitrate:    1_000_000,
+	})
+	bwe.onDelayUpdate(DelayStats{Usage: usageOver, State: stateDecrease, TargetBitrate: 500_000})
+
+	decoder := json.NewDecoder(trace)
+	record := traceRecord{}
+	require.NoError(t, decoder.Decode(&record))
+	require.Equal(t, 20.0, record.ReceiveDelta)
+	require.Equal(t, 2.0, record.Measurement)
+	require.Equal(t, 1.5, record.Trendline)
+	require.Equal(t, 6.0, record.Threshold)
+	require.Equal(t, "normal", record.Usage)
+	require.Equal(t, "hold", record.State)
+	require.Equal(t, 1_000_000, record.DelayTargetBitrate)
+	require.Equal(t, latestBitrate, record.LossTargetBitrate)
+
+	require.NoError(t, decoder.Decode(&record))
+	require.Equal(t, "overuse", record.Usage)
+	require.Equal(t, 500_000, record.DelayTargetBitrate)
+	require.Equal(t, bwe.GetTargetBitrate(), record.TargetBitrate)
+	require.False(t, decoder.More())
+}
+
+func TestSendSideBWE_ProbeResult(t *testing.T) {
+	bwe, err := NewSendSideBWE(SendSideBWEPacer(NewNoOpPacer()), SendSideBWEMaxBitrate(1_000_000))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, bwe.Close())
+	}()
+
+	results := []interceptor.ProbeResult{}
+	bus := interceptor.NewEventBus()
+	bus.Subscribe(func(event interceptor.Event) {
+		if result, ok := event.(interceptor.ProbeResult); ok {
+			results = append(results, result)
+		}
+	})
+	bwe.SetEventBus(bus)
+
+	// The first probe ends with overuse, the second one reaches the max bitrate
+	for _, stats := range []DelayStats{
+		{Usage: usageNormal, State: stateIncrease, TargetBitrate: 600_000},
+		{Usage: usageNormal, State: stateIncrease, TargetBitrate: 700_000},
+		{Usage: usageOver, State: stateDecrease, TargetBitrate: 500_000},
+		{Usage: usageOver, State: stateDecrease, TargetBitrate: 400_000},
+		{Usage: usageNormal, State: stateIncrease, TargetBitrate: 800_000},
+		{Usage: usageNormal, State: stateIncrease, TargetBitrate: 1_000_000},
+		{Usage: usageNormal, State: stateIncrease, TargetBitrate: 1_000_000},
+		{Usage: usageOver, State: stateDecrease, TargetBitrate: 900_000},
+	} {
+		bwe.onDelayUpdate(stats)
+	}
+
+	require.Equal(t, []interceptor.ProbeResult{
+		{Bitrate: 700_000, Success: false},
+		{Bitrate: 1_000_000, Success: true},
+	}, results)
+}
+
+func TestSendSideBWE_ECN(t *testing.T) {
+	bwe, err := NewSendSideBWE(SendSideBWEPacer(NewNoOpPacer()), SendSideBWEECN(interceptor.ECNECT1))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, bwe.Close())
+	}()
+
+	marks := make(chan interceptor.ECN, 2)
+	writer := bwe.AddStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(_ *rtp.Header, _ []byte, attributes interceptor.Attributes) (int, error) {
+			ecn, ok := attributes.GetECN()
+			require.True(t, ok)
+			marks <- ecn
+
+			return 0, nil
+		},
+	))
+
+	_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: 0}, make([]byte, 100), nil)
+	require.NoError(t, err)
+	require.Equal(t, interceptor.ECNECT1, <-marks)
+
+	// A codepoint set by the application is kept
+	attributes := interceptor.Attributes{}
+	attributes.SetECN(interceptor.ECNNotECT)
+	_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: 1}, make([]byte, 100), attributes)
+	require.NoError(t, err)
+	require.Equal(t, interceptor.ECNNotECT, <-marks)
+}
+
+func TestSendSideBWE_PacketFeedbackMetadata(t *testing.T) {
+	bwe, err := NewSendSideBWE(SendSideBWEPacer(NewNoOpPacer()))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, bwe.Close())
+	}()
+
+	var feedback []PacketFeedback
+	bwe.OnPacketFeedback(func(f []PacketFeedback) {
+		feedback = f
+	})
+
+	writer := bwe.AddStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+			return 0, nil
+		},
+	))
+	for i := uint16(0); i < 2; i++ {
+		_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: i}, make([]byte, 100), interceptor.Attributes{
+			PacketMetadataAttributesKey: fmt.Sprintf("frame-%v", i),
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, bwe.WriteRTCP([]rtcp.Packet{&rtcp.CCFeedbackReport{
+		ReportBlocks: []rtcp.CCFeedbackReportBlock{{
+			MediaSSRC:     1,
+			BeginSequence: 0,
+			MetricBlocks: []rtcp.CCFeedbackMetricBlock{
+				{Received: true},
+				{Received: false},
+			},
+		}},
+	}}, nil))
+
+	require.Equal(t, 2, len(feedback))
+	require.Equal(t, "frame-0", feedback[0].Metadata)
+	require.False(t, feedback[0].Lost())
+	require.Equal(t, "frame-1", feedback[1].Metadata)
+	require.True(t, feedback[1].Lost())
+}
+
 func BenchmarkSendSideBWE_WriteRTCP(b *testing.B) {
 	numSequencesPerTwccReport := []int{10, 100, 500, 1000}
 
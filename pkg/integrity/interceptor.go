@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package integrity provides an interceptor validating the payload of received
+// RTP packets, so packets of a broken sender don't distort statistics.
+package integrity
+
+import (
+	"sync"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+// Validator checks a received RTP packet, e.g. with a depacketizer, and
+// returns false if it is invalid. The payload doesn't include padding.
+type Validator func(info *interceptor.StreamInfo, header *rtp.Header, payload []byte) bool
+
+// NewPeerConnectionCallback receives a new Interceptor for every PeerConnection.
+type NewPeerConnectionCallback func(id string, i *Interceptor)
+
+// InterceptorFactory is a interceptor.Factory for a integrity Interceptor.
+type InterceptorFactory struct {
+	opts              []Option
+	addPeerConnection NewPeerConnectionCallback
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{
+		opts:              opts,
+		addPeerConnection: nil,
+	}, nil
+}
+
+// OnNewPeerConnection sets the callback that is called when a new
+// PeerConnection is created.
+func (f *InterceptorFactory) OnNewPeerConnection(cb NewPeerConnectionCallback) {
+	f.addPeerConnection = cb
+}
+
+// NewInterceptor constructs a new Interceptor.
+func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	i := &Interceptor{
+		NoOp:             interceptor.NoOp{},
+		log:              logging.NewDefaultLoggerFactory().NewLogger("integrity"),
+		defaultValidator: nil,
+		dropInvalid:      false,
+		validators:       map[uint32]Validator{},
+		invalid:          map[uint32]uint64{},
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.addPeerConnection != nil {
+		f.addPeerConnection(id, i)
+	}
+
+	return i, nil
+}
+
+// Interceptor validates received RTP packets. Invalid packets are counted and
+// marked with interceptor.Attributes.MarkInvalid, or dropped if DropInvalid is
+// set. It must be registered before the interceptors computing statistics,
+// e.g. the report and stats interceptors, so they see the mark.
+type Interceptor struct {
+	interceptor.NoOp
+	log              logging.LeveledLogger
+	defaultValidator Validator
+	dropInvalid      bool
+
+	m          sync.Mutex
+	validators map[uint32]Validator
+	invalid    map[uint32]uint64
+}
+
+// SetValidator sets the Validator for the stream with the given SSRC, which
+// replaces the DefaultValidator. A nil Validator removes it again.
+func (i *Interceptor) SetValidator(ssrc uint32, v Validator) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if v == nil {
+		delete(i.validators, ssrc)
+
+		return
+	}
+	i.validators[ssrc] = v
+}
+
+// Invalid returns the number of invalid packets received on the stream with
+// the given SSRC.
+func (i *Interceptor) Invalid(ssrc uint32) uint64 {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	return i.invalid[ssrc]
+}
+
+func (i *Interceptor) validator(ssrc uint32) Validator {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if v, ok := i.validators[ssrc]; ok {
+		return v
+	}
+
+	return i.defaultValidator
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+// The returned method will be called once per rtp packet.
+func (i *Interceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		for {
+			n, attr, err := reader.Read(b, a)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			validate := i.validator(info.SSRC)
+			if validate == nil {
+				return n, attr, nil
+			}
+
+			if attr == nil {
+				attr = make(interceptor.Attributes)
+			}
+			header, err := attr.GetRTPHeader(b[:n])
+			if err != nil {
+				return 0, nil, err
+			}
+			if validate(info, header, payload(header, b[:n])) {
+				return n, attr, nil
+			}
+
+			i.m.Lock()
+			i.invalid[info.SSRC]++
+			i.m.Unlock()
+
+			if !i.dropInvalid {
+				attr.MarkInvalid()
+
+				return n, attr, nil
+			}
+			i.log.Debugf("dropping invalid packet %d of stream %d", header.SequenceNumber, info.SSRC)
+			// The attributes of the dropped packet must not leak into the next one
+			a = interceptor.Attributes{}
+		}
+	})
+}
+
+// UnbindRemoteStream removes the counter and Validator of the stream.
+func (i *Interceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	delete(i.invalid, info.SSRC)
+	delete(i.validators, info.SSRC)
+}
+
+// payload returns the payload of the packet in raw without padding.
+func payload(header *rtp.Header, raw []byte) []byte {
+	end := len(raw)
+	if header.Padding && end > 0 {
+		end -= int(raw[end-1])
+	}
+	start := header.MarshalSize()
+	if start > end {
+		return nil
+	}
+
+	return raw[start:end]
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package integrity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/mock"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nonEmpty(_ *interceptor.StreamInfo, _ *rtp.Header, payload []byte) bool {
+	return len(payload) > 0
+}
+
+// newTestInterceptor returns an Interceptor and a stream, followed by an
+// interceptor reporting whether the packets it reads are marked as invalid.
+func newTestInterceptor(t *testing.T, opts ...Option) (*Interceptor, *test.MockStream, chan bool) {
+	t.Helper()
+
+	factory, err := NewInterceptor(opts...)
+	require.NoError(t, err)
+
+	var integrity *Interceptor
+	factory.OnNewPeerConnection(func(_ string, i *Interceptor) {
+		integrity = i
+	})
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+	require.Equal(t, integrity, i)
+
+	invalid := make(chan bool, 10)
+	probe := &mock.Interceptor{
+		BindRemoteStreamFn: func(_ *interceptor.StreamInfo, reader interceptor.RTPReader) interceptor.RTPReader {
+			return interceptor.RTPReaderFunc(
+				func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+					n, attr, err := reader.Read(b, a)
+					if err == nil {
+						invalid <- attr.IsInvalid()
+					}
+
+					return n, attr, err
+				},
+			)
+		},
+	}
+
+	chain := interceptor.NewChain([]interceptor.Interceptor{i, probe})
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, chain)
+	t.Cleanup(func() {
+		assert.NoError(t, stream.Close())
+	})
+
+	return integrity, stream, invalid
+}
+
+func readRTP(t *testing.T, stream *test.MockStream, invalid chan bool) (*rtp.Packet, bool) {
+	t.Helper()
+
+	select {
+	case r := <-stream.ReadRTP():
+		assert.NoError(t, r.Err)
+
+		return r.Packet, <-invalid
+	case <-time.After(time.Second):
+		assert.FailNow(t, "receiver rtp packet not found")
+	}
+
+	return nil, false
+}
+
+func TestInterceptor_MarkInvalid(t *testing.T) {
+	integrity, stream, invalid := newTestInterceptor(t, DefaultValidator(nonEmpty))
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123, SequenceNumber: 1}, Payload: []byte{1}})
+	_, isInvalid := readRTP(t, stream, invalid)
+	assert.False(t, isInvalid)
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123, SequenceNumber: 2}})
+	pkt, isInvalid := readRTP(t, stream, invalid)
+	assert.Equal(t, uint16(2), pkt.SequenceNumber)
+	assert.True(t, isInvalid)
+
+	assert.Equal(t, uint64(1), integrity.Invalid(123))
+}
+
+func TestInterceptor_DropInvalid(t *testing.T) {
+	integrity, stream, invalid := newTestInterceptor(t, DropInvalid())
+
+	// Without a validator every packet is valid
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123, SequenceNumber: 1}})
+	pkt, _ := readRTP(t, stream, invalid)
+	assert.Equal(t, uint16(1), pkt.SequenceNumber)
+
+	integrity.SetValidator(123, nonEmpty)
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123, SequenceNumber: 2}})
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123, SequenceNumber: 3}, Payload: []byte{1}})
+
+	pkt, isInvalid := readRTP(t, stream, invalid)
+	assert.Equal(t, uint16(3), pkt.SequenceNumber)
+	assert.False(t, isInvalid)
+	assert.Equal(t, uint64(1), integrity.Invalid(123))
+}
+
+func TestPayload(t *testing.T) {
+	header := &rtp.Header{Padding: true}
+	raw := append(make([]byte, 12), 1, 2, 0, 2)
+	assert.Equal(t, []byte{1, 2}, payload(header, raw))
+
+	raw[len(raw)-1] = 20
+	assert.Nil(t, payload(header, raw))
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package integrity
+
+import (
+	"github.com/pion/logging"
+)
+
+// Option can be used to configure the Interceptor.
+type Option func(*Interceptor) error
+
+// Log sets a logger for the interceptor.
+func Log(log logging.LeveledLogger) Option {
+	return func(i *Interceptor) error {
+		i.log = log
+
+		return nil
+	}
+}
+
+// DefaultValidator sets the Validator used for streams without a Validator
+// registered with Interceptor.SetValidator.
+func DefaultValidator(v Validator) Option {
+	return func(i *Interceptor) error {
+		i.defaultValidator = v
+
+		return nil
+	}
+}
+
+// DropInvalid drops invalid packets instead of only marking them invalid.
+func DropInvalid() Option {
+	return func(i *Interceptor) error {
+		i.dropInvalid = true
+
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interop
+
+import (
+	"time"
+
+	"github.com/pion/interceptor/pkg/jitterbuffer"
+	"github.com/pion/interceptor/pkg/nack"
+)
+
+// Network describes the timing of the path to the remote, which the NACK
+// retransmissions and the jitter buffer are tuned for. The zero values keep
+// the defaults of the interceptors, which are tuned for round trip times
+// below 100 ms.
+type Network struct {
+	Name string
+	// NackInterval is the interval missing packets are checked for.
+	NackInterval time.Duration
+	// NackRetryInterval is the minimum time between two NACKs of the same
+	// packet, which should be a bit above the round trip time.
+	NackRetryInterval time.Duration
+	// MaxNacksPerPacket limits the NACKs of a missing packet.
+	MaxNacksPerPacket uint16
+	// JitterBufferMinPackets is the number of packets buffered before the
+	// playout starts.
+	JitterBufferMinPackets uint16
+	// JitterBufferTargetDelay is the time packets wait in the jitter buffer
+	// for retransmissions.
+	JitterBufferTargetDelay time.Duration
+}
+
+// LowRTT returns the network of the defaults, for round trip times below
+// 100 ms.
+func LowRTT() Network {
+	return Network{Name: "low-rtt"}
+}
+
+// HighRTT returns the network of satellite and intercontinental paths, with
+// round trip times of up to 600 ms. A missing packet is NACKed at most twice,
+// 800 ms apart so the retransmission of the first NACK can arrive, and the
+// jitter buffer holds packets for 1.5 s, long enough for the retransmission
+// of the second NACK.
+func HighRTT() Network {
+	return Network{
+		Name:                    "high-rtt",
+		NackInterval:            100 * time.Millisecond,
+		NackRetryInterval:       800 * time.Millisecond,
+		MaxNacksPerPacket:       2,
+		JitterBufferMinPackets:  150,
+		JitterBufferTargetDelay: 1500 * time.Millisecond,
+	}
+}
+
+// NetworkByName returns the built-in network with the given name.
+func NetworkByName(name string) (Network, bool) {
+	for _, network := range []Network{LowRTT(), HighRTT()} {
+		if network.Name == name {
+			return network, true
+		}
+	}
+
+	return Network{}, false
+}
+
+// nackGeneratorOptions returns the options configuring a NACK generator for
+// the network.
+func (n Network) nackGeneratorOptions() []nack.GeneratorOption {
+	opts := []nack.GeneratorOption{}
+	if n.NackInterval > 0 {
+		opts = append(opts, nack.GeneratorInterval(n.NackInterval))
+	}
+	if n.NackRetryInterval > 0 {
+		opts = append(opts, nack.GeneratorRetryInterval(n.NackRetryInterval))
+	}
+	if n.MaxNacksPerPacket > 0 {
+		opts = append(opts, nack.GeneratorMaxNacksPerPacket(n.MaxNacksPerPacket))
+	}
+
+	return opts
+}
+
+// JitterBufferOptions returns the options configuring a jitter buffer for the
+// network.
+func (n Network) JitterBufferOptions() []jitterbuffer.ReceiverInterceptorOption {
+	opts := []jitterbuffer.Option{}
+	if n.JitterBufferMinPackets > 0 {
+		opts = append(opts, jitterbuffer.WithMinimumPacketCount(n.JitterBufferMinPackets))
+	}
+	if n.JitterBufferTargetDelay > 0 {
+		opts = append(opts, jitterbuffer.WithTargetDelay(n.JitterBufferTargetDelay))
+	}
+	if len(opts) == 0 {
+		return []jitterbuffer.ReceiverInterceptorOption{}
+	}
+
+	return []jitterbuffer.ReceiverInterceptorOption{jitterbuffer.BufferOptions(opts...)}
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interop
+
+import (
+	"testing"
+
+	"github.com/pion/interceptor/pkg/jitterbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkByName(t *testing.T) {
+	network, ok := NetworkByName("high-rtt")
+	assert.True(t, ok)
+	assert.Equal(t, HighRTT(), network)
+
+	_, ok = NetworkByName("unknown")
+	assert.False(t, ok)
+}
+
+func TestNetworkOptions(t *testing.T) {
+	assert.Empty(t, LowRTT().JitterBufferOptions())
+	assert.Len(t, HighRTT().JitterBufferOptions(), 1)
+
+	// The network is selected per profile
+	profile := LegacySIPGateway().WithNetwork(HighRTT())
+	assert.Equal(t, HighRTT(), profile.Network)
+	assert.Equal(t, LowRTT(), LegacySIPGateway().Network)
+	assert.Len(t, profile.NackGeneratorOptions(), 4)
+	assert.Len(t, Chrome().WithNetwork(HighRTT()).NackGeneratorOptions(), 3)
+
+	factory, err := jitterbuffer.NewInterceptor(HighRTT().JitterBufferOptions()...)
+	require.NoError(t, err)
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+	assert.NoError(t, i.Close())
+}
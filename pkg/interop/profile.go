@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package interop collects the behaviors which depend on quirks of the remote
+// stack in profiles, instead of configuring each interceptor separately.
+package interop
+
+import (
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/nack"
+	"github.com/pion/interceptor/pkg/remb"
+	"github.com/pion/interceptor/pkg/report"
+	"github.com/pion/interceptor/pkg/rtcpaggregator"
+	"github.com/pion/interceptor/pkg/twcc"
+)
+
+// CongestionFeedback is the congestion control feedback preferred by a remote stack.
+type CongestionFeedback int
+
+const (
+	// FeedbackTWCC prefers transport wide congestion control feedback.
+	FeedbackTWCC CongestionFeedback = iota
+	// FeedbackREMB prefers receiver estimated maximum bitrate messages.
+	FeedbackREMB
+)
+
+// Profile describes the quirks of a remote stack.
+type Profile struct {
+	Name string
+
+	// NackBitmask packs subsequent missing packets into the bitmask of NACK
+	// pairs. Some stacks only read the packet ID of each pair.
+	NackBitmask bool
+	// ReducedSizeRTCP allows sending RTCP packets which don't start with a
+	// report as described in RFC 5506. Stacks not negotiating rtcp-rsize
+	// require compound packets.
+	ReducedSizeRTCP bool
+	// CongestionFeedback is the congestion control feedback to send.
+	CongestionFeedback CongestionFeedback
+	// Network is the timing of the path to the remote.
+	Network Network
+}
+
+// Chrome returns the profile of libwebrtc based browsers.
+func Chrome() Profile {
+	return Profile{
+		Name:               "chrome",
+		NackBitmask:        true,
+		ReducedSizeRTCP:    true,
+		CongestionFeedback: FeedbackTWCC,
+		Network:            LowRTT(),
+	}
+}
+
+// Firefox returns the profile of Firefox.
+func Firefox() Profile {
+	return Profile{
+		Name:               "firefox",
+		NackBitmask:        true,
+		ReducedSizeRTCP:    false,
+		CongestionFeedback: FeedbackTWCC,
+		Network:            LowRTT(),
+	}
+}
+
+// LegacySIPGateway returns a conservative profile for SIP gateways and other
+// older RTP stacks.
+func LegacySIPGateway() Profile {
+	return Profile{
+		Name:               "legacy-sip",
+		NackBitmask:        false,
+		ReducedSizeRTCP:    false,
+		CongestionFeedback: FeedbackREMB,
+		Network:            LowRTT(),
+	}
+}
+
+// ProfileByName returns the built-in profile with the given name.
+func ProfileByName(name string) (Profile, bool) {
+	for _, profile := range []Profile{Chrome(), Firefox(), LegacySIPGateway()} {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+
+	return Profile{}, false
+}
+
+// WithNetwork returns a copy of the profile for the network, e.g. HighRTT for
+// a remote behind a satellite link, as the network may differ for every
+// PeerConnection of a remote stack.
+func (p Profile) WithNetwork(network Network) Profile {
+	p.Network = network
+
+	return p
+}
+
+// NackGeneratorOptions returns the options configuring a NACK generator for the profile.
+func (p Profile) NackGeneratorOptions() []nack.GeneratorOption {
+	opts := p.Network.nackGeneratorOptions()
+	if !p.NackBitmask {
+		opts = append(opts, nack.GeneratorNoBitmask())
+	}
+
+	return opts
+}
+
+// RTCPAggregatorOptions returns the options configuring a RTCP aggregator for the profile.
+func (p Profile) RTCPAggregatorOptions() []rtcpaggregator.Option {
+	opts := []rtcpaggregator.Option{}
+	if !p.ReducedSizeRTCP {
+		opts = append(opts, rtcpaggregator.RequireCompound())
+	}
+
+	return opts
+}
+
+// Register adds the RTCP aggregator, NACK and report interceptors configured
+// for the profile to registry, and the TWCC or the REMB interceptors for the
+// preferred congestion feedback. The aggregator must
+// see the RTCP of all other interceptors, so Register must be called before
+// other interceptors are added.
+// The jitter buffer isn't added, as it changes how the remote streams are
+// read. It can be added with the options of Network.JitterBufferOptions.
+func (p Profile) Register(registry *interceptor.Registry) error {
+	aggregator, err := rtcpaggregator.NewInterceptor(p.RTCPAggregatorOptions()...)
+	if err != nil {
+		return err
+	}
+	registry.Add(aggregator)
+
+	generator, err := nack.NewGeneratorInterceptor(p.NackGeneratorOptions()...)
+	if err != nil {
+		return err
+	}
+	registry.Add(generator)
+
+	responder, err := nack.NewResponderInterceptor()
+	if err != nil {
+		return err
+	}
+	registry.Add(responder)
+
+	receiver, err := report.NewReceiverInterceptor()
+	if err != nil {
+		return err
+	}
+	registry.Add(receiver)
+
+	sender, err := report.NewSenderInterceptor()
+	if err != nil {
+		return err
+	}
+	registry.Add(sender)
+
+	if p.CongestionFeedback == FeedbackREMB {
+		rembReceiver, err := remb.NewReceiverInterceptor()
+		if err != nil {
+			return err
+		}
+		registry.Add(rembReceiver)
+
+		return nil
+	}
+
+	headerExtension, err := twcc.NewHeaderExtensionInterceptor()
+	if err != nil {
+		return err
+	}
+	registry.Add(headerExtension)
+
+	twccSender, err := twcc.NewSenderInterceptor()
+	if err != nil {
+		return err
+	}
+	registry.Add(twccSender)
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileByName(t *testing.T) {
+	profile, ok := ProfileByName("legacy-sip")
+	assert.True(t, ok)
+	assert.Equal(t, LegacySIPGateway(), profile)
+
+	_, ok = ProfileByName("unknown")
+	assert.False(t, ok)
+}
+
+func TestProfileOptions(t *testing.T) {
+	assert.Empty(t, Chrome().NackGeneratorOptions())
+	assert.Empty(t, Chrome().RTCPAggregatorOptions())
+	assert.Len(t, LegacySIPGateway().NackGeneratorOptions(), 1)
+	assert.Len(t, Firefox().RTCPAggregatorOptions(), 1)
+}
+
+func TestProfileRegister(t *testing.T) {
+	registry := &interceptor.Registry{}
+	require.NoError(t, LegacySIPGateway().Register(registry))
+
+	i, err := registry.Build("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// The legacy profile requires compound RTCP
+	pli := &rtcp.PictureLossIndication{MediaSSRC: 123}
+	assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{pli}))
+
+	pkts := <-stream.WrittenRTCP()
+	require.Len(t, pkts, 2)
+	assert.IsType(t, &rtcp.ReceiverReport{}, pkts[0])
+	assert.Equal(t, pli, pkts[1])
+}
+
+func TestProfileRegisterREMB(t *testing.T) {
+	registry := &interceptor.Registry{}
+	require.NoError(t, LegacySIPGateway().Register(registry))
+
+	i, err := registry.Build("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         123,
+		ClockRate:    90000,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "goog-remb"}},
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123}, Payload: make([]byte, 1000)})
+	<-stream.ReadRTP()
+
+	// The legacy profile prefers REMB, which is sent for the remote stream
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			for _, pkt := range pkts {
+				if remb, ok := pkt.(*rtcp.ReceiverEstimatedMaximumBitrate); ok {
+					assert.Equal(t, []uint32{123}, remb.SSRCs)
+
+					return
+				}
+			}
+		case <-timeout:
+			assert.FailNow(t, "no REMB written")
+		}
+	}
+}
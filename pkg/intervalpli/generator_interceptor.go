@@ -5,6 +5,7 @@ package intervalpli
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
@@ -38,9 +39,10 @@ type GeneratorInterceptor struct {
 	streams            sync.Map
 	immediatePLINeeded chan []uint32
 
-	log logging.LeveledLogger
-	m   sync.Mutex
-	wg  sync.WaitGroup
+	log    logging.LeveledLogger
+	events atomic.Pointer[interceptor.EventBus]
+	m      sync.Mutex
+	wg     sync.WaitGroup
 
 	close chan struct{}
 }
@@ -156,14 +158,33 @@ func (r *GeneratorInterceptor) writePLIs(rtcpWriter interceptor.RTCPWriter, ssrc
 	}
 
 	pkts := []rtcp.Packet{}
+	requireCompound := false
 
 	for _, ssrc := range ssrcs {
 		pkts = append(pkts, &rtcp.PictureLossIndication{MediaSSRC: ssrc})
+		value, _ := r.streams.Load(ssrc)
+		if compound, _ := value.(bool); compound {
+			requireCompound = true
+		}
+	}
+	if requireCompound {
+		pkts = interceptor.CompoundRTCP(pkts, 0)
 	}
 
 	if _, err := rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
 		r.log.Warnf("failed sending: %+v", err)
+
+		return
 	}
+	for _, ssrc := range ssrcs {
+		r.events.Load().Publish(interceptor.KeyframeRequested{SSRC: ssrc})
+	}
+}
+
+// SetEventBus sets the bus an interceptor.KeyframeRequested event is published
+// on for every PLI sent.
+func (r *GeneratorInterceptor) SetEventBus(bus *interceptor.EventBus) {
+	r.events.Store(bus)
 }
 
 // BindRemoteStream lets you modify any incoming RTP packets.
@@ -176,7 +197,7 @@ func (r *GeneratorInterceptor) BindRemoteStream(
 		return reader
 	}
 
-	r.streams.Store(info.SSRC, nil)
+	r.streams.Store(info.SSRC, info.RequireCompoundRTCP)
 	// New streams need to receive a PLI as soon as possible.
 	r.ForcePLI(info.SSRC)
 
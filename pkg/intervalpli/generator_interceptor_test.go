@@ -85,3 +85,60 @@ func TestPLIGeneratorInterceptor(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, &rtcp.PictureLossIndication{MediaSSRC: streamSSRC}, sr)
 }
+
+func TestPLIGeneratorInterceptor_EventBus(t *testing.T) {
+	generatorInterceptor, err := NewGeneratorInterceptor(
+		GeneratorLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	events := make(chan interceptor.Event, 1)
+	bus := interceptor.NewEventBus()
+	bus.Subscribe(func(event interceptor.Event) {
+		events <- event
+	})
+	generatorInterceptor.SetEventBus(bus)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         123456,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack", Parameter: "pli"}},
+	}, generatorInterceptor)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	<-stream.WrittenRTCP()
+	select {
+	case event := <-events:
+		assert.Equal(t, interceptor.KeyframeRequested{SSRC: 123456}, event)
+	case <-time.After(time.Second):
+		t.Fatal("no event published")
+	}
+}
+
+func TestPLIGeneratorInterceptor_RequireCompound(t *testing.T) {
+	generatorInterceptor, err := NewGeneratorInterceptor(
+		GeneratorInterval(time.Second*1),
+		GeneratorLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.Nil(t, err)
+
+	streamSSRC := uint32(123456)
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      streamSSRC,
+		ClockRate: 90000,
+		MimeType:  "video/h264",
+		RTCPFeedback: []interceptor.RTCPFeedback{
+			{Type: "nack", Parameter: "pli"},
+		},
+		RequireCompoundRTCP: true,
+	}, generatorInterceptor)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	assert.Equal(t, []rtcp.Packet{
+		&rtcp.ReceiverReport{},
+		&rtcp.PictureLossIndication{MediaSSRC: streamSSRC},
+	}, <-stream.WrittenRTCP())
+}
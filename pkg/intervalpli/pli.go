@@ -8,11 +8,5 @@ package intervalpli
 import "github.com/pion/interceptor"
 
 func streamSupportPli(info *interceptor.StreamInfo) bool {
-	for _, fb := range info.RTCPFeedback {
-		if fb.Type == "nack" && fb.Parameter == "pli" {
-			return true
-		}
-	}
-
-	return false
+	return info.SupportsPLI()
 }
@@ -8,7 +8,9 @@ package jitterbuffer
 import (
 	"errors"
 	"sync"
+	"time"
 
+	"github.com/pion/interceptor/internal/clockdrift"
 	"github.com/pion/rtp"
 )
 
@@ -42,6 +44,8 @@ const (
 	BufferUnderflow = "underflow"
 	// BufferOverflow is emitted when the buffer has exceeded its limit.
 	BufferOverflow = "overflow"
+	// PacketDiscarded is emitted when a packet arrives after its playout position.
+	PacketDiscarded = "discarded"
 )
 
 func (jbs State) String() string {
@@ -72,10 +76,22 @@ type JitterBuffer struct {
 	lastSequence  uint16
 	playoutHead   uint16
 	playoutReady  bool
-	state         State
-	stats         Stats
-	listeners     map[Event][]EventListener
-	mutex         sync.Mutex
+	// playoutAdvanced is set once the playout head is driven by sequence
+	// number, only then packets behind it can be considered too late.
+	playoutAdvanced bool
+	state           State
+	stats           Stats
+	listeners       map[Event][]EventListener
+	mutex           sync.Mutex
+
+	// clockRate enables the estimation of the sender's clock drift
+	clockRate uint32
+	drift     *clockdrift.Estimator
+	now       func() time.Time
+
+	// playout maps timestamps to playout times, see PlayoutTime
+	playout     *playoutClock
+	targetDelay time.Duration
 }
 
 // Stats Track interesting statistics for the life of this JitterBuffer
@@ -85,27 +101,35 @@ type JitterBuffer struct {
 //
 // underflowCount will provide the count of attempts to Pop an empty buffer
 // overflowCount will track the number of times the jitter buffer exceeds its limit.
+// discardedCount will track the number of packets which arrived too late for playout.
 type Stats struct {
 	outOfOrderCount uint32
 	underflowCount  uint32
 	overflowCount   uint32
+	discardedCount  uint32
 }
 
 // New will initialize a jitter buffer and its associated statistics.
 func New(opts ...Option) *JitterBuffer {
 	jb := &JitterBuffer{
 		state:         Buffering,
-		stats:         Stats{0, 0, 0},
+		stats:         Stats{0, 0, 0, 0},
 		minStartCount: 50,
 		overflowLen:   100,
 		packets:       NewQueue(),
 		listeners:     make(map[Event][]EventListener),
+		now:           time.Now,
 	}
 
 	for _, o := range opts {
 		o(jb)
 	}
 
+	if jb.clockRate != 0 {
+		jb.drift = clockdrift.NewEstimator(jb.clockRate)
+		jb.playout = &playoutClock{clockRate: float64(jb.clockRate)}
+	}
+
 	return jb
 }
 
@@ -117,6 +141,24 @@ func WithMinimumPacketCount(count uint16) Option {
 	}
 }
 
+// WithClockRate sets the clock rate of the buffered stream, which enables the
+// estimation of the drift between the sender's clock and the local clock, see
+// RTPDuration.
+func WithClockRate(clockRate uint32) Option {
+	return func(jb *JitterBuffer) {
+		jb.clockRate = clockRate
+	}
+}
+
+// WithTargetDelay sets the delay added to the earliest arrival times of the
+// packets to get their playout times, see PlayoutTime. It's the time packets
+// wait in the buffer for late and retransmitted packets.
+func WithTargetDelay(delay time.Duration) Option {
+	return func(jb *JitterBuffer) {
+		jb.targetDelay = delay
+	}
+}
+
 // Listen will register an event listener
 // The jitter buffer may emit events correspnding, interested listerns should
 // look at Event for available events.
@@ -139,6 +181,7 @@ func (jb *JitterBuffer) SetPlayoutHead(playoutHead uint16) {
 	defer jb.mutex.Unlock()
 
 	jb.playoutHead = playoutHead
+	jb.playoutAdvanced = true
 }
 
 func (jb *JitterBuffer) updateStats(lastPktSeqNo uint16) {
@@ -154,9 +197,22 @@ func (jb *JitterBuffer) updateStats(lastPktSeqNo uint16) {
 // the data so if the memory is expected to be reused, the caller should
 // take this in to account and pass a copy of the packet they wish to buffer.
 func (jb *JitterBuffer) Push(packet *rtp.Packet) {
+	jb.push(packet)
+}
+
+// push adds packet to the buffer. It returns false if the packet was
+// discarded because it arrived after its playout position.
+func (jb *JitterBuffer) push(packet *rtp.Packet) bool {
 	jb.mutex.Lock()
 	defer jb.mutex.Unlock()
 
+	if jb.playoutAdvanced && int16(packet.SequenceNumber-jb.playoutHead) < 0 { //nolint:gosec // G115
+		jb.stats.discardedCount++
+		jb.emit(PacketDiscarded)
+
+		return false
+	}
+
 	if jb.packets.Length() == 0 {
 		jb.emit(StartBuffering)
 	}
@@ -170,9 +226,16 @@ func (jb *JitterBuffer) Push(packet *rtp.Packet) {
 		jb.playoutHead = packet.SequenceNumber
 	}
 
+	if jb.drift != nil {
+		now := jb.now()
+		jb.drift.Update(now, packet.Timestamp)
+		jb.playout.update(now, packet.Timestamp, jb.drift.Drift())
+	}
 	jb.updateStats(packet.SequenceNumber)
 	jb.packets.Push(packet, packet.SequenceNumber)
 	jb.updateState()
+
+	return true
 }
 
 func (jb *JitterBuffer) emit(event Event) {
@@ -225,6 +288,7 @@ func (jb *JitterBuffer) Pop() (*rtp.Packet, error) {
 		return nil, err
 	}
 	jb.playoutHead = (jb.playoutHead + 1)
+	jb.playoutAdvanced = true
 	jb.updateState()
 
 	return packet, nil
@@ -245,6 +309,7 @@ func (jb *JitterBuffer) PopAtSequence(sq uint16) (*rtp.Packet, error) {
 		return nil, err
 	}
 	jb.playoutHead = (jb.playoutHead + 1)
+	jb.playoutAdvanced = true
 	jb.updateState()
 
 	return packet, nil
@@ -283,6 +348,31 @@ func (jb *JitterBuffer) PopAtTimestamp(ts uint32) (*rtp.Packet, error) {
 	return packet, nil
 }
 
+// ClockDrift returns the estimated drift of the sender's clock relative to the
+// local clock in parts per million, positive if the sender's clock runs faster.
+// It is 0 unless WithClockRate was set.
+func (jb *JitterBuffer) ClockDrift() float64 {
+	jb.mutex.Lock()
+	defer jb.mutex.Unlock()
+
+	if jb.drift == nil {
+		return 0
+	}
+
+	return jb.drift.Drift()
+}
+
+// RTPDuration returns the number of RTP timestamp units the sender's clock
+// advances while the local clock advances by d, corrected by the clock drift.
+// Advancing the timestamp passed to PopAtTimestamp by it keeps the fill level
+// of the buffer stable on long running streams, where popping by the nominal
+// clock rate slowly runs into underruns or overflows. It requires WithClockRate.
+func (jb *JitterBuffer) RTPDuration(d time.Duration) uint32 {
+	drift := jb.ClockDrift()
+
+	return uint32(d.Seconds() * float64(jb.clockRate) * (1 + drift/1e6)) //nolint:gosec // G115
+}
+
 // Clear will empty the buffer and optionally reset the state.
 func (jb *JitterBuffer) Clear(resetState bool) {
 	jb.mutex.Lock()
@@ -292,7 +382,12 @@ func (jb *JitterBuffer) Clear(resetState bool) {
 	if resetState {
 		jb.lastSequence = 0
 		jb.state = Buffering
-		jb.stats = Stats{0, 0, 0}
+		jb.stats = Stats{0, 0, 0, 0}
+		jb.playoutAdvanced = false
 		jb.minStartCount = 50
+		if jb.drift != nil {
+			jb.drift = clockdrift.NewEstimator(jb.clockRate)
+			jb.playout = &playoutClock{clockRate: float64(jb.clockRate)}
+		}
 	}
 }
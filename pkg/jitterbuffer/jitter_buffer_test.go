@@ -6,6 +6,7 @@ package jitterbuffer
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/assert"
@@ -263,6 +264,31 @@ func TestJitterBuffer(t *testing.T) {
 		}
 	})
 
+	t.Run("Discards packets behind the playout head", func(*testing.T) {
+		jb := New(WithMinimumPacketCount(1))
+		events := make([]Event, 0)
+		jb.Listen(PacketDiscarded, func(event Event, _ *JitterBuffer) {
+			events = append(events, event)
+		})
+
+		jb.Push(&rtp.Packet{Header: rtp.Header{SequenceNumber: 10, Timestamp: 510}, Payload: []byte{0x00}})
+		jb.Push(&rtp.Packet{Header: rtp.Header{SequenceNumber: 11, Timestamp: 511}, Payload: []byte{0x00}})
+		pkt, err := jb.Pop()
+		assert.NoError(err)
+		assert.Equal(pkt.SequenceNumber, uint16(10))
+
+		// Packets after the playout head are accepted, even when reordered
+		assert.True(jb.push(&rtp.Packet{Header: rtp.Header{SequenceNumber: 13, Timestamp: 513}}))
+		assert.True(jb.push(&rtp.Packet{Header: rtp.Header{SequenceNumber: 12, Timestamp: 512}}))
+
+		// Packets which were already played out are too late
+		assert.False(jb.push(&rtp.Packet{Header: rtp.Header{SequenceNumber: 9, Timestamp: 509}}))
+		assert.False(jb.push(&rtp.Packet{Header: rtp.Header{SequenceNumber: 10, Timestamp: 510}}))
+		assert.Equal(jb.stats.discardedCount, uint32(2))
+		assert.Equal(2, len(events))
+		assert.Equal(jb.packets.Length(), uint16(3))
+	})
+
 	t.Run("Allows clearing the buffer", func(*testing.T) {
 		jb := New()
 		jb.Clear(false)
@@ -278,4 +304,59 @@ func TestJitterBuffer(t *testing.T) {
 		assert.Equal(jb.stats.outOfOrderCount, uint32(0))
 		assert.Equal(jb.packets.Length(), uint16(0))
 	})
+	t.Run("Estimates the clock drift", func(*testing.T) {
+		jb := New(WithClockRate(48000))
+		assert.Equal(uint32(960), jb.RTPDuration(20*time.Millisecond))
+
+		// The sender's clock runs 500ppm slow, 50 packets per second for a minute
+		start := time.Unix(1000, 0)
+		for i := 0; i < 3000; i++ {
+			arrival := time.Duration(float64(i) * float64(20*time.Millisecond) / (1 - 500e-6))
+			jb.now = func() time.Time { return start.Add(arrival) }
+			//nolint:gosec // G115
+			jb.Push(&rtp.Packet{Header: rtp.Header{SequenceNumber: uint16(i), Timestamp: uint32(i * 960)}})
+			if jb.packets.Length() > 10 {
+				_, _ = jb.Pop()
+			}
+		}
+
+		assert.InDelta(-500, jb.ClockDrift(), 1)
+		assert.InDelta(float64(48000*(1-500e-6)), float64(jb.RTPDuration(time.Second)), 1)
+
+		jb.Clear(true)
+		assert.Zero(jb.ClockDrift())
+		assert.Zero(New().ClockDrift())
+	})
+	t.Run("Computes playout times", func(*testing.T) {
+		jb := New(WithClockRate(90000), WithTargetDelay(100*time.Millisecond))
+		_, ok := jb.PlayoutTime(0)
+		assert.False(ok)
+		_, ok = New().PlayoutTime(0)
+		assert.False(ok)
+
+		// 30 frames per second across the timestamp wrap around, every
+		// second packet with 30ms of network jitter
+		start := time.Unix(1000, 0)
+		timestamp := uint32(0xFFFFF000)
+		for i := 0; i < 300; i++ {
+			arrival := time.Duration(i) * time.Second / 30
+			if i%2 == 0 {
+				arrival += 30 * time.Millisecond
+			}
+			jb.now = func() time.Time { return start.Add(arrival) }
+			//nolint:gosec // G115
+			jb.Push(&rtp.Packet{Header: rtp.Header{SequenceNumber: uint16(i), Timestamp: timestamp + uint32(i*3000)}})
+		}
+
+		for _, i := range []int{0, 1, 150, 299, 330} {
+			playout, ok := jb.PlayoutTime(timestamp + uint32(i*3000)) //nolint:gosec // G115
+			assert.True(ok)
+			expected := start.Add(time.Duration(i)*time.Second/30 + 100*time.Millisecond)
+			assert.InDelta(0, playout.Sub(expected), float64(time.Millisecond))
+		}
+
+		jb.Clear(true)
+		_, ok = jb.PlayoutTime(0)
+		assert.False(ok)
+	})
 }
@@ -4,6 +4,8 @@
 package jitterbuffer
 
 import (
+	"time"
+
 	"github.com/pion/logging"
 )
 
@@ -18,3 +20,25 @@ func Log(log logging.LeveledLogger) ReceiverInterceptorOption {
 		return nil
 	}
 }
+
+// ReportInterval enables sending the metrics of the buffer to the remote in a
+// De-Jitter Buffer Metrics Block of RFC 7005, in a XR every interval. The
+// absolute maximum is reported as unavailable, since the buffer is limited by
+// the number of packets instead of time.
+func ReportInterval(interval time.Duration) ReceiverInterceptorOption {
+	return func(d *ReceiverInterceptor) error {
+		d.reportInterval = interval
+
+		return nil
+	}
+}
+
+// BufferOptions sets the options of the JitterBuffer of the interceptor, e.g.
+// WithMinimumPacketCount to buffer more packets before the playout starts.
+func BufferOptions(opts ...Option) ReceiverInterceptorOption {
+	return func(d *ReceiverInterceptor) error {
+		d.buffer = New(opts...)
+
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package jitterbuffer
+
+import (
+	"time"
+)
+
+// rebaseTimestamps is the distance of timestamps from the base after which
+// the base is moved, to stay far from the wrap around of their difference.
+const rebaseTimestamps = 1 << 30
+
+// playoutClock maps RTP timestamps to local times. Its base is the packet
+// which arrived with the least delay, so the mapped times are the earliest
+// arrival times without network jitter.
+type playoutClock struct {
+	clockRate float64
+	started   bool
+	arrival   time.Time
+	timestamp uint32
+}
+
+// update moves the base to the packet if it arrived earlier than mapped.
+// drift is the drift of the sender's clock in parts per million.
+func (c *playoutClock) update(arrival time.Time, timestamp uint32, drift float64) {
+	if !c.started {
+		c.started = true
+		c.arrival = arrival
+		c.timestamp = timestamp
+
+		return
+	}
+
+	mapped := c.time(timestamp, drift)
+	if arrival.Before(mapped) {
+		c.arrival = arrival
+		c.timestamp = timestamp
+
+		return
+	}
+	if diff := int32(timestamp - c.timestamp); diff > rebaseTimestamps || diff < -rebaseTimestamps { //nolint:gosec // G115
+		c.arrival = mapped
+		c.timestamp = timestamp
+	}
+}
+
+// time returns the local time of the timestamp.
+func (c *playoutClock) time(timestamp uint32, drift float64) time.Time {
+	diff := float64(int32(timestamp - c.timestamp)) //nolint:gosec // G115
+
+	return c.arrival.Add(time.Duration(diff / (c.clockRate * (1 + drift/1e6)) * float64(time.Second)))
+}
+
+// PlayoutTime returns the local time at which the media with the RTP
+// timestamp should be played out, so renderers can schedule frames without
+// tracking the arrival times themselves. It's the earliest arrival time of
+// the timestamp, derived from the packet which arrived with the least network
+// delay and corrected by the clock drift of the sender, plus the target delay
+// set with WithTargetDelay. It returns false if no packet was pushed yet. It
+// requires WithClockRate.
+func (jb *JitterBuffer) PlayoutTime(timestamp uint32) (time.Time, bool) {
+	jb.mutex.Lock()
+	defer jb.mutex.Unlock()
+
+	if jb.playout == nil || !jb.playout.started {
+		return time.Time{}, false
+	}
+
+	return jb.playout.time(timestamp, jb.drift.Drift()).Add(jb.targetDelay), true
+}
@@ -4,10 +4,14 @@
 package jitterbuffer
 
 import (
+	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/logging"
+	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
 
@@ -19,9 +23,12 @@ type InterceptorFactory struct {
 // NewInterceptor constructs a new ReceiverInterceptor.
 func (g *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
 	i := &ReceiverInterceptor{
-		close:  make(chan struct{}),
-		log:    logging.NewDefaultLoggerFactory().NewLogger("jitterbuffer"),
-		buffer: New(),
+		close:          make(chan struct{}),
+		log:            logging.NewDefaultLoggerFactory().NewLogger("jitterbuffer"),
+		buffer:         New(),
+		reportInterval: 0,
+		senderSSRC:     rand.Uint32(), // #nosec
+		delays:         newBufferDelays(),
 	}
 
 	for _, opt := range g.opts {
@@ -57,6 +64,12 @@ type ReceiverInterceptor struct {
 	wg     sync.WaitGroup
 	close  chan struct{}
 	log    logging.LeveledLogger
+
+	reportInterval time.Duration
+	senderSSRC     uint32
+	ssrc           uint32
+	delays         *bufferDelays
+	events         atomic.Pointer[interceptor.EventBus]
 }
 
 // NewInterceptor returns a new InterceptorFactory.
@@ -64,11 +77,76 @@ func NewInterceptor(opts ...ReceiverInterceptorOption) (*InterceptorFactory, err
 	return &InterceptorFactory{opts}, nil
 }
 
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (i *ReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if i.reportInterval <= 0 || i.isClosed() {
+		return writer
+	}
+
+	i.wg.Add(1)
+	go i.loop(writer)
+
+	return writer
+}
+
+func (i *ReceiverInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(i.reportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			i.m.Lock()
+			ssrc := i.ssrc
+			metrics := i.delays.metrics(ssrc)
+			i.m.Unlock()
+			if ssrc == 0 {
+				continue
+			}
+
+			xr := &rtcp.ExtendedReport{
+				SenderSSRC: i.senderSSRC,
+				Reports:    []rtcp.ReportBlock{metrics.Block()},
+			}
+			if _, err := rtcpWriter.Write([]rtcp.Packet{xr}, interceptor.Attributes{}); err != nil {
+				i.log.Warnf("failed sending de-jitter buffer metrics: %+v", err)
+			}
+		case <-i.close:
+			return
+		}
+	}
+}
+
+func (i *ReceiverInterceptor) isClosed() bool {
+	select {
+	case <-i.close:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetEventBus sets the bus an interceptor.PacketDiscarded event is published
+// on for every packet which arrived too late for playout.
+func (i *ReceiverInterceptor) SetEventBus(bus *interceptor.EventBus) {
+	i.events.Store(bus)
+}
+
 // BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
 // The returned method will be called once per rtp packet.
 func (i *ReceiverInterceptor) BindRemoteStream(
-	_ *interceptor.StreamInfo, reader interceptor.RTPReader,
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
 ) interceptor.RTPReader {
+	i.m.Lock()
+	i.ssrc = info.SSRC
+	i.m.Unlock()
+
 	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
 		buf := make([]byte, len(b))
 		n, attr, err := reader.Read(buf, a)
@@ -81,12 +159,20 @@ func (i *ReceiverInterceptor) BindRemoteStream(
 		}
 		i.m.Lock()
 		defer i.m.Unlock()
-		i.buffer.Push(packet)
+		if i.buffer.push(packet) {
+			i.delays.push(packet.SequenceNumber, time.Now())
+		} else {
+			i.events.Load().Publish(interceptor.PacketDiscarded{
+				SSRC:           packet.SSRC,
+				SequenceNumber: packet.SequenceNumber,
+			})
+		}
 		if i.buffer.state == Emitting {
 			newPkt, err := i.buffer.Pop()
 			if err != nil {
 				return 0, nil, err
 			}
+			i.delays.pop(newPkt.SequenceNumber, time.Now())
 			nlen, err := newPkt.MarshalTo(b)
 
 			return nlen, attr, err
@@ -98,10 +184,11 @@ func (i *ReceiverInterceptor) BindRemoteStream(
 
 // UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
 func (i *ReceiverInterceptor) UnbindRemoteStream(_ *interceptor.StreamInfo) {
-	defer i.wg.Wait()
 	i.m.Lock()
 	defer i.m.Unlock()
 	i.buffer.Clear(true)
+	i.ssrc = 0
+	i.delays = newBufferDelays()
 }
 
 // Close closes the interceptor.
@@ -110,6 +197,9 @@ func (i *ReceiverInterceptor) Close() error {
 	i.m.Lock()
 	defer i.m.Unlock()
 	i.buffer.Clear(true)
+	if !i.isClosed() {
+		close(i.close)
+	}
 
 	return nil
 }
@@ -96,3 +96,144 @@ func TestReceiverBuffersAndPlaysout(t *testing.T) {
 	err = testInterceptor.Close()
 	assert.NoError(t, err)
 }
+
+func TestReceiverInterceptor_PacketDiscarded(t *testing.T) {
+	factory, err := NewInterceptor()
+	assert.NoError(t, err)
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, testInterceptor.Close())
+	}()
+
+	bus := interceptor.NewEventBus()
+	var events []interceptor.Event
+	bus.Subscribe(func(event interceptor.Event) {
+		events = append(events, event)
+	})
+	publisher, ok := testInterceptor.(interceptor.EventPublisher)
+	assert.True(t, ok)
+	publisher.SetEventBus(bus)
+
+	// The last packet arrives after it was played out
+	var seqs []uint16
+	for s := 0; s < 60; s++ {
+		seqs = append(seqs, uint16(s)) //nolint:gosec // G115
+	}
+	seqs = append(seqs, 1)
+	reader := testInterceptor.BindRemoteStream(&interceptor.StreamInfo{SSRC: 123456, ClockRate: 90000},
+		interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			pkt := &rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: seqs[0]}}
+			seqs = seqs[1:]
+			n, err := pkt.MarshalTo(b)
+
+			return n, a, err
+		}),
+	)
+	for len(seqs) > 0 {
+		_, _, err := reader.Read(make([]byte, 1500), nil)
+		if err != nil {
+			assert.ErrorIs(t, err, ErrPopWhileBuffering)
+		}
+	}
+
+	assert.Equal(t, []interceptor.Event{
+		interceptor.PacketDiscarded{SSRC: 123456, SequenceNumber: 1},
+	}, events)
+}
+
+func TestReceiverInterceptor_DeJitterBufferMetrics(t *testing.T) {
+	factory, err := NewInterceptor(ReportInterval(50 * time.Millisecond))
+	assert.NoError(t, err)
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, testInterceptor)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// Playout starts with the 50th packet
+	for i := 0; i < 60; i++ {
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: uint16(i)}}) //nolint:gosec // G115
+	}
+	for i := 0; i < 11; i++ {
+		<-stream.ReadRTP()
+	}
+
+	metrics := DeJitterBufferMetrics{Nominal: MetricUnavailable}
+	timeout := time.After(time.Second)
+	for metrics.Nominal == MetricUnavailable {
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			assert.Len(t, pkts, 1)
+			xr, ok := pkts[0].(*rtcp.ExtendedReport)
+			assert.True(t, ok)
+			assert.Len(t, xr.Reports, 1)
+
+			// Parse the block as the remote would
+			raw, err := xr.Marshal()
+			assert.NoError(t, err)
+			received := &rtcp.ExtendedReport{}
+			assert.NoError(t, received.Unmarshal(raw))
+			block, ok := received.Reports[0].(*rtcp.UnknownReportBlock)
+			assert.True(t, ok)
+			metrics, ok = ParseDeJitterBufferMetrics(block)
+			assert.True(t, ok)
+			assert.Equal(t, uint32(123456), metrics.SSRC)
+			assert.Equal(t, MetricUnavailable, metrics.AbsoluteMaximum)
+		case <-timeout:
+			assert.FailNow(t, "no de-jitter buffer metrics received")
+		}
+	}
+	assert.LessOrEqual(t, metrics.Nominal, metrics.Maximum)
+}
+
+func TestDeJitterBufferMetrics(t *testing.T) {
+	delays := newBufferDelays()
+	assert.Equal(t, DeJitterBufferMetrics{
+		SSRC:            1,
+		Nominal:         MetricUnavailable,
+		Maximum:         MetricUnavailable,
+		AbsoluteMaximum: MetricUnavailable,
+	}, delays.metrics(1))
+
+	start := time.Unix(1000, 0)
+	delays.push(1, start)
+	delays.push(2, start.Add(10*time.Millisecond))
+	delays.push(3, start.Add(20*time.Millisecond))
+	delays.pop(1, start.Add(100*time.Millisecond))
+	delays.pop(2, start.Add(150*time.Millisecond))
+	delays.pop(3, start.Add(time.Minute*2))
+	metrics := delays.metrics(1)
+	assert.Equal(t, uint16((100+140+119980)/3), metrics.Nominal)
+	assert.Equal(t, MetricOverRange, metrics.Maximum)
+
+	block := metrics.Block()
+	assert.Equal(t, DeJitterBufferMetricsBlockType, block.BlockType)
+	// Interval metrics (I=10) of a fixed jitter buffer (C=1)
+	assert.Equal(t, rtcp.TypeSpecificField(0b10010000), block.TypeSpecific)
+	parsed, ok := ParseDeJitterBufferMetrics(block)
+	assert.True(t, ok)
+	assert.Equal(t, metrics, parsed)
+
+	_, ok = ParseDeJitterBufferMetrics(&rtcp.UnknownReportBlock{XRHeader: rtcp.XRHeader{BlockType: 42}})
+	assert.False(t, ok)
+}
+
+func TestReceiverInterceptor_BufferOptions(t *testing.T) {
+	factory, err := NewInterceptor(BufferOptions(WithMinimumPacketCount(2), WithTargetDelay(time.Second)))
+	assert.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+	receiver, ok := i.(*ReceiverInterceptor)
+	assert.True(t, ok)
+	assert.Equal(t, uint16(2), receiver.buffer.minStartCount)
+	assert.Equal(t, time.Second, receiver.buffer.targetDelay)
+	assert.NoError(t, i.Close())
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package jitterbuffer
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+const (
+	// DeJitterBufferMetricsBlockType is the block type of the De-Jitter Buffer
+	// Metrics Block of RFC 7005, which isn't parsed by pion/rtcp.
+	DeJitterBufferMetricsBlockType rtcp.BlockTypeType = 23
+
+	// MetricUnavailable is reported for a delay which isn't known.
+	MetricUnavailable uint16 = 0xFFFF
+	// MetricOverRange is reported for a delay which exceeds the range of the
+	// block.
+	MetricOverRange uint16 = 0xFFFE
+
+	// intervalMetric sets the interval metric flag (I) of the type specific
+	// byte to interval duration, and the jitter buffer configuration (C) to
+	// fixed, RFC 7005 section 3.1.
+	intervalMetric = 0b10<<6 | 0b01<<4
+
+	deJitterBufferMetricsLength = 12
+)
+
+// DeJitterBufferMetrics are the metrics of the De-Jitter Buffer Metrics Block
+// of RFC 7005, in milliseconds.
+type DeJitterBufferMetrics struct {
+	SSRC uint32
+	// Nominal is the average time packets spent in the buffer.
+	Nominal uint16
+	// Maximum is the longest time a packet spent in the buffer.
+	Maximum uint16
+	// AbsoluteMaximum is the longest time a packet can spend in the buffer.
+	AbsoluteMaximum uint16
+}
+
+// Block returns the metrics as XR report block.
+func (m DeJitterBufferMetrics) Block() *rtcp.UnknownReportBlock {
+	raw := make([]byte, deJitterBufferMetricsLength)
+	binary.BigEndian.PutUint32(raw, m.SSRC)
+	binary.BigEndian.PutUint16(raw[4:], m.Nominal)
+	binary.BigEndian.PutUint16(raw[6:], m.Maximum)
+	binary.BigEndian.PutUint16(raw[8:], m.AbsoluteMaximum)
+
+	return &rtcp.UnknownReportBlock{
+		XRHeader: rtcp.XRHeader{
+			BlockType:    DeJitterBufferMetricsBlockType,
+			TypeSpecific: intervalMetric,
+		},
+		Bytes: raw,
+	}
+}
+
+// ParseDeJitterBufferMetrics returns the metrics of a De-Jitter Buffer Metrics
+// Block received in a XR, which pion/rtcp unmarshals as unknown block.
+func ParseDeJitterBufferMetrics(block *rtcp.UnknownReportBlock) (DeJitterBufferMetrics, bool) {
+	if block.BlockType != DeJitterBufferMetricsBlockType || len(block.Bytes) < deJitterBufferMetricsLength {
+		return DeJitterBufferMetrics{}, false
+	}
+
+	return DeJitterBufferMetrics{
+		SSRC:            binary.BigEndian.Uint32(block.Bytes),
+		Nominal:         binary.BigEndian.Uint16(block.Bytes[4:]),
+		Maximum:         binary.BigEndian.Uint16(block.Bytes[6:]),
+		AbsoluteMaximum: binary.BigEndian.Uint16(block.Bytes[8:]),
+	}, true
+}
+
+// bufferDelays tracks the time packets spend in the buffer during a report
+// interval.
+type bufferDelays struct {
+	arrivals map[uint16]time.Time
+	sum      time.Duration
+	count    int
+	max      time.Duration
+}
+
+func newBufferDelays() *bufferDelays {
+	return &bufferDelays{arrivals: map[uint16]time.Time{}}
+}
+
+func (d *bufferDelays) push(seq uint16, now time.Time) {
+	d.arrivals[seq] = now
+}
+
+func (d *bufferDelays) pop(seq uint16, now time.Time) {
+	arrival, ok := d.arrivals[seq]
+	if !ok {
+		return
+	}
+	delete(d.arrivals, seq)
+
+	delay := now.Sub(arrival)
+	d.sum += delay
+	d.count++
+	if delay > d.max {
+		d.max = delay
+	}
+}
+
+// metrics returns the metrics of the interval and starts the next one.
+func (d *bufferDelays) metrics(ssrc uint32) DeJitterBufferMetrics {
+	metrics := DeJitterBufferMetrics{
+		SSRC:            ssrc,
+		Nominal:         MetricUnavailable,
+		Maximum:         MetricUnavailable,
+		AbsoluteMaximum: MetricUnavailable,
+	}
+	if d.count > 0 {
+		metrics.Nominal = toMetric(d.sum / time.Duration(d.count))
+		metrics.Maximum = toMetric(d.max)
+	}
+	d.sum, d.count, d.max = 0, 0, 0
+
+	return metrics
+}
+
+func toMetric(d time.Duration) uint16 {
+	ms := d.Milliseconds()
+	if ms >= int64(MetricOverRange) {
+		return MetricOverRange
+	}
+
+	return uint16(ms) //nolint:gosec // G115
+}
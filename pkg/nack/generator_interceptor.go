@@ -25,9 +25,17 @@ func (g *GeneratorInterceptorFactory) NewInterceptor(_ string) (interceptor.Inte
 		size:              512,
 		skipLastN:         0,
 		maxNacksPerPacket: 0,
+		noBitmask:         false,
 		interval:          time.Millisecond * 100,
+		retryInterval:     0,
+		adaptiveRetry:     false,
+		maxUsefulLatency:  0,
+		rtt:               &rttEstimator{},
+		onUnrecoverable:   nil,
 		receiveLogs:       map[uint32]*receiveLog{},
-		nackCountLogs:     map[uint32]map[uint16]uint16{},
+		preBound:          map[uint32]*receiveLog{},
+		requireCompound:   map[uint32]bool{},
+		nackLogs:          map[uint32]map[uint16]nackLog{},
 		close:             make(chan struct{}),
 		log:               logging.NewDefaultLoggerFactory().NewLogger("nack_generator"),
 	}
@@ -52,15 +60,23 @@ type GeneratorInterceptor struct {
 	size              uint16
 	skipLastN         uint16
 	maxNacksPerPacket uint16
+	noBitmask         bool
 	interval          time.Duration
+	retryInterval     time.Duration
+	adaptiveRetry     bool
+	maxUsefulLatency  time.Duration
+	rtt               *rttEstimator
+	onUnrecoverable   UnrecoverableCallback
 	m                 sync.Mutex
 	wg                sync.WaitGroup
 	close             chan struct{}
 	log               logging.LeveledLogger
-	nackCountLogs     map[uint32]map[uint16]uint16
+	nackLogs          map[uint32]map[uint16]nackLog
 
-	receiveLogs   map[uint32]*receiveLog
-	receiveLogsMu sync.Mutex
+	receiveLogs     map[uint32]*receiveLog
+	preBound        map[uint32]*receiveLog
+	requireCompound map[uint32]bool
+	receiveLogsMu   sync.Mutex
 }
 
 // NewGeneratorInterceptor returns a new GeneratorInterceptorFactory.
@@ -85,6 +101,27 @@ func (n *GeneratorInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) int
 	return writer
 }
 
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (n *GeneratorInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	if !n.adaptiveRetry && n.maxUsefulLatency == 0 {
+		return reader
+	}
+
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if rtt, ok := attr.GetRTT(); ok {
+			n.rtt.update(rtt)
+		}
+
+		return i, attr, nil
+	})
+}
+
 // BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
 // The returned method will be called once per rtp packet.
 func (n *GeneratorInterceptor) BindRemoteStream(
@@ -94,10 +131,17 @@ func (n *GeneratorInterceptor) BindRemoteStream(
 		return reader
 	}
 
-	// error is already checked in NewGeneratorInterceptor
-	receiveLog, _ := newReceiveLog(n.size)
 	n.receiveLogsMu.Lock()
+	receiveLog, ok := n.preBound[info.SSRC]
+	delete(n.preBound, info.SSRC)
+	if !ok {
+		// error is already checked in NewGeneratorInterceptor
+		receiveLog, _ = newReceiveLog(n.size)
+	}
 	n.receiveLogs[info.SSRC] = receiveLog
+	if info.RequireCompoundRTCP {
+		n.requireCompound[info.SSRC] = true
+	}
 	n.receiveLogsMu.Unlock()
 
 	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
@@ -119,10 +163,30 @@ func (n *GeneratorInterceptor) BindRemoteStream(
 	})
 }
 
+// PreBindLocalStream does nothing, the generator keeps no state of local
+// streams.
+func (n *GeneratorInterceptor) PreBindLocalStream(_ *interceptor.StreamInfo) {}
+
+// PreBindRemoteStream allocates the receive log of a RemoteStream, which is
+// expected to be bound with info soon.
+func (n *GeneratorInterceptor) PreBindRemoteStream(info *interceptor.StreamInfo) {
+	if !n.streamsFilter(info) {
+		return
+	}
+
+	// error is already checked in NewGeneratorInterceptor
+	receiveLog, _ := newReceiveLog(n.size)
+	n.receiveLogsMu.Lock()
+	n.preBound[info.SSRC] = receiveLog
+	n.receiveLogsMu.Unlock()
+}
+
 // UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
 func (n *GeneratorInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
 	n.receiveLogsMu.Lock()
 	delete(n.receiveLogs, info.SSRC)
+	delete(n.preBound, info.SSRC)
+	delete(n.requireCompound, info.SSRC)
 	n.receiveLogsMu.Unlock()
 }
 
@@ -150,39 +214,34 @@ func (n *GeneratorInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 	for {
 		select {
 		case <-ticker.C:
+			unrecoverable := map[uint32][]uint16{}
 			func() {
 				n.receiveLogsMu.Lock()
 				defer n.receiveLogsMu.Unlock()
 
+				now := time.Now()
 				for ssrc, receiveLog := range n.receiveLogs {
 					missing := receiveLog.missingSeqNumbers(n.skipLastN)
 
-					if len(missing) == 0 || n.nackCountLogs[ssrc] == nil {
-						n.nackCountLogs[ssrc] = map[uint16]uint16{}
+					if len(missing) == 0 || n.nackLogs[ssrc] == nil {
+						n.nackLogs[ssrc] = map[uint16]nackLog{}
 					}
 					if len(missing) == 0 {
 						continue
 					}
 
-					filteredMissing := []uint16{}
-					if n.maxNacksPerPacket > 0 {
-						for _, missingSeq := range missing {
-							if n.nackCountLogs[ssrc][missingSeq] < n.maxNacksPerPacket {
-								filteredMissing = append(filteredMissing, missingSeq)
-							}
-							n.nackCountLogs[ssrc][missingSeq]++
-						}
-					} else {
-						filteredMissing = missing
+					filteredMissing, lost := n.filterMissing(n.nackLogs[ssrc], missing, now)
+					if len(lost) > 0 {
+						unrecoverable[ssrc] = lost
 					}
 
 					nack := &rtcp.TransportLayerNack{
 						SenderSSRC: senderSSRC,
 						MediaSSRC:  ssrc,
-						Nacks:      rtcp.NackPairsFromSequenceNumbers(filteredMissing),
+						Nacks:      n.nackPairs(filteredMissing),
 					}
 
-					for nackSeq := range n.nackCountLogs[ssrc] {
+					for nackSeq := range n.nackLogs[ssrc] {
 						isMissing := false
 						for _, missingSeq := range missing {
 							if missingSeq == nackSeq {
@@ -192,7 +251,7 @@ func (n *GeneratorInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 							}
 						}
 						if !isMissing {
-							delete(n.nackCountLogs[ssrc], nackSeq)
+							delete(n.nackLogs[ssrc], nackSeq)
 						}
 					}
 
@@ -200,17 +259,112 @@ func (n *GeneratorInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 						continue
 					}
 
-					if _, err := rtcpWriter.Write([]rtcp.Packet{nack}, interceptor.Attributes{}); err != nil {
+					pkts := []rtcp.Packet{nack}
+					if n.requireCompound[ssrc] {
+						pkts = interceptor.CompoundRTCP(pkts, senderSSRC)
+					}
+					if _, err := rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
 						n.log.Warnf("failed sending nack: %+v", err)
 					}
 				}
 			}()
+			// The callback is called without holding the lock, so it may use the generator
+			for ssrc, seqs := range unrecoverable {
+				n.log.Debugf("%d packets of ssrc %d are unrecoverable", len(seqs), ssrc)
+				if n.onUnrecoverable != nil {
+					n.onUnrecoverable(ssrc, seqs)
+				}
+			}
 		case <-n.close:
 			return
 		}
 	}
 }
 
+// nackLog is the NACK history of a missing packet.
+type nackLog struct {
+	count uint16
+	last  time.Time
+	// unrecoverable is set once the packet was NACKed maxNacksPerPacket times
+	// or its retransmission could not arrive within maxUsefulLatency.
+	unrecoverable bool
+	// missingSince is when the packet was first detected as missing, if
+	// maxUsefulLatency is set.
+	missingSince time.Time
+}
+
+// currentRetryInterval returns the retry interval derived from the RTT if
+// GeneratorAdaptiveRetryInterval is set and the RTT was measured, and the
+// fixed retryInterval otherwise.
+func (n *GeneratorInterceptor) currentRetryInterval() time.Duration {
+	if !n.adaptiveRetry {
+		return n.retryInterval
+	}
+	if interval, ok := n.rtt.retryInterval(); ok {
+		return interval
+	}
+
+	return n.retryInterval
+}
+
+// filterMissing returns the missing sequence numbers which are NACKed now,
+// leaving out the packets which were already NACKed maxNacksPerPacket times,
+// or less than the retry interval ago. Only sent NACKs count towards
+// maxNacksPerPacket when a retry interval is set. It also returns the packets
+// which are declared unrecoverable now, as they are still missing when they
+// would be NACKed once more than maxNacksPerPacket, or their retransmission
+// would arrive after maxUsefulLatency.
+//
+//nolint:cyclop
+func (n *GeneratorInterceptor) filterMissing(
+	logs map[uint16]nackLog, missing []uint16, now time.Time,
+) (nacked []uint16, unrecoverable []uint16) {
+	retryInterval := n.currentRetryInterval()
+	if n.maxNacksPerPacket == 0 && retryInterval == 0 && n.maxUsefulLatency == 0 {
+		return missing, nil
+	}
+	var rtt time.Duration
+	if n.maxUsefulLatency > 0 {
+		rtt, _ = n.rtt.smoothed()
+	}
+
+	nacked = []uint16{}
+	for _, seq := range missing {
+		log := logs[seq]
+		if log.unrecoverable {
+			continue
+		}
+		if n.maxUsefulLatency > 0 {
+			if log.missingSince.IsZero() {
+				log.missingSince = now
+			}
+			if now.Add(rtt).Sub(log.missingSince) > n.maxUsefulLatency {
+				log.unrecoverable = true
+				unrecoverable = append(unrecoverable, seq)
+				logs[seq] = log
+
+				continue
+			}
+		}
+		if retryInterval > 0 && !log.last.IsZero() && now.Sub(log.last) < retryInterval {
+			logs[seq] = log
+
+			continue
+		}
+		if n.maxNacksPerPacket > 0 && log.count >= n.maxNacksPerPacket {
+			log.unrecoverable = true
+			unrecoverable = append(unrecoverable, seq)
+		} else {
+			log.count++
+			log.last = now
+			nacked = append(nacked, seq)
+		}
+		logs[seq] = log
+	}
+
+	return nacked, unrecoverable
+}
+
 func (n *GeneratorInterceptor) isClosed() bool {
 	select {
 	case <-n.close:
@@ -219,3 +373,16 @@ func (n *GeneratorInterceptor) isClosed() bool {
 		return false
 	}
 }
+
+func (n *GeneratorInterceptor) nackPairs(seqs []uint16) []rtcp.NackPair {
+	if !n.noBitmask {
+		return rtcp.NackPairsFromSequenceNumbers(seqs)
+	}
+
+	pairs := make([]rtcp.NackPair, 0, len(seqs))
+	for _, seq := range seqs {
+		pairs = append(pairs, rtcp.NackPair{PacketID: seq})
+	}
+
+	return pairs
+}
@@ -134,3 +134,296 @@ func TestGeneratorInterceptor_StreamFilter(t *testing.T) {
 		}
 	}
 }
+
+func TestGeneratorInterceptor_NoBitmask(t *testing.T) {
+	f, err := NewGeneratorInterceptor(
+		GeneratorSize(64),
+		GeneratorInterval(time.Millisecond*10),
+		GeneratorNoBitmask(),
+		GeneratorLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	for _, seqNum := range []uint16{10, 12, 14} {
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: seqNum}})
+		<-stream.ReadRTP()
+	}
+
+	// A nack might be sent before all packets were received
+	for {
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			p, ok := pkts[0].(*rtcp.TransportLayerNack)
+			assert.True(t, ok, "TransportLayerNack rtcp packet expected, found: %T", pkts[0])
+			if len(p.Nacks) < 2 {
+				continue
+			}
+			assert.Equal(t, []rtcp.NackPair{{PacketID: 11}, {PacketID: 13}}, p.Nacks)
+
+			return
+		case <-time.After(time.Second):
+			t.Fatal("written rtcp packet not found")
+		}
+	}
+}
+
+func TestGeneratorInterceptor_RequireCompound(t *testing.T) {
+	f, err := NewGeneratorInterceptor(
+		GeneratorSize(64),
+		GeneratorSkipLastN(1),
+		GeneratorInterval(time.Millisecond*10),
+		GeneratorLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:                1,
+		RTCPFeedback:        []interceptor.RTCPFeedback{{Type: "nack"}},
+		RequireCompoundRTCP: true,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	for _, seqNum := range []uint16{10, 12, 13} {
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: seqNum}})
+		<-stream.ReadRTP()
+	}
+
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.Len(t, pkts, 2)
+		rr, ok := pkts[0].(*rtcp.ReceiverReport)
+		assert.True(t, ok, "ReceiverReport rtcp packet expected, found: %T", pkts[0])
+		p, ok := pkts[1].(*rtcp.TransportLayerNack)
+		assert.True(t, ok, "TransportLayerNack rtcp packet expected, found: %T", pkts[1])
+		assert.Equal(t, p.SenderSSRC, rr.SSRC)
+		assert.Equal(t, []rtcp.NackPair{{PacketID: 11}}, p.Nacks)
+	case <-time.After(time.Second):
+		t.Fatal("written rtcp packet not found")
+	}
+}
+
+// FuzzNackPairs checks that the NACK pairs generated for a set of missing
+// sequence numbers cover exactly these sequence numbers.
+func FuzzNackPairs(f *testing.F) {
+	f.Add(uint16(0), []byte{0, 1, 16, 17}, false)
+	f.Add(uint16(65520), []byte{0, 0, 0, 39, 3}, false)
+	f.Add(uint16(10), []byte{1, 2, 3}, true)
+
+	f.Fuzz(func(t *testing.T, start uint16, data []byte, noBitmask bool) {
+		// Keep the sequence numbers within half the sequence number space
+		if len(data) > 800 {
+			data = data[:800]
+		}
+		seqs := make([]uint16, 0, len(data))
+		seq := start
+		for _, b := range data {
+			seq += uint16(b % 40)
+			seqs = append(seqs, seq)
+			seq++
+		}
+
+		generator := &GeneratorInterceptor{noBitmask: noBitmask}
+		covered := []uint16{}
+		for _, pair := range generator.nackPairs(seqs) {
+			if noBitmask {
+				assert.Zero(t, pair.LostPackets)
+			}
+			covered = append(covered, pair.PacketList()...)
+		}
+		assert.Equal(t, seqs, covered)
+	})
+}
+
+func TestGeneratorInterceptor_PreBind(t *testing.T) {
+	f, err := NewGeneratorInterceptor(
+		GeneratorLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	generator, ok := i.(*GeneratorInterceptor)
+	assert.True(t, ok)
+
+	info := &interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}
+	generator.PreBindRemoteStream(info)
+	preBound := generator.preBound[1]
+	assert.NotNil(t, preBound)
+
+	// Binding the stream uses the pre-bound receive log.
+	stream := test.NewMockStream(info, generator)
+	assert.Same(t, preBound, generator.receiveLogs[1])
+	assert.Empty(t, generator.preBound)
+	assert.NoError(t, stream.Close())
+
+	// Unbinding drops pre-bound state of streams which were never bound.
+	generator.PreBindRemoteStream(info)
+	generator.UnbindRemoteStream(info)
+	assert.Empty(t, generator.preBound)
+	assert.NoError(t, generator.Close())
+}
+
+func TestGeneratorInterceptor_RetryInterval(t *testing.T) {
+	generator := &GeneratorInterceptor{maxNacksPerPacket: 2, retryInterval: time.Second}
+	logs := map[uint16]nackLog{}
+	now := time.Now()
+
+	assert.Equal(t, []uint16{1, 2}, nacked(generator, logs, []uint16{1, 2}, now))
+
+	// Packets are NACKed again once the retry interval passed
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, []uint16{3}, nacked(generator, logs, []uint16{1, 2, 3}, now))
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, []uint16{1, 2}, nacked(generator, logs, []uint16{1, 2, 3}, now))
+
+	// Only sent NACKs count towards the maximum
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, []uint16{3}, nacked(generator, logs, []uint16{1, 2, 3}, now))
+	now = now.Add(time.Second)
+	filtered, unrecoverable := generator.filterMissing(logs, []uint16{1, 2, 3}, now)
+	assert.Empty(t, filtered)
+	assert.Equal(t, []uint16{1, 2, 3}, unrecoverable)
+
+	// Unrecoverable packets are only reported once
+	filtered, unrecoverable = generator.filterMissing(logs, []uint16{1, 2, 3}, now.Add(time.Second))
+	assert.Empty(t, filtered)
+	assert.Empty(t, unrecoverable)
+}
+
+func TestGeneratorInterceptor_MaxUsefulLatency(t *testing.T) {
+	generator := &GeneratorInterceptor{maxUsefulLatency: 200 * time.Millisecond, rtt: &rttEstimator{}}
+	generator.rtt.update(100 * time.Millisecond)
+	logs := map[uint16]nackLog{}
+	now := time.Now()
+
+	assert.Equal(t, []uint16{1}, nacked(generator, logs, []uint16{1}, now))
+	now = now.Add(50 * time.Millisecond)
+	assert.Equal(t, []uint16{1, 2}, nacked(generator, logs, []uint16{1, 2}, now))
+
+	// The retransmission of packet 1 would arrive 250ms after it went missing
+	now = now.Add(100 * time.Millisecond)
+	filtered, unrecoverable := generator.filterMissing(logs, []uint16{1, 2}, now)
+	assert.Equal(t, []uint16{2}, filtered)
+	assert.Equal(t, []uint16{1}, unrecoverable)
+
+	now = now.Add(100 * time.Millisecond)
+	filtered, unrecoverable = generator.filterMissing(logs, []uint16{1, 2}, now)
+	assert.Empty(t, filtered)
+	assert.Equal(t, []uint16{2}, unrecoverable)
+}
+
+// nacked returns the packets NACKed by filterMissing.
+func nacked(generator *GeneratorInterceptor, logs map[uint16]nackLog, missing []uint16, now time.Time) []uint16 {
+	filtered, _ := generator.filterMissing(logs, missing, now)
+
+	return filtered
+}
+
+func TestGeneratorInterceptor_AdaptiveRetryInterval(t *testing.T) {
+	f, err := NewGeneratorInterceptor(
+		GeneratorRetryInterval(time.Second),
+		GeneratorAdaptiveRetryInterval(),
+		GeneratorLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	generator, ok := i.(*GeneratorInterceptor)
+	assert.True(t, ok)
+
+	// The fixed retry interval is used until the RTT was measured
+	logs := map[uint16]nackLog{}
+	now := time.Now()
+	assert.Equal(t, []uint16{1}, nacked(generator, logs, []uint16{1}, now))
+	assert.Empty(t, nacked(generator, logs, []uint16{1}, now.Add(500*time.Millisecond)))
+
+	reader := generator.BindRTCPReader(interceptor.RTCPReaderFunc(
+		func(_ []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+			attr := interceptor.Attributes{}
+			attr.SetRTT(50 * time.Millisecond)
+
+			return 0, attr, nil
+		},
+	))
+	_, _, err = reader.Read(nil, nil)
+	assert.NoError(t, err)
+
+	// 50ms RTT plus four times its initial variation of 25ms
+	assert.Empty(t, nacked(generator, logs, []uint16{1}, now.Add(149*time.Millisecond)))
+	assert.Equal(t, []uint16{1}, nacked(generator, logs, []uint16{1}, now.Add(150*time.Millisecond)))
+	assert.NoError(t, generator.Close())
+}
+
+func TestGeneratorInterceptor_Unrecoverable(t *testing.T) {
+	unrecoverable := make(chan []uint16, 1)
+	f, err := NewGeneratorInterceptor(
+		GeneratorSize(64),
+		GeneratorInterval(time.Millisecond*10),
+		GeneratorMaxNacksPerPacket(2),
+		GeneratorOnUnrecoverable(func(ssrc uint32, seqs []uint16) {
+			assert.Equal(t, uint32(1), ssrc)
+			unrecoverable <- seqs
+		}),
+		GeneratorLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	for _, seqNum := range []uint16{10, 12} {
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: seqNum}})
+		<-stream.ReadRTP()
+	}
+
+	select {
+	case seqs := <-unrecoverable:
+		assert.Equal(t, []uint16{11}, seqs)
+	case <-time.After(time.Second):
+		t.Fatal("no unrecoverable packets reported")
+	}
+
+	// The packet was NACKed twice
+	nacks := 0
+	for {
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			nack, ok := pkts[0].(*rtcp.TransportLayerNack)
+			assert.True(t, ok)
+			assert.Equal(t, []rtcp.NackPair{{PacketID: 11}}, nack.Nacks)
+			nacks++
+		default:
+			assert.Equal(t, 2, nacks)
+
+			return
+		}
+	}
+}
@@ -35,7 +35,8 @@ func GeneratorSkipLastN(skipLastN uint16) GeneratorOption {
 }
 
 // GeneratorMaxNacksPerPacket sets the maximum number of NACKs sent per missing packet, e.g. if set to 2, a missing
-// packet will only be NACKed at most twice. If set to 0 (default), max number of NACKs is unlimited.
+// packet will only be NACKed at most twice. If set to 0 (default), max number of NACKs is unlimited. A packet still
+// missing when it would be NACKed again is declared unrecoverable, see GeneratorOnUnrecoverable.
 func GeneratorMaxNacksPerPacket(maxNacks uint16) GeneratorOption {
 	return func(r *GeneratorInterceptor) error {
 		r.maxNacksPerPacket = maxNacks
@@ -44,6 +45,78 @@ func GeneratorMaxNacksPerPacket(maxNacks uint16) GeneratorOption {
 	}
 }
 
+// UnrecoverableCallback receives the sequence numbers of the packets of the
+// stream with the SSRC which were declared unrecoverable.
+type UnrecoverableCallback func(ssrc uint32, seqs []uint16)
+
+// GeneratorOnUnrecoverable sets a callback which is called with the packets
+// which were NACKed the maximum number of times set with
+// GeneratorMaxNacksPerPacket and are still missing, or exceeded the latency
+// set with GeneratorMaxUsefulLatency, e.g. to stop waiting for them in the
+// jitter buffer. It is called from the loop sending the NACKs and
+// should return quickly.
+func GeneratorOnUnrecoverable(cb UnrecoverableCallback) GeneratorOption {
+	return func(r *GeneratorInterceptor) error {
+		r.onUnrecoverable = cb
+
+		return nil
+	}
+}
+
+// GeneratorRetryInterval sets the minimum time between two NACKs of the same
+// missing packet. By default a missing packet is NACKed again with every
+// interval, which on paths with a round trip time above the interval requests
+// packets again whose retransmission is still on the way. It should be a bit
+// above the round trip time.
+func GeneratorRetryInterval(interval time.Duration) GeneratorOption {
+	return func(r *GeneratorInterceptor) error {
+		r.retryInterval = interval
+
+		return nil
+	}
+}
+
+// GeneratorAdaptiveRetryInterval derives the minimum time between two NACKs of
+// the same missing packet from the round trip time, as the smoothed RTT plus
+// four times its variation, so a packet is only NACKed again once its
+// retransmission should have arrived. The RTT is read from the attributes of
+// the received RTCP packets, see interceptor.Attributes.SetRTT, so an
+// interceptor measuring it, e.g. the report SenderInterceptor, must be added
+// to the registry before the generator. Until the RTT was measured, the
+// interval set with GeneratorRetryInterval is used.
+func GeneratorAdaptiveRetryInterval() GeneratorOption {
+	return func(r *GeneratorInterceptor) error {
+		r.adaptiveRetry = true
+
+		return nil
+	}
+}
+
+// GeneratorMaxUsefulLatency sets the time after which a missing packet is no
+// longer of use to the receiver, e.g. the target delay of the jitter buffer.
+// Missing packets are not NACKed anymore once their retransmission could not
+// arrive within latency of the packet being detected as missing, taking the
+// smoothed round trip time into account if it is measured, see
+// GeneratorAdaptiveRetryInterval. Such packets are declared unrecoverable.
+func GeneratorMaxUsefulLatency(latency time.Duration) GeneratorOption {
+	return func(r *GeneratorInterceptor) error {
+		r.maxUsefulLatency = latency
+
+		return nil
+	}
+}
+
+// GeneratorNoBitmask sends one NACK pair per missing packet instead of packing
+// subsequent missing packets into the bitmask of a pair, for remote stacks
+// which ignore the bitmask.
+func GeneratorNoBitmask() GeneratorOption {
+	return func(r *GeneratorInterceptor) error {
+		r.noBitmask = true
+
+		return nil
+	}
+}
+
 // GeneratorLog sets a logger for the interceptor.
 func GeneratorLog(log logging.LeveledLogger) GeneratorOption {
 	return func(r *GeneratorInterceptor) error {
@@ -7,11 +7,5 @@ package nack
 import "github.com/pion/interceptor"
 
 func streamSupportNack(info *interceptor.StreamInfo) bool {
-	for _, fb := range info.RTCPFeedback {
-		if fb.Type == "nack" && fb.Parameter == "" {
-			return true
-		}
-	}
-
-	return false
+	return info.SupportsNack()
 }
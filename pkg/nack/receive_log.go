@@ -7,15 +7,15 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/pion/interceptor/internal/rtpbuffer"
+	"github.com/pion/interceptor/internal/sequencenumber"
 )
 
 type receiveLog struct {
-	packets         []uint64
-	size            uint16
-	end             uint16
-	started         bool
-	lastConsecutive uint16
+	packets []uint64
+	size    uint16
+	// seqnums holds the highest extended sequence number received.
+	seqnums         sequencenumber.Unroller
+	lastConsecutive uint64
 	m               sync.RWMutex
 }
 
@@ -45,36 +45,34 @@ func (s *receiveLog) add(seq uint16) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
-	if !s.started {
+	if !s.seqnums.Started() {
 		s.setReceived(seq)
-		s.end = seq
-		s.started = true
-		s.lastConsecutive = seq
+		s.lastConsecutive, _ = s.seqnums.Unroll(seq)
 
 		return
 	}
 
-	diff := seq - s.end
+	end := s.seqnums.Highest()
+	extended, ok := s.seqnums.Unroll(seq)
 	switch {
-	case diff == 0:
+	case !ok || extended == end:
+		// packets from before the first one are too old to be nacked
 		return
-	case diff < rtpbuffer.Uint16SizeHalf:
-		// this means a positive diff, in other words seq > end (with counting for rollovers)
-		for i := s.end + 1; i != seq; i++ {
+	case extended > end:
+		for i := end + 1; i != extended; i++ {
 			// clear packets between end and seq (these may contain packets from a "size" ago)
-			s.delReceived(i)
+			s.delReceived(uint16(i)) //nolint:gosec // G115
 		}
-		s.end = seq
 
-		if s.lastConsecutive+1 == seq {
-			s.lastConsecutive = seq
-		} else if seq-s.lastConsecutive > s.size {
-			s.lastConsecutive = seq - s.size
+		if s.lastConsecutive+1 == extended {
+			s.lastConsecutive = extended
+		} else if extended-s.lastConsecutive > uint64(s.size) {
+			s.lastConsecutive = extended - uint64(s.size)
 			s.fixLastConsecutive() // there might be valid packets at the beginning of the buffer now
 		}
-	case s.lastConsecutive+1 == seq:
-		// negative diff, seq < end (with counting for rollovers)
-		s.lastConsecutive = seq
+	case s.lastConsecutive+1 == extended:
+		// seq < end
+		s.lastConsecutive = extended
 		s.fixLastConsecutive() // there might be other valid packets after seq
 	}
 
@@ -85,12 +83,13 @@ func (s *receiveLog) get(seq uint16) bool {
 	s.m.RLock()
 	defer s.m.RUnlock()
 
-	diff := s.end - seq
-	if diff >= rtpbuffer.Uint16SizeHalf {
+	extended, ok := s.seqnums.Extend(seq)
+	end := s.seqnums.Highest()
+	if !ok || extended > end {
 		return false
 	}
 
-	if diff >= s.size {
+	if end-extended >= uint64(s.size) {
 		return false
 	}
 
@@ -101,16 +100,16 @@ func (s *receiveLog) missingSeqNumbers(skipLastN uint16) []uint16 {
 	s.m.RLock()
 	defer s.m.RUnlock()
 
-	until := s.end - skipLastN
-	if until-s.lastConsecutive >= rtpbuffer.Uint16SizeHalf {
-		// until < s.lastConsecutive (counting for rollover)
+	end := s.seqnums.Highest()
+	if end < uint64(skipLastN) || end-uint64(skipLastN) < s.lastConsecutive {
 		return nil
 	}
+	until := end - uint64(skipLastN)
 
 	missingPacketSeqNums := make([]uint16, 0)
 	for i := s.lastConsecutive + 1; i != until+1; i++ {
-		if !s.getReceived(i) {
-			missingPacketSeqNums = append(missingPacketSeqNums, i)
+		if !s.getReceived(uint16(i)) { //nolint:gosec // G115
+			missingPacketSeqNums = append(missingPacketSeqNums, uint16(i)) //nolint:gosec // G115
 		}
 	}
 
@@ -135,7 +134,8 @@ func (s *receiveLog) getReceived(seq uint16) bool {
 
 func (s *receiveLog) fixLastConsecutive() {
 	i := s.lastConsecutive + 1
-	for ; i != s.end+1 && s.getReceived(i); i++ { //nolint:revive
+	end := s.seqnums.Highest()
+	for ; i != end+1 && s.getReceived(uint16(i)); i++ { //nolint:revive,gosec // G115
 		// find all consecutive packets
 	}
 
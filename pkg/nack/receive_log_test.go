@@ -80,7 +80,7 @@ func TestReceivedBuffer(t *testing.T) {
 			}
 			assertLastConsecutive := func(lastConsecutive uint16) {
 				want := lastConsecutive + start
-				if rl.lastConsecutive != want {
+				if uint16(rl.lastConsecutive) != want { //nolint:gosec // G115
 					t.Errorf("invalid lastConsecutive want %d got %d", want, rl.lastConsecutive)
 				}
 			}
@@ -143,3 +143,22 @@ func TestReceivedBuffer(t *testing.T) {
 		})
 	}
 }
+
+func TestReceivedBufferBeforeFirst(t *testing.T) {
+	rl, err := newReceiveLog(128)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// A packet reordered before the first one across the wrap is no jump by
+	// a whole cycle, and too old to be nacked.
+	rl.add(1)
+	rl.add(65535)
+	rl.add(3)
+	if rl.get(65535) {
+		t.Errorf("packet found for 65535")
+	}
+	if missing := rl.missingSeqNumbers(0); !reflect.DeepEqual([]uint16{2}, missing) {
+		t.Errorf("missing want/got %v / %v", []uint16{2}, missing)
+	}
+}
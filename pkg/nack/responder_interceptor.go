@@ -4,7 +4,10 @@
 package nack
 
 import (
+	"encoding/binary"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/rtpbuffer"
@@ -13,18 +16,33 @@ import (
 	"github.com/pion/rtp"
 )
 
+// NewResponderCallback receives a new ResponderInterceptor for every PeerConnection.
+type NewResponderCallback func(id string, r *ResponderInterceptor)
+
 // ResponderInterceptorFactory is a interceptor.Factory for a ResponderInterceptor.
 type ResponderInterceptorFactory struct {
-	opts []ResponderOption
+	opts         []ResponderOption
+	addResponder NewResponderCallback
+}
+
+// OnNewPeerConnection sets the callback that is called when a new
+// PeerConnection is created, e.g. to read its RetransmissionStats.
+func (r *ResponderInterceptorFactory) OnNewPeerConnection(cb NewResponderCallback) {
+	r.addResponder = cb
 }
 
 // NewInterceptor constructs a new ResponderInterceptor.
-func (r *ResponderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+func (r *ResponderInterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
 	responderInterceptor := &ResponderInterceptor{
-		streamsFilter: streamSupportNack,
-		size:          1024,
-		log:           logging.NewDefaultLoggerFactory().NewLogger("nack_responder"),
-		streams:       map[uint32]*localStream{},
+		streamsFilter:   streamSupportNack,
+		size:            1024,
+		maxPacketAge:    0,
+		now:             time.Now,
+		log:             logging.NewDefaultLoggerFactory().NewLogger("nack_responder"),
+		streams:         map[uint32]*localStream{},
+		preBound:        map[uint32]*rtpbuffer.RTPBuffer{},
+		transport:       "",
+		retransmissions: &retransmissionCounter{},
 	}
 
 	for _, opt := range r.opts {
@@ -33,6 +51,15 @@ func (r *ResponderInterceptorFactory) NewInterceptor(_ string) (interceptor.Inte
 		}
 	}
 
+	if responderInterceptor.transport == "" {
+		// The coordinator is shared across PeerConnections, whose ids needn't
+		// be unique, so the responders sharing its state must be explicit
+		if responderInterceptor.coordinator != nil {
+			return nil, errMissingTransport
+		}
+		responderInterceptor.transport = id
+	}
+
 	if responderInterceptor.packetFactory == nil {
 		responderInterceptor.packetFactory = rtpbuffer.NewPacketFactoryCopy()
 	}
@@ -41,30 +68,53 @@ func (r *ResponderInterceptorFactory) NewInterceptor(_ string) (interceptor.Inte
 		return nil, err
 	}
 
+	if r.addResponder != nil {
+		r.addResponder(id, responderInterceptor)
+	}
+
 	return responderInterceptor, nil
 }
 
-// ResponderInterceptor responds to nack feedback messages.
+// ResponderInterceptor responds to nack feedback messages. If the stream has a
+// SSRCRetransmission, packets are retransmitted in RFC 4588 RTX format with
+// the payload type of StreamInfo.RetransmissionPayloadType.
 type ResponderInterceptor struct {
 	interceptor.NoOp
 	streamsFilter func(info *interceptor.StreamInfo) bool
 	size          uint16
+	maxPacketAge  time.Duration
+	now           func() time.Time
 	log           logging.LeveledLogger
 	packetFactory rtpbuffer.PacketFactory
+	packetStore   PacketStore
+	coordinator   *RetransmissionCoordinator
+	policy        RetransmissionPolicy
+	limiter       *retransmissionLimiter
+	transport     string
+
+	retransmissions *retransmissionCounter
+	onMissRate      MissRateCallback
+	events          atomic.Pointer[interceptor.EventBus]
 
 	streams   map[uint32]*localStream
+	preBound  map[uint32]*rtpbuffer.RTPBuffer
 	streamsMu sync.Mutex
 }
 
 type localStream struct {
+	info           *interceptor.StreamInfo
 	rtpBuffer      *rtpbuffer.RTPBuffer
 	rtpBufferMutex sync.RWMutex
 	rtpWriter      interceptor.RTPWriter
+	rtxSequencer   rtp.Sequencer
+	// sentAt holds the send times of the buffered packets by their index in
+	// rtpBuffer, if ResponderMaxPacketAge is set.
+	sentAt []time.Time
 }
 
 // NewResponderInterceptor returns a new ResponderInterceptorFactor.
 func NewResponderInterceptor(opts ...ResponderOption) (*ResponderInterceptorFactory, error) {
-	return &ResponderInterceptorFactory{opts}, nil
+	return &ResponderInterceptorFactory{opts: opts}, nil
 }
 
 // BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
@@ -105,13 +155,46 @@ func (n *ResponderInterceptor) BindLocalStream(
 		return writer
 	}
 
-	// error is already checked in NewGeneratorInterceptor
-	rtpBuffer, _ := rtpbuffer.NewRTPBuffer(n.size)
+	if n.packetStore != nil {
+		n.streamsMu.Lock()
+		n.streams[info.SSRC] = &localStream{
+			info:         info,
+			rtpWriter:    writer,
+			rtxSequencer: rtp.NewRandomSequencer(),
+		}
+		n.streamsMu.Unlock()
+
+		adder, ok := n.packetStore.(PacketAdder)
+		if !ok {
+			return writer
+		}
+
+		return interceptor.RTPWriterFunc(
+			func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+				if header.SSRC == info.SSRC {
+					adder.Add(info.SSRC, header, payload)
+				}
+
+				return writer.Write(header, payload, attributes)
+			},
+		)
+	}
+
+	n.streamsMu.Lock()
+	rtpBuffer, ok := n.preBound[info.SSRC]
+	delete(n.preBound, info.SSRC)
+	if !ok {
+		// error is already checked in NewGeneratorInterceptor
+		rtpBuffer, _ = rtpbuffer.NewRTPBuffer(n.size)
+	}
 	stream := &localStream{
+		info:      info,
 		rtpBuffer: rtpBuffer,
 		rtpWriter: writer,
 	}
-	n.streamsMu.Lock()
+	if n.maxPacketAge > 0 {
+		stream.sentAt = make([]time.Time, n.size)
+	}
 	n.streams[info.SSRC] = stream
 	n.streamsMu.Unlock()
 
@@ -122,7 +205,9 @@ func (n *ResponderInterceptor) BindLocalStream(
 				return writer.Write(header, payload, attributes)
 			}
 
-			pkt, err := n.packetFactory.NewPacket(header, payload, info.SSRCRetransmission, info.PayloadTypeRetransmission)
+			// Packets of payload types without RTX are retransmitted as is
+			rtxPayloadType, _ := info.RetransmissionPayloadType(header.PayloadType)
+			pkt, err := n.packetFactory.NewPacket(header, payload, info.SSRCRetransmission, rtxPayloadType)
 			if err != nil {
 				return 0, err
 			}
@@ -130,16 +215,38 @@ func (n *ResponderInterceptor) BindLocalStream(
 			defer stream.rtpBufferMutex.Unlock()
 
 			rtpBuffer.Add(pkt)
+			if stream.sentAt != nil {
+				stream.sentAt[header.SequenceNumber%n.size] = n.now()
+			}
 
 			return writer.Write(header, payload, attributes)
 		},
 	)
 }
 
+// PreBindLocalStream allocates the retransmission buffer of a LocalStream,
+// which is expected to be bound with info soon.
+func (n *ResponderInterceptor) PreBindLocalStream(info *interceptor.StreamInfo) {
+	if !n.streamsFilter(info) || n.packetStore != nil {
+		return
+	}
+
+	// error is already checked in NewGeneratorInterceptor
+	rtpBuffer, _ := rtpbuffer.NewRTPBuffer(n.size)
+	n.streamsMu.Lock()
+	n.preBound[info.SSRC] = rtpBuffer
+	n.streamsMu.Unlock()
+}
+
+// PreBindRemoteStream does nothing, the responder keeps no state of remote
+// streams.
+func (n *ResponderInterceptor) PreBindRemoteStream(_ *interceptor.StreamInfo) {}
+
 // UnbindLocalStream is called when the Stream is removed. It can be used to clean up any data related to that track.
 func (n *ResponderInterceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
 	n.streamsMu.Lock()
 	delete(n.streams, info.SSRC)
+	delete(n.preBound, info.SSRC)
 	n.streamsMu.Unlock()
 }
 
@@ -151,14 +258,36 @@ func (n *ResponderInterceptor) resendPackets(nack *rtcp.TransportLayerNack) {
 		return
 	}
 
+	if n.packetStore != nil {
+		n.resendStoredPackets(stream, nack)
+
+		return
+	}
+
 	for i := range nack.Nacks {
 		nack.Nacks[i].Range(func(seq uint16) bool {
 			stream.rtpBufferMutex.Lock()
 			defer stream.rtpBufferMutex.Unlock()
 
-			if p := stream.rtpBuffer.Get(seq); p != nil {
+			p := stream.rtpBuffer.Get(seq)
+			if p != nil && n.expired(stream, seq) {
+				n.log.Debugf("not resending expired packet %d of ssrc %d", seq, nack.MediaSSRC)
+				p.Release()
+
+				return true
+			}
+			n.countRetransmission(nack.MediaSSRC, p != nil)
+			if p != nil {
+				size := p.Header().MarshalSize() + len(p.Payload())
+				if !n.allowRetransmission(nack.MediaSSRC, seq) || !n.allowBitrate(nack.MediaSSRC, seq, size) {
+					p.Release()
+
+					return true
+				}
 				if _, err := stream.rtpWriter.Write(p.Header(), p.Payload(), interceptor.Attributes{}); err != nil {
 					n.log.Warnf("failed resending nacked packet: %+v", err)
+				} else {
+					n.recordRetransmission(nack.MediaSSRC, seq)
 				}
 				p.Release()
 			}
@@ -167,3 +296,150 @@ func (n *ResponderInterceptor) resendPackets(nack *rtcp.TransportLayerNack) {
 		})
 	}
 }
+
+// expired returns whether the buffered packet with seq was sent more than
+// maxPacketAge ago. It must be called with the rtpBufferMutex of the stream
+// held.
+func (n *ResponderInterceptor) expired(stream *localStream, seq uint16) bool {
+	if stream.sentAt == nil {
+		return false
+	}
+
+	return n.now().Sub(stream.sentAt[seq%n.size]) > n.maxPacketAge
+}
+
+func (n *ResponderInterceptor) resendStoredPackets(stream *localStream, nack *rtcp.TransportLayerNack) {
+	for i := range nack.Nacks {
+		nack.Nacks[i].Range(func(seq uint16) bool {
+			pkt := n.packetStore.Get(nack.MediaSSRC, seq)
+			n.countRetransmission(nack.MediaSSRC, pkt != nil)
+			if pkt == nil {
+				return true
+			}
+			if releaser, ok := n.packetStore.(PacketReleaser); ok {
+				defer releaser.Release(nack.MediaSSRC, pkt)
+			}
+			if !n.allowRetransmission(nack.MediaSSRC, seq) {
+				return true
+			}
+
+			payload, valid := unpaddedPayload(pkt)
+			if !valid {
+				n.log.Warnf("not resending packet %d of ssrc %d with invalid padding", seq, nack.MediaSSRC)
+
+				return true
+			}
+			header := &pkt.Header
+			rtxPayloadType, ok := stream.info.RetransmissionPayloadType(pkt.PayloadType)
+			if ok && stream.info.SSRCRetransmission != 0 {
+				header, payload = stream.rtxPacket(pkt, payload, rtxPayloadType)
+			} else if header.Padding {
+				// The padding is not retransmitted
+				unpadded := header.Clone()
+				unpadded.Padding = false
+				header = &unpadded
+			}
+			if !n.allowBitrate(nack.MediaSSRC, seq, header.MarshalSize()+len(payload)) {
+				return true
+			}
+			if _, err := stream.rtpWriter.Write(header, payload, interceptor.Attributes{}); err != nil {
+				n.log.Warnf("failed resending nacked packet: %+v", err)
+			} else {
+				n.recordRetransmission(nack.MediaSSRC, seq)
+			}
+
+			return true
+		})
+	}
+}
+
+// RetransmissionStats returns the number of NACKed sequence numbers of all
+// streams which were found or not found in the retransmission buffer.
+func (n *ResponderInterceptor) RetransmissionStats() RetransmissionStats {
+	return n.retransmissions.get()
+}
+
+func (n *ResponderInterceptor) countRetransmission(ssrc uint32, hit bool) {
+	window, exceeded := n.retransmissions.count(hit)
+	if !exceeded {
+		return
+	}
+
+	n.log.Warnf("nack miss rate %.2f exceeds threshold, %d of %d nacked packets not buffered (last ssrc %d)",
+		window.MissRate(), window.Misses, window.Hits+window.Misses, ssrc)
+	if n.onMissRate != nil {
+		n.onMissRate(window)
+	}
+	n.events.Load().Publish(interceptor.NACKStorm{SSRC: ssrc, Hits: window.Hits, Misses: window.Misses})
+}
+
+// SetEventBus sets the bus an interceptor.NACKStorm event is published on
+// whenever the miss rate set with ResponderMissRateWarning is exceeded.
+func (n *ResponderInterceptor) SetEventBus(bus *interceptor.EventBus) {
+	n.events.Store(bus)
+}
+
+// allowRetransmission reports whether the policy and the coordinator allow a
+// retransmission of the packet. The retransmission is only recorded with the
+// coordinator by recordRetransmission, once it was written, so packets dropped
+// by allowBitrate can be retransmitted on the next NACK.
+func (n *ResponderInterceptor) allowRetransmission(ssrc uint32, seq uint16) bool {
+	if n.policy != nil && !n.policy.Retransmittable(n.transport, ssrc, seq) {
+		return false
+	}
+
+	return n.coordinator == nil || n.coordinator.allow(n.transport, ssrc, seq)
+}
+
+func (n *ResponderInterceptor) recordRetransmission(ssrc uint32, seq uint16) {
+	if n.coordinator != nil {
+		n.coordinator.record(n.transport, ssrc, seq)
+	}
+}
+
+// allowBitrate reports whether a retransmission of size bytes is within the
+// limit set with ResponderMaxRetransmissionBitrate.
+func (n *ResponderInterceptor) allowBitrate(ssrc uint32, seq uint16, size int) bool {
+	if n.limiter == nil || n.limiter.allow(n.now(), size) {
+		return true
+	}
+	n.log.Debugf("not resending packet %d of ssrc %d, retransmission bitrate exceeded", seq, ssrc)
+
+	return false
+}
+
+// unpaddedPayload returns the payload of a packet from a PacketStore without
+// its padding, and false if the padding is invalid.
+func unpaddedPayload(pkt *rtp.Packet) ([]byte, bool) {
+	if !pkt.Padding || pkt.PaddingSize > 0 {
+		return pkt.Payload, true
+	}
+
+	// The padding is included in the payload
+	if len(pkt.Payload) == 0 {
+		return nil, false
+	}
+	size := int(pkt.Payload[len(pkt.Payload)-1])
+	if size == 0 || size > len(pkt.Payload) {
+		return nil, false
+	}
+
+	return pkt.Payload[:len(pkt.Payload)-size], true
+}
+
+// rtxPacket converts a packet with the payload without padding to a RFC 4588
+// retransmission packet with the payload type, without modifying the
+// original.
+func (s *localStream) rtxPacket(pkt *rtp.Packet, payload []byte, payloadType uint8) (*rtp.Header, []byte) {
+	header := pkt.Header.Clone()
+	header.SSRC = s.info.SSRCRetransmission
+	header.PayloadType = payloadType
+	header.SequenceNumber = s.rtxSequencer.NextSequenceNumber()
+	header.Padding = false
+
+	rtxPayload := make([]byte, 2+len(payload))
+	binary.BigEndian.PutUint16(rtxPayload, pkt.SequenceNumber)
+	copy(rtxPayload[2:], payload)
+
+	return &header, rtxPayload
+}
@@ -325,6 +325,65 @@ func TestResponderInterceptor_RFC4588(t *testing.T) {
 	}
 }
 
+func TestResponderInterceptor_RFC4588PayloadTypes(t *testing.T) {
+	store := &mapPacketStore{packets: map[uint16]*rtp.Packet{}}
+	for _, opt := range []ResponderOption{ResponderSize(8), ResponderPacketStore(store)} {
+		f, err := NewResponderInterceptor(opt)
+		require.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		require.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:               1,
+			SSRCRetransmission: 2,
+			PayloadType:        96,
+			Codecs: []interceptor.Codec{
+				{PayloadType: 96, MimeType: "video/VP8", RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}}},
+				{PayloadType: 97, MimeType: "video/rtx", Fmtp: map[string]string{"apt": "96"}},
+				{PayloadType: 102, MimeType: "video/H264"},
+				{PayloadType: 103, MimeType: "video/rtx", Fmtp: map[string]string{"apt": "102"}},
+				{PayloadType: 104, MimeType: "video/AV1"},
+			},
+		}, i)
+
+		for k, payloadType := range []uint8{96, 102, 104} {
+			seqNum := uint16(10 + k) //nolint:gosec // G115
+			pkt := &rtp.Packet{Header: rtp.Header{SequenceNumber: seqNum, SSRC: 1, PayloadType: payloadType}}
+			store.packets[seqNum] = pkt
+			require.NoError(t, stream.WriteRTP(pkt))
+			<-stream.WrittenRTP()
+		}
+
+		stream.ReceiveRTCP([]rtcp.Packet{
+			&rtcp.TransportLayerNack{MediaSSRC: 1, Nacks: []rtcp.NackPair{{PacketID: 10, LostPackets: 0b11}}},
+		})
+
+		// Packets without a RTX payload type are retransmitted as is
+		expected := map[uint16]rtp.Header{
+			10: {SSRC: 2, PayloadType: 97},
+			11: {SSRC: 2, PayloadType: 103},
+			12: {SSRC: 1, PayloadType: 104},
+		}
+		for k := 0; k < 3; k++ {
+			select {
+			case p := <-stream.WrittenRTP():
+				seqNum := p.SequenceNumber
+				if p.SSRC == 2 {
+					seqNum = binary.BigEndian.Uint16(p.Payload)
+				}
+				require.Equal(t, expected[seqNum].SSRC, p.SSRC)
+				require.Equal(t, expected[seqNum].PayloadType, p.PayloadType)
+				delete(expected, seqNum)
+			case <-time.After(time.Second):
+				t.Fatal("written rtp packet not found")
+			}
+		}
+		require.Empty(t, expected)
+		require.NoError(t, stream.Close())
+	}
+}
+
 //nolint:cyclop
 func TestResponderInterceptor_BypassUnknownSSRCs(t *testing.T) {
 	f, err := NewResponderInterceptor(
@@ -397,3 +456,478 @@ func TestResponderInterceptor_BypassUnknownSSRCs(t *testing.T) {
 		}
 	}
 }
+
+type mapPacketStore struct {
+	mu      sync.Mutex
+	packets map[uint16]*rtp.Packet
+	gets    int
+}
+
+func (s *mapPacketStore) Get(ssrc uint32, seq uint16) *rtp.Packet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.gets++
+	if ssrc != 1 {
+		return nil
+	}
+
+	return s.packets[seq]
+}
+
+func TestResponderInterceptor_PacketStore(t *testing.T) {
+	store := &mapPacketStore{packets: map[uint16]*rtp.Packet{}}
+	for _, seqNum := range []uint16{11, 12, 15} {
+		store.packets[seqNum] = &rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: seqNum, SSRC: 1, PayloadType: 96},
+			Payload: []byte{0x01, 0x02},
+		}
+	}
+
+	f, err := NewResponderInterceptor(ResponderPacketStore(store))
+	require.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:                      1,
+		SSRCRetransmission:        2,
+		PayloadTypeRetransmission: 97,
+		RTCPFeedback:              []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	// Packets written to the stream are not buffered by the responder
+	require.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: 10, SSRC: 1}}))
+	<-stream.WrittenRTP()
+
+	stream.ReceiveRTCP([]rtcp.Packet{
+		&rtcp.TransportLayerNack{
+			MediaSSRC:  1,
+			SenderSSRC: 2,
+			Nacks: []rtcp.NackPair{
+				{PacketID: 10, LostPackets: 0b10111}, // sequence numbers: 10, 11, 12, 13, 15
+			},
+		},
+	})
+
+	for _, seqNum := range []uint16{11, 12, 15} {
+		select {
+		case p := <-stream.WrittenRTP():
+			require.Equal(t, uint32(2), p.SSRC)
+			require.Equal(t, uint8(97), p.PayloadType)
+			require.Equal(t, seqNum, binary.BigEndian.Uint16(p.Payload))
+			require.Equal(t, []byte{0x01, 0x02}, p.Payload[2:])
+		case <-time.After(10 * time.Millisecond):
+			t.Fatal("written rtp packet not found")
+		}
+	}
+
+	select {
+	case p := <-stream.WrittenRTP():
+		t.Errorf("no more rtp packets expected, found sequence number: %v", p.SequenceNumber)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	store.mu.Lock()
+	require.Equal(t, 5, store.gets)
+	store.mu.Unlock()
+
+	// The stored packets are not modified
+	require.Equal(t, uint32(1), store.packets[11].SSRC)
+	require.Equal(t, []byte{0x01, 0x02}, store.packets[11].Payload)
+}
+
+// managedPacketStore is a mapPacketStore filled by the responder, which
+// counts the released packets.
+type managedPacketStore struct {
+	mapPacketStore
+	releases int
+}
+
+func (s *managedPacketStore) Add(ssrc uint32, header *rtp.Header, payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ssrc == 1 {
+		s.packets[header.SequenceNumber] = &rtp.Packet{Header: header.Clone(), Payload: append([]byte{}, payload...)}
+	}
+}
+
+func (s *managedPacketStore) Release(_ uint32, _ *rtp.Packet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.releases++
+}
+
+func TestResponderInterceptor_ManagedPacketStore(t *testing.T) {
+	store := &managedPacketStore{mapPacketStore: mapPacketStore{packets: map[uint16]*rtp.Packet{}}}
+
+	f, err := NewResponderInterceptor(ResponderPacketStore(store))
+	require.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	// Packets written to the stream are added to the store
+	for _, seqNum := range []uint16{10, 11} {
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: seqNum, SSRC: 1},
+			Payload: []byte{0x01, 0x02},
+		}))
+		<-stream.WrittenRTP()
+	}
+	store.mu.Lock()
+	require.Len(t, store.packets, 2)
+	store.mu.Unlock()
+
+	stream.ReceiveRTCP([]rtcp.Packet{
+		&rtcp.TransportLayerNack{
+			MediaSSRC:  1,
+			SenderSSRC: 2,
+			Nacks:      []rtcp.NackPair{{PacketID: 10, LostPackets: 0b11}}, // sequence numbers: 10, 11, 12
+		},
+	})
+
+	for _, seqNum := range []uint16{10, 11} {
+		select {
+		case p := <-stream.WrittenRTP():
+			require.Equal(t, seqNum, p.SequenceNumber)
+			require.Equal(t, []byte{0x01, 0x02}, p.Payload)
+		case <-time.After(time.Second):
+			t.Fatal("written rtp packet not found")
+		}
+	}
+
+	// Every packet returned by Get is released
+	require.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		return store.gets == 3 && store.releases == 2
+	}, time.Second, time.Millisecond)
+}
+
+func TestResponderInterceptor_RetransmissionStats(t *testing.T) {
+	var windows []RetransmissionStats
+	var windowsMu sync.Mutex
+	f, err := NewResponderInterceptor(
+		ResponderSize(8),
+		ResponderMissRateWarning(0.5, 4, func(window RetransmissionStats) {
+			windowsMu.Lock()
+			defer windowsMu.Unlock()
+			windows = append(windows, window)
+		}),
+	)
+	require.NoError(t, err)
+
+	var responder *ResponderInterceptor
+	f.OnNewPeerConnection(func(_ string, r *ResponderInterceptor) {
+		responder = r
+	})
+
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+	require.NotNil(t, responder)
+	require.Equal(t, RetransmissionStats{}, responder.RetransmissionStats())
+	require.Zero(t, responder.RetransmissionStats().HitRatio())
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	for _, seqNum := range []uint16{10, 11, 12, 14, 15} {
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: seqNum, SSRC: 1}}))
+		<-stream.WrittenRTP()
+	}
+
+	// 11, 12, 15 are buffered, 13 was never sent
+	stream.ReceiveRTCP([]rtcp.Packet{
+		&rtcp.TransportLayerNack{
+			MediaSSRC: 1,
+			Nacks:     []rtcp.NackPair{{PacketID: 11, LostPackets: 0b1011}},
+		},
+	})
+	require.Eventually(t, func() bool {
+		return responder.RetransmissionStats() == RetransmissionStats{Hits: 3, Misses: 1}
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 0.75, responder.RetransmissionStats().HitRatio())
+
+	windowsMu.Lock()
+	require.Empty(t, windows)
+	windowsMu.Unlock()
+
+	// 1 to 3 were never sent and 14 is buffered
+	stream.ReceiveRTCP([]rtcp.Packet{
+		&rtcp.TransportLayerNack{
+			MediaSSRC: 1,
+			Nacks: []rtcp.NackPair{
+				{PacketID: 1, LostPackets: 0b11},
+				{PacketID: 14},
+			},
+		},
+	})
+	require.Eventually(t, func() bool {
+		return responder.RetransmissionStats() == RetransmissionStats{Hits: 4, Misses: 4}
+	}, time.Second, time.Millisecond)
+
+	windowsMu.Lock()
+	require.Equal(t, []RetransmissionStats{{Hits: 1, Misses: 3}}, windows)
+	windowsMu.Unlock()
+}
+
+func TestResponderInterceptor_InvalidMissRateWarning(t *testing.T) {
+	for _, opt := range []ResponderOption{
+		ResponderMissRateWarning(0, 10, nil),
+		ResponderMissRateWarning(1.5, 10, nil),
+		ResponderMissRateWarning(0.5, 0, nil),
+	} {
+		f, err := NewResponderInterceptor(opt)
+		require.NoError(t, err)
+
+		_, err = f.NewInterceptor("")
+		require.ErrorIs(t, err, errInvalidMissRateWarning)
+	}
+}
+
+func TestResponderInterceptor_PreBind(t *testing.T) {
+	f, err := NewResponderInterceptor(
+		ResponderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	require.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+	responder, ok := i.(*ResponderInterceptor)
+	require.True(t, ok)
+
+	info := &interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}
+	responder.PreBindLocalStream(info)
+	preBound := responder.preBound[1]
+	require.NotNil(t, preBound)
+
+	// Binding the stream uses the pre-bound retransmission buffer.
+	stream := test.NewMockStream(info, responder)
+	require.Same(t, preBound, responder.streams[1].rtpBuffer)
+	require.Empty(t, responder.preBound)
+	require.NoError(t, stream.Close())
+
+	// Unbinding drops pre-bound state of streams which were never bound.
+	responder.PreBindLocalStream(info)
+	responder.UnbindLocalStream(info)
+	require.Empty(t, responder.preBound)
+	require.NoError(t, responder.Close())
+}
+
+func TestResponderInterceptor_MaxPacketAge(t *testing.T) {
+	f, err := NewResponderInterceptor(
+		ResponderSize(8),
+		ResponderMaxPacketAge(500*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+	responder, ok := i.(*ResponderInterceptor)
+	require.True(t, ok)
+	clock := &test.MockTime{}
+	start := time.Now()
+	clock.SetNow(start)
+	responder.now = clock.Now
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	for _, seqNum := range []uint16{10, 11} {
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: seqNum, SSRC: 1}}))
+		<-stream.WrittenRTP()
+		clock.SetNow(start.Add(400 * time.Millisecond))
+	}
+
+	// Packet 10 was sent 600ms ago, packet 11 only 200ms ago
+	clock.SetNow(start.Add(600 * time.Millisecond))
+	stream.ReceiveRTCP([]rtcp.Packet{
+		&rtcp.TransportLayerNack{
+			MediaSSRC:  1,
+			SenderSSRC: 2,
+			Nacks:      []rtcp.NackPair{{PacketID: 10, LostPackets: 0b1}},
+		},
+	})
+
+	select {
+	case p := <-stream.WrittenRTP():
+		require.Equal(t, uint16(11), p.SequenceNumber)
+	case <-time.After(time.Second):
+		t.Fatal("written rtp packet not found")
+	}
+
+	select {
+	case p := <-stream.WrittenRTP():
+		t.Errorf("no more rtp packets expected, found sequence number: %v", p.SequenceNumber)
+	case <-time.After(10 * time.Millisecond):
+	}
+	require.Equal(t, RetransmissionStats{Hits: 1, Misses: 0}, responder.RetransmissionStats())
+}
+
+func TestResponderInterceptor_InvalidMaxPacketAge(t *testing.T) {
+	f, err := NewResponderInterceptor(ResponderMaxPacketAge(0))
+	require.NoError(t, err)
+
+	_, err = f.NewInterceptor("")
+	require.ErrorIs(t, err, errInvalidMaxPacketAge)
+}
+
+func TestResponderInterceptor_MaxRetransmissionBitrate(t *testing.T) {
+	f, err := NewResponderInterceptor(
+		ResponderSize(8),
+		ResponderMaxRetransmissionBitrate(8_000, 250),
+	)
+	require.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	for _, seqNum := range []uint16{10, 11, 12} {
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: seqNum, SSRC: 1},
+			Payload: make([]byte, 88),
+		}))
+		<-stream.WrittenRTP()
+	}
+
+	// Only two retransmissions of 100 bytes fit into the burst
+	stream.ReceiveRTCP([]rtcp.Packet{
+		&rtcp.TransportLayerNack{
+			MediaSSRC:  1,
+			SenderSSRC: 2,
+			Nacks:      []rtcp.NackPair{{PacketID: 10, LostPackets: 0b11}},
+		},
+	})
+
+	for _, seqNum := range []uint16{10, 11} {
+		select {
+		case p := <-stream.WrittenRTP():
+			require.Equal(t, seqNum, p.SequenceNumber)
+		case <-time.After(time.Second):
+			t.Fatal("written rtp packet not found")
+		}
+	}
+
+	select {
+	case p := <-stream.WrittenRTP():
+		t.Errorf("no more rtp packets expected, found sequence number: %v", p.SequenceNumber)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestResponderInterceptor_InvalidMaxRetransmissionBitrate(t *testing.T) {
+	for _, opt := range []ResponderOption{
+		ResponderMaxRetransmissionBitrate(0, 1000),
+		ResponderMaxRetransmissionBitrate(1000, 0),
+	} {
+		f, err := NewResponderInterceptor(opt)
+		require.NoError(t, err)
+
+		_, err = f.NewInterceptor("")
+		require.ErrorIs(t, err, errInvalidRetransmissionBitrate)
+	}
+}
+
+func TestResponderInterceptor_PacketStorePadding(t *testing.T) {
+	padded, err := (&rtp.Packet{
+		Header:      rtp.Header{SequenceNumber: 11, SSRC: 1, PayloadType: 96, Padding: true},
+		Payload:     []byte{0x01, 0xff},
+		PaddingSize: 4,
+	}).Marshal()
+	require.NoError(t, err)
+	unmarshaled := &rtp.Packet{}
+	require.NoError(t, unmarshaled.Unmarshal(padded))
+
+	store := &mapPacketStore{packets: map[uint16]*rtp.Packet{
+		11: unmarshaled,
+		// The last byte of the payload is no valid padding size
+		12: {
+			Header:  rtp.Header{SequenceNumber: 12, SSRC: 1, PayloadType: 96, Padding: true},
+			Payload: []byte{0x01, 0xff},
+		},
+	}}
+
+	for _, rtx := range []bool{false, true} {
+		f, err := NewResponderInterceptor(ResponderPacketStore(store))
+		require.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		require.NoError(t, err)
+
+		info := &interceptor.StreamInfo{
+			SSRC:         1,
+			RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+		}
+		if rtx {
+			info.SSRCRetransmission = 2
+			info.PayloadTypeRetransmission = 97
+		}
+		stream := test.NewMockStream(info, i)
+
+		stream.ReceiveRTCP([]rtcp.Packet{
+			&rtcp.TransportLayerNack{
+				MediaSSRC:  1,
+				SenderSSRC: 2,
+				Nacks:      []rtcp.NackPair{{PacketID: 11, LostPackets: 0b1}},
+			},
+		})
+
+		select {
+		case p := <-stream.WrittenRTP():
+			require.False(t, p.Padding)
+			if rtx {
+				require.Equal(t, uint32(2), p.SSRC)
+				require.Equal(t, []byte{0x00, 0x0b, 0x01, 0xff}, p.Payload)
+			} else {
+				require.Equal(t, uint16(11), p.SequenceNumber)
+				require.Equal(t, []byte{0x01, 0xff}, p.Payload)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("written rtp packet not found")
+		}
+
+		select {
+		case p := <-stream.WrittenRTP():
+			t.Errorf("no more rtp packets expected, found sequence number: %v", p.SequenceNumber)
+		case <-time.After(10 * time.Millisecond):
+		}
+		require.NoError(t, stream.Close())
+	}
+}
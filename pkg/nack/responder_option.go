@@ -4,9 +4,19 @@
 package nack
 
 import (
+	"errors"
+	"time"
+
 	"github.com/pion/interceptor"
 	"github.com/pion/interceptor/internal/rtpbuffer"
 	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+var (
+	errInvalidMaxPacketAge          = errors.New("max packet age must be positive")
+	errInvalidRetransmissionBitrate = errors.New("retransmission bitrate and burst must be positive")
+	errMissingTransport             = errors.New("a retransmission coordinator requires a responder transport")
 )
 
 // ResponderOption can be used to configure ResponderInterceptor.
@@ -31,6 +41,37 @@ func ResponderLog(log logging.LeveledLogger) ResponderOption {
 	}
 }
 
+// ResponderMaxPacketAge sets the age after which sent packets are no longer
+// retransmitted, even if they are still buffered, as they most likely arrive
+// too late for playback, e.g. 500ms. It is not used with a
+// ResponderPacketStore. NACKs of expired packets don't count towards the miss
+// rate.
+func ResponderMaxPacketAge(age time.Duration) ResponderOption {
+	return func(r *ResponderInterceptor) error {
+		if age <= 0 {
+			return errInvalidMaxPacketAge
+		}
+		r.maxPacketAge = age
+
+		return nil
+	}
+}
+
+// ResponderMaxRetransmissionBitrate limits the retransmissions of all streams
+// to bitrate bits per second, allowing bursts of up to burst bytes, so a burst
+// of NACKs e.g. after an outage doesn't congest the path even more.
+// Retransmissions exceeding the limit are dropped.
+func ResponderMaxRetransmissionBitrate(bitrate, burst int) ResponderOption {
+	return func(r *ResponderInterceptor) error {
+		if bitrate <= 0 || burst <= 0 {
+			return errInvalidRetransmissionBitrate
+		}
+		r.limiter = newRetransmissionLimiter(bitrate, burst)
+
+		return nil
+	}
+}
+
 // DisableCopy bypasses copy of underlying packets. It should be used when
 // you are not re-using underlying buffers of packets that have been written.
 func DisableCopy() ResponderOption {
@@ -41,6 +82,89 @@ func DisableCopy() ResponderOption {
 	}
 }
 
+// PacketStore provides the packets retransmitted by the ResponderInterceptor,
+// e.g. from a cache which is shared by all subscribers of a source in an SFU.
+// A PacketStore may also implement PacketAdder and PacketReleaser.
+type PacketStore interface {
+	// Get returns the packet of the local stream with ssrc and the sequence
+	// number seq, or nil if it is not available. The returned packet must not
+	// be modified while it is in use by the responder. As for packets returned
+	// by rtp.Packet.Unmarshal, Payload excludes the padding, which is
+	// PaddingSize bytes long. If Padding is set with a PaddingSize of 0, the
+	// padding is expected at the end of Payload, as written to a RTPWriter.
+	// Packets are retransmitted without their padding.
+	Get(ssrc uint32, seq uint16) *rtp.Packet
+}
+
+// PacketAdder is implemented by a PacketStore which is filled by the
+// responder with the packets sent on its local streams, instead of by the
+// application.
+type PacketAdder interface {
+	// Add stores a packet sent on the local stream with ssrc. The header and
+	// payload are only valid during the call and must be copied if kept.
+	Add(ssrc uint32, header *rtp.Header, payload []byte)
+}
+
+// PacketReleaser is implemented by a PacketStore which needs to know when the
+// responder is done with a packet returned by Get, e.g. to return it to a
+// pool.
+type PacketReleaser interface {
+	// Release is called once for every packet returned by Get, after it was
+	// retransmitted or dropped.
+	Release(ssrc uint32, pkt *rtp.Packet)
+}
+
+// ResponderPacketStore sets an external PacketStore. When set, the responder
+// doesn't buffer sent packets itself and the size set by ResponderSize is
+// not used. If the store is a PacketAdder, the responder adds the sent packets
+// to it.
+func ResponderPacketStore(store PacketStore) ResponderOption {
+	return func(r *ResponderInterceptor) error {
+		r.packetStore = store
+
+		return nil
+	}
+}
+
+// ResponderCoordinator sets a RetransmissionCoordinator which is shared with
+// other responders to avoid redundant retransmissions. The transport must be
+// set with ResponderTransport as well.
+func ResponderCoordinator(coordinator *RetransmissionCoordinator) ResponderOption {
+	return func(r *ResponderInterceptor) error {
+		r.coordinator = coordinator
+
+		return nil
+	}
+}
+
+// RetransmissionPolicy decides whether a NACKed packet is still worth
+// retransmitting, e.g. based on its age or the frame it belongs to.
+type RetransmissionPolicy interface {
+	Retransmittable(transport string, ssrc uint32, seq uint16) bool
+}
+
+// ResponderPolicy sets a RetransmissionPolicy which is asked before a NACKed
+// packet is retransmitted, either as is or as RTX packet.
+func ResponderPolicy(policy RetransmissionPolicy) ResponderOption {
+	return func(r *ResponderInterceptor) error {
+		r.policy = policy
+
+		return nil
+	}
+}
+
+// ResponderTransport sets the transport used by the RetransmissionCoordinator
+// and RetransmissionPolicy to group responders. It is required with a
+// RetransmissionCoordinator, without one it defaults to the id of the
+// PeerConnection.
+func ResponderTransport(transport string) ResponderOption {
+	return func(r *ResponderInterceptor) error {
+		r.transport = transport
+
+		return nil
+	}
+}
+
 // ResponderStreamsFilter sets filter for local streams.
 func ResponderStreamsFilter(filter func(info *interceptor.StreamInfo) bool) ResponderOption {
 	return func(r *ResponderInterceptor) error {
@@ -49,3 +173,19 @@ func ResponderStreamsFilter(filter func(info *interceptor.StreamInfo) bool) Resp
 		return nil
 	}
 }
+
+// ResponderMissRateWarning logs a warning and calls cb, if not nil, whenever
+// more than threshold of a window of NACKed sequence numbers weren't found in
+// the retransmission buffer, which indicates the buffer is too small.
+func ResponderMissRateWarning(threshold float64, window uint64, cb MissRateCallback) ResponderOption {
+	return func(r *ResponderInterceptor) error {
+		if threshold <= 0 || threshold > 1 || window == 0 {
+			return errInvalidMissRateWarning
+		}
+		r.retransmissions.threshold = threshold
+		r.retransmissions.windowSize = window
+		r.onMissRate = cb
+
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nack
+
+import (
+	"errors"
+	"sync"
+)
+
+var errInvalidMissRateWarning = errors.New("miss rate threshold must be in (0, 1] and the window must be positive")
+
+// RetransmissionStats holds the number of NACKed sequence numbers which were
+// found or not found in the retransmission buffer.
+type RetransmissionStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRatio returns the share of NACKed sequence numbers which were found in
+// the retransmission buffer, or 0 if nothing was NACKed yet.
+func (s RetransmissionStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// MissRate returns the share of NACKed sequence numbers which were not found
+// in the retransmission buffer, or 0 if nothing was NACKed yet.
+func (s RetransmissionStats) MissRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Misses) / float64(s.Hits+s.Misses)
+}
+
+// MissRateCallback is called when the miss rate of a window of NACKed
+// sequence numbers exceeded the threshold set by ResponderMissRateWarning.
+// The stats only cover that window.
+type MissRateCallback func(window RetransmissionStats)
+
+// retransmissionCounter counts buffer hits and misses in total and per
+// warning window.
+type retransmissionCounter struct {
+	mu     sync.Mutex
+	total  RetransmissionStats
+	window RetransmissionStats
+
+	// a threshold of 0 disables the warning
+	threshold  float64
+	windowSize uint64
+}
+
+// count records a lookup and returns the stats of the finished window, if the
+// lookup finished one with a miss rate above the threshold.
+func (c *retransmissionCounter) count(hit bool) (RetransmissionStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if hit {
+		c.total.Hits++
+		c.window.Hits++
+	} else {
+		c.total.Misses++
+		c.window.Misses++
+	}
+
+	if c.threshold == 0 || c.window.Hits+c.window.Misses < c.windowSize {
+		return RetransmissionStats{}, false
+	}
+
+	window := c.window
+	c.window = RetransmissionStats{}
+
+	return window, window.MissRate() > c.threshold
+}
+
+func (c *retransmissionCounter) get() RetransmissionStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.total
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nack
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+var errInvalidCoordinatorWindow = errors.New("coordinator window must be positive")
+
+// CoordinatorOption can be used to configure a RetransmissionCoordinator.
+type CoordinatorOption func(c *RetransmissionCoordinator) error
+
+// CoordinatorWindow sets for how long a retransmitted packet is not sent
+// again on the same transport. It should be about the round trip time, so a
+// receiver which still misses the packet afterwards can get it again.
+func CoordinatorWindow(window time.Duration) CoordinatorOption {
+	return func(c *RetransmissionCoordinator) error {
+		if window <= 0 {
+			return errInvalidCoordinatorWindow
+		}
+		c.window = window
+
+		return nil
+	}
+}
+
+// CoordinatorMaxRate limits the number of retransmissions per second on a
+// single transport. Retransmissions exceeding the limit are dropped. A rate
+// <= 0 disables the limit.
+func CoordinatorMaxRate(packetsPerSecond float64) CoordinatorOption {
+	return func(c *RetransmissionCoordinator) error {
+		c.maxRate = packetsPerSecond
+
+		return nil
+	}
+}
+
+// CoordinatorNow sets an alternative for the time.Now function.
+func CoordinatorNow(now func() time.Time) CoordinatorOption {
+	return func(c *RetransmissionCoordinator) error {
+		c.now = now
+
+		return nil
+	}
+}
+
+// RetransmissionCoordinator is shared by several ResponderInterceptors, e.g.
+// the outbound legs of an SFU forwarding the same source. When the same lost
+// packet is requested multiple times on a transport, it is only retransmitted
+// once per window, and the total retransmission rate of a transport can be
+// limited.
+//
+// The transport of a ResponderInterceptor must be set with ResponderTransport,
+// responders only share state if they use the same transport.
+type RetransmissionCoordinator struct {
+	window  time.Duration
+	maxRate float64
+	now     func() time.Time
+
+	mu          sync.Mutex
+	sent        map[retransmissionKey]time.Time
+	buckets     map[string]*retransmissionBucket
+	lastCleanup time.Time
+}
+
+type retransmissionKey struct {
+	transport string
+	ssrc      uint32
+	seq       uint16
+}
+
+// retransmissionBucket is a token bucket limiting the retransmission rate.
+type retransmissionBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewRetransmissionCoordinator returns a new RetransmissionCoordinator.
+func NewRetransmissionCoordinator(opts ...CoordinatorOption) (*RetransmissionCoordinator, error) {
+	coordinator := &RetransmissionCoordinator{
+		window:      100 * time.Millisecond,
+		maxRate:     0,
+		now:         time.Now,
+		sent:        map[retransmissionKey]time.Time{},
+		buckets:     map[string]*retransmissionBucket{},
+		lastCleanup: time.Time{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(coordinator); err != nil {
+			return nil, err
+		}
+	}
+
+	return coordinator, nil
+}
+
+// allow reports whether the packet may be retransmitted on the transport now.
+// The retransmission is only recorded with record, once it was written.
+func (c *RetransmissionCoordinator) allow(transport string, ssrc uint32, seq uint16) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.cleanup(now)
+
+	key := retransmissionKey{transport: transport, ssrc: ssrc, seq: seq}
+	if last, ok := c.sent[key]; ok && now.Sub(last) < c.window {
+		return false
+	}
+
+	return c.maxRate <= 0 || c.bucket(transport, now).tokens >= 1
+}
+
+// record records the retransmission of the packet on the transport.
+func (c *RetransmissionCoordinator) record(transport string, ssrc uint32, seq uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.maxRate > 0 {
+		c.bucket(transport, now).tokens--
+	}
+	c.sent[retransmissionKey{transport: transport, ssrc: ssrc, seq: seq}] = now
+}
+
+// bucket returns the refilled token bucket of the transport. It must be
+// called with c.mu held.
+func (c *RetransmissionCoordinator) bucket(transport string, now time.Time) *retransmissionBucket {
+	// Allow bursts of up to a tenth of a second worth of packets
+	burst := math.Max(1, c.maxRate/10)
+	bucket, ok := c.buckets[transport]
+	if !ok {
+		bucket = &retransmissionBucket{tokens: burst, lastRefill: now}
+		c.buckets[transport] = bucket
+	}
+	bucket.tokens = math.Min(burst, bucket.tokens+now.Sub(bucket.lastRefill).Seconds()*c.maxRate)
+	bucket.lastRefill = now
+
+	return bucket
+}
+
+// cleanup removes expired state, at most once per window. It must be called
+// with c.mu held.
+func (c *RetransmissionCoordinator) cleanup(now time.Time) {
+	if now.Sub(c.lastCleanup) < c.window {
+		return
+	}
+	c.lastCleanup = now
+
+	for key, last := range c.sent {
+		if now.Sub(last) >= c.window {
+			delete(c.sent, key)
+		}
+	}
+	// Buckets which were idle for a second are full again
+	for transport, bucket := range c.buckets {
+		if now.Sub(bucket.lastRefill) >= time.Second {
+			delete(c.buckets, transport)
+		}
+	}
+}
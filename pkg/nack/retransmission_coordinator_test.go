@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetransmissionCoordinator(t *testing.T) {
+	_, err := NewRetransmissionCoordinator(CoordinatorWindow(0))
+	assert.ErrorIs(t, err, errInvalidCoordinatorWindow)
+
+	t.Run("deduplicates per transport", func(t *testing.T) {
+		now := time.Now()
+		c, err := NewRetransmissionCoordinator(
+			CoordinatorWindow(100*time.Millisecond),
+			CoordinatorNow(func() time.Time { return now }),
+		)
+		require.NoError(t, err)
+
+		assert.True(t, retransmit(c, "a", 1, 10))
+		assert.False(t, retransmit(c, "a", 1, 10))
+		assert.True(t, retransmit(c, "a", 1, 11))
+		assert.True(t, retransmit(c, "a", 2, 10))
+		assert.True(t, retransmit(c, "b", 1, 10))
+
+		now = now.Add(50 * time.Millisecond)
+		assert.False(t, retransmit(c, "a", 1, 10))
+
+		now = now.Add(50 * time.Millisecond)
+		assert.True(t, retransmit(c, "a", 1, 10))
+		assert.Len(t, c.sent, 1)
+	})
+
+	t.Run("limits rate per transport", func(t *testing.T) {
+		now := time.Now()
+		c, err := NewRetransmissionCoordinator(
+			CoordinatorMaxRate(100),
+			CoordinatorNow(func() time.Time { return now }),
+		)
+		require.NoError(t, err)
+
+		allowed := 0
+		for seq := uint16(0); seq < 100; seq++ {
+			if retransmit(c, "a", 1, seq) {
+				allowed++
+			}
+		}
+		assert.Equal(t, 10, allowed)
+		assert.True(t, retransmit(c, "b", 1, 0))
+
+		// 100 packets per second refill a token every 10ms
+		now = now.Add(30 * time.Millisecond)
+		allowed = 0
+		for seq := uint16(100); seq < 200; seq++ {
+			if retransmit(c, "a", 1, seq) {
+				allowed++
+			}
+		}
+		assert.Equal(t, 3, allowed)
+
+		now = now.Add(2 * time.Second)
+		assert.True(t, retransmit(c, "a", 1, 200))
+		assert.Len(t, c.buckets, 1)
+	})
+}
+
+// retransmit records the retransmission of the packet if c allows it.
+func retransmit(c *RetransmissionCoordinator, transport string, ssrc uint32, seq uint16) bool {
+	if !c.allow(transport, ssrc, seq) {
+		return false
+	}
+	c.record(transport, ssrc, seq)
+
+	return true
+}
+
+func TestResponderInterceptor_Coordinator(t *testing.T) {
+	coordinator, err := NewRetransmissionCoordinator(CoordinatorWindow(time.Hour))
+	require.NoError(t, err)
+
+	f, err := NewResponderInterceptor(ResponderCoordinator(coordinator), ResponderTransport("relay"))
+	require.NoError(t, err)
+
+	streams := []*test.MockStream{}
+	for _, id := range []string{"subscriber-1", "subscriber-2"} {
+		i, err := f.NewInterceptor(id)
+		require.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:         1,
+			RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+		}, i)
+		defer func() {
+			require.NoError(t, stream.Close())
+		}()
+		streams = append(streams, stream)
+
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SequenceNumber: 10, SSRC: 1}}))
+		<-stream.WrittenRTP()
+	}
+
+	resent := 0
+	for _, stream := range streams {
+		stream.ReceiveRTCP([]rtcp.Packet{
+			&rtcp.TransportLayerNack{
+				MediaSSRC: 1,
+				Nacks:     []rtcp.NackPair{{PacketID: 10}},
+			},
+		})
+		select {
+		case p := <-stream.WrittenRTP():
+			require.Equal(t, uint16(10), p.SequenceNumber)
+			resent++
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	assert.Equal(t, 1, resent)
+}
+
+func TestResponderInterceptor_CoordinatorWithoutTransport(t *testing.T) {
+	coordinator, err := NewRetransmissionCoordinator()
+	require.NoError(t, err)
+
+	f, err := NewResponderInterceptor(ResponderCoordinator(coordinator))
+	require.NoError(t, err)
+
+	_, err = f.NewInterceptor("pc")
+	assert.ErrorIs(t, err, errMissingTransport)
+}
+
+func TestResponderInterceptor_CoordinatorBitrateLimit(t *testing.T) {
+	coordinator, err := NewRetransmissionCoordinator(CoordinatorWindow(time.Hour))
+	require.NoError(t, err)
+
+	f, err := NewResponderInterceptor(
+		ResponderCoordinator(coordinator),
+		ResponderTransport("relay"),
+		ResponderMaxRetransmissionBitrate(8_000, 100),
+	)
+	require.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, i)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	for _, seqNum := range []uint16{10, 11} {
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: seqNum, SSRC: 1},
+			Payload: make([]byte, 88),
+		}))
+		<-stream.WrittenRTP()
+	}
+
+	nack := func(seqNum uint16) {
+		stream.ReceiveRTCP([]rtcp.Packet{
+			&rtcp.TransportLayerNack{MediaSSRC: 1, Nacks: []rtcp.NackPair{{PacketID: seqNum}}},
+		})
+	}
+	expectResent := func(seqNum uint16) {
+		select {
+		case p := <-stream.WrittenRTP():
+			require.Equal(t, seqNum, p.SequenceNumber)
+		case <-time.After(time.Second):
+			t.Fatal("written rtp packet not found")
+		}
+	}
+
+	// Only one retransmission of 100 bytes fits into the burst
+	nack(10)
+	expectResent(10)
+	nack(11)
+	select {
+	case p := <-stream.WrittenRTP():
+		t.Fatalf("no rtp packet expected, found sequence number: %v", p.SequenceNumber)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The dropped packet wasn't recorded by the coordinator, so it is resent
+	// once the limit allows it again
+	time.Sleep(100 * time.Millisecond)
+	nack(11)
+	expectResent(11)
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nack
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// retransmissionLimiter is a token bucket limiting the bitrate of the
+// retransmissions of a responder. It holds up to burst bytes, which are
+// refilled at bitrate.
+type retransmissionLimiter struct {
+	bitrate int
+	burst   int
+
+	m          sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newRetransmissionLimiter(bitrate, burst int) *retransmissionLimiter {
+	return &retransmissionLimiter{
+		bitrate: bitrate,
+		burst:   burst,
+		tokens:  float64(burst),
+	}
+}
+
+// allow reports whether a retransmission of size bytes may be sent now and
+// takes its tokens if so.
+func (l *retransmissionLimiter) allow(now time.Time, size int) bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if !l.lastRefill.IsZero() {
+		refill := now.Sub(l.lastRefill).Seconds() * float64(l.bitrate) / 8
+		l.tokens = math.Min(float64(l.burst), l.tokens+refill)
+	}
+	l.lastRefill = now
+
+	if l.tokens < float64(size) {
+		return false
+	}
+	l.tokens -= float64(size)
+
+	return true
+}
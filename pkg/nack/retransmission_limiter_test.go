@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetransmissionLimiter(t *testing.T) {
+	limiter := newRetransmissionLimiter(80_000, 2500)
+	now := time.Now()
+
+	// The burst is available at once
+	assert.True(t, limiter.allow(now, 1200))
+	assert.True(t, limiter.allow(now, 1200))
+	assert.False(t, limiter.allow(now, 1200))
+
+	// 100ms refill 1000 bytes
+	now = now.Add(100 * time.Millisecond)
+	assert.True(t, limiter.allow(now, 1000))
+	assert.False(t, limiter.allow(now, 200))
+
+	// The bucket doesn't fill beyond the burst
+	now = now.Add(10 * time.Second)
+	assert.True(t, limiter.allow(now, 2500))
+	assert.False(t, limiter.allow(now, 1))
+}
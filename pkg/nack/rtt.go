@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nack
+
+import (
+	"sync"
+	"time"
+)
+
+// rttVarianceFactor weights the RTT variation in the retry interval, as in the
+// retransmission timeout of RFC 6298.
+const rttVarianceFactor = 4
+
+// rttEstimator smooths the RTT measurements of the connection as described in
+// RFC 6298 section 2.
+type rttEstimator struct {
+	m        sync.Mutex
+	srtt     time.Duration
+	rttvar   time.Duration
+	measured bool
+}
+
+func (e *rttEstimator) update(rtt time.Duration) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	if !e.measured {
+		e.srtt = rtt
+		e.rttvar = rtt / 2
+		e.measured = true
+
+		return
+	}
+
+	deviation := e.srtt - rtt
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	e.rttvar = (3*e.rttvar + deviation) / 4
+	e.srtt = (7*e.srtt + rtt) / 8
+}
+
+// retryInterval returns the time after which the retransmission of a NACKed
+// packet should have arrived, and false before the first RTT measurement.
+func (e *rttEstimator) retryInterval() (time.Duration, bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return e.srtt + rttVarianceFactor*e.rttvar, e.measured
+}
+
+// smoothed returns the smoothed RTT, and false before the first RTT
+// measurement.
+func (e *rttEstimator) smoothed() (time.Duration, bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return e.srtt, e.measured
+}
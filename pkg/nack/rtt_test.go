@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package nack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTTEstimator(t *testing.T) {
+	estimator := &rttEstimator{}
+	_, ok := estimator.retryInterval()
+	assert.False(t, ok)
+
+	estimator.update(100 * time.Millisecond)
+	interval, ok := estimator.retryInterval()
+	assert.True(t, ok)
+	assert.Equal(t, 300*time.Millisecond, interval)
+
+	// A stable RTT reduces the variation
+	for k := 0; k < 50; k++ {
+		estimator.update(100 * time.Millisecond)
+	}
+	interval, _ = estimator.retryInterval()
+	assert.InDelta(t, 100*time.Millisecond, interval, float64(time.Millisecond))
+
+	// A jump increases it again
+	estimator.update(200 * time.Millisecond)
+	interval, _ = estimator.retryInterval()
+	assert.Equal(t, 212500*time.Microsecond, interval.Round(100*time.Microsecond))
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package ntp provides conversion methods between time.Time and the NTP
+// timestamps used by RTCP, in the 64 bit format of sender reports and the 32
+// bit compact format of the LSR, DLSR and DLRR fields.
+package ntp
+
+import (
+	"time"
+)
+
+const (
+	// unixOffset is the number of seconds from 1st January 1900, the start of
+	// NTP era 0, to 1st January 1970.
+	unixOffset = 2208988800
+	// eraSeconds is the number of seconds of a NTP era.
+	eraSeconds = 1 << 32
+)
+
+// ToNTP converts a time.Time object to an uint64 NTP timestamp, rounded to
+// the nearest fraction. Times from 2036 on are in NTP era 1, whose timestamps
+// start from 0 again.
+func ToNTP(t time.Time) uint64 {
+	// higher 32 bits are the integer part, lower 32 bits are the fractional part
+	seconds := uint64(t.Unix() + unixOffset)             //nolint:gosec // G115
+	fraction := (uint64(t.Nanosecond())<<32 + 5e8) / 1e9 //nolint:gosec // G115
+
+	return seconds<<32 + fraction
+}
+
+// ToNTP32 converts a time.Time object to a uint32 NTP timestamp in the compact
+// format, the middle 32 bits of the 64 bit timestamp. It's truncated like the
+// LSR field of reports, so it equals the LSR of a sender report sent at t.
+func ToNTP32(t time.Time) uint32 {
+	return uint32(ToNTP(t) >> 16) //nolint:gosec // G115
+}
+
+// ToTime converts a uint64 NTP timestamps to a time.Time object, rounded to
+// the nearest nanosecond. Timestamps with the highest bit unset are taken to
+// be in NTP era 1, from 2036 on, as recommended by RFC 4330 section 3. The
+// timestamp 0 is used for unknown times and stays in era 0.
+func ToTime(t uint64) time.Time {
+	seconds := int64(t >> 32) //nolint:gosec // G115
+	if t != 0 && t&(1<<63) == 0 {
+		seconds += eraSeconds
+	}
+	nanoseconds := int64(((t&0xFFFFFFFF)*1e9 + 1<<31) >> 32) //nolint:gosec // G115
+
+	return time.Unix(seconds-unixOffset, nanoseconds)
+}
+
+// ToTime32 converts a uint32 NTP timestamp to a time.Time object, using the
+// highest 16 bits of the reference to recover the lost bits, choosing the time
+// closest to the reference. The low 16 bits are not recovered.
+func ToTime32(t uint32, reference time.Time) time.Time {
+	candidate := ToTime(ToNTP(reference)&0xFFFF000000000000 | uint64(t)<<16)
+	// the 16 bits wrap every 18 hours
+	const wrap = (1 << 16) * time.Second
+	switch diff := candidate.Sub(reference); {
+	case diff > wrap/2:
+		candidate = candidate.Add(-wrap)
+	case diff < -wrap/2:
+		candidate = candidate.Add(wrap)
+	}
+
+	return candidate
+}
+
+// ToDuration converts a duration in the compact NTP format, in units of 1/65536
+// seconds, like the DLSR and DLRR fields of RTCP reports, to a time.Duration.
+func ToDuration(d uint32) time.Duration {
+	return time.Duration((uint64(d)*uint64(time.Second) + 1<<15) >> 16) //nolint:gosec // G115
+}
+
+// FromDuration converts a time.Duration to the compact NTP format, rounded to
+// the nearest 1/65536 second. Negative durations are converted to 0, and
+// durations beyond the range of the format to its maximum.
+func FromDuration(d time.Duration) uint32 {
+	switch {
+	case d <= 0:
+		return 0
+	case d >= (1<<16)*time.Second:
+		return 0xFFFFFFFF
+	}
+	compact := (uint64(d)<<16 + uint64(time.Second)/2) / uint64(time.Second)
+	if compact > 0xFFFFFFFF {
+		return 0xFFFFFFFF
+	}
+
+	return uint32(compact)
+}
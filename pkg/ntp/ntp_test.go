@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package ntp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNTPToTimeConverstion(t *testing.T) {
+	for i, cc := range []struct {
+		ts time.Time
+	}{
+		{
+			ts: time.Now(),
+		},
+		{
+			ts: time.Unix(0, 0),
+		},
+		{
+			ts: time.Unix(1700000000, 999999999),
+		},
+		{
+			ts: time.Date(2036, time.February, 7, 6, 30, 0, 1, time.UTC),
+		},
+	} {
+		t.Run(fmt.Sprintf("TimeToNTP/%v", i), func(t *testing.T) {
+			// The fractions of NTP timestamps are finer than nanoseconds
+			assert.Equal(t, cc.ts.UnixNano(), ToTime(ToNTP(cc.ts)).UnixNano())
+			assert.InDelta(t, 0, cc.ts.Sub(ToTime32(ToNTP32(cc.ts), cc.ts)), float64(time.Second/65536))
+		})
+	}
+}
+
+func TestTimeToNTPConverstion(t *testing.T) {
+	for i, cc := range []struct {
+		ts uint64
+	}{
+		{
+			ts: 0,
+		},
+		{
+			ts: 65535,
+		},
+		{
+			ts: 16606669245815957503,
+		},
+		{
+			ts: 9487534653230284800,
+		},
+	} {
+		t.Run(fmt.Sprintf("TimeToNTP/%v", i), func(t *testing.T) {
+			// A nanosecond spans more than 4 fractions
+			assert.InDelta(t, cc.ts, ToNTP(ToTime(cc.ts)), 2)
+		})
+	}
+}
+
+func TestNTPValues(t *testing.T) {
+	unix := time.Unix(0, 0)
+	assert.Equal(t, uint64(2208988800)<<32, ToNTP(unix))
+	assert.Equal(t, uint64(2208988800)<<32|1<<31, ToNTP(unix.Add(500*time.Millisecond)))
+	// 1ns is 4.29 fractions
+	assert.Equal(t, uint64(2208988800)<<32|4, ToNTP(unix.Add(time.Nanosecond)))
+	assert.Equal(t, uint64(2208988801)<<32, ToNTP(unix.Add(time.Second-time.Nanosecond/2)))
+
+	assert.True(t, unix.Equal(ToTime(uint64(2208988800)<<32)))
+	assert.True(t, unix.Add(500*time.Millisecond).Equal(ToTime(uint64(2208988800)<<32|1<<31)))
+	assert.True(t, unix.Add(time.Second).Equal(ToTime(uint64(2208988800)<<32|0xFFFFFFFF)))
+
+	// Era 1 starts in 2036
+	era1 := time.Date(2036, time.February, 7, 6, 28, 16, 0, time.UTC)
+	assert.Equal(t, uint64(0x80)<<32, ToNTP(era1.Add(128*time.Second)))
+	assert.True(t, era1.Add(128*time.Second).Equal(ToTime(uint64(0x80)<<32)))
+	assert.True(t, time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC).Equal(ToTime(0)))
+}
+
+func TestNTPTime32(t *testing.T) {
+	zero := time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	notSoLongAgo := time.Date(2022, time.May, 5, 14, 48, 20, 0, time.UTC)
+	for i, cc := range []struct {
+		input    time.Time
+		expected uint32
+	}{
+		{
+			input:    zero,
+			expected: 0,
+		},
+		{
+			input:    zero.Add(time.Second),
+			expected: 1 << 16,
+		},
+		{
+			input: notSoLongAgo,
+			//nolint:gosec // G115
+			expected: uint32(uint(notSoLongAgo.Sub(zero).Seconds())&0xffff) << 16,
+		},
+		{
+			input:    zero.Add(400 * time.Millisecond),
+			expected: 26214,
+		},
+		{
+			input:    zero.Add(1400 * time.Millisecond),
+			expected: 1<<16 + 26214,
+		},
+	} {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			res := ToNTP32(cc.input)
+			assert.Equalf(t, cc.expected, res, "%b != %b", cc.expected, res)
+		})
+	}
+}
+
+func TestNTPTime32Wrap(t *testing.T) {
+	// The time closest to the reference is chosen, even if the 16 bits
+	// recovered from it wrapped in between.
+	wrap := time.Date(2022, time.May, 5, 14, 48, 20, 0, time.UTC)
+	for (ToNTP(wrap)>>48)&0xFFFF == (ToNTP(wrap.Add(-time.Second))>>48)&0xFFFF {
+		wrap = wrap.Add(time.Second)
+	}
+	before, after := wrap.Add(-10*time.Second), wrap.Add(10*time.Second)
+	assert.True(t, before.Equal(ToTime32(ToNTP32(before), after)))
+	assert.True(t, after.Equal(ToTime32(ToNTP32(after), before)))
+}
+
+func TestNTPDuration(t *testing.T) {
+	for i, cc := range []struct {
+		duration time.Duration
+		compact  uint32
+	}{
+		{duration: 0, compact: 0},
+		{duration: -time.Second, compact: 0},
+		{duration: time.Second, compact: 65536},
+		{duration: 1500 * time.Millisecond, compact: 98304},
+		{duration: 400 * time.Millisecond, compact: 26214},
+		{duration: 15 * time.Microsecond, compact: 1},
+		{duration: 24 * time.Hour, compact: 0xFFFFFFFF},
+	} {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			assert.Equal(t, cc.compact, FromDuration(cc.duration))
+		})
+	}
+
+	assert.Equal(t, time.Second, ToDuration(65536))
+	assert.Equal(t, 15259*time.Nanosecond, ToDuration(1))
+	for _, d := range []time.Duration{time.Millisecond, 123456789, time.Hour} {
+		assert.InDelta(t, d, ToDuration(FromDuration(d)), float64(time.Second/65536/2))
+	}
+}
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package packetdump
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrRecordTooLarge is returned when decrypting a record larger than the maximum record size.
+	ErrRecordTooLarge = errors.New("encrypted record too large")
+	// ErrDumpTruncated is returned when an encrypted dump ends before its end record.
+	ErrDumpTruncated = errors.New("encrypted dump truncated")
+
+	errDataAfterEnd = errors.New("data after the end record of the encrypted dump")
+)
+
+// maxRecordSize limits the size of decrypted records, to not allocate
+// arbitrary amounts of memory for corrupted dumps.
+const maxRecordSize = 1 << 24
+
+// endRecordFlag is set in the length of the end record.
+const endRecordFlag = 1 << 31
+
+// counterSize is the size of the record counter at the end of the nonce.
+const counterSize = 8
+
+// encryptingWriter encrypts every write with AES-GCM. The stream starts with a
+// random nonce prefix, followed by one record per write consisting of the big
+// endian uint32 length of the sealed data and the sealed data. The nonce of a
+// record is the prefix followed by the big endian uint64 index of the record,
+// which is also authenticated as additional data, so records can't be
+// reordered or dropped without failing to decrypt. Close writes an empty end
+// record, its length has the endRecordFlag set, which is authenticated as
+// well, so a dump truncated at a record boundary is detected.
+type encryptingWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	nonce   []byte
+	started bool
+	ended   bool
+	counter uint64
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func newEncryptingWriter(w io.Writer, key []byte) (*encryptingWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce[:len(nonce)-counterSize]); err != nil {
+		return nil, err
+	}
+
+	return &encryptingWriter{w: w, aead: aead, nonce: nonce}, nil
+}
+
+// recordData returns the additional data of the record with counter.
+func recordData(counter []byte, end bool) []byte {
+	data := append(make([]byte, 0, counterSize+1), counter...)
+	if end {
+		return append(data, 1)
+	}
+
+	return append(data, 0)
+}
+
+// Write encrypts b as a single record, preceded by the nonce prefix on the
+// first write.
+func (e *encryptingWriter) Write(b []byte) (int, error) {
+	if err := e.writeRecord(b, false); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Close writes the end record. Nothing must be written afterwards.
+func (e *encryptingWriter) Close() error {
+	if e.ended {
+		return nil
+	}
+	e.ended = true
+
+	return e.writeRecord(nil, true)
+}
+
+func (e *encryptingWriter) writeRecord(b []byte, end bool) error {
+	prefix := e.nonce[:len(e.nonce)-counterSize]
+	counter := e.nonce[len(prefix):]
+	binary.BigEndian.PutUint64(counter, e.counter)
+
+	record := make([]byte, 0, len(prefix)+4+len(b)+e.aead.Overhead())
+	if !e.started {
+		record = append(record, prefix...)
+	}
+	start := len(record)
+	record = append(record, 0, 0, 0, 0)
+	record = e.aead.Seal(record, e.nonce, b, recordData(counter, end))
+	length := uint32(len(record) - start - 4) //nolint:gosec // G115
+	if end {
+		length |= endRecordFlag
+	}
+	binary.BigEndian.PutUint32(record[start:], length)
+
+	if _, err := e.w.Write(record); err != nil {
+		return err
+	}
+	e.started = true
+	e.counter++
+
+	return nil
+}
+
+// DecryptAESGCM decrypts a dump written with the EncryptAESGCM option from r
+// to w. It returns ErrDumpTruncated if the dump ends before the end record
+// written when the dumper was closed, the records before are written to w
+// anyway.
+func DecryptAESGCM(key []byte, r io.Reader, w io.Writer) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	prefix := nonce[:len(nonce)-counterSize]
+	counter := nonce[len(prefix):]
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrDumpTruncated
+		}
+
+		return err
+	}
+
+	length := make([]byte, 4)
+	for index := uint64(0); ; index++ {
+		if _, err := io.ReadFull(r, length); err != nil {
+			if errors.Is(err, io.EOF) {
+				return ErrDumpTruncated
+			}
+
+			return err
+		}
+		size := binary.BigEndian.Uint32(length)
+		end := size&endRecordFlag != 0
+		size &^= endRecordFlag
+		if size > maxRecordSize {
+			return ErrRecordTooLarge
+		}
+		sealed := make([]byte, size)
+		if _, err := io.ReadFull(r, sealed); err != nil {
+			return err
+		}
+
+		binary.BigEndian.PutUint64(counter, index)
+		plain, err := aead.Open(sealed[:0], nonce, sealed, recordData(counter, end))
+		if err != nil {
+			return fmt.Errorf("decrypt record: %w", err)
+		}
+		if end {
+			if n, _ := r.Read(length[:1]); n > 0 {
+				return errDataAfterEnd
+			}
+
+			return nil
+		}
+		if _, err := w.Write(plain); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package packetdump
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptAESGCM(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+	encrypt := func(records ...string) []byte {
+		buf := bytes.Buffer{}
+		writer, err := newEncryptingWriter(&buf, key)
+		require.NoError(t, err)
+		for _, record := range records {
+			_, err := writer.Write([]byte(record))
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+		require.NoError(t, writer.Close())
+
+		return buf.Bytes()
+	}
+
+	// Every dump uses its own nonce prefix
+	first, second := encrypt("first", "second"), encrypt("first", "second")
+	assert.NotEqual(t, first, second)
+
+	decrypted := bytes.Buffer{}
+	assert.NoError(t, DecryptAESGCM(key, bytes.NewReader(first), &decrypted))
+	assert.Equal(t, "firstsecond", decrypted.String())
+
+	// Records are 4 bytes of length and 16 bytes of tag longer than the data,
+	// after the 4 bytes of nonce prefix
+	prefix, one, two, end := first[:4], first[4:4+4+5+16], first[4+4+5+16:len(first)-4-16], first[len(first)-4-16:]
+	reordered := bytes.Join([][]byte{prefix, two, one, end}, nil)
+	assert.ErrorContains(t, DecryptAESGCM(key, bytes.NewReader(reordered), &bytes.Buffer{}), "decrypt record")
+	dropped := bytes.Join([][]byte{prefix, two, end}, nil)
+	assert.ErrorContains(t, DecryptAESGCM(key, bytes.NewReader(dropped), &bytes.Buffer{}), "decrypt record")
+
+	// A dump without its end record is truncated, even at a record boundary
+	decrypted.Reset()
+	truncated := bytes.Join([][]byte{prefix, one}, nil)
+	assert.ErrorIs(t, DecryptAESGCM(key, bytes.NewReader(truncated), &decrypted), ErrDumpTruncated)
+	assert.Equal(t, "first", decrypted.String())
+	assert.ErrorIs(t, DecryptAESGCM(key, bytes.NewReader(nil), &bytes.Buffer{}), ErrDumpTruncated)
+
+	// The end flag is authenticated, and nothing may follow the end record
+	unflagged := bytes.Join([][]byte{prefix, one, two, {0}, end[1:]}, nil)
+	assert.ErrorContains(t, DecryptAESGCM(key, bytes.NewReader(unflagged), &bytes.Buffer{}), "decrypt record")
+	flagged := bytes.Join([][]byte{prefix, {0x80}, one[1:]}, nil)
+	assert.ErrorContains(t, DecryptAESGCM(key, bytes.NewReader(flagged), &bytes.Buffer{}), "decrypt record")
+	appended := bytes.Join([][]byte{first, one}, nil)
+	assert.ErrorIs(t, DecryptAESGCM(key, bytes.NewReader(appended), &bytes.Buffer{}), errDataAfterEnd)
+
+	// An empty dump decrypts to nothing
+	decrypted.Reset()
+	assert.NoError(t, DecryptAESGCM(key, bytes.NewReader(encrypt()), &decrypted))
+	assert.Empty(t, decrypted.Bytes())
+}
@@ -20,3 +20,45 @@ type RTCPFilterCallback func(pkt []rtcp.Packet) bool
 // RTCPPerPacketFilterCallback can be used to filter RTCP packets to dump.
 // It's called once per every packet opposing to RTCPFilterCallback which is called once per packet batch.
 type RTCPPerPacketFilterCallback func(pkt rtcp.Packet) bool
+
+// RTCPTypeFilter returns a RTCPPerPacketFilterCallback which only lets RTCP
+// packets of the given types pass, e.g. rtcp.TypePayloadSpecificFeedback to
+// only dump PLI, SLI, FIR and REMB packets.
+func RTCPTypeFilter(types ...rtcp.PacketType) RTCPPerPacketFilterCallback {
+	return func(pkt rtcp.Packet) bool {
+		header, ok := rtcpHeader(pkt)
+		if !ok {
+			return false
+		}
+		for _, t := range types {
+			if header.Type == t {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+type headerer interface {
+	Header() rtcp.Header
+}
+
+// rtcpHeader returns the header of pkt, marshaling the packet if it doesn't
+// expose its header.
+func rtcpHeader(pkt rtcp.Packet) (rtcp.Header, bool) {
+	if h, ok := pkt.(headerer); ok {
+		return h.Header(), true
+	}
+
+	raw, err := pkt.Marshal()
+	if err != nil {
+		return rtcp.Header{}, false
+	}
+	var header rtcp.Header
+	if err := header.Unmarshal(raw); err != nil {
+		return rtcp.Header{}, false
+	}
+
+	return header, true
+}
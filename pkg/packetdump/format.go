@@ -23,9 +23,14 @@ type RTPFormatCallback func(*rtp.Packet, interceptor.Attributes) string
 // Deprecated: prefer RTCPBinaryFormatCallback.
 type RTCPFormatCallback func([]rtcp.Packet, interceptor.Attributes) string
 
-// DefaultRTPFormatter returns the default log format for RTP packets
+// DefaultRTPFormatter returns the default log format for RTP packets, preceded
+// by the labels of the stream, see interceptor.Labels.
 // Deprecated: useless export since set by default.
-func DefaultRTPFormatter(pkt *rtp.Packet, _ interceptor.Attributes) string {
+func DefaultRTPFormatter(pkt *rtp.Packet, attributes interceptor.Attributes) string {
+	if labels := attributes.GetLabels(); labels != nil {
+		return fmt.Sprintf("Labels: %s\n%s\n", labels, pkt)
+	}
+
 	return fmt.Sprintf("%s\n", pkt)
 }
 
@@ -40,3 +45,67 @@ type RTPBinaryFormatCallback func(*rtp.Packet, interceptor.Attributes) ([]byte,
 
 // RTCPBinaryFormatCallback can be used to apply custom formatting or marshaling to each dumped RTCP packet.
 type RTCPBinaryFormatCallback func(rtcp.Packet, interceptor.Attributes) ([]byte, error)
+
+// SummaryRTCPFormatter formats each RTCP packet as a single line holding its
+// type, SSRCs and the fields most useful to debug feedback, e.g.
+//
+//	RTCP PLI sender=123 media=456
+//
+// It is more readable than the default format in production logs.
+func SummaryRTCPFormatter(pkt rtcp.Packet, _ interceptor.Attributes) ([]byte, error) {
+	var line string
+	switch pkt := pkt.(type) {
+	case *rtcp.SenderReport:
+		line = fmt.Sprintf("SR ssrc=%d ntp=%d rtp=%d packets=%d octets=%d%s",
+			pkt.SSRC, pkt.NTPTime, pkt.RTPTime, pkt.PacketCount, pkt.OctetCount, summarizeReports(pkt.Reports))
+	case *rtcp.ReceiverReport:
+		line = fmt.Sprintf("RR ssrc=%d%s", pkt.SSRC, summarizeReports(pkt.Reports))
+	case *rtcp.PictureLossIndication:
+		line = fmt.Sprintf("PLI sender=%d media=%d", pkt.SenderSSRC, pkt.MediaSSRC)
+	case *rtcp.FullIntraRequest:
+		line = fmt.Sprintf("FIR sender=%d media=%d", pkt.SenderSSRC, pkt.MediaSSRC)
+		for _, entry := range pkt.FIR {
+			line += fmt.Sprintf(" [ssrc=%d seq=%d]", entry.SSRC, entry.SequenceNumber)
+		}
+	case *rtcp.TransportLayerNack:
+		line = fmt.Sprintf("NACK sender=%d media=%d", pkt.SenderSSRC, pkt.MediaSSRC)
+		for _, pair := range pkt.Nacks {
+			line += fmt.Sprintf(" %v", pair.PacketList())
+		}
+	case *rtcp.TransportLayerCC:
+		line = fmt.Sprintf("TWCC sender=%d media=%d base=%d count=%d reference=%d fbcount=%d",
+			pkt.SenderSSRC, pkt.MediaSSRC, pkt.BaseSequenceNumber, pkt.PacketStatusCount,
+			pkt.ReferenceTime, pkt.FbPktCount)
+	case *rtcp.ReceiverEstimatedMaximumBitrate:
+		line = fmt.Sprintf("REMB sender=%d bitrate=%.0f ssrcs=%v", pkt.SenderSSRC, pkt.Bitrate, pkt.SSRCs)
+	case *rtcp.CCFeedbackReport:
+		line = fmt.Sprintf("CCFB sender=%d timestamp=%d", pkt.SenderSSRC, pkt.ReportTimestamp)
+		for _, block := range pkt.ReportBlocks {
+			line += fmt.Sprintf(" [media=%d begin=%d count=%d]",
+				block.MediaSSRC, block.BeginSequence, len(block.MetricBlocks))
+		}
+	case *rtcp.Goodbye:
+		line = fmt.Sprintf("BYE sources=%v reason=%q", pkt.Sources, pkt.Reason)
+	case *rtcp.SourceDescription:
+		line = "SDES"
+		for _, chunk := range pkt.Chunks {
+			line += fmt.Sprintf(" [ssrc=%d items=%d]", chunk.Source, len(chunk.Items))
+		}
+	default:
+		header, _ := rtcpHeader(pkt)
+		line = fmt.Sprintf("%s fmt=%d destinations=%v", header.Type, header.Count, pkt.DestinationSSRC())
+	}
+
+	return []byte("RTCP " + line + "\n"), nil
+}
+
+func summarizeReports(reports []rtcp.ReceptionReport) string {
+	var summary string
+	for _, report := range reports {
+		summary += fmt.Sprintf(" [ssrc=%d fraction=%d lost=%d seq=%d jitter=%d lsr=%d dlsr=%d]",
+			report.SSRC, report.FractionLost, report.TotalLost, report.LastSequenceNumber,
+			report.Jitter, report.LastSenderReport, report.Delay)
+	}
+
+	return summary
+}
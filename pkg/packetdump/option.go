@@ -7,6 +7,7 @@ import (
 	"io"
 
 	"github.com/pion/logging"
+	"github.com/pion/rtcp"
 )
 
 // PacketDumperOption can be used to configure SenderInterceptor.
@@ -21,19 +22,34 @@ func Log(log logging.LeveledLogger) PacketDumperOption {
 	}
 }
 
-// RTPWriter sets the io.Writer on which RTP packets will be dumped.
+// Writer sets the io.Writer on which RTP and RTCP packets will be dumped.
+func Writer(w io.Writer) PacketDumperOption {
+	return func(d *PacketDumper) error {
+		d.rtpStream = w
+		d.rtcpStream = w
+		d.sharedStream = true
+
+		return nil
+	}
+}
+
+// RTPWriter sets the io.Writer on which RTP packets will be dumped. Use Writer
+// to dump RTP and RTCP packets to the same encrypted writer.
 func RTPWriter(w io.Writer) PacketDumperOption {
 	return func(d *PacketDumper) error {
 		d.rtpStream = w
+		d.sharedStream = false
 
 		return nil
 	}
 }
 
-// RTCPWriter sets the io.Writer on which RTCP packets will be dumped.
+// RTCPWriter sets the io.Writer on which RTCP packets will be dumped. Use
+// Writer to dump RTP and RTCP packets to the same encrypted writer.
 func RTCPWriter(w io.Writer) PacketDumperOption {
 	return func(d *PacketDumper) error {
 		d.rtcpStream = w
+		d.sharedStream = false
 
 		return nil
 	}
@@ -104,3 +120,47 @@ func RTCPPerPacketFilter(callback RTCPPerPacketFilterCallback) PacketDumperOptio
 		return nil
 	}
 }
+
+// RTCPTypes only dumps RTCP packets of the given types.
+func RTCPTypes(types ...rtcp.PacketType) PacketDumperOption {
+	return RTCPPerPacketFilter(RTCPTypeFilter(types...))
+}
+
+// RTCPSummary dumps a single summary line per RTCP packet instead of the full
+// packet, see SummaryRTCPFormatter.
+func RTCPSummary() PacketDumperOption {
+	return func(d *PacketDumper) error {
+		d.rtcpFormat = nil
+		d.rtcpFormatBinary = SummaryRTCPFormatter
+
+		return nil
+	}
+}
+
+// EncryptAESGCM encrypts everything written to the RTP and RTCP writers with
+// AES-GCM using key, which must be 16, 24 or 32 bytes long. Every packet is
+// written as a separate record, dumps can be decrypted with DecryptAESGCM.
+// RTP and RTCP packets can only be dumped to the same writer, e.g. the default
+// stdout, if it is set with Writer, as the records of every writer are
+// numbered. The dump ends with an end record written when the interceptor is
+// closed.
+func EncryptAESGCM(key []byte) PacketDumperOption {
+	return func(d *PacketDumper) error {
+		if _, err := newAEAD(key); err != nil {
+			return err
+		}
+		d.encryptionKey = key
+
+		return nil
+	}
+}
+
+// RedactPayload removes the payload of RTP packets before they are dumped, so
+// only headers are written.
+func RedactPayload() PacketDumperOption {
+	return func(d *PacketDumper) error {
+		d.redactPayload = true
+
+		return nil
+	}
+}
@@ -30,6 +30,9 @@ type PacketDumper struct {
 
 	rtpStream  io.Writer
 	rtcpStream io.Writer
+	// sharedStream is set if RTP and RTCP packets are dumped to the same
+	// writer, see Writer.
+	sharedStream bool
 
 	rtpFormatBinary  RTPBinaryFormatCallback
 	rtcpFormatBinary RTCPBinaryFormatCallback
@@ -40,6 +43,10 @@ type PacketDumper struct {
 	rtpFilter           RTPFilterCallback
 	rtcpFilter          RTCPFilterCallback
 	rtcpPerPacketFilter RTCPPerPacketFilterCallback
+
+	encryptionKey []byte
+	encrypting    []*encryptingWriter
+	redactPayload bool
 }
 
 // NewPacketDumper creates a new PacketDumper.
@@ -52,6 +59,7 @@ func NewPacketDumper(opts ...PacketDumperOption) (*PacketDumper, error) {
 		rtcpChan:         make(chan *rtcpDump),
 		rtpStream:        os.Stdout,
 		rtcpStream:       os.Stdout,
+		sharedStream:     true,
 		rtpFormat:        nil,
 		rtcpFormat:       nil,
 		rtpFormatBinary:  nil,
@@ -85,20 +93,72 @@ func NewPacketDumper(opts ...PacketDumperOption) (*PacketDumper, error) {
 		dumper.rtcpFormat = DefaultRTCPFormatter
 	}
 
+	if err := dumper.encryptStreams(); err != nil {
+		return nil, err
+	}
+
 	dumper.wg.Add(1)
 	go dumper.loop()
 
 	return dumper, nil
 }
 
-func (d *PacketDumper) logRTPPacket(header *rtp.Header, payload []byte, attributes interceptor.Attributes) {
+// encryptStreams wraps the writers with encrypting ones, after all options
+// were applied.
+func (d *PacketDumper) encryptStreams() error {
+	if d.encryptionKey == nil {
+		return nil
+	}
+
+	rtpStream, err := newEncryptingWriter(d.rtpStream, d.encryptionKey)
+	if err != nil {
+		return err
+	}
+	// Records of a stream are numbered, so streams sharing a writer must share
+	// the encrypting writer as well
+	if d.sharedStream {
+		d.rtpStream, d.rtcpStream = rtpStream, rtpStream
+		d.encrypting = []*encryptingWriter{rtpStream}
+
+		return nil
+	}
+	rtcpStream, err := newEncryptingWriter(d.rtcpStream, d.encryptionKey)
+	if err != nil {
+		return err
+	}
+	d.rtpStream, d.rtcpStream = rtpStream, rtcpStream
+	d.encrypting = []*encryptingWriter{rtpStream, rtcpStream}
+
+	return nil
+}
+
+func (d *PacketDumper) logRTPPacket(
+	header *rtp.Header, payload []byte, attributes interceptor.Attributes, labels interceptor.Labels,
+) {
+	if labels != nil {
+		// The attributes of the packet must not be modified
+		annotated := make(interceptor.Attributes, len(attributes)+1)
+		for key, val := range attributes {
+			annotated[key] = val
+		}
+		annotated.SetLabels(labels)
+		attributes = annotated
+	}
+
+	packet := &rtp.Packet{
+		Header:  *header,
+		Payload: payload,
+	}
+	if d.redactPayload {
+		// Padding is part of the payload
+		packet.Header.Padding = false
+		packet.Payload = nil
+	}
+
 	select {
 	case d.rtpChan <- &rtpDump{
 		attributes: attributes,
-		packet: &rtp.Packet{
-			Header:  *header,
-			Payload: payload,
-		},
+		packet:     packet,
 	}:
 	case <-d.close:
 	}
@@ -114,13 +174,19 @@ func (d *PacketDumper) logRTCPPackets(pkts []rtcp.Packet, attributes interceptor
 	}
 }
 
-// Close closes the PacketDumper.
+// Close closes the PacketDumper. Encrypted dumps are ended with an end record.
 func (d *PacketDumper) Close() error {
-	defer d.wg.Wait()
-
 	if !d.isClosed() {
 		close(d.close)
 	}
+	d.wg.Wait()
+
+	// The loop doesn't write anymore
+	for _, stream := range d.encrypting {
+		if err := stream.Close(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -42,8 +42,10 @@ type ReceiverInterceptor struct {
 // BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
 // The returned method will be called once per rtp packet.
 func (r *ReceiverInterceptor) BindRemoteStream(
-	_ *interceptor.StreamInfo, reader interceptor.RTPReader,
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
 ) interceptor.RTPReader {
+	labels := info.Attributes.GetLabels()
+
 	return interceptor.RTPReaderFunc(
 		func(bytes []byte, attributes interceptor.Attributes) (int, interceptor.Attributes, error) {
 			i, attr, err := reader.Read(bytes, attributes)
@@ -59,7 +61,7 @@ func (r *ReceiverInterceptor) BindRemoteStream(
 				return 0, nil, err
 			}
 
-			r.logRTPPacket(header, bytes[header.MarshalSize():i], attr)
+			r.logRTPPacket(header, bytes[header.MarshalSize():i], attr, labels)
 
 			return i, attr, nil
 		},
@@ -53,11 +53,13 @@ func (s *SenderInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interc
 // BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
 // will be called once per rtp packet.
 func (s *SenderInterceptor) BindLocalStream(
-	_ *interceptor.StreamInfo, writer interceptor.RTPWriter,
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
 ) interceptor.RTPWriter {
+	labels := info.Attributes.GetLabels()
+
 	return interceptor.RTPWriterFunc(
 		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
-			s.logRTPPacket(header, payload, attributes)
+			s.logRTPPacket(header, payload, attributes, labels)
 
 			return writer.Write(header, payload, attributes)
 		},
@@ -175,6 +175,43 @@ func TestSenderCustomBinaryFormatter(t *testing.T) {
 	assert.Equal(t, []byte{45}, rtcpBuf.Bytes())
 }
 
+func TestSenderLabels(t *testing.T) {
+	buf := bytes.Buffer{}
+
+	factory, err := NewSenderInterceptor(
+		RTPWriter(&buf),
+		Log(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	attributes := interceptor.Attributes{}
+	attributes.SetLabels(interceptor.Labels{"conference": "42"})
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:       123456,
+		ClockRate:  90000,
+		Attributes: attributes,
+	}, testInterceptor)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	err = stream.WriteRTP(&rtp.Packet{Header: rtp.Header{
+		SequenceNumber: uint16(123),
+	}})
+	assert.NoError(t, err)
+
+	// Give time for packets to be handled and stream written to.
+	time.Sleep(50 * time.Millisecond)
+
+	err = testInterceptor.Close()
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "Labels: conference=42\n")
+}
+
 func TestSenderRTCPPerPacketFilter(t *testing.T) {
 	buf := bytes.Buffer{}
 
@@ -227,3 +264,104 @@ func TestSenderRTCPPerPacketFilter(t *testing.T) {
 	// Only single PictureLossIndication should have been written.
 	assert.Equal(t, []byte{123}, buf.Bytes())
 }
+
+func TestSenderRTCPTypesSummary(t *testing.T) {
+	buf := bytes.Buffer{}
+
+	factory, err := NewSenderInterceptor(
+		RTCPWriter(&buf),
+		Log(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		RTCPTypes(rtcp.TypePayloadSpecificFeedback, rtcp.TypeTransportSpecificFeedback),
+		RTCPSummary(),
+	)
+	assert.NoError(t, err)
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, testInterceptor)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	err = stream.WriteRTCP([]rtcp.Packet{
+		&rtcp.ReceiverReport{SSRC: 789},
+		&rtcp.PictureLossIndication{SenderSSRC: 123, MediaSSRC: 456},
+		&rtcp.TransportLayerNack{
+			SenderSSRC: 123,
+			MediaSSRC:  456,
+			Nacks:      rtcp.NackPairsFromSequenceNumbers([]uint16{10, 12}),
+		},
+	})
+	assert.NoError(t, err)
+
+	// Give time for packets to be handled and stream written to.
+	time.Sleep(50 * time.Millisecond)
+
+	err = testInterceptor.Close()
+	assert.NoError(t, err)
+
+	// The receiver report is filtered out.
+	assert.Equal(t, "RTCP PLI sender=123 media=456\nRTCP NACK sender=123 media=456 [10 12]\n", buf.String())
+}
+
+func TestSenderEncryptedRedacted(t *testing.T) {
+	buf := bytes.Buffer{}
+	key := bytes.Repeat([]byte{1}, 16)
+
+	factory, err := NewSenderInterceptor(
+		Writer(&buf),
+		Log(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		RTPBinaryFormatter(func(p *rtp.Packet, _ interceptor.Attributes) ([]byte, error) {
+			return p.Marshal()
+		}),
+		RTCPBinaryFormatter(func(p rtcp.Packet, _ interceptor.Attributes) ([]byte, error) {
+			return p.Marshal()
+		}),
+		EncryptAESGCM(key),
+		RedactPayload(),
+	)
+	assert.NoError(t, err)
+
+	testInterceptor, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, testInterceptor)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	err = stream.WriteRTP(&rtp.Packet{
+		Header:  rtp.Header{Version: 2, SSRC: 123456, SequenceNumber: 7},
+		Payload: []byte("secret media"),
+	})
+	assert.NoError(t, err)
+
+	// Give time for packets to be handled and stream written to.
+	time.Sleep(50 * time.Millisecond)
+
+	err = testInterceptor.Close()
+	assert.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), "secret")
+
+	decrypted := bytes.Buffer{}
+	assert.ErrorContains(t, DecryptAESGCM(bytes.Repeat([]byte{2}, 16), bytes.NewReader(buf.Bytes()), &decrypted),
+		"decrypt record")
+	decrypted.Reset()
+	assert.NoError(t, DecryptAESGCM(key, &buf, &decrypted))
+
+	pkt := &rtp.Packet{}
+	assert.NoError(t, pkt.Unmarshal(decrypted.Bytes()))
+	assert.Equal(t, uint16(7), pkt.SequenceNumber)
+	assert.Empty(t, pkt.Payload)
+
+	_, err = NewPacketDumper(EncryptAESGCM([]byte{1}))
+	assert.Error(t, err)
+}
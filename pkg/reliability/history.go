@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package reliability
+
+import (
+	"time"
+
+	"github.com/pion/interceptor"
+)
+
+// historySize matches the default size of the nack responder's buffer.
+const historySize = 1024
+
+type sentPacket struct {
+	valid     bool
+	seq       uint16
+	sent      time.Time
+	frameType interceptor.FrameType
+}
+
+// history holds the most recent sent packets of a stream in a preallocated
+// ring, so recording a packet doesn't allocate.
+type history struct {
+	packets [historySize]sentPacket
+}
+
+func (h *history) add(seq uint16, sent time.Time, frameType interceptor.FrameType) {
+	h.packets[seq%historySize] = sentPacket{valid: true, seq: seq, sent: sent, frameType: frameType}
+}
+
+func (h *history) get(seq uint16) (sentPacket, bool) {
+	pkt := h.packets[seq%historySize]
+	if !pkt.valid || pkt.seq != seq {
+		return sentPacket{}, false
+	}
+
+	return pkt, true
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package reliability
+
+import (
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/ntp"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// Interceptor records the send time and frame type of the packets sent on a
+// PeerConnection for the Policy, and measures the round trip time from
+// received reports. The frame type is read from the attributes, see
+// interceptor.Attributes.SetFrameType.
+type Interceptor struct {
+	interceptor.NoOp
+	policy    *Policy
+	id        string
+	transport *transport
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (i *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		pkts, err := attr.GetRTCPPackets(b[:n])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		for _, pkt := range pkts {
+			switch pkt := pkt.(type) {
+			case *rtcp.ReceiverReport:
+				i.updateRTT(pkt.Reports)
+			case *rtcp.SenderReport:
+				i.updateRTT(pkt.Reports)
+			}
+		}
+
+		return n, attr, nil
+	})
+}
+
+func (i *Interceptor) updateRTT(reports []rtcp.ReceptionReport) {
+	t := i.transport
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := ntp.ToNTP32(i.policy.now())
+	for _, report := range reports {
+		if _, ok := t.streams[report.SSRC]; !ok || report.LastSenderReport == 0 {
+			continue
+		}
+		// In units of 1/65536 seconds, a negative RTT wraps around
+		rtt := now - report.LastSenderReport - report.Delay
+		if rtt >= 1<<31 {
+			continue
+		}
+		t.rtt = ntp.ToDuration(rtt)
+	}
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream.
+// The returned method will be called once per rtp packet.
+func (i *Interceptor) BindLocalStream(
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	i.transport.mu.Lock()
+	i.transport.streams[info.SSRC] = &history{}
+	i.transport.mu.Unlock()
+
+	return interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			if header.SSRC == info.SSRC {
+				i.transport.record(header.SSRC, header.SequenceNumber, i.policy.now(), attributes.GetFrameType())
+			}
+
+			return writer.Write(header, payload, attributes)
+		},
+	)
+}
+
+// UnbindLocalStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (i *Interceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	i.transport.mu.Lock()
+	defer i.transport.mu.Unlock()
+
+	delete(i.transport.streams, info.SSRC)
+}
+
+// Close removes the packets of the PeerConnection from the Policy.
+func (i *Interceptor) Close() error {
+	i.policy.remove(i.id, i.transport)
+
+	return nil
+}
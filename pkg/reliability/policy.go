@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package reliability provides a partial reliability policy for latency
+// bounded streaming. It decides per packet whether a lost packet is still
+// worth retransmitting, based on its age, the type of the frame it belongs to
+// and the remaining latency budget.
+package reliability
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+)
+
+var (
+	errInvalidMaxAge        = errors.New("max age must not be negative")
+	errInvalidLatencyBudget = errors.New("latency budget must not be negative")
+	errDuplicateTransport   = errors.New("an interceptor for the transport already exists")
+)
+
+// Option can be used to configure the Policy.
+type Option func(*Policy) error
+
+// MaxAge sets the age after which packets aren't retransmitted anymore,
+// including the packets of keyframes. An age of 0 disables the limit.
+func MaxAge(age time.Duration) Option {
+	return func(p *Policy) error {
+		if age < 0 {
+			return errInvalidMaxAge
+		}
+		p.maxAge = age
+
+		return nil
+	}
+}
+
+// LatencyBudget sets the time after sending within which a packet must reach
+// the receiver to be useful. A packet isn't retransmitted, if the
+// retransmission would arrive later, estimated with half of the round trip
+// time measured from received reports. Keyframes are exempt, since losing
+// them breaks decoding until the next one. A budget of 0 disables the limit.
+func LatencyBudget(budget time.Duration) Option {
+	return func(p *Policy) error {
+		if budget < 0 {
+			return errInvalidLatencyBudget
+		}
+		p.latencyBudget = budget
+
+		return nil
+	}
+}
+
+// Now sets an alternative for the time.Now function.
+func Now(now func() time.Time) Option {
+	return func(p *Policy) error {
+		p.now = now
+
+		return nil
+	}
+}
+
+// Log sets a logger for the policy.
+func Log(log logging.LeveledLogger) Option {
+	return func(p *Policy) error {
+		p.log = log
+
+		return nil
+	}
+}
+
+// Policy is a interceptor.Factory for the Interceptors recording sent packets
+// and a nack.RetransmissionPolicy, which decides based on the recorded packets.
+// It must be passed to the nack responder with nack.ResponderPolicy, and the
+// responder must use the id of the PeerConnection as transport. The ids of the
+// PeerConnections must be unique while their interceptors are open.
+//
+// Packets of discardable frames are never retransmitted, since no other frame
+// depends on them. Packets unknown to the policy are left to the responder.
+type Policy struct {
+	maxAge        time.Duration
+	latencyBudget time.Duration
+	now           func() time.Time
+	log           logging.LeveledLogger
+
+	// mu only guards transports, the state of a transport has its own lock.
+	mu         sync.Mutex
+	transports map[string]*transport
+}
+
+// NewPolicy returns a new Policy.
+func NewPolicy(opts ...Option) (*Policy, error) {
+	policy := &Policy{
+		maxAge:        time.Second,
+		latencyBudget: 0,
+		now:           time.Now,
+		log:           logging.NewDefaultLoggerFactory().NewLogger("reliability"),
+		transports:    map[string]*transport{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(policy); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+// NewInterceptor constructs a new Interceptor recording the packets sent on
+// the PeerConnection. It fails if the Interceptor of another PeerConnection
+// with the same id wasn't closed yet.
+func (p *Policy) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.transports[id]; ok {
+		return nil, errDuplicateTransport
+	}
+	t := &transport{
+		rtt:     0,
+		streams: map[uint32]*history{},
+	}
+	p.transports[id] = t
+
+	return &Interceptor{
+		NoOp:      interceptor.NoOp{},
+		policy:    p,
+		id:        id,
+		transport: t,
+	}, nil
+}
+
+// remove removes the transport t of the id, if it wasn't replaced.
+func (p *Policy) remove(id string, t *transport) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.transports[id] == t {
+		delete(p.transports, id)
+	}
+}
+
+// Retransmittable reports whether the packet sent on the transport should be
+// retransmitted now.
+func (p *Policy) Retransmittable(transport string, ssrc uint32, seq uint16) bool {
+	p.mu.Lock()
+	t, ok := p.transports[transport]
+	p.mu.Unlock()
+	if !ok {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stream, ok := t.streams[ssrc]
+	if !ok {
+		return true
+	}
+	pkt, ok := stream.get(seq)
+	if !ok {
+		return true
+	}
+
+	age := p.now().Sub(pkt.sent)
+	switch {
+	case pkt.frameType == interceptor.FrameTypeDiscardable:
+		p.log.Debugf("not retransmitting packet %d of ssrc %d: discardable frame", seq, ssrc)
+
+		return false
+	case p.maxAge > 0 && age > p.maxAge:
+		p.log.Debugf("not retransmitting packet %d of ssrc %d: sent %v ago", seq, ssrc, age)
+
+		return false
+	case pkt.frameType == interceptor.FrameTypeKey:
+		return true
+	case p.latencyBudget > 0 && age+t.rtt/2 > p.latencyBudget:
+		p.log.Debugf("not retransmitting packet %d of ssrc %d: latency budget exceeded", seq, ssrc)
+
+		return false
+	default:
+		return true
+	}
+}
+
+// transport is the state of a PeerConnection.
+type transport struct {
+	mu      sync.Mutex
+	rtt     time.Duration
+	streams map[uint32]*history
+}
+
+// record stores the send time and frame type of a packet of a bound stream.
+func (t *transport) record(ssrc uint32, seq uint16, sent time.Time, frameType interceptor.FrameType) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if stream, ok := t.streams[ssrc]; ok {
+		stream.add(seq, sent, frameType)
+	}
+}
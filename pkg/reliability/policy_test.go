@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package reliability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/mock"
+	"github.com/pion/interceptor/pkg/nack"
+	"github.com/pion/interceptor/pkg/ntp"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frameTypes sets the frame type of the written packets by sequence number.
+func frameTypes(types map[uint16]interceptor.FrameType) *mock.Interceptor {
+	return &mock.Interceptor{
+		BindLocalStreamFn: func(_ *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
+			return interceptor.RTPWriterFunc(
+				func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+					if attributes == nil {
+						attributes = interceptor.Attributes{}
+					}
+					attributes.SetFrameType(types[header.SequenceNumber])
+
+					return writer.Write(header, payload, attributes)
+				},
+			)
+		},
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	mt := &test.MockTime{}
+	start := time.Unix(1000, 0)
+	mt.SetNow(start)
+
+	policy, err := NewPolicy(MaxAge(500*time.Millisecond), LatencyBudget(200*time.Millisecond), Now(mt.Now))
+	require.NoError(t, err)
+
+	i, err := policy.NewInterceptor("pc")
+	require.NoError(t, err)
+
+	chain := interceptor.NewChain([]interceptor.Interceptor{i, frameTypes(map[uint16]interceptor.FrameType{
+		1: interceptor.FrameTypeKey,
+		2: interceptor.FrameTypeDelta,
+		3: interceptor.FrameTypeDiscardable,
+	})})
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1}, chain)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	for seq := uint16(1); seq <= 4; seq++ {
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: seq}}))
+		<-stream.WrittenRTP()
+	}
+
+	mt.SetNow(start.Add(50 * time.Millisecond))
+	assert.True(t, policy.Retransmittable("pc", 1, 1))
+	assert.True(t, policy.Retransmittable("pc", 1, 2))
+	assert.False(t, policy.Retransmittable("pc", 1, 3))
+	assert.True(t, policy.Retransmittable("pc", 1, 4))
+
+	// Unknown packets are left to the responder
+	assert.True(t, policy.Retransmittable("pc", 1, 5))
+	assert.True(t, policy.Retransmittable("pc", 2, 1))
+	assert.True(t, policy.Retransmittable("other", 1, 1))
+
+	// A retransmission would take another 100ms to arrive
+	mt.SetNow(start.Add(150 * time.Millisecond))
+	stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ReceiverReport{
+		Reports: []rtcp.ReceptionReport{{
+			SSRC:             1,
+			LastSenderReport: ntp.ToNTP32(start.Add(-100 * time.Millisecond)),
+			Delay:            uint32(50 * time.Millisecond * 65536 / time.Second),
+		}},
+	}})
+	<-stream.ReadRTCP()
+	transport := policy.transports["pc"]
+	transport.mu.Lock()
+	assert.InDelta(t, 200*time.Millisecond, transport.rtt, float64(time.Millisecond))
+	transport.mu.Unlock()
+
+	assert.True(t, policy.Retransmittable("pc", 1, 1))
+	assert.False(t, policy.Retransmittable("pc", 1, 2))
+
+	// Keyframes are only limited by the max age
+	mt.SetNow(start.Add(600 * time.Millisecond))
+	assert.False(t, policy.Retransmittable("pc", 1, 1))
+
+	require.NoError(t, i.Close())
+	assert.True(t, policy.Retransmittable("pc", 1, 2))
+}
+
+func TestPolicy_DuplicateTransport(t *testing.T) {
+	policy, err := NewPolicy()
+	require.NoError(t, err)
+
+	first, err := policy.NewInterceptor("pc")
+	require.NoError(t, err)
+	_, err = policy.NewInterceptor("pc")
+	assert.ErrorIs(t, err, errDuplicateTransport)
+
+	// The id can be reused once the first interceptor is closed, closing it
+	// again doesn't remove the state of the second one
+	require.NoError(t, first.Close())
+	second, err := policy.NewInterceptor("pc")
+	require.NoError(t, err)
+	require.NoError(t, first.Close())
+	policy.mu.Lock()
+	assert.Same(t, second.(*Interceptor).transport, policy.transports["pc"])
+	policy.mu.Unlock()
+	require.NoError(t, second.Close())
+}
+
+func TestPolicy_Responder(t *testing.T) {
+	policy, err := NewPolicy()
+	require.NoError(t, err)
+
+	i, err := policy.NewInterceptor("pc")
+	require.NoError(t, err)
+
+	f, err := nack.NewResponderInterceptor(nack.ResponderPolicy(policy))
+	require.NoError(t, err)
+	responder, err := f.NewInterceptor("pc")
+	require.NoError(t, err)
+
+	chain := interceptor.NewChain([]interceptor.Interceptor{responder, i, frameTypes(map[uint16]interceptor.FrameType{
+		1: interceptor.FrameTypeKey,
+		2: interceptor.FrameTypeDiscardable,
+		3: interceptor.FrameTypeDelta,
+	})})
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         1,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}, chain)
+	defer func() {
+		require.NoError(t, stream.Close())
+	}()
+
+	for seq := uint16(1); seq <= 3; seq++ {
+		require.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: seq}}))
+		<-stream.WrittenRTP()
+	}
+
+	stream.ReceiveRTCP([]rtcp.Packet{&rtcp.TransportLayerNack{
+		MediaSSRC: 1,
+		Nacks:     []rtcp.NackPair{{PacketID: 1, LostPackets: 0b11}},
+	}})
+
+	for _, seq := range []uint16{1, 3} {
+		select {
+		case pkt := <-stream.WrittenRTP():
+			assert.Equal(t, seq, pkt.SequenceNumber)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "packet not retransmitted")
+		}
+	}
+
+	select {
+	case pkt := <-stream.WrittenRTP():
+		assert.Failf(t, "unexpected retransmission", "sequence number %d", pkt.SequenceNumber)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestPolicy_InvalidOptions(t *testing.T) {
+	_, err := NewPolicy(MaxAge(-time.Second))
+	assert.ErrorIs(t, err, errInvalidMaxAge)
+
+	_, err = NewPolicy(LatencyBudget(-time.Second))
+	assert.ErrorIs(t, err, errInvalidLatencyBudget)
+}
@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// overuseThreshold is the queuing delay above which the path is
+	// considered overused.
+	overuseThreshold = 25 * time.Millisecond
+	// rateWindow is the window of the received bitrate, and the minimum time
+	// between two decreases of the estimate, for the queue to drain.
+	rateWindow = time.Second
+	// increaseFactor is the increase of the estimate per second without
+	// overuse, decreaseFactor the fraction of the received bitrate the
+	// estimate is set to on overuse.
+	increaseFactor = 1.08
+	decreaseFactor = 0.85
+	// maxReceivedFactor limits the estimate to a multiple of the received
+	// bitrate, plus receivedHeadroom, so it can't grow without the remote
+	// sending more.
+	maxReceivedFactor = 1.5
+	receivedHeadroom  = 10_000
+	// delayWindow is the window of the smallest relative delay of a stream,
+	// the delay without queuing. The window keeps the drift of the clock of
+	// the sender from being taken for queuing, at 50 ppm it is 0.5ms.
+	delayWindow = 10 * time.Second
+)
+
+// delaySample is the relative delay of a frame which arrived at time.
+type delaySample struct {
+	time  time.Time
+	delay time.Duration
+}
+
+// streamDelay tracks the queuing delay of a stream from the arrival times of
+// its frames compared to their RTP timestamps.
+type streamDelay struct {
+	clockRate float64
+	started   bool
+	timestamp uint32
+	arrival   time.Time
+	// relative is the delay of the last frame relative to the first one,
+	// minimums are the ascending minimums of the relative delays within the
+	// delayWindow, the first one being the smallest.
+	relative time.Duration
+	minimums []delaySample
+	delay    time.Duration
+}
+
+// add adds the arrival of a packet with the RTP timestamp. Only the first
+// packet of a frame is used, as the packets of a frame are sent in a burst.
+func (s *streamDelay) add(now time.Time, timestamp uint32) {
+	if !s.started {
+		s.started, s.timestamp, s.arrival = true, timestamp, now
+		s.minimums = append(s.minimums, delaySample{time: now})
+
+		return
+	}
+	diff := int32(timestamp - s.timestamp) //nolint:gosec // G115
+	// Packets of the same or an older frame don't tell the delay
+	if diff <= 0 || s.clockRate == 0 {
+		return
+	}
+	sent := time.Duration(float64(diff) / s.clockRate * float64(time.Second))
+	s.relative += now.Sub(s.arrival) - sent
+	s.timestamp, s.arrival = timestamp, now
+
+	last := len(s.minimums)
+	for last > 0 && s.minimums[last-1].delay >= s.relative {
+		last--
+	}
+	s.minimums = append(s.minimums[:last], delaySample{time: now, delay: s.relative})
+	first := 0
+	for s.minimums[first].time.Before(now.Add(-delayWindow)) {
+		first++
+	}
+	s.minimums = s.minimums[first:]
+	s.delay = s.relative - s.minimums[0].delay
+}
+
+type arrival struct {
+	time time.Time
+	size int
+}
+
+// estimator is a simple delay based bandwidth estimator. The queuing delay of
+// every stream is the growth of the time between the arrival of its frames
+// compared to the time between their RTP timestamps, since the smallest
+// delay within the delayWindow. If the queuing delay of
+// any stream exceeds overuseThreshold, the estimate is decreased to a fraction
+// of the received bitrate, otherwise it is increased multiplicatively.
+type estimator struct {
+	m          sync.Mutex
+	bitrate    int
+	minBitrate int
+	maxBitrate int
+
+	seeded       bool
+	streams      map[uint32]*streamDelay
+	arrivals     []arrival
+	received     int
+	lastUpdate   time.Time
+	lastDecrease time.Time
+}
+
+func newEstimator(initialBitrate, minBitrate, maxBitrate int) *estimator {
+	return &estimator{
+		bitrate:    initialBitrate,
+		minBitrate: minBitrate,
+		maxBitrate: maxBitrate,
+		streams:    map[uint32]*streamDelay{},
+	}
+}
+
+func (e *estimator) addStream(ssrc, clockRate uint32) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.streams[ssrc] = &streamDelay{clockRate: float64(clockRate)}
+}
+
+func (e *estimator) removeStream(ssrc uint32) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	delete(e.streams, ssrc)
+}
+
+// ssrcs returns the SSRCs of the streams, in ascending order.
+func (e *estimator) ssrcs() []uint32 {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	ssrcs := make([]uint32, 0, len(e.streams))
+	for ssrc := range e.streams {
+		ssrcs = append(ssrcs, ssrc)
+	}
+	sort.Slice(ssrcs, func(i, j int) bool { return ssrcs[i] < ssrcs[j] })
+
+	return ssrcs
+}
+
+// addPacket adds a packet of size bytes of the stream with the SSRC, which
+// arrived at now.
+func (e *estimator) addPacket(now time.Time, ssrc, timestamp uint32, size int) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	stream, ok := e.streams[ssrc]
+	if !ok {
+		return
+	}
+	stream.add(now, timestamp)
+	e.arrivals = append(e.arrivals, arrival{time: now, size: size})
+	e.received += size
+}
+
+// seed sets the estimate to bitrate, within the bounds, unless it was seeded
+// before.
+func (e *estimator) seed(bitrate int) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	if e.seeded {
+		return
+	}
+	e.seeded = true
+	e.bitrate = bitrate
+	if e.bitrate < e.minBitrate {
+		e.bitrate = e.minBitrate
+	}
+	if e.bitrate > e.maxBitrate {
+		e.bitrate = e.maxBitrate
+	}
+}
+
+// update updates the estimate at now and returns it.
+func (e *estimator) update(now time.Time) int {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	del := 0
+	for _, a := range e.arrivals {
+		if !a.time.Before(now.Add(-rateWindow)) {
+			break
+		}
+		e.received -= a.size
+		del++
+	}
+	e.arrivals = e.arrivals[del:]
+	received := float64(8*e.received) / rateWindow.Seconds()
+
+	elapsed := time.Duration(0)
+	if !e.lastUpdate.IsZero() {
+		elapsed = now.Sub(e.lastUpdate)
+	}
+	e.lastUpdate = now
+
+	switch {
+	case e.queuingDelay() > overuseThreshold:
+		if now.Sub(e.lastDecrease) >= rateWindow {
+			e.bitrate = int(decreaseFactor * received)
+			e.lastDecrease = now
+		}
+	case received > 0:
+		increased := int(float64(e.bitrate) * math.Pow(increaseFactor, elapsed.Seconds()))
+		if limit := int(maxReceivedFactor*received) + receivedHeadroom; increased > limit {
+			increased = limit
+		}
+		// The estimate isn't decreased without overuse
+		if increased > e.bitrate {
+			e.bitrate = increased
+		}
+	}
+	if e.bitrate < e.minBitrate {
+		e.bitrate = e.minBitrate
+	}
+	if e.bitrate > e.maxBitrate {
+		e.bitrate = e.maxBitrate
+	}
+
+	return e.bitrate
+}
+
+// estimate returns the estimate of the last update.
+func (e *estimator) estimate() int {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return e.bitrate
+}
+
+// queuingDelay returns the highest queuing delay of the streams. It must be
+// called with e.m held.
+func (e *estimator) queuingDelay() time.Duration {
+	delay := time.Duration(0)
+	for _, stream := range e.streams {
+		if stream.delay > delay {
+			delay = stream.delay
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimator(t *testing.T) {
+	e := newEstimator(300_000, 30_000, 1_000_000)
+	e.addStream(1, 90000)
+	now := time.Now()
+	timestamp := uint32(0)
+
+	// 25 frames per second of 2500 bytes, 500 kbit/s
+	send := func(seconds int, delay time.Duration) {
+		for i := 0; i < 25*seconds; i++ {
+			e.addPacket(now, 1, timestamp, 2500)
+			now = now.Add(40*time.Millisecond + delay)
+			timestamp += 3600
+		}
+	}
+
+	// Without queuing the estimate increases by 8% per second
+	send(1, 0)
+	assert.Equal(t, 300_000, e.update(now))
+	send(1, 0)
+	assert.Equal(t, 324_000, e.update(now))
+
+	// It is limited by the received bitrate
+	for k := 0; k < 20; k++ {
+		send(1, 0)
+		e.update(now)
+	}
+	assert.Equal(t, 760_000, e.update(now))
+
+	// The estimate decreases to 85% of the 23 frames received within the last
+	// second once the queuing delay exceeds the threshold, at most once per
+	// second
+	send(1, 2*time.Millisecond)
+	bitrate := e.update(now)
+	assert.Equal(t, 391_000, bitrate)
+	assert.Equal(t, bitrate, e.update(now.Add(500*time.Millisecond)))
+
+	// It is kept within the minimum and maximum
+	e.removeStream(1)
+	assert.Empty(t, e.ssrcs())
+	assert.Equal(t, 30_000, newEstimator(10_000, 30_000, 1_000_000).update(now))
+}
+
+func TestEstimator_ClockDrift(t *testing.T) {
+	e := newEstimator(300_000, 30_000, 1_000_000)
+	e.addStream(1, 90000)
+	now := time.Now()
+	timestamp := uint32(0)
+
+	// The clock of the sender is 50 ppm slower, so the frames seem to arrive
+	// later and later, by 90ms after 30 minutes
+	interval := 40*time.Millisecond + 2*time.Microsecond
+	for seconds := 0; seconds < 30*60; seconds++ {
+		for i := 0; i < 25; i++ {
+			e.addPacket(now, 1, timestamp, 2500)
+			now = now.Add(interval)
+			timestamp += 3600
+		}
+		previous := e.estimate()
+		assert.GreaterOrEqual(t, e.update(now), previous)
+		assert.Less(t, e.queuingDelay(), overuseThreshold)
+	}
+}
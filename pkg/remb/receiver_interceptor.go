@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/gcc"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+// ReceiverInterceptorFactory is a interceptor.Factory for a ReceiverInterceptor.
+type ReceiverInterceptorFactory struct {
+	opts []ReceiverOption
+}
+
+// NewReceiverInterceptor returns a new ReceiverInterceptorFactory.
+func NewReceiverInterceptor(opts ...ReceiverOption) (*ReceiverInterceptorFactory, error) {
+	return &ReceiverInterceptorFactory{opts}, nil
+}
+
+// NewInterceptor constructs a new ReceiverInterceptor.
+func (r *ReceiverInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	receiverInterceptor := &ReceiverInterceptor{
+		interval:       time.Second,
+		initialBitrate: 300_000,
+		minBitrate:     30_000,
+		maxBitrate:     10_000_000,
+		now:            time.Now,
+		log:            logging.NewDefaultLoggerFactory().NewLogger("remb_receiver"),
+		senderSSRC:     rand.Uint32(), // #nosec
+		close:          make(chan struct{}),
+	}
+
+	for _, opt := range r.opts {
+		if err := opt(receiverInterceptor); err != nil {
+			return nil, err
+		}
+	}
+	receiverInterceptor.estimator = newEstimator(
+		receiverInterceptor.initialBitrate, receiverInterceptor.minBitrate, receiverInterceptor.maxBitrate,
+	)
+
+	return receiverInterceptor, nil
+}
+
+// ReceiverInterceptor estimates the bitrate available for the remote streams
+// which negotiated goog-remb feedback, and sends the estimate in a REMB
+// message every interval. The estimate is delay based: it decreases when the
+// time between the arrival of frames grows compared to their RTP timestamps,
+// which indicates queuing on the path, and increases otherwise.
+type ReceiverInterceptor struct {
+	interceptor.NoOp
+	interval       time.Duration
+	initialBitrate int
+	minBitrate     int
+	maxBitrate     int
+	now            func() time.Time
+	log            logging.LeveledLogger
+	senderSSRC     uint32
+	estimator      *estimator
+	burstEstimate  bool
+	burstOpts      []gcc.BurstEstimatorOption
+
+	m     sync.Mutex
+	wg    sync.WaitGroup
+	close chan struct{}
+}
+
+// Estimate returns the current estimate in bits per second, which was sent in
+// the last REMB message, or the initial bitrate before the first one.
+func (r *ReceiverInterceptor) Estimate() int {
+	return r.estimator.estimate()
+}
+
+func (r *ReceiverInterceptor) isClosed() bool {
+	select {
+	case <-r.close:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the interceptor.
+func (r *ReceiverInterceptor) Close() error {
+	defer r.wg.Wait()
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if !r.isClosed() {
+		close(r.close)
+	}
+
+	return nil
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (r *ReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.isClosed() {
+		return writer
+	}
+
+	r.wg.Add(1)
+
+	go r.loop(writer)
+
+	return writer
+}
+
+func (r *ReceiverInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			bitrate := r.estimator.update(r.now())
+			ssrcs := r.estimator.ssrcs()
+			if len(ssrcs) == 0 {
+				continue
+			}
+
+			remb := &rtcp.ReceiverEstimatedMaximumBitrate{
+				SenderSSRC: r.senderSSRC,
+				Bitrate:    float32(bitrate),
+				SSRCs:      ssrcs,
+			}
+			if _, err := rtcpWriter.Write([]rtcp.Packet{remb}, interceptor.Attributes{}); err != nil {
+				r.log.Warnf("failed sending REMB: %+v", err)
+			}
+		case <-r.close:
+			return
+		}
+	}
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+// The returned method will be called once per rtp packet.
+func (r *ReceiverInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	if !streamSupportREMB(info) {
+		return reader
+	}
+
+	r.estimator.addStream(info.SSRC, info.ClockRate)
+
+	var burst *gcc.BurstEstimator
+	if r.burstEstimate {
+		var err error
+		if burst, err = gcc.NewBurstEstimator(r.burstOpts...); err != nil {
+			r.log.Warnf("failed to create burst estimator: %+v", err)
+		}
+	}
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		header, err := attr.GetRTPHeader(b[:i])
+		if err != nil {
+			return 0, nil, err
+		}
+		now := r.now()
+		r.estimator.addPacket(now, info.SSRC, header.Timestamp, i)
+		if burst != nil {
+			if bitrate, ok := burst.OnPacket(now, header, i); ok {
+				r.estimator.seed(bitrate)
+				burst = nil
+			}
+		}
+
+		return i, attr, nil
+	})
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (r *ReceiverInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	r.estimator.removeStream(info.SSRC)
+}
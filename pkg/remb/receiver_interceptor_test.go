@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/gcc"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiverInterceptor(t *testing.T) {
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(10*time.Millisecond),
+		ReceiverBitrates(100_000, 50_000, 1_000_000),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	receiver, ok := i.(*ReceiverInterceptor)
+	assert.True(t, ok)
+	assert.Equal(t, 100_000, receiver.Estimate())
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         123456,
+		ClockRate:    90000,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "goog-remb"}},
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456}, Payload: make([]byte, 1000)})
+	<-stream.ReadRTP()
+
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.Len(t, pkts, 1)
+		remb, ok := pkts[0].(*rtcp.ReceiverEstimatedMaximumBitrate)
+		assert.True(t, ok)
+		assert.Equal(t, []uint32{123456}, remb.SSRCs)
+		assert.InDelta(t, 100_000, remb.Bitrate, 1000)
+		// later updates may have changed the estimate
+		assert.GreaterOrEqual(t, receiver.Estimate(), 50_000)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no REMB")
+	}
+}
+
+func TestReceiverInterceptor_Unsupported(t *testing.T) {
+	f, err := NewReceiverInterceptor(ReceiverInterval(10 * time.Millisecond))
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123456, ClockRate: 90000}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	select {
+	case <-stream.WrittenRTCP():
+		assert.FailNow(t, "REMB for a stream without goog-remb")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for _, opt := range []ReceiverOption{
+		ReceiverBitrates(100_000, 0, 1_000_000),
+		ReceiverBitrates(100_000, 200_000, 1_000_000),
+		ReceiverBitrates(100_000, 50_000, 10_000),
+	} {
+		f, err = NewReceiverInterceptor(opt)
+		assert.NoError(t, err)
+		_, err = f.NewInterceptor("")
+		assert.ErrorIs(t, err, errInvalidBitrates)
+	}
+}
+
+func TestReceiverInterceptor_BurstEstimate(t *testing.T) {
+	f, err := NewReceiverInterceptor(ReceiverInterval(time.Hour), ReceiverBurstEstimate())
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	receiver, ok := i.(*ReceiverInterceptor)
+	assert.True(t, ok)
+	mt := &test.MockTime{}
+	mt.SetNow(time.Unix(1000, 0))
+	receiver.now = mt.Now
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:         123456,
+		ClockRate:    90000,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "goog-remb"}},
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// A frame of 5 packets of 1012 bytes arriving 1ms apart, the first one
+	// only marks the start of the burst
+	for seq := uint16(0); seq < 5; seq++ {
+		stream.ReceiveRTP(&rtp.Packet{
+			Header:  rtp.Header{SSRC: 123456, SequenceNumber: seq, Timestamp: 3000, Marker: seq == 4},
+			Payload: make([]byte, 1000),
+		})
+		<-stream.ReadRTP()
+		mt.SetNow(mt.Now().Add(time.Millisecond))
+	}
+	assert.Equal(t, 4*1012*8*1000/4, receiver.Estimate())
+
+	// Later bursts don't change the estimate
+	for seq := uint16(5); seq < 10; seq++ {
+		stream.ReceiveRTP(&rtp.Packet{
+			Header:  rtp.Header{SSRC: 123456, SequenceNumber: seq, Timestamp: 6000, Marker: seq == 9},
+			Payload: make([]byte, 100),
+		})
+		<-stream.ReadRTP()
+		mt.SetNow(mt.Now().Add(time.Millisecond))
+	}
+	assert.Equal(t, 4*1012*8*1000/4, receiver.Estimate())
+
+	f, err = NewReceiverInterceptor(ReceiverBurstEstimate(gcc.BurstEstimatorMinPackets(1)))
+	assert.NoError(t, err)
+	_, err = f.NewInterceptor("")
+	assert.Error(t, err)
+}
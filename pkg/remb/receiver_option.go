@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pion/interceptor/pkg/gcc"
+	"github.com/pion/logging"
+)
+
+var errInvalidBitrates = errors.New("REMB bitrates must be positive, the minimum below the maximum")
+
+// ReceiverOption can be used to configure ReceiverInterceptor.
+type ReceiverOption func(r *ReceiverInterceptor) error
+
+// ReceiverLog sets a logger for the interceptor.
+func ReceiverLog(log logging.LeveledLogger) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.log = log
+
+		return nil
+	}
+}
+
+// ReceiverInterval sets the interval REMB messages are sent with.
+func ReceiverInterval(interval time.Duration) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.interval = interval
+
+		return nil
+	}
+}
+
+// ReceiverBitrates sets the initial estimate, and the minimum and the maximum
+// the estimate is kept within, in bits per second.
+func ReceiverBitrates(initial, minimum, maximum int) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		if minimum <= 0 || minimum > maximum || initial < minimum || initial > maximum {
+			return errInvalidBitrates
+		}
+		r.initialBitrate, r.minBitrate, r.maxBitrate = initial, minimum, maximum
+
+		return nil
+	}
+}
+
+// ReceiverBurstEstimate seeds the estimate with the estimate of a
+// gcc.BurstEstimator, from the dispersion of the first packet burst of a
+// remote stream, so the REMB messages sent right after joining already
+// approximate the capacity of the path instead of the initial bitrate. Only the
+// first burst estimate of all streams is used.
+func ReceiverBurstEstimate(opts ...gcc.BurstEstimatorOption) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		if _, err := gcc.NewBurstEstimator(opts...); err != nil {
+			return err
+		}
+		r.burstEstimate = true
+		r.burstOpts = opts
+
+		return nil
+	}
+}
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package remb provides interceptors for receiver estimated maximum bitrate
+// (REMB) messages, the congestion control feedback of endpoints which don't
+// support transport wide congestion control. The ReceiverInterceptor
+// estimates the bitrate of the remote streams and sends it in REMB messages,
+// the SenderInterceptor exposes the estimates of received REMB messages.
+package remb
+
+import "github.com/pion/interceptor"
+
+func streamSupportREMB(info *interceptor.StreamInfo) bool {
+	return info.SupportsFeedback("goog-remb", "")
+}
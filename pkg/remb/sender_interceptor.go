@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+// SenderInterceptorFactory is a interceptor.Factory for a SenderInterceptor.
+type SenderInterceptorFactory struct {
+	opts []SenderOption
+}
+
+// NewSenderInterceptor returns a new SenderInterceptorFactory.
+func NewSenderInterceptor(opts ...SenderOption) (*SenderInterceptorFactory, error) {
+	return &SenderInterceptorFactory{opts}, nil
+}
+
+// NewInterceptor constructs a new SenderInterceptor.
+func (s *SenderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	senderInterceptor := &SenderInterceptor{
+		log: logging.NewDefaultLoggerFactory().NewLogger("remb_sender"),
+	}
+
+	for _, opt := range s.opts {
+		if err := opt(senderInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	return senderInterceptor, nil
+}
+
+// SenderInterceptor parses the REMB messages received from the remote and
+// exposes their estimate.
+type SenderInterceptor struct {
+	interceptor.NoOp
+	log        logging.LeveledLogger
+	onEstimate EstimateCallback
+	events     atomic.Pointer[interceptor.EventBus]
+
+	m        sync.Mutex
+	bitrate  int
+	ssrcs    []uint32
+	received bool
+}
+
+// Estimate returns the bitrate in bits per second of the last received REMB
+// message and the SSRCs it applies to, and whether one was received.
+func (s *SenderInterceptor) Estimate() (int, []uint32, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.bitrate, append([]uint32{}, s.ssrcs...), s.received
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (s *SenderInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		pkts, err := attr.GetRTCPPackets(b[:i])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		for _, pkt := range pkts {
+			if remb, ok := pkt.(*rtcp.ReceiverEstimatedMaximumBitrate); ok {
+				s.processREMB(remb)
+			}
+		}
+
+		return i, attr, nil
+	})
+}
+
+func (s *SenderInterceptor) processREMB(remb *rtcp.ReceiverEstimatedMaximumBitrate) {
+	bitrate := int(remb.Bitrate)
+	s.m.Lock()
+	s.bitrate = bitrate
+	s.ssrcs = append([]uint32{}, remb.SSRCs...)
+	s.received = true
+	s.m.Unlock()
+
+	s.log.Debugf("received REMB of %d bps for %v", bitrate, remb.SSRCs)
+	if s.onEstimate != nil {
+		s.onEstimate(bitrate, remb.SSRCs)
+	}
+	s.events.Load().Publish(interceptor.EstimateChanged{Bitrate: bitrate, SSRCs: remb.SSRCs})
+}
+
+// SetEventBus sets the bus an interceptor.EstimateChanged event is published
+// on for every received REMB message.
+func (s *SenderInterceptor) SetEventBus(bus *interceptor.EventBus) {
+	s.events.Store(bus)
+}
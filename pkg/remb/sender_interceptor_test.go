@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"testing"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSenderInterceptor(t *testing.T) {
+	estimates := make(chan int, 1)
+	f, err := NewSenderInterceptor(SenderOnEstimate(func(bitrate int, ssrcs []uint32) {
+		assert.Equal(t, []uint32{1, 2}, ssrcs)
+		estimates <- bitrate
+	}))
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	senderInterceptor, ok := i.(*SenderInterceptor)
+	assert.True(t, ok)
+	_, _, ok = senderInterceptor.Estimate()
+	assert.False(t, ok)
+
+	events := []interceptor.Event{}
+	bus := interceptor.NewEventBus()
+	bus.Subscribe(func(event interceptor.Event) {
+		events = append(events, event)
+	})
+	senderInterceptor.SetEventBus(bus)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 1}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ReceiverEstimatedMaximumBitrate{
+		SenderSSRC: 123,
+		Bitrate:    500_000,
+		SSRCs:      []uint32{1, 2},
+	}})
+	<-stream.ReadRTCP()
+	assert.Equal(t, 500_000, <-estimates)
+	assert.Equal(t, []interceptor.Event{interceptor.EstimateChanged{Bitrate: 500_000, SSRCs: []uint32{1, 2}}}, events)
+
+	bitrate, ssrcs, ok := senderInterceptor.Estimate()
+	assert.True(t, ok)
+	assert.Equal(t, 500_000, bitrate)
+	assert.Equal(t, []uint32{1, 2}, ssrcs)
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package remb
+
+import (
+	"github.com/pion/logging"
+)
+
+// EstimateCallback is called with the bitrate in bits per second of a
+// received REMB message, and the SSRCs of the local streams it applies to.
+type EstimateCallback func(bitrate int, ssrcs []uint32)
+
+// SenderOption can be used to configure SenderInterceptor.
+type SenderOption func(s *SenderInterceptor) error
+
+// SenderLog sets a logger for the interceptor.
+func SenderLog(log logging.LeveledLogger) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.log = log
+
+		return nil
+	}
+}
+
+// SenderOnEstimate sets a callback which is called with the estimate of every
+// received REMB message. It is called from the RTCP reader and should return
+// quickly.
+func SenderOnEstimate(cb EstimateCallback) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.onEstimate = cb
+
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Packet is a RTP or RTCP packet read from a capture.
+type Packet struct {
+	// Offset is the time the packet was captured relative to the start of the
+	// capture.
+	Offset time.Duration
+	// Source and Destination are the UDP addresses of the packet, if the
+	// capture format records them.
+	Source      netip.AddrPort
+	Destination netip.AddrPort
+	Data        []byte
+}
+
+// IsRTCP returns whether the packet is a RTCP packet, distinguished from RTP
+// by the payload type range reserved for RTCP in RFC 5761.
+func (p *Packet) IsRTCP() bool {
+	return len(p.Data) > 1 && p.Data[1] >= 192 && p.Data[1] <= 223
+}
+
+// isRTPOrRTCP returns whether data looks like a RTP or RTCP packet of version 2,
+// as opposed to e.g. STUN or DTLS multiplexed on the same port.
+func isRTPOrRTCP(data []byte) bool {
+	return len(data) >= 4 && data[0]>>6 == 2
+}
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/netip"
+	"time"
+)
+
+var (
+	errInvalidPcap         = errors.New("invalid pcap file")
+	errUnsupportedLinkType = errors.New("unsupported pcap link type")
+)
+
+const (
+	pcapMagicMicroseconds = 0xa1b2c3d4
+	pcapMagicNanoseconds  = 0xa1b23c4d
+
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLinuxSLL = 113
+
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+
+	protocolUDP = 17
+
+	// maxSnapLen is the largest snapshot length of libpcap, which is assumed
+	// for captures not declaring one.
+	maxSnapLen = 262144
+)
+
+// ReadPcap reads the RTP and RTCP packets sent over UDP from a capture in the
+// pcap format, as written by e.g. tcpdump and Wireshark. Other packets, e.g.
+// STUN and DTLS, are skipped. SRTP can't be decrypted, so the capture must
+// contain plain RTP.
+func ReadPcap(r io.Reader) ([]*Packet, error) {
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errInvalidPcap
+	}
+
+	var order binary.ByteOrder
+	var resolution time.Duration
+	switch {
+	case binary.LittleEndian.Uint32(header) == pcapMagicMicroseconds:
+		order, resolution = binary.LittleEndian, time.Microsecond
+	case binary.BigEndian.Uint32(header) == pcapMagicMicroseconds:
+		order, resolution = binary.BigEndian, time.Microsecond
+	case binary.LittleEndian.Uint32(header) == pcapMagicNanoseconds:
+		order, resolution = binary.LittleEndian, time.Nanosecond
+	case binary.BigEndian.Uint32(header) == pcapMagicNanoseconds:
+		order, resolution = binary.BigEndian, time.Nanosecond
+	default:
+		return nil, errInvalidPcap
+	}
+	snapLen := order.Uint32(header[16:20])
+	if snapLen == 0 || snapLen > maxSnapLen {
+		snapLen = maxSnapLen
+	}
+	linkType := order.Uint32(header[20:24])
+	switch linkType {
+	case linkTypeNull, linkTypeEthernet, linkTypeRaw, linkTypeLinuxSLL:
+	default:
+		return nil, errUnsupportedLinkType
+	}
+
+	pkts := []*Packet{}
+	var start time.Time
+	record := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(r, record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return pkts, nil
+			}
+
+			return nil, errInvalidPcap
+		}
+		timestamp := time.Unix(int64(order.Uint32(record[0:4])), int64(order.Uint32(record[4:8]))*int64(resolution))
+		// A record can't be longer than the snapshot length, a larger length
+		// is corrupt and mustn't be allocated
+		length := order.Uint32(record[8:12])
+		if length > snapLen {
+			return nil, errInvalidPcap
+		}
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return nil, errInvalidPcap
+		}
+
+		pkt := parseFrame(linkType, frame)
+		if pkt == nil {
+			continue
+		}
+		if start.IsZero() {
+			start = timestamp
+		}
+		pkt.Offset = timestamp.Sub(start)
+		pkts = append(pkts, pkt)
+	}
+}
+
+// parseFrame returns the RTP or RTCP packet in frame, or nil if it doesn't
+// contain one.
+func parseFrame(linkType uint32, frame []byte) *Packet {
+	var etherType uint16
+	switch linkType {
+	case linkTypeEthernet:
+		if len(frame) < 14 {
+			return nil
+		}
+		etherType = binary.BigEndian.Uint16(frame[12:14])
+		frame = frame[14:]
+		for etherType == etherTypeVLAN && len(frame) >= 4 {
+			etherType = binary.BigEndian.Uint16(frame[2:4])
+			frame = frame[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(frame) < 16 {
+			return nil
+		}
+		etherType = binary.BigEndian.Uint16(frame[14:16])
+		frame = frame[16:]
+	default:
+		if linkType == linkTypeNull {
+			if len(frame) < 4 {
+				return nil
+			}
+			frame = frame[4:]
+		}
+		if len(frame) == 0 {
+			return nil
+		}
+		etherType = etherTypeIPv4
+		if frame[0]>>4 == 6 {
+			etherType = etherTypeIPv6
+		}
+	}
+
+	return parseIP(etherType, frame)
+}
+
+func parseIP(etherType uint16, packet []byte) *Packet {
+	var source, destination netip.Addr
+	switch etherType {
+	case etherTypeIPv4:
+		if len(packet) < 20 || packet[9] != protocolUDP {
+			return nil
+		}
+		headerLength := int(packet[0]&0x0f) * 4
+		if len(packet) < headerLength {
+			return nil
+		}
+		source = netip.AddrFrom4([4]byte(packet[12:16]))
+		destination = netip.AddrFrom4([4]byte(packet[16:20]))
+		packet = packet[headerLength:]
+	case etherTypeIPv6:
+		// Extension headers aren't supported
+		if len(packet) < 40 || packet[6] != protocolUDP {
+			return nil
+		}
+		source = netip.AddrFrom16([16]byte(packet[8:24]))
+		destination = netip.AddrFrom16([16]byte(packet[24:40]))
+		packet = packet[40:]
+	default:
+		return nil
+	}
+
+	if len(packet) < 8 {
+		return nil
+	}
+	data := packet[8:]
+	if !isRTPOrRTCP(data) {
+		return nil
+	}
+
+	return &Packet{
+		Source:      netip.AddrPortFrom(source, binary.BigEndian.Uint16(packet[0:2])),
+		Destination: netip.AddrPortFrom(destination, binary.BigEndian.Uint16(packet[2:4])),
+		Data:        data,
+	}
+}
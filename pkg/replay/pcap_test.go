@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePcapHeader(b *bytes.Buffer, linkType uint32) {
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:4], pcapMagicMicroseconds)
+	binary.LittleEndian.PutUint32(header[16:20], 65535)
+	binary.LittleEndian.PutUint32(header[20:24], linkType)
+	b.Write(header)
+}
+
+func writePcapRecord(b *bytes.Buffer, ts time.Time, frame []byte) {
+	record := make([]byte, 16)
+	binary.LittleEndian.PutUint32(record[0:4], uint32(ts.Unix()))            //nolint:gosec // G115
+	binary.LittleEndian.PutUint32(record[4:8], uint32(ts.Nanosecond()/1000)) //nolint:gosec // G115
+	binary.LittleEndian.PutUint32(record[8:12], uint32(len(frame)))          //nolint:gosec // G115
+	binary.LittleEndian.PutUint32(record[12:16], uint32(len(frame)))         //nolint:gosec // G115
+	b.Write(record)
+	b.Write(frame)
+}
+
+// ethernetFrame returns a Ethernet frame with a IPv4 UDP packet from
+// 10.0.0.1:5000 to 10.0.0.2:6000.
+func ethernetFrame(payload []byte) []byte {
+	frame := make([]byte, 14+20+8)
+	binary.BigEndian.PutUint16(frame[12:14], etherTypeIPv4)
+	ip := frame[14:]
+	ip[0] = 0x45
+	ip[9] = protocolUDP
+	copy(ip[12:16], []byte{10, 0, 0, 1})
+	copy(ip[16:20], []byte{10, 0, 0, 2})
+	udp := ip[20:]
+	binary.BigEndian.PutUint16(udp[0:2], 5000)
+	binary.BigEndian.PutUint16(udp[2:4], 6000)
+
+	return append(frame, payload...)
+}
+
+func TestReadPcap(t *testing.T) {
+	rtpPkt := []byte{0x80, 96, 0, 1, 0, 0, 0, 0, 0, 0, 0, 123}
+	stun := []byte{0, 1, 0, 0, 0x21, 0x12, 0xa4, 0x42}
+
+	capture := &bytes.Buffer{}
+	writePcapHeader(capture, linkTypeEthernet)
+	start := time.Unix(1000, 0)
+	writePcapRecord(capture, start, ethernetFrame(stun))
+	writePcapRecord(capture, start.Add(10*time.Millisecond), ethernetFrame(rtpPkt))
+	writePcapRecord(capture, start.Add(25*time.Millisecond), ethernetFrame(rtpPkt))
+
+	pkts, err := ReadPcap(capture)
+	require.NoError(t, err)
+	require.Len(t, pkts, 2)
+	assert.Equal(t, time.Duration(0), pkts[0].Offset)
+	assert.Equal(t, 15*time.Millisecond, pkts[1].Offset)
+	assert.Equal(t, rtpPkt, pkts[0].Data)
+	assert.Equal(t, netip.MustParseAddrPort("10.0.0.1:5000"), pkts[0].Source)
+	assert.Equal(t, netip.MustParseAddrPort("10.0.0.2:6000"), pkts[0].Destination)
+}
+
+func TestReadPcap_Invalid(t *testing.T) {
+	_, err := ReadPcap(bytes.NewBuffer(make([]byte, 24)))
+	assert.ErrorIs(t, err, errInvalidPcap)
+
+	capture := &bytes.Buffer{}
+	writePcapHeader(capture, 147)
+	_, err = ReadPcap(capture)
+	assert.ErrorIs(t, err, errUnsupportedLinkType)
+
+	// Records longer than the snapshot length are corrupt
+	capture = &bytes.Buffer{}
+	writePcapHeader(capture, linkTypeEthernet)
+	record := make([]byte, 16)
+	binary.LittleEndian.PutUint32(record[8:12], 0xffffffff)
+	capture.Write(record)
+	_, err = ReadPcap(capture)
+	assert.ErrorIs(t, err, errInvalidPcap)
+}
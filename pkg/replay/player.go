@@ -0,0 +1,302 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package replay feeds the packets of a capture through an interceptor with
+// their original timing, so the RTCP and estimates of interceptors can be
+// checked against traffic recorded in the field.
+package replay
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+var errInvalidSpeed = errors.New("speed must not be negative")
+
+// Direction is the direction of a replayed packet, seen from the side the
+// interceptor runs on.
+type Direction int
+
+const (
+	// DirectionIncoming packets are read from the remote peer.
+	DirectionIncoming Direction = iota
+	// DirectionOutgoing packets are written to the remote peer.
+	DirectionOutgoing
+)
+
+// Option can be used to configure the Player.
+type Option func(*Player) error
+
+// Speed sets the factor by which playback is accelerated, e.g. 2 plays a
+// capture in half its duration. 0 plays it as fast as possible. The default is
+// 1, the original timing.
+func Speed(speed float64) Option {
+	return func(p *Player) error {
+		if speed < 0 {
+			return errInvalidSpeed
+		}
+		p.speed = speed
+
+		return nil
+	}
+}
+
+// Streams sets the StreamInfo streams are bound with, e.g. to provide the
+// clock rate and the negotiated feedback. Streams without one are bound with a
+// StreamInfo only carrying their SSRC.
+func Streams(infos ...*interceptor.StreamInfo) Option {
+	return func(p *Player) error {
+		for _, info := range infos {
+			p.streams[info.SSRC] = info
+		}
+
+		return nil
+	}
+}
+
+// Classify sets the function deciding the Direction of a packet, e.g. by its
+// Destination. By default all packets are incoming.
+func Classify(classify func(*Packet) Direction) Option {
+	return func(p *Player) error {
+		p.classify = classify
+
+		return nil
+	}
+}
+
+// Log sets a logger for the Player.
+func Log(log logging.LeveledLogger) Option {
+	return func(p *Player) error {
+		p.log = log
+
+		return nil
+	}
+}
+
+// RTCP is a batch of RTCP packets written by the interceptor.
+type RTCP struct {
+	// Offset is the time the batch was written in the timeline of the capture.
+	Offset  time.Duration
+	Packets []rtcp.Packet
+}
+
+// Player plays captured packets through an interceptor. Incoming packets are
+// read through the streams bound with BindRemoteStream and BindRTCPReader,
+// outgoing ones are written through the streams bound with BindLocalStream and
+// BindRTCPWriter.
+type Player struct {
+	log         logging.LeveledLogger
+	interceptor interceptor.Interceptor
+	speed       float64
+	classify    func(*Packet) Direction
+	streams     map[uint32]*interceptor.StreamInfo
+
+	rtcpReader    interceptor.RTCPReader
+	rtcpWriter    interceptor.RTCPWriter
+	remoteStreams map[uint32]interceptor.RTPReader
+	localStreams  map[uint32]interceptor.RTPWriter
+	pending       []byte
+
+	m       sync.Mutex
+	started time.Time
+	offset  time.Duration
+	rtcp    []RTCP
+}
+
+// NewPlayer returns a Player for the interceptor, e.g. a chain built from a
+// interceptor.Registry. The interceptor keeps being owned by the caller, who
+// has to close it.
+func NewPlayer(i interceptor.Interceptor, opts ...Option) (*Player, error) {
+	player := &Player{
+		log:         logging.NewDefaultLoggerFactory().NewLogger("replay"),
+		interceptor: i,
+		speed:       1,
+		classify: func(*Packet) Direction {
+			return DirectionIncoming
+		},
+		streams:       map[uint32]*interceptor.StreamInfo{},
+		rtcpReader:    nil,
+		rtcpWriter:    nil,
+		remoteStreams: map[uint32]interceptor.RTPReader{},
+		localStreams:  map[uint32]interceptor.RTPWriter{},
+		pending:       nil,
+		started:       time.Time{},
+		offset:        0,
+		rtcp:          []RTCP{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(player); err != nil {
+			return nil, err
+		}
+	}
+
+	player.rtcpReader = i.BindRTCPReader(interceptor.RTCPReaderFunc(player.readPending))
+	player.rtcpWriter = i.BindRTCPWriter(interceptor.RTCPWriterFunc(player.writeRTCP))
+
+	return player, nil
+}
+
+// Play plays the packets, waiting for the offset of each one depending on
+// the Speed. It returns after the last packet was played. Malformed packets,
+// which captures from the field may contain, are logged and skipped. RTCP
+// written by the interceptor afterwards, e.g. reports sent on a timer, is
+// still collected.
+func (p *Player) Play(pkts []*Packet) error {
+	p.m.Lock()
+	p.started = time.Now()
+	p.m.Unlock()
+
+	for _, pkt := range pkts {
+		if p.speed > 0 {
+			time.Sleep(time.Until(p.started.Add(time.Duration(float64(pkt.Offset) / p.speed))))
+		}
+		p.m.Lock()
+		p.offset = pkt.Offset
+		p.m.Unlock()
+
+		var err error
+		switch {
+		case p.classify(pkt) == DirectionOutgoing && pkt.IsRTCP():
+			err = p.writeOutgoingRTCP(pkt.Data)
+		case p.classify(pkt) == DirectionOutgoing:
+			err = p.writeOutgoingRTP(pkt.Data)
+		case pkt.IsRTCP():
+			err = p.readIncoming(p.rtcpReader, pkt.Data)
+		default:
+			err = p.readIncomingRTP(pkt.Data)
+		}
+		if err != nil {
+			p.log.Warnf("skipping malformed replayed packet at %v: %v", pkt.Offset, err)
+		}
+	}
+
+	return nil
+}
+
+// RTCP returns the RTCP written by the interceptor so far, including the
+// outgoing RTCP of the capture.
+func (p *Player) RTCP() []RTCP {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return append([]RTCP{}, p.rtcp...)
+}
+
+// Close unbinds the streams bound while playing.
+func (p *Player) Close() error {
+	for ssrc := range p.remoteStreams {
+		p.interceptor.UnbindRemoteStream(p.streamInfo(ssrc))
+	}
+	for ssrc := range p.localStreams {
+		p.interceptor.UnbindLocalStream(p.streamInfo(ssrc))
+	}
+
+	return nil
+}
+
+func (p *Player) streamInfo(ssrc uint32) *interceptor.StreamInfo {
+	if info, ok := p.streams[ssrc]; ok {
+		return info
+	}
+	info := &interceptor.StreamInfo{SSRC: ssrc}
+	p.streams[ssrc] = info
+
+	return info
+}
+
+func (p *Player) readIncomingRTP(data []byte) error {
+	header := &rtp.Header{}
+	if _, err := header.Unmarshal(data); err != nil {
+		return err
+	}
+
+	reader, ok := p.remoteStreams[header.SSRC]
+	if !ok {
+		reader = p.interceptor.BindRemoteStream(p.streamInfo(header.SSRC), interceptor.RTPReaderFunc(p.readPending))
+		p.remoteStreams[header.SSRC] = reader
+	}
+
+	return p.readIncoming(reader, data)
+}
+
+type reader interface {
+	Read([]byte, interceptor.Attributes) (int, interceptor.Attributes, error)
+}
+
+// readIncoming reads data through the reader. Interceptors may drop or hold
+// back packets and try reading the next one, which ends the read.
+func (p *Player) readIncoming(r reader, data []byte) error {
+	p.pending = data
+	_, _, err := r.Read(make([]byte, len(data)), interceptor.Attributes{})
+	p.pending = nil
+	if err != nil && !errors.Is(err, io.EOF) {
+		p.log.Warnf("failed to read replayed packet: %v", err)
+	}
+
+	return nil
+}
+
+func (p *Player) readPending(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+	if p.pending == nil {
+		return 0, nil, io.EOF
+	}
+	n := copy(b, p.pending)
+	p.pending = nil
+
+	return n, a, nil
+}
+
+func (p *Player) writeOutgoingRTP(data []byte) error {
+	pkt := &rtp.Packet{}
+	if err := pkt.Unmarshal(data); err != nil {
+		return err
+	}
+
+	writer, ok := p.localStreams[pkt.SSRC]
+	if !ok {
+		writer = p.interceptor.BindLocalStream(p.streamInfo(pkt.SSRC), interceptor.RTPWriterFunc(
+			func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+				return header.MarshalSize() + len(payload), nil
+			},
+		))
+		p.localStreams[pkt.SSRC] = writer
+	}
+	if _, err := writer.Write(&pkt.Header, pkt.Payload, interceptor.Attributes{}); err != nil {
+		p.log.Warnf("failed to write replayed packet: %v", err)
+	}
+
+	return nil
+}
+
+func (p *Player) writeOutgoingRTCP(data []byte) error {
+	pkts, err := rtcp.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := p.rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
+		p.log.Warnf("failed to write replayed packet: %v", err)
+	}
+
+	return nil
+}
+
+func (p *Player) writeRTCP(pkts []rtcp.Packet, _ interceptor.Attributes) (int, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	offset := p.offset
+	if p.speed > 0 && !p.started.IsZero() {
+		offset = time.Duration(float64(time.Since(p.started)) * p.speed)
+	}
+	p.rtcp = append(p.rtcp, RTCP{Offset: offset, Packets: pkts})
+
+	return 0, nil
+}
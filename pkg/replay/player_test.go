@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/mock"
+	"github.com/pion/interceptor/pkg/nack"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rtpPacket(t *testing.T, offset time.Duration, ssrc uint32, seq uint16) *Packet {
+	t.Helper()
+
+	data, err := (&rtp.Packet{
+		Header:  rtp.Header{Version: 2, SSRC: ssrc, SequenceNumber: seq},
+		Payload: []byte{1, 2, 3},
+	}).Marshal()
+	require.NoError(t, err)
+
+	return &Packet{Offset: offset, Data: data}
+}
+
+func TestPlayer_Incoming(t *testing.T) {
+	factory, err := nack.NewGeneratorInterceptor(nack.GeneratorInterval(10 * time.Millisecond))
+	require.NoError(t, err)
+	generator, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, generator.Close())
+	}()
+
+	player, err := NewPlayer(generator, Speed(0), Streams(&interceptor.StreamInfo{
+		SSRC:         123,
+		RTCPFeedback: []interceptor.RTCPFeedback{{Type: "nack"}},
+	}))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, player.Close())
+	}()
+
+	require.NoError(t, player.Play([]*Packet{
+		rtpPacket(t, 0, 123, 10),
+		rtpPacket(t, 20*time.Millisecond, 123, 11),
+		rtpPacket(t, 40*time.Millisecond, 123, 14),
+	}))
+
+	var nacks *rtcp.TransportLayerNack
+	assert.Eventually(t, func() bool {
+		for _, r := range player.RTCP() {
+			for _, pkt := range r.Packets {
+				if n, ok := pkt.(*rtcp.TransportLayerNack); ok {
+					nacks = n
+					assert.Equal(t, 40*time.Millisecond, r.Offset)
+
+					return true
+				}
+			}
+		}
+
+		return false
+	}, time.Second, 10*time.Millisecond)
+	require.NotNil(t, nacks)
+	assert.Equal(t, uint32(123), nacks.MediaSSRC)
+	assert.Equal(t, []uint16{12, 13}, nacks.Nacks[0].PacketList())
+}
+
+func TestPlayer_Outgoing(t *testing.T) {
+	written := make(chan uint16, 10)
+	probe := &mock.Interceptor{
+		BindLocalStreamFn: func(_ *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
+			return interceptor.RTPWriterFunc(
+				func(header *rtp.Header, payload []byte, a interceptor.Attributes) (int, error) {
+					written <- header.SequenceNumber
+
+					return writer.Write(header, payload, a)
+				},
+			)
+		},
+	}
+
+	pli, err := rtcp.Marshal([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 123}})
+	require.NoError(t, err)
+
+	player, err := NewPlayer(probe, Classify(func(p *Packet) Direction {
+		if p.IsRTCP() {
+			return DirectionOutgoing
+		}
+
+		return DirectionIncoming
+	}))
+	require.NoError(t, err)
+
+	started := time.Now()
+	require.NoError(t, player.Play([]*Packet{
+		rtpPacket(t, 0, 123, 1),
+		{Offset: 50 * time.Millisecond, Data: pli},
+	}))
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+	assert.NoError(t, player.Close())
+
+	// The RTP packet is incoming, so it isn't written
+	assert.Empty(t, written)
+
+	writes := player.RTCP()
+	require.Len(t, writes, 1)
+	assert.GreaterOrEqual(t, writes[0].Offset, 50*time.Millisecond)
+	assert.Equal(t, []rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 123}}, writes[0].Packets)
+}
+
+func TestPlayer_OutgoingRTP(t *testing.T) {
+	written := make(chan uint16, 10)
+	probe := &mock.Interceptor{
+		BindLocalStreamFn: func(_ *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
+			return interceptor.RTPWriterFunc(
+				func(header *rtp.Header, payload []byte, a interceptor.Attributes) (int, error) {
+					written <- header.SequenceNumber
+
+					return writer.Write(header, payload, a)
+				},
+			)
+		},
+	}
+
+	player, err := NewPlayer(probe, Speed(0), Classify(func(*Packet) Direction {
+		return DirectionOutgoing
+	}))
+	require.NoError(t, err)
+	// The malformed packet in between is skipped
+	require.NoError(t, player.Play([]*Packet{
+		rtpPacket(t, 0, 123, 1),
+		{Offset: time.Minute, Data: []byte{0x80, 96}},
+		rtpPacket(t, time.Hour, 123, 2),
+	}))
+	assert.NoError(t, player.Close())
+
+	assert.Equal(t, uint16(1), <-written)
+	assert.Equal(t, uint16(2), <-written)
+}
+
+func TestNewPlayer_InvalidSpeed(t *testing.T) {
+	_, err := NewPlayer(&mock.Interceptor{}, Speed(-1))
+	assert.ErrorIs(t, err, errInvalidSpeed)
+}
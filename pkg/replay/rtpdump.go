@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var errInvalidRTPDump = errors.New("invalid rtpdump file")
+
+const (
+	rtpDumpMagic        = "#!rtpplay1.0 "
+	rtpDumpHeaderSize   = 16
+	rtpDumpPacketHeader = 8
+)
+
+// ReadRTPDump reads the packets of a capture in the rtpdump format of
+// rtptools, as written by e.g. rtpdump and Wireshark.
+func ReadRTPDump(r io.Reader) ([]*Packet, error) {
+	reader := bufio.NewReader(r)
+	line, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, rtpDumpMagic) {
+		return nil, errInvalidRTPDump
+	}
+
+	// The file header holds the start time and source address, offsets are
+	// relative to the start.
+	if _, err := io.ReadFull(reader, make([]byte, rtpDumpHeaderSize)); err != nil {
+		return nil, errInvalidRTPDump
+	}
+
+	pkts := []*Packet{}
+	header := make([]byte, rtpDumpPacketHeader)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return pkts, nil
+			}
+
+			return nil, errInvalidRTPDump
+		}
+		length := int(binary.BigEndian.Uint16(header[0:2]))
+		if length < rtpDumpPacketHeader {
+			return nil, errInvalidRTPDump
+		}
+		offset := binary.BigEndian.Uint32(header[4:8])
+
+		data := make([]byte, length-rtpDumpPacketHeader)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, errInvalidRTPDump
+		}
+		if !isRTPOrRTCP(data) {
+			continue
+		}
+		pkts = append(pkts, &Packet{
+			Offset: time.Duration(offset) * time.Millisecond,
+			Data:   data,
+		})
+	}
+}
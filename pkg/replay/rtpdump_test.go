@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRTPDump(t *testing.T) {
+	rtpPkt := []byte{0x80, 96, 0, 1, 0, 0, 0, 0, 0, 0, 0, 123}
+	rtcpPkt := []byte{0x81, 201, 0, 1, 0, 0, 0, 123}
+
+	dump := bytes.NewBufferString("#!rtpplay1.0 127.0.0.1/5000\n")
+	dump.Write(make([]byte, 16))
+	for _, p := range []struct {
+		offset uint32
+		data   []byte
+	}{{10, rtpPkt}, {30, rtcpPkt}, {40, []byte{0, 1, 0, 0}}} {
+		header := make([]byte, 8)
+		binary.BigEndian.PutUint16(header[0:2], uint16(8+len(p.data))) //nolint:gosec // G115
+		binary.BigEndian.PutUint16(header[2:4], uint16(len(p.data)))   //nolint:gosec // G115
+		binary.BigEndian.PutUint32(header[4:8], p.offset)
+		dump.Write(header)
+		dump.Write(p.data)
+	}
+
+	pkts, err := ReadRTPDump(dump)
+	require.NoError(t, err)
+	require.Len(t, pkts, 2)
+	assert.Equal(t, 10*time.Millisecond, pkts[0].Offset)
+	assert.Equal(t, rtpPkt, pkts[0].Data)
+	assert.False(t, pkts[0].IsRTCP())
+	assert.Equal(t, 30*time.Millisecond, pkts[1].Offset)
+	assert.True(t, pkts[1].IsRTCP())
+}
+
+func TestReadRTPDump_Invalid(t *testing.T) {
+	_, err := ReadRTPDump(bytes.NewBufferString("RTP\n"))
+	assert.ErrorIs(t, err, errInvalidRTPDump)
+
+	dump := bytes.NewBufferString("#!rtpplay1.0 127.0.0.1/5000\n")
+	dump.Write(make([]byte, 16))
+	dump.Write([]byte{0, 20, 0, 12, 0, 0, 0, 0, 0x80})
+	_, err = ReadRTPDump(dump)
+	assert.ErrorIs(t, err, errInvalidRTPDump)
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"errors"
+	"time"
+)
+
+var errInvalidAdaptiveInterval = errors.New("adaptive report interval must be positive and active <= idle")
+
+// adaptiveInterval chooses the interval until the next report depending on
+// whether the streams were active since the previous one.
+type adaptiveInterval struct {
+	active time.Duration
+	idle   time.Duration
+}
+
+func newAdaptiveInterval(active, idle time.Duration) (*adaptiveInterval, error) {
+	if active <= 0 || idle < active {
+		return nil, errInvalidAdaptiveInterval
+	}
+
+	return &adaptiveInterval{
+		active: active,
+		idle:   idle,
+	}, nil
+}
+
+func (a *adaptiveInterval) next(active bool) time.Duration {
+	if active {
+		return a.active
+	}
+
+	return a.idle
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"sort"
+
+	"github.com/pion/rtcp"
+)
+
+// maxReceptionReports is the number of reception report blocks fitting into
+// the 5 bit count of a receiver report.
+const maxReceptionReports = 31
+
+// combinedReport is the report of a stream pending to be combined with the
+// reports of the other streams sent from the same SSRC.
+type combinedReport struct {
+	receiverSSRC uint32
+	report       rtcp.ReceptionReport
+	blocks       []rtcp.ReportBlock
+}
+
+// combinedReportChunk is a receiver report combining the reception reports of
+// up to maxReceptionReports streams, with their extended report blocks. first
+// is set for the first chunk of every receiver SSRC.
+type combinedReportChunk struct {
+	rr     *rtcp.ReceiverReport
+	blocks []rtcp.ReportBlock
+	first  bool
+}
+
+// combineReports combines the reports by their receiver SSRC into receiver
+// reports of at most maxReceptionReports blocks, ordered by SSRC.
+func combineReports(reports []combinedReport) []combinedReportChunk {
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].receiverSSRC != reports[j].receiverSSRC {
+			return reports[i].receiverSSRC < reports[j].receiverSSRC
+		}
+
+		return reports[i].report.SSRC < reports[j].report.SSRC
+	})
+
+	var chunks []combinedReportChunk
+	for k, report := range reports {
+		first := k == 0 || reports[k-1].receiverSSRC != report.receiverSSRC
+		if first || len(chunks[len(chunks)-1].rr.Reports) == maxReceptionReports {
+			chunks = append(chunks, combinedReportChunk{
+				rr:    &rtcp.ReceiverReport{SSRC: report.receiverSSRC},
+				first: first,
+			})
+		}
+		chunk := &chunks[len(chunks)-1]
+		chunk.rr.Reports = append(chunk.rr.Reports, report.report)
+		chunk.blocks = append(chunk.blocks, report.blocks...)
+	}
+
+	return chunks
+}
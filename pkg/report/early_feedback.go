@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var errInvalidMinInterval = errors.New("minimum report interval must not be negative")
+
+// earlyFeedback implements the timing rules of RFC 4585 section 3.5.3 for
+// early receiver reports. An early report is allowed once per regular
+// interval, and the regular report following it is skipped, which doubles
+// that interval to keep the average RTCP bandwidth.
+type earlyFeedback struct {
+	// minInterval is T_rr_interval, regular reports sent less than
+	// minInterval after the previous one are suppressed.
+	minInterval time.Duration
+
+	mu          sync.Mutex
+	allowEarly  bool
+	skipRegular bool
+	lastRegular time.Time
+}
+
+func newEarlyFeedback(minInterval time.Duration) (*earlyFeedback, error) {
+	if minInterval < 0 {
+		return nil, errInvalidMinInterval
+	}
+
+	return &earlyFeedback{
+		minInterval: minInterval,
+		allowEarly:  true,
+	}, nil
+}
+
+// early returns whether an early report may be sent now, committing to send
+// it if so.
+func (e *earlyFeedback) early() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.allowEarly {
+		return false
+	}
+	e.allowEarly = false
+	e.skipRegular = true
+
+	return true
+}
+
+// regular returns whether the regular report due now should be sent.
+func (e *earlyFeedback) regular(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.skipRegular {
+		e.skipRegular = false
+
+		return false
+	}
+	e.allowEarly = true
+	if e.minInterval > 0 && !e.lastRegular.IsZero() && now.Sub(e.lastRegular) < e.minInterval {
+		return false
+	}
+	e.lastRegular = now
+
+	return true
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"errors"
+)
+
+var errInvalidFractionLostSmoothing = errors.New("fraction lost smoothing factor must be in (0, 1]")
+
+// fractionLostFilter holds the settings of ReceiverFractionLost.
+type fractionLostFilter struct {
+	minPackets uint64
+	smoothing  float64
+}
+
+func newFractionLostFilter(minPackets uint32, smoothing float64) (*fractionLostFilter, error) {
+	if smoothing <= 0 || smoothing > 1 {
+		return nil, errInvalidFractionLostSmoothing
+	}
+
+	return &fractionLostFilter{
+		minPackets: uint64(minPackets),
+		smoothing:  smoothing,
+	}, nil
+}
+
+// fractionLost is the state of the filtered fraction lost of a stream.
+type fractionLost struct {
+	// expected and lost are the packets of the intervals since the fraction
+	// was last computed, which had too few packets on their own.
+	expected uint64
+	lost     uint64
+	started  bool
+	smoothed float64
+	reported uint8
+}
+
+// update returns the fraction lost to report for an interval in which
+// expected packets were expected and lost of them were lost.
+func (f *fractionLost) update(filter *fractionLostFilter, expected, lost uint64) uint8 {
+	f.expected += expected
+	f.lost += lost
+	if f.expected == 0 || f.expected < filter.minPackets {
+		return f.reported
+	}
+
+	fraction := float64(f.lost) / float64(f.expected)
+	f.expected = 0
+	f.lost = 0
+	if f.started {
+		fraction = filter.smoothing*fraction + (1-filter.smoothing)*f.smoothed
+	}
+	f.started = true
+	f.smoothed = fraction
+
+	reported := fraction * 256
+	if reported > 255 {
+		reported = 255
+	}
+	f.reported = uint8(reported)
+
+	return f.reported
+}
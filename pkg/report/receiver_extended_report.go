@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"errors"
+	"math"
+
+	"github.com/pion/rtcp"
+)
+
+var errUnsupportedExtendedReport = errors.New("unsupported RTCP XR report block type")
+
+const (
+	// maxRunLength is the longest run of a run length chunk.
+	maxRunLength = 1<<14 - 1
+	// bitVectorLength is the number of packets of a bit vector chunk.
+	bitVectorLength = 15
+)
+
+// extendedReports holds the RTCP XR report blocks sent with every receiver
+// report.
+type extendedReports struct {
+	lossRLE           bool
+	statisticsSummary bool
+}
+
+func newExtendedReports(types []rtcp.BlockTypeType) (extendedReports, error) {
+	xr := extendedReports{}
+	for _, t := range types {
+		switch t {
+		case rtcp.LossRLEReportBlockType:
+			xr.lossRLE = true
+		case rtcp.StatisticsSummaryReportBlockType:
+			xr.statisticsSummary = true
+		default:
+			return xr, errUnsupportedExtendedReport
+		}
+	}
+
+	return xr, nil
+}
+
+// summary holds the statistics of a value of the packets since the previous
+// report, e.g. the interarrival jitter in timestamp units.
+type summary struct {
+	count uint32
+	min   float64
+	max   float64
+	sum   float64
+	sumSq float64
+}
+
+func (j *summary) add(d float64) {
+	if j.count == 0 || d < j.min {
+		j.min = d
+	}
+	if d > j.max {
+		j.max = d
+	}
+	j.count++
+	j.sum += d
+	j.sumSq += d * d
+}
+
+func (j *summary) mean() float64 {
+	return j.sum / float64(j.count)
+}
+
+// dev returns the standard deviation.
+func (j *summary) dev() float64 {
+	mean := j.mean()
+
+	return math.Sqrt(math.Max(0, j.sumSq/float64(j.count)-mean*mean))
+}
+
+// extendedReportBlocks returns the XR report blocks covering the packets since
+// the previous report. It must be called with stream.m held, before the
+// receiver report is generated.
+func (stream *receiverStream) extendedReportBlocks(xr extendedReports) []rtcp.ReportBlock {
+	if !stream.started || stream.seqnums.Highest() == stream.lastReportSeqnum {
+		return nil
+	}
+	beginSeq := uint16(stream.lastReportSeqnum + 1) //nolint:gosec // G115
+	endSeq := uint16(stream.seqnums.Highest() + 1)  //nolint:gosec // G115
+
+	blocks := []rtcp.ReportBlock{}
+	if xr.lossRLE {
+		blocks = append(blocks, &rtcp.LossRLEReportBlock{
+			SSRC:     stream.ssrc,
+			BeginSeq: beginSeq,
+			EndSeq:   endSeq,
+			Chunks:   stream.lossRLEChunks(beginSeq, endSeq),
+		})
+	}
+	if xr.statisticsSummary {
+		lost := uint32(0)
+		for seq := beginSeq; seq != endSeq; seq++ {
+			if !stream.getReceived(seq) {
+				lost++
+			}
+		}
+		block := &rtcp.StatisticsSummaryReportBlock{
+			LossReports:      true,
+			DuplicateReports: true,
+			JitterReports:    true,
+			TTLorHopLimit:    rtcp.ToHMissing,
+			SSRC:             stream.ssrc,
+			BeginSeq:         beginSeq,
+			EndSeq:           endSeq,
+			LostPackets:      lost,
+			DupPackets:       stream.duplicates,
+		}
+		if j := stream.jitterSummary; j.count > 0 {
+			block.MinJitter = uint32(j.min)
+			block.MaxJitter = uint32(j.max)
+			block.MeanJitter = uint32(j.mean())
+			block.DevJitter = uint32(j.dev())
+		}
+		if h := stream.hopLimitSummary; h.count > 0 {
+			block.TTLorHopLimit = rtcp.ToHIPv4
+			if stream.hopLimitIPv6 {
+				block.TTLorHopLimit = rtcp.ToHIPv6
+			}
+			block.MinTTLOrHL = uint8(h.min)
+			block.MaxTTLOrHL = uint8(h.max)
+			block.MeanTTLOrHL = uint8(math.Round(h.mean()))
+			block.DevTTLOrHL = uint8(math.Round(h.dev()))
+		}
+		blocks = append(blocks, block)
+	}
+	stream.duplicates = 0
+	stream.jitterSummary = summary{}
+	stream.hopLimitSummary = summary{}
+
+	return blocks
+}
+
+// lossRLEChunks encodes the reception of the packets from beginSeq up to
+// endSeq, exclusive, as described in RFC 3611 section 4.1. Runs longer than a
+// bit vector are encoded as run length chunks. It must be called with
+// stream.m held.
+func (stream *receiverStream) lossRLEChunks(beginSeq, endSeq uint16) []rtcp.Chunk {
+	chunks := []rtcp.Chunk{}
+	for seq := beginSeq; seq != endSeq; {
+		received := stream.getReceived(seq)
+		run := uint16(1)
+		for run < maxRunLength && seq+run != endSeq && stream.getReceived(seq+run) == received {
+			run++
+		}
+
+		if run > bitVectorLength {
+			chunk := rtcp.Chunk(run)
+			if received {
+				chunk |= 1 << 14
+			}
+			chunks = append(chunks, chunk)
+			seq += run
+
+			continue
+		}
+
+		chunk := rtcp.Chunk(1 << 15)
+		for i := uint16(0); i < bitVectorLength && seq != endSeq; i++ {
+			if stream.getReceived(seq) {
+				chunk |= 1 << (bitVectorLength - 1 - i)
+			}
+			seq++
+		}
+		chunks = append(chunks, chunk)
+	}
+	// pad the block to 32 bits
+	if len(chunks)%2 != 0 {
+		chunks = append(chunks, 0)
+	}
+
+	return chunks
+}
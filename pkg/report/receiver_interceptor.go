@@ -5,6 +5,7 @@ package report
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
@@ -24,6 +25,10 @@ func (r *ReceiverInterceptorFactory) NewInterceptor(_ string) (interceptor.Inter
 		now:      time.Now,
 		log:      logging.NewDefaultLoggerFactory().NewLogger("receiver_interceptor"),
 		close:    make(chan struct{}),
+		early:    make(chan struct{}, 1),
+
+		referenceTimes:  newReferenceTimeTracker(),
+		restartDetector: defaultRestartDetector(),
 	}
 
 	for _, opt := range r.opts {
@@ -31,6 +36,9 @@ func (r *ReceiverInterceptorFactory) NewInterceptor(_ string) (interceptor.Inter
 			return nil, err
 		}
 	}
+	if receiverInterceptor.sessionBandwidth > 0 {
+		receiverInterceptor.rtcpInterval = newRTCPInterval(receiverInterceptor.sessionBandwidth, false)
+	}
 
 	return receiverInterceptor, nil
 }
@@ -50,6 +58,104 @@ type ReceiverInterceptor struct {
 	m        sync.Mutex
 	wg       sync.WaitGroup
 	close    chan struct{}
+
+	referenceTime  bool
+	referenceTimes *referenceTimeTracker
+
+	receiverSSRC      func(info *interceptor.StreamInfo) uint32
+	sourceDescription *SourceDescription
+	resolveClockRate  ClockRateResolver
+
+	mode             Mode
+	sessionBandwidth float64
+	rtcpInterval     *rtcpInterval
+	adaptiveInterval *adaptiveInterval
+
+	restartDetector *restartDetector
+	onRestart       RestartCallback
+
+	fractionLostFilter *fractionLostFilter
+
+	onReport         ReceiverReportCallback
+	onReceivedReport SenderReportCallback
+	onRTT            RTTCallback
+
+	ignoredSenderReports atomic.Uint64
+
+	extendedReports extendedReports
+
+	earlyFeedback *earlyFeedback
+	early         chan struct{}
+
+	combinedReports bool
+}
+
+// IgnoredSenderReports returns the number of received sender reports which
+// were ignored, because they were duplicates, older than a previous one, or
+// had an RTP timestamp not matching their NTP time. Ignored reports don't
+// update the LSR and DLSR fields of the receiver reports.
+func (r *ReceiverInterceptor) IgnoredSenderReports() uint64 {
+	return r.ignoredSenderReports.Load()
+}
+
+// RTT returns the round trip time to the sender of the remote stream with the
+// SSRC, computed from the last DLRR report block it sent answering a Receiver
+// Reference Time report, and whether one was received. It requires
+// ReceiverReferenceTime.
+func (r *ReceiverInterceptor) RTT(ssrc uint32) (time.Duration, bool) {
+	value, ok := r.streams.Load(ssrc)
+	if !ok {
+		return 0, false
+	}
+	stream, ok := value.(*receiverStream)
+	if !ok {
+		return 0, false
+	}
+
+	return stream.roundTripTime()
+}
+
+// LossMetrics returns the burst and gap metrics of the loss of the remote
+// stream with the SSRC, and whether the stream is known.
+func (r *ReceiverInterceptor) LossMetrics(ssrc uint32) (LossMetrics, bool) {
+	value, ok := r.streams.Load(ssrc)
+	if !ok {
+		return LossMetrics{}, false
+	}
+	stream, ok := value.(*receiverStream)
+	if !ok {
+		return LossMetrics{}, false
+	}
+
+	return stream.lossMetrics(), true
+}
+
+// Stats returns a snapshot of the reception statistics of the remote stream
+// with the SSRC, and whether the stream is known.
+func (r *ReceiverInterceptor) Stats(ssrc uint32) (ReceiverStats, bool) {
+	value, ok := r.streams.Load(ssrc)
+	if !ok {
+		return ReceiverStats{}, false
+	}
+	stream, ok := value.(*receiverStream)
+	if !ok {
+		return ReceiverStats{}, false
+	}
+
+	return stream.stats(), true
+}
+
+// processRTT records the round trip time measured with a DLRR report block
+// sent by the SSRC.
+func (r *ReceiverInterceptor) processRTT(ssrc uint32, rtt time.Duration) {
+	if value, ok := r.streams.Load(ssrc); ok {
+		if stream, ok := value.(*receiverStream); ok {
+			stream.setRoundTripTime(rtt)
+		}
+	}
+	if r.onRTT != nil {
+		r.onRTT(ssrc, rtt)
+	}
 }
 
 func (r *ReceiverInterceptor) isClosed() bool {
@@ -80,7 +186,7 @@ func (r *ReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) inte
 	r.m.Lock()
 	defer r.m.Unlock()
 
-	if r.isClosed() {
+	if r.isClosed() || r.mode == ModeSendOnly {
 		return writer
 	}
 
@@ -94,23 +200,37 @@ func (r *ReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) inte
 func (r *ReceiverInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 	defer r.wg.Done()
 
+	if r.rtcpInterval != nil {
+		rtcpWriter = r.rtcpInterval.writer(rtcpWriter)
+	}
+
 	ticker := time.NewTicker(r.interval)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
 			now := r.now()
-			r.streams.Range(func(_, value interface{}) bool {
-				if stream, ok := value.(*receiverStream); !ok {
-					r.log.Warnf("failed to cast ReceiverInterceptor stream")
-				} else if _, err := rtcpWriter.Write(
-					[]rtcp.Packet{stream.generateReport(now)}, interceptor.Attributes{},
-				); err != nil {
-					r.log.Warnf("failed sending: %+v", err)
+			if r.rtcpInterval != nil {
+				if wait, ok := r.rtcpInterval.reconsider(now); ok {
+					ticker.Reset(wait)
+
+					continue
 				}
+			}
+			if r.earlyFeedback != nil && !r.earlyFeedback.regular(now) {
+				continue
+			}
+			active := r.writeReports(rtcpWriter, now, true)
+			r.writeDLRR(rtcpWriter, now)
+			if r.rtcpInterval != nil {
+				r.rtcpInterval.sent(now)
+			}
+			if next, ok := r.nextInterval(now, active); ok {
+				ticker.Reset(next)
+			}
 
-				return true
-			})
+		case <-r.early:
+			r.writeReports(rtcpWriter, r.now(), false)
 
 		case <-r.close:
 			return
@@ -118,12 +238,168 @@ func (r *ReceiverInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 	}
 }
 
+// writeReports writes a receiver report for every stream and returns whether
+// any of them was active. Early reports don't take the activity, which only
+// affects the regular interval.
+func (r *ReceiverInterceptor) writeReports(rtcpWriter interceptor.RTCPWriter, now time.Time, regular bool) bool {
+	active := false
+	var combined []combinedReport
+	r.streams.Range(func(_, value interface{}) bool {
+		stream, ok := value.(*receiverStream)
+		if !ok {
+			r.log.Warnf("failed to cast ReceiverInterceptor stream")
+
+			return true
+		}
+		if regular && stream.takeActivity() {
+			active = true
+			if r.rtcpInterval != nil {
+				r.rtcpInterval.addMember(stream.ssrc, now, true)
+			}
+		}
+		rr, blocks := stream.generateReports(now, r.extendedReports)
+		if r.combinedReports {
+			combined = append(combined, combinedReport{
+				receiverSSRC: rr.SSRC,
+				report:       rr.Reports[0],
+				blocks:       blocks,
+			})
+
+			return true
+		}
+		if r.onReport != nil {
+			r.onReport(rr)
+		}
+		r.writeReport(rtcpWriter, now, rr, blocks, true)
+
+		return true
+	})
+
+	for _, chunk := range combineReports(combined) {
+		if r.onReport != nil {
+			r.onReport(chunk.rr)
+		}
+		r.writeReport(rtcpWriter, now, chunk.rr, chunk.blocks, chunk.first)
+	}
+
+	return active
+}
+
+// writeReport writes the receiver report as a compound packet with the SDES
+// packet and the extended report blocks, and with a Receiver Reference Time
+// report if referenceTime is set.
+func (r *ReceiverInterceptor) writeReport(
+	rtcpWriter interceptor.RTCPWriter, now time.Time, rr *rtcp.ReceiverReport, blocks []rtcp.ReportBlock,
+	referenceTime bool,
+) {
+	pkts := []rtcp.Packet{rr}
+	if r.sourceDescription != nil {
+		pkts = append(pkts, r.sourceDescription.packet(rr.SSRC))
+	}
+	if len(blocks) > 0 {
+		pkts = append(pkts, &rtcp.ExtendedReport{
+			SenderSSRC: rr.SSRC,
+			Reports:    blocks,
+		})
+	}
+	if r.referenceTime && referenceTime {
+		pkts = append(pkts, r.referenceTimes.generateReferenceTime(now, rr.SSRC))
+	}
+	if _, err := rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
+		r.log.Warnf("failed sending: %+v", err)
+	}
+}
+
+// ForceReport sends a receiver report for every remote stream right away,
+// outside of the regular interval and regardless of the timing rules of early
+// feedback, e.g. after a renegotiation or a switch of streams. Requests made
+// while the previous one is still pending are merged with it.
+func (r *ReceiverInterceptor) ForceReport() {
+	select {
+	case r.early <- struct{}{}:
+	default:
+	}
+}
+
+// requestEarly schedules an early report, if the timing rules allow one.
+func (r *ReceiverInterceptor) requestEarly() {
+	if r.earlyFeedback == nil || !r.earlyFeedback.early() {
+		return
+	}
+	select {
+	case r.early <- struct{}{}:
+	default:
+	}
+}
+
+// nextInterval returns the interval until the next report, if it differs from
+// the configured interval.
+func (r *ReceiverInterceptor) nextInterval(now time.Time, active bool) (time.Duration, bool) {
+	next := time.Duration(0)
+	if r.adaptiveInterval != nil {
+		next = r.adaptiveInterval.next(active)
+	}
+	if r.rtcpInterval != nil {
+		if computed := r.rtcpInterval.next(now); computed > next {
+			next = computed
+		}
+	}
+
+	return next, next > 0
+}
+
+func (r *ReceiverInterceptor) writeDLRR(rtcpWriter interceptor.RTCPWriter, now time.Time) {
+	if !r.referenceTime {
+		return
+	}
+	dlrr := r.referenceTimes.generateDLRR(now)
+	if dlrr == nil {
+		return
+	}
+	xr := &rtcp.ExtendedReport{
+		SenderSSRC: r.referenceTimeSSRC(),
+		Reports:    []rtcp.ReportBlock{dlrr},
+	}
+	if _, err := rtcpWriter.Write([]rtcp.Packet{xr}, interceptor.Attributes{}); err != nil {
+		r.log.Warnf("failed sending: %+v", err)
+	}
+}
+
+// referenceTimeSSRC returns the SSRC used as sender of DLRR reports. It is
+// the receiver SSRC of any bound stream, as the receiver doesn't have a media
+// SSRC of its own.
+func (r *ReceiverInterceptor) referenceTimeSSRC() uint32 {
+	ssrc := uint32(0)
+	r.streams.Range(func(_, value interface{}) bool {
+		if stream, ok := value.(*receiverStream); ok {
+			ssrc = stream.receiverSSRC
+
+			return false
+		}
+
+		return true
+	})
+
+	return ssrc
+}
+
 // BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
 // The returned method will be called once per rtp packet.
 func (r *ReceiverInterceptor) BindRemoteStream(
 	info *interceptor.StreamInfo, reader interceptor.RTPReader,
 ) interceptor.RTPReader {
+	if r.mode == ModeSendOnly {
+		return reader
+	}
+
 	stream := newReceiverStream(info.SSRC, info.ClockRate)
+	if r.receiverSSRC != nil {
+		stream.receiverSSRC = r.receiverSSRC(info)
+	}
+	stream.info = info
+	stream.resolveClockRate = r.resolveClockRate
+	stream.restart = r.restartDetector
+	stream.fractionLostFilter = r.fractionLostFilter
 	r.streams.Store(info.SSRC, stream)
 
 	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
@@ -140,7 +416,21 @@ func (r *ReceiverInterceptor) BindRemoteStream(
 			return 0, nil, err
 		}
 
-		stream.processRTP(r.now(), header)
+		// Invalid packets are received, but would distort jitter
+		if attr.IsInvalid() {
+			stream.processInvalidRTP(header)
+		} else if stream.processRTP(r.now(), header) {
+			r.log.Debugf("sender of stream %d restarted", info.SSRC)
+			if r.onRestart != nil {
+				r.onRestart(info.SSRC)
+			}
+		}
+		if hopLimit, ok := attr.GetHopLimit(); ok && !attr.IsInvalid() {
+			stream.processHopLimit(hopLimit)
+		}
+		if stream.takeLoss() {
+			r.requestEarly()
+		}
 
 		return i, attr, nil
 	})
@@ -148,12 +438,26 @@ func (r *ReceiverInterceptor) BindRemoteStream(
 
 // UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
 func (r *ReceiverInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
-	r.streams.Delete(info.SSRC)
+	value, ok := r.streams.LoadAndDelete(info.SSRC)
+	if !ok {
+		return
+	}
+	if stream, ok := value.(*receiverStream); ok {
+		r.referenceTimes.forgetSent(stream.receiverSSRC)
+	}
+	// The sender of the stream no longer counts towards the session size
+	if r.rtcpInterval != nil {
+		r.rtcpInterval.removeMember(info.SSRC)
+	}
 }
 
 // BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
 // change in the future. The returned method will be called once per packet batch.
 func (r *ReceiverInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	if r.mode == ModeSendOnly {
+		return reader
+	}
+
 	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
 		i, attr, err := reader.Read(b, a)
 		if err != nil {
@@ -167,9 +471,23 @@ func (r *ReceiverInterceptor) BindRTCPReader(reader interceptor.RTCPReader) inte
 		if err != nil {
 			return 0, nil, err
 		}
+		if r.rtcpInterval != nil {
+			r.rtcpInterval.processPackets(r.now(), pkts)
+		}
 
 		for _, pkt := range pkts {
+			if xr, ok := (pkt).(*rtcp.ExtendedReport); ok && r.referenceTime {
+				if rtt, ok := r.referenceTimes.processExtendedReport(r.now(), xr); ok {
+					attr.SetRTT(rtt)
+					r.processRTT(xr.SenderSSRC, rtt)
+				}
+
+				continue
+			}
 			if sr, ok := (pkt).(*rtcp.SenderReport); ok {
+				if r.onReceivedReport != nil {
+					r.onReceivedReport(sr)
+				}
 				value, ok := r.streams.Load(sr.SSRC)
 				if !ok {
 					continue
@@ -177,8 +495,10 @@ func (r *ReceiverInterceptor) BindRTCPReader(reader interceptor.RTCPReader) inte
 
 				if stream, ok := value.(*receiverStream); !ok {
 					r.log.Warnf("failed to cast ReceiverInterceptor stream")
-				} else {
-					stream.processSenderReport(r.now(), sr)
+				} else if !stream.processSenderReport(r.now(), sr) {
+					r.ignoredSenderReports.Add(1)
+					r.log.Debugf("ignored sender report of stream %d with NTP time %d and RTP time %d",
+						sr.SSRC, sr.NTPTime, sr.RTPTime)
 				}
 			}
 		}
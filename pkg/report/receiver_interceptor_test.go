@@ -4,12 +4,13 @@
 package report
 
 import (
+	"io"
 	"testing"
 	"time"
 
 	"github.com/pion/interceptor"
-	"github.com/pion/interceptor/internal/ntp"
 	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
@@ -452,4 +453,668 @@ func TestReceiverInterceptor(t *testing.T) {
 			Jitter:             0,
 		}, rr.Reports[0])
 	})
+
+	t.Run("reference time", func(t *testing.T) {
+		mt := test.MockTime{}
+		f, err := NewReceiverInterceptor(
+			ReceiverInterval(time.Millisecond*50),
+			ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+			ReceiverNow(mt.Now),
+			ReceiverReferenceTime(),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:      123456,
+			ClockRate: 90000,
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		mt.SetNow(rtpTime)
+		stream.ReceiveRTCP([]rtcp.Packet{
+			&rtcp.ExtendedReport{
+				SenderSSRC: 654321,
+				Reports: []rtcp.ReportBlock{
+					&rtcp.ReceiverReferenceTimeReportBlock{
+						NTPTimestamp: ntp.ToNTP(rtpTime),
+					},
+				},
+			},
+		})
+		<-stream.ReadRTCP()
+
+		mt.SetNow(rtpTime.Add(time.Second))
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, 2, len(pkts))
+		rr, ok := pkts[0].(*rtcp.ReceiverReport)
+		assert.True(t, ok)
+		rrtr, ok := pkts[1].(*rtcp.ExtendedReport)
+		assert.True(t, ok)
+		assert.Equal(t, rr.SSRC, rrtr.SenderSSRC)
+		assert.Equal(t, []rtcp.ReportBlock{
+			&rtcp.ReceiverReferenceTimeReportBlock{
+				NTPTimestamp: ntp.ToNTP(rtpTime.Add(time.Second)),
+			},
+		}, rrtr.Reports)
+
+		pkts = <-stream.WrittenRTCP()
+		assert.Equal(t, 1, len(pkts))
+		xr, ok := pkts[0].(*rtcp.ExtendedReport)
+		assert.True(t, ok)
+		assert.Equal(t, rr.SSRC, xr.SenderSSRC)
+		assert.Equal(t, []rtcp.ReportBlock{
+			&rtcp.DLRRReportBlock{
+				Reports: []rtcp.DLRRReport{{
+					SSRC:   654321,
+					LastRR: ntp.ToNTP32(rtpTime),
+					DLRR:   65536,
+				}},
+			},
+		}, xr.Reports)
+
+		// Each reference time is only answered once
+		pkts = <-stream.WrittenRTCP()
+		assert.Equal(t, 2, len(pkts))
+	})
+}
+
+func TestReceiverInterceptor_SendOnly(t *testing.T) {
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Millisecond*10),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverMode(ModeSendOnly),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456}})
+	<-stream.ReadRTP()
+
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.FailNow(t, "unexpected receiver report", "%v", pkts)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestReceiverInterceptor_RecvOnlyInterval(t *testing.T) {
+	interval := newRTCPInterval(64000, false)
+	interval.random = func() float64 { return 0.5 }
+	now := time.Now()
+
+	// The reduced minimum of 360 / 64 kbit/s dominates small reports.
+	interval.addPacket(32)
+	assert.InDelta(t, 5.625/compensation, interval.next(now).Seconds(), 1e-6)
+
+	// 2400 bytes per report exceed the 300 bytes per second receiver share.
+	interval.avgRTCPSize = 2400
+	assert.InDelta(t, 8/compensation, interval.next(now).Seconds(), 1e-6)
+
+	interval.addPacket(1572)
+	assert.Equal(t, 2350.0, interval.avgRTCPSize)
+
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Millisecond*10),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverMode(ModeRecvOnly),
+		ReceiverSessionBandwidth(64000),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// The first report is sent after the configured interval, the next one
+	// only after the computed one of several seconds.
+	pkts := <-stream.WrittenRTCP()
+	_, ok := pkts[0].(*rtcp.ReceiverReport)
+	assert.True(t, ok)
+
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.FailNow(t, "unexpected receiver report", "%v", pkts)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestReceiverInterceptor_SessionMembers(t *testing.T) {
+	interval := newRTCPInterval(64000, false)
+	interval.random = func() float64 { return 0.5 }
+	interval.avgRTCPSize = 100
+	now := time.Now()
+
+	reports := func(n int, first uint32, sender bool) []rtcp.Packet {
+		pkts := make([]rtcp.Packet, n)
+		for i := range pkts {
+			if sender {
+				pkts[i] = &rtcp.SenderReport{SSRC: first + uint32(i)}
+			} else {
+				pkts[i] = &rtcp.ReceiverReport{SSRC: first + uint32(i)}
+			}
+		}
+
+		return pkts
+	}
+	deterministic := func() float64 {
+		interval.next(now)
+
+		return interval.deterministic.Seconds()
+	}
+
+	// 3 senders and 37 receivers, including us, share 75% with the receivers.
+	interval.processPackets(now, reports(3, 1, true))
+	interval.processPackets(now, reports(36, 100, false))
+	assert.InDelta(t, 100*37/300.0, deterministic(), 1e-6)
+
+	interval.processPackets(now, []rtcp.Packet{&rtcp.Goodbye{Sources: []uint32{100}}})
+	assert.InDelta(t, 100*36/300.0, deterministic(), 1e-6)
+
+	// With more than a quarter of senders all members share the bandwidth.
+	interval.processPackets(now, reports(10, 10, true))
+	assert.InDelta(t, 100*49/400.0, deterministic(), 1e-6)
+
+	// Senders time out after two intervals, members after five.
+	now = now.Add(25 * time.Second)
+	assert.InDelta(t, 100*49/300.0, deterministic(), 1e-6)
+	now = now.Add(90 * time.Second)
+	assert.InDelta(t, 5.625, deterministic(), 1e-6)
+
+	// Reports are postponed when the interval grew since the previous one.
+	_, ok := interval.reconsider(now)
+	assert.False(t, ok)
+	interval.sent(now)
+	wait, ok := interval.reconsider(now.Add(time.Second))
+	assert.True(t, ok)
+	assert.InDelta(t, 5.625/compensation-1, wait.Seconds(), 1e-6)
+	_, ok = interval.reconsider(now.Add(5 * time.Second))
+	assert.False(t, ok)
+
+	interval.processPackets(now, reports(100, 1000, false))
+	wait, ok = interval.reconsider(now.Add(5 * time.Second))
+	assert.True(t, ok)
+	assert.InDelta(t, 100*101/300.0/compensation-5, wait.Seconds(), 1e-6)
+}
+
+func TestReceiverInterceptor_CombinedReports(t *testing.T) {
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Hour),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverSSRC(1),
+		ReceiverCombinedReports(),
+		ReceiverSourceDescription(SourceDescription{CNAME: "cname"}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      100,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+	for ssrc := uint32(101); ssrc < 133; ssrc++ {
+		i.BindRemoteStream(&interceptor.StreamInfo{SSRC: ssrc, ClockRate: 90000}, interceptor.RTPReaderFunc(
+			func([]byte, interceptor.Attributes) (int, interceptor.Attributes, error) {
+				return 0, nil, io.EOF
+			},
+		))
+	}
+
+	// The reports of the 33 streams are split into two receiver reports
+	receiverInterceptor, ok := i.(*ReceiverInterceptor)
+	assert.True(t, ok)
+	receiverInterceptor.ForceReport()
+	for _, count := range []int{31, 2} {
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			assert.Len(t, pkts, 2)
+			rr, ok := pkts[0].(*rtcp.ReceiverReport)
+			assert.True(t, ok)
+			assert.Equal(t, uint32(1), rr.SSRC)
+			assert.Len(t, rr.Reports, count)
+			_, ok = pkts[1].(*rtcp.SourceDescription)
+			assert.True(t, ok)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "no combined receiver report")
+		}
+	}
+}
+
+func TestReceiverInterceptor_Unbind(t *testing.T) {
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Hour),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverSessionBandwidth(64000),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	receiverInterceptor, ok := i.(*ReceiverInterceptor)
+	assert.True(t, ok)
+
+	info := &interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}
+	stream := test.NewMockStream(info, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+	receiverInterceptor.rtcpInterval.addMember(123456, time.Now(), true)
+
+	i.UnbindRemoteStream(info)
+	_, ok = receiverInterceptor.streams.Load(uint32(123456))
+	assert.False(t, ok)
+	receiverInterceptor.rtcpInterval.m.Lock()
+	assert.Empty(t, receiverInterceptor.rtcpInterval.members)
+	assert.Empty(t, receiverInterceptor.rtcpInterval.senders)
+	receiverInterceptor.rtcpInterval.m.Unlock()
+}
+
+func TestReceiverInterceptor_ForceReport(t *testing.T) {
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Hour),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	receiverInterceptor, ok := i.(*ReceiverInterceptor)
+	assert.True(t, ok)
+	for k := 0; k < 2; k++ {
+		receiverInterceptor.ForceReport()
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			rr, ok := pkts[0].(*rtcp.ReceiverReport)
+			assert.True(t, ok)
+			assert.Equal(t, uint32(123456), rr.Reports[0].SSRC)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "no forced receiver report")
+		}
+	}
+}
+
+func TestReceiverInterceptor_SourceDescription(t *testing.T) {
+	f, err := NewReceiverInterceptor(ReceiverSourceDescription(SourceDescription{Name: "name"}))
+	assert.NoError(t, err)
+	_, err = f.NewInterceptor("")
+	assert.ErrorIs(t, err, errMissingCNAME)
+
+	f, err = NewReceiverInterceptor(
+		ReceiverInterval(time.Millisecond*10),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverSSRC(4321),
+		ReceiverSourceDescription(SourceDescription{CNAME: "cname", Tool: "pion"}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	pkts := <-stream.WrittenRTCP()
+	assert.Len(t, pkts, 2)
+	_, ok := pkts[0].(*rtcp.ReceiverReport)
+	assert.True(t, ok)
+	assert.Equal(t, &rtcp.SourceDescription{Chunks: []rtcp.SourceDescriptionChunk{{
+		Source: 4321,
+		Items: []rtcp.SourceDescriptionItem{
+			{Type: rtcp.SDESCNAME, Text: "cname"},
+			{Type: rtcp.SDESTool, Text: "pion"},
+		},
+	}}}, pkts[1])
+}
+
+func TestReceiverInterceptor_AdaptiveInterval(t *testing.T) {
+	_, err := newAdaptiveInterval(time.Second, time.Millisecond)
+	assert.ErrorIs(t, err, errInvalidAdaptiveInterval)
+
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Millisecond*10),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverAdaptiveInterval(time.Millisecond*10, time.Hour),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456}})
+	<-stream.ReadRTP()
+
+	// The stream was active before the first report, so the second one follows
+	// after the active interval. It was idle since, so no third one is sent.
+	for i := 0; i < 2; i++ {
+		pkts := <-stream.WrittenRTCP()
+		_, ok := pkts[0].(*rtcp.ReceiverReport)
+		assert.True(t, ok)
+	}
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.FailNow(t, "unexpected receiver report", "%v", pkts)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestReceiverInterceptor_ReportCallbacks(t *testing.T) {
+	generated := make(chan *rtcp.ReceiverReport, 10)
+	received := make(chan *rtcp.SenderReport, 10)
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Millisecond*10),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverOnReport(func(rr *rtcp.ReceiverReport) {
+			generated <- rr
+		}),
+		ReceiverOnReceivedReport(func(sr *rtcp.SenderReport) {
+			received <- sr
+		}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456}})
+	<-stream.ReadRTP()
+
+	pkts := <-stream.WrittenRTCP()
+	assert.Equal(t, pkts[0], <-generated)
+
+	sr := &rtcp.SenderReport{SSRC: 123456}
+	stream.ReceiveRTCP([]rtcp.Packet{sr})
+	assert.NoError(t, (<-stream.ReadRTCP()).Err)
+	assert.Equal(t, sr, <-received)
+}
+
+func TestReceiverInterceptor_EarlyFeedback(t *testing.T) {
+	t.Run("Timing", func(t *testing.T) {
+		_, err := newEarlyFeedback(-time.Second)
+		assert.ErrorIs(t, err, errInvalidMinInterval)
+
+		early, err := newEarlyFeedback(time.Second)
+		assert.NoError(t, err)
+
+		now := time.Unix(0, 0)
+		assert.True(t, early.regular(now))
+		// Only one early report per regular interval, the regular report
+		// following it is skipped.
+		assert.True(t, early.early())
+		assert.False(t, early.early())
+		assert.False(t, early.regular(now.Add(500*time.Millisecond)))
+		assert.False(t, early.early())
+		// Regular reports within T_rr_interval are suppressed, but allow
+		// early reports again.
+		assert.False(t, early.regular(now.Add(999*time.Millisecond)))
+		assert.True(t, early.early())
+		assert.False(t, early.regular(now.Add(1500*time.Millisecond)))
+		assert.True(t, early.regular(now.Add(2*time.Second)))
+	})
+
+	t.Run("Loss", func(t *testing.T) {
+		f, err := NewReceiverInterceptor(
+			ReceiverInterval(time.Hour),
+			ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+			ReceiverEarlyFeedback(0),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:      123456,
+			ClockRate: 90000,
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		for _, seq := range []uint16{1, 2, 3} {
+			stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: seq}})
+			<-stream.ReadRTP()
+		}
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			assert.FailNow(t, "unexpected receiver report", "%v", pkts)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		// A gap triggers an early report, but only the first one until the
+		// next regular report.
+		for _, seq := range []uint16{5, 7} {
+			stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: seq}})
+			<-stream.ReadRTP()
+		}
+		pkts := <-stream.WrittenRTCP()
+		rr, ok := pkts[0].(*rtcp.ReceiverReport)
+		assert.True(t, ok)
+		assert.Equal(t, uint32(123456), rr.Reports[0].SSRC)
+		assert.NotZero(t, rr.Reports[0].TotalLost)
+
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			assert.FailNow(t, "unexpected receiver report", "%v", pkts)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
+
+func TestReceiverInterceptor_IgnoredSenderReports(t *testing.T) {
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Hour),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	receiver, ok := i.(*ReceiverInterceptor)
+	assert.True(t, ok)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// The duplicate isn't used for the LSR and DLSR fields
+	sr := &rtcp.SenderReport{SSRC: 123456, NTPTime: ntp.ToNTP(time.Now()), RTPTime: 1000}
+	for n := 0; n < 2; n++ {
+		stream.ReceiveRTCP([]rtcp.Packet{sr})
+		assert.NoError(t, (<-stream.ReadRTCP()).Err)
+	}
+	assert.Equal(t, uint64(1), receiver.IgnoredSenderReports())
+}
+
+func TestReceiverInterceptor_ExtendedReports(t *testing.T) {
+	f, err := NewReceiverInterceptor(ReceiverExtendedReports(rtcp.DLRRReportBlockType))
+	assert.NoError(t, err)
+	_, err = f.NewInterceptor("")
+	assert.ErrorIs(t, err, errUnsupportedExtendedReport)
+
+	f, err = NewReceiverInterceptor(
+		ReceiverInterval(time.Millisecond*10),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverExtendedReports(rtcp.LossRLEReportBlockType, rtcp.StatisticsSummaryReportBlockType),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	for _, seq := range []uint16{1, 3} {
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: seq}})
+		<-stream.ReadRTP()
+	}
+
+	pkts := <-stream.WrittenRTCP()
+	assert.Len(t, pkts, 2)
+	rr, ok := pkts[0].(*rtcp.ReceiverReport)
+	assert.True(t, ok)
+	xr, ok := pkts[1].(*rtcp.ExtendedReport)
+	assert.True(t, ok)
+	assert.Equal(t, rr.SSRC, xr.SenderSSRC)
+	assert.Len(t, xr.Reports, 2)
+
+	_, err = rtcp.Marshal(pkts)
+	assert.NoError(t, err)
+}
+
+func TestReceiverInterceptor_ReceiverSSRC(t *testing.T) {
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Millisecond*10),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverSSRC(4242),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: 1}})
+	<-stream.ReadRTP()
+
+	pkts := <-stream.WrittenRTCP()
+	assert.Len(t, pkts, 1)
+	rr, ok := pkts[0].(*rtcp.ReceiverReport)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(4242), rr.SSRC)
+	assert.Equal(t, uint32(123456), rr.Reports[0].SSRC)
+}
+
+func TestReceiverInterceptor_RTT(t *testing.T) {
+	mt := &test.MockTime{}
+	sent := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	mt.SetNow(sent)
+	rtts := make(chan time.Duration, 10)
+	f, err := NewReceiverInterceptor(
+		ReceiverInterval(time.Millisecond*50),
+		ReceiverLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		ReceiverNow(mt.Now),
+		ReceiverReferenceTime(),
+		ReceiverOnRTT(func(ssrc uint32, rtt time.Duration) {
+			assert.Equal(t, uint32(123456), ssrc)
+			rtts <- rtt
+		}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	receiver, ok := i.(*ReceiverInterceptor)
+	assert.True(t, ok)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	pkts := <-stream.WrittenRTCP()
+	assert.Len(t, pkts, 2)
+	rrtr, ok := pkts[1].(*rtcp.ExtendedReport)
+	assert.True(t, ok)
+	_, ok = receiver.RTT(123456)
+	assert.False(t, ok)
+
+	mt.SetNow(sent.Add(150 * time.Millisecond))
+	stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ExtendedReport{
+		SenderSSRC: 123456,
+		Reports: []rtcp.ReportBlock{&rtcp.DLRRReportBlock{
+			Reports: []rtcp.DLRRReport{
+				// Reports not answering a sent reference time are ignored
+				{SSRC: rrtr.SenderSSRC + 1, LastRR: ntp.ToNTP32(sent), DLRR: 1},
+				{SSRC: rrtr.SenderSSRC, LastRR: ntp.ToNTP32(sent) + 1, DLRR: 1},
+				{SSRC: rrtr.SenderSSRC, LastRR: ntp.ToNTP32(sent), DLRR: ntp.FromDuration(50 * time.Millisecond)},
+			},
+		}},
+	}})
+	assert.NoError(t, (<-stream.ReadRTCP()).Err)
+
+	// The LastRR and DLRR have a resolution of 1/65536 seconds
+	assert.InDelta(t, 100*time.Millisecond, <-rtts, float64(time.Second/65536)*2)
+	assert.Len(t, rtts, 0)
+	rtt, ok := receiver.RTT(123456)
+	assert.True(t, ok)
+	assert.InDelta(t, 100*time.Millisecond, rtt, float64(time.Second/65536)*2)
 }
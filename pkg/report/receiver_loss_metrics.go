@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import "time"
+
+// minGap is the number of packets received in a row ending a burst, Gmin of
+// RFC 3611 section 4.7.2.
+const minGap = 16
+
+// LossMetrics are the burst and gap metrics of the loss of a remote stream, as
+// in the VoIP metrics report block of RFC 3611 section 4.7, which tell bursty
+// from random loss. A burst is a period starting and ending with a lost
+// packet, with at least two lost packets and without 16 packets received in a
+// row. The gaps are the periods between bursts. The metrics cover the packets
+// until the last receiver report.
+type LossMetrics struct {
+	// Bursts is the number of bursts and Gaps the number of gaps.
+	Bursts uint32
+	Gaps   uint32
+	// BurstDensity is the fraction of the packets within bursts which were
+	// lost, GapDensity the fraction of the packets within gaps.
+	BurstDensity float64
+	GapDensity   float64
+	// BurstDuration and GapDuration are the mean durations of the bursts and
+	// gaps, estimated from the mean interval between packets.
+	BurstDuration time.Duration
+	GapDuration   time.Duration
+}
+
+// burstMetrics tracks the bursts and gaps of the reported packets, in
+// sequence number order.
+type burstMetrics struct {
+	// received is the number of packets received since the last loss.
+	received uint64
+	// inBurst is set until the last loss is followed by minGap received
+	// packets, burstPackets and burstLost are the packets and lost packets
+	// from the first to the last loss of the possible burst.
+	inBurst      bool
+	burstPackets uint64
+	burstLost    uint64
+
+	bursts          uint32
+	gaps            uint32
+	totalBurst      uint64
+	totalBurstLost  uint64
+	totalGap        uint64
+	totalGapLost    uint64
+	currentGapCount uint64
+}
+
+func (b *burstMetrics) add(received bool) {
+	if received {
+		b.received++
+		if b.inBurst && b.received >= minGap {
+			b.endBurst()
+		}
+
+		return
+	}
+
+	if b.inBurst {
+		b.burstPackets += b.received + 1
+		b.burstLost++
+	} else {
+		b.addGap(b.received, 0)
+		b.inBurst = true
+		b.burstPackets = 1
+		b.burstLost = 1
+	}
+	b.received = 0
+}
+
+// endBurst ends the possible burst after minGap received packets. A single
+// loss isn't a burst, it is part of the gap. The received packets are added
+// to the gap with the next loss.
+func (b *burstMetrics) endBurst() {
+	b.inBurst = false
+	if b.burstLost < 2 {
+		b.addGap(b.burstPackets, b.burstLost)
+
+		return
+	}
+	b.bursts++
+	b.totalBurst += b.burstPackets
+	b.totalBurstLost += b.burstLost
+	if b.currentGapCount > 0 {
+		b.gaps++
+		b.currentGapCount = 0
+	}
+}
+
+func (b *burstMetrics) addGap(packets, lost uint64) {
+	b.totalGap += packets
+	b.totalGapLost += lost
+	b.currentGapCount += packets
+}
+
+// metrics returns the metrics of the tracked packets, with packetInterval as
+// the mean interval between packets. Packets which may still be part of a
+// burst are left out.
+func (b *burstMetrics) metrics(packetInterval time.Duration) LossMetrics {
+	gaps, gapPackets := b.gaps, b.totalGap
+	if !b.inBurst {
+		gapPackets += b.received
+	}
+	if b.currentGapCount > 0 || (!b.inBurst && b.received > 0) {
+		gaps++
+	}
+
+	metrics := LossMetrics{Bursts: b.bursts, Gaps: gaps}
+	if b.totalBurst > 0 {
+		metrics.BurstDensity = float64(b.totalBurstLost) / float64(b.totalBurst)
+		metrics.BurstDuration = time.Duration(float64(packetInterval) * float64(b.totalBurst) / float64(b.bursts))
+	}
+	if gapPackets > 0 {
+		metrics.GapDensity = float64(b.totalGapLost) / float64(gapPackets)
+		metrics.GapDuration = time.Duration(float64(packetInterval) * float64(gapPackets) / float64(gaps))
+	}
+
+	return metrics
+}
+
+// lossMetrics returns the loss metrics of the stream.
+func (stream *receiverStream) lossMetrics() LossMetrics {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	packetInterval := time.Duration(0)
+	if packets := stream.seqnums.Highest() - stream.firstSeqnum; stream.started && packets > 0 {
+		packetInterval = stream.lastRTPTimeTime.Sub(stream.firstArrival) / time.Duration(packets)
+	}
+
+	return stream.burstMetrics.metrics(packetInterval)
+}
+
+// addLossMetrics adds the packets since the previous report to the loss
+// metrics. It must be called with stream.m held, before the receiver report
+// is generated.
+func (stream *receiverStream) addLossMetrics() {
+	for seq := stream.lastReportSeqnum + 1; seq <= stream.seqnums.Highest(); seq++ {
+		stream.burstMetrics.add(stream.getReceived(uint16(seq))) //nolint:gosec // G115
+	}
+}
@@ -6,7 +6,9 @@ package report
 import (
 	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/pion/logging"
+	"github.com/pion/rtcp"
 )
 
 // ReceiverOption can be used to configure ReceiverInterceptor.
@@ -38,3 +40,234 @@ func ReceiverNow(f func() time.Time) ReceiverOption {
 		return nil
 	}
 }
+
+// ReceiverReferenceTime enables sending RTCP XR Receiver Reference Time report
+// blocks with every receiver report, and answering the ones received from
+// remote endpoints with DLRR report blocks. This allows round trip time
+// measurements in both directions even if no media is sent. The round trip
+// times computed from the DLRR report blocks answering ours are returned by
+// ReceiverInterceptor.RTT, and passed to the callback set with ReceiverOnRTT.
+func ReceiverReferenceTime() ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.referenceTime = true
+
+		return nil
+	}
+}
+
+// ReceiverMode sets the direction of the session. In ModeSendOnly the
+// interceptor doesn't track remote streams and never sends receiver reports.
+func ReceiverMode(mode Mode) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.mode = mode
+
+		return nil
+	}
+}
+
+// ReceiverSessionBandwidth sets the session bandwidth in bits per second. The
+// interval between receiver reports is then computed from the RTCP bandwidth
+// share of receivers and the members of the session heard from, as described
+// in RFC 3550 section 6.3, using the reduced minimum interval, instead of the
+// one set with ReceiverInterval. Members which weren't heard from for five
+// intervals time out, and reports are postponed when the interval grew since
+// they were scheduled. The first report is still sent after ReceiverInterval.
+func ReceiverSessionBandwidth(bitsPerSecond float64) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.sessionBandwidth = bitsPerSecond
+
+		return nil
+	}
+}
+
+// ReceiverAdaptiveInterval sends receiver reports every active interval while
+// packets are received and every idle interval during silence, so loss and
+// round trip times are fresh during talk spurts without increasing the
+// overhead of idle streams. It replaces the interval set with
+// ReceiverInterval after the first report. With ReceiverSessionBandwidth the
+// interval computed from the bandwidth is still a lower bound.
+func ReceiverAdaptiveInterval(active, idle time.Duration) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		adaptive, err := newAdaptiveInterval(active, idle)
+		if err != nil {
+			return err
+		}
+		r.adaptiveInterval = adaptive
+
+		return nil
+	}
+}
+
+// ReceiverRestartDetection detects restarts of remote senders, e.g. after an
+// ICE restart of a publisher, by a jump of the sequence numbers by more than
+// maxSeqJump, or of the RTP timestamps by more than maxTimestampJump compared
+// to the arrival times, which is confirmed by the following packet. The
+// reception statistics and the jitter of the stream are reset then, instead of
+// reporting the skipped packets as lost, and cb is called if it isn't nil. A
+// maxTimestampJump of 0 only checks the sequence numbers. By default restarts
+// are detected as in RFC 3550 Appendix A.1, by jumps of the sequence numbers of
+// more than 3000 forward or 100 backward. Backward jumps by less than the
+// 8192 packets of the reception history are never a restart, so late
+// retransmissions are still counted as received.
+func ReceiverRestartDetection(maxSeqJump uint16, maxTimestampJump time.Duration, cb RestartCallback) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		detector, err := newRestartDetector(maxSeqJump, maxTimestampJump)
+		if err != nil {
+			return err
+		}
+		r.restartDetector = detector
+		r.onRestart = cb
+
+		return nil
+	}
+}
+
+// ReceiverFractionLost filters the fraction lost of the receiver reports, as
+// the fraction of a single interval is noisy when only a few packets were
+// expected in it, which makes congestion controllers of the sender overreact.
+// Intervals in which fewer than minPackets packets were expected are merged
+// with the following ones, repeating the previous fraction meanwhile, and the
+// fractions are smoothed exponentially with the factor smoothing in (0, 1],
+// the weight of the latest interval. A smoothing of 1 disables the smoothing.
+// The cumulative number of lost packets isn't affected.
+func ReceiverFractionLost(minPackets uint32, smoothing float64) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		filter, err := newFractionLostFilter(minPackets, smoothing)
+		if err != nil {
+			return err
+		}
+		r.fractionLostFilter = filter
+
+		return nil
+	}
+}
+
+// ReceiverOnReport sets a callback which is called with every generated
+// receiver report before it is written. It is called from the report loop and
+// must not modify the report.
+func ReceiverOnReport(cb ReceiverReportCallback) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.onReport = cb
+
+		return nil
+	}
+}
+
+// ReceiverOnReceivedReport sets a callback which is called with every received
+// sender report.
+func ReceiverOnReceivedReport(cb SenderReportCallback) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.onReceivedReport = cb
+
+		return nil
+	}
+}
+
+// ReceiverEarlyFeedback sends receiver reports early when packet loss is
+// detected, following the AVPF timing rules of RFC 4585. An early report is
+// sent at most once per regular interval, and the regular report following it
+// is skipped to keep the long-term RTCP bandwidth. Regular reports sent less
+// than minInterval (T_rr_interval) after the previous one are suppressed, a
+// minInterval of 0 disables the suppression.
+func ReceiverEarlyFeedback(minInterval time.Duration) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		early, err := newEarlyFeedback(minInterval)
+		if err != nil {
+			return err
+		}
+		r.earlyFeedback = early
+
+		return nil
+	}
+}
+
+// ReceiverExtendedReports sends RTCP XR report blocks of the given types with
+// every receiver report, covering the same packets. Supported are loss RLE
+// blocks with the reception of every packet, and statistics summary blocks
+// with the number of lost and duplicate packets and the interarrival jitter
+// of the packets, as described in RFC 3611. The statistics summary includes
+// the TTL or hop limit of the packets if the transport sets it with
+// interceptor.Attributes.SetHopLimit.
+func ReceiverExtendedReports(types ...rtcp.BlockTypeType) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		xr, err := newExtendedReports(types)
+		if err != nil {
+			return err
+		}
+		r.extendedReports = xr
+
+		return nil
+	}
+}
+
+// ReceiverSSRC sets the SSRC the receiver reports of all remote streams are
+// sent from, usually the SSRC of a local stream or the one signaled for the
+// receiver. By default every remote stream is reported from a random SSRC.
+func ReceiverSSRC(ssrc uint32) ReceiverOption {
+	return ReceiverSSRCFunc(func(*interceptor.StreamInfo) uint32 {
+		return ssrc
+	})
+}
+
+// ReceiverSSRCFunc sets a function which returns the SSRC the receiver reports
+// of a remote stream are sent from. It's called once when the stream is bound.
+func ReceiverSSRCFunc(f func(info *interceptor.StreamInfo) uint32) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.receiverSSRC = f
+
+		return nil
+	}
+}
+
+// ReceiverOnRTT sets a callback which is called with the round trip time
+// computed from every received DLRR report block answering a Receiver
+// Reference Time report, and the SSRC of its sender, see
+// ReceiverReferenceTime.
+func ReceiverOnRTT(cb RTTCallback) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.onRTT = cb
+
+		return nil
+	}
+}
+
+// ReceiverSourceDescription bundles a SDES packet describing the receiver
+// SSRC with every receiver report. The description is shared by all streams.
+func ReceiverSourceDescription(desc SourceDescription) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		if desc.CNAME == "" {
+			return errMissingCNAME
+		}
+		r.sourceDescription = &desc
+
+		return nil
+	}
+}
+
+// ReceiverClockRateResolver sets a resolver which is consulted whenever the
+// payload type of a remote stream changes, e.g. when the sender switches
+// codecs, to compute the jitter with the right clock rate. It takes precedence
+// over the clock rates of the StreamInfo, which are only known at bind time
+// and used if the resolver returns 0.
+func ReceiverClockRateResolver(resolver ClockRateResolver) ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.resolveClockRate = resolver
+
+		return nil
+	}
+}
+
+// ReceiverCombinedReports sends a single receiver report covering all remote
+// streams reported from the same SSRC, instead of one per stream, to reduce
+// the number of RTCP packets of sessions with many streams. Reports of more
+// than 31 streams are split into several compound packets, as a receiver
+// report holds at most 31 reception report blocks. It is most useful with
+// ReceiverSSRC, as by default every stream is reported from its own SSRC.
+// The callback of ReceiverOnReport is called with the combined reports.
+func ReceiverCombinedReports() ReceiverOption {
+	return func(r *ReceiverInterceptor) error {
+		r.combinedReports = true
+
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor/pkg/ntp"
+	"github.com/pion/rtcp"
+)
+
+// referenceTime is a Receiver Reference Time report received from a remote
+// endpoint, see https://www.rfc-editor.org/rfc/rfc3611#section-4.4.
+type referenceTime struct {
+	lastRR  uint32
+	arrival time.Time
+}
+
+// sentReferenceTimes holds the last Receiver Reference Time reports sent from
+// a SSRC, in the compact NTP format of the LastRR field.
+type sentReferenceTimes struct {
+	lastRRs [sentReportsHistory]uint32
+	next    int
+}
+
+// referenceTimeTracker remembers incoming Receiver Reference Time reports so
+// they can be answered with DLRR report blocks, allowing the remote endpoints
+// to compute their round trip time to us. It also remembers the reports sent,
+// to compute our round trip time from the DLRR report blocks answering them.
+type referenceTimeTracker struct {
+	m       sync.Mutex
+	pending map[uint32]referenceTime
+	sent    map[uint32]*sentReferenceTimes
+}
+
+func newReferenceTimeTracker() *referenceTimeTracker {
+	return &referenceTimeTracker{
+		pending: map[uint32]referenceTime{},
+		sent:    map[uint32]*sentReferenceTimes{},
+	}
+}
+
+// processExtendedReport remembers the Receiver Reference Time reports of xr,
+// and returns the round trip time computed from its DLRR report blocks
+// answering a sent reference time report, if there is one.
+func (t *referenceTimeTracker) processExtendedReport(now time.Time, xr *rtcp.ExtendedReport) (time.Duration, bool) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	rtt, found := time.Duration(0), false
+	for _, block := range xr.Reports {
+		switch block := block.(type) {
+		case *rtcp.ReceiverReferenceTimeReportBlock:
+			t.pending[xr.SenderSSRC] = referenceTime{
+				lastRR:  uint32(block.NTPTimestamp >> 16), //nolint:gosec // G115
+				arrival: now,
+			}
+		case *rtcp.DLRRReportBlock:
+			for _, report := range block.Reports {
+				if d, ok := t.roundTripTime(now, report); ok {
+					rtt, found = d, true
+				}
+			}
+		}
+	}
+
+	return rtt, found
+}
+
+// roundTripTime must be called with t.m held.
+func (t *referenceTimeTracker) roundTripTime(now time.Time, report rtcp.DLRRReport) (time.Duration, bool) {
+	sent, ok := t.sent[report.SSRC]
+	if !ok || report.LastRR == 0 {
+		return 0, false
+	}
+	for _, lastRR := range sent.lastRRs {
+		if lastRR != report.LastRR {
+			continue
+		}
+		// In units of 1/65536 seconds, a negative RTT wraps around
+		rtt := ntp.ToNTP32(now) - report.LastRR - report.DLRR
+		if rtt >= 1<<31 {
+			return 0, false
+		}
+
+		return ntp.ToDuration(rtt), true
+	}
+
+	return 0, false
+}
+
+// generateDLRR returns a DLRR report block answering all reference times
+// received since the last call, or nil if there are none.
+func (t *referenceTimeTracker) generateDLRR(now time.Time) *rtcp.DLRRReportBlock {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if len(t.pending) == 0 {
+		return nil
+	}
+
+	block := &rtcp.DLRRReportBlock{}
+	for ssrc, rt := range t.pending {
+		block.Reports = append(block.Reports, rtcp.DLRRReport{
+			SSRC:   ssrc,
+			LastRR: rt.lastRR,
+			DLRR:   ntp.FromDuration(now.Sub(rt.arrival)),
+		})
+		delete(t.pending, ssrc)
+	}
+
+	return block
+}
+
+// forgetSent forgets the reference time reports sent from the SSRC.
+func (t *referenceTimeTracker) forgetSent(ssrc uint32) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	delete(t.sent, ssrc)
+}
+
+// generateReferenceTime returns a Receiver Reference Time report sent by the
+// SSRC.
+func (t *referenceTimeTracker) generateReferenceTime(now time.Time, ssrc uint32) *rtcp.ExtendedReport {
+	t.m.Lock()
+	sent, ok := t.sent[ssrc]
+	if !ok {
+		sent = &sentReferenceTimes{}
+		t.sent[ssrc] = sent
+	}
+	// Streams sharing the SSRC send the same reference times
+	if lastRR := ntp.ToNTP32(now); sent.lastRRs[(sent.next+sentReportsHistory-1)%sentReportsHistory] != lastRR {
+		sent.lastRRs[sent.next] = lastRR
+		sent.next = (sent.next + 1) % sentReportsHistory
+	}
+	t.m.Unlock()
+
+	return &rtcp.ExtendedReport{
+		SenderSSRC: ssrc,
+		Reports: []rtcp.ReportBlock{
+			&rtcp.ReceiverReferenceTimeReportBlock{
+				NTPTimestamp: ntp.ToNTP(now),
+			},
+		},
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/sequencenumber"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
@@ -23,20 +26,61 @@ type receiverStream struct {
 	ssrc         uint32
 	receiverSSRC uint32
 	clockRate    float64
+	// info overrides clockRate for specific payload types, see
+	// interceptor.StreamInfo.ClockRateForPayloadType.
+	info *interceptor.StreamInfo
+	// resolveClockRate overrides both when the payload type changes.
+	resolveClockRate ClockRateResolver
 
-	m                    sync.Mutex
-	size                 uint16
-	packets              []uint64
-	started              bool
-	seqnumCycles         uint16
-	lastSeqnum           uint16
-	lastReportSeqnum     uint16
+	m       sync.Mutex
+	size    uint16
+	packets []uint64
+	started bool
+	seqnums sequencenumber.Unroller
+	// lastReportSeqnum is the extended highest sequence number of the previous
+	// report, or the one preceding the first packet until the first report.
+	lastReportSeqnum     uint64
 	lastRTPTimeRTP       uint32
 	lastRTPTimeTime      time.Time
+	lastClockRate        float64
+	lastPayloadType      uint8
 	jitter               float64
 	lastSenderReport     uint32
 	lastSenderReportTime time.Time
 	totalLost            uint32
+	packetsReceived      uint64
+	// lastSenderReportNTP and lastSenderReportRTP are the timestamps of the
+	// last accepted sender report, used to validate the next one.
+	lastSenderReportNTP uint64
+	lastSenderReportRTP uint32
+	// active is set when packets were received since takeActivity was called.
+	active bool
+	// duplicates, jitterSummary and hopLimitSummary cover the packets since
+	// the previous report, for RTCP XR statistics summary blocks.
+	duplicates      uint32
+	jitterSummary   summary
+	hopLimitSummary summary
+	hopLimitIPv6    bool
+	// loss is set when a gap in the sequence numbers was detected since
+	// takeLoss was called.
+	loss bool
+	// burstMetrics covers the reported packets, firstSeqnum and firstArrival
+	// are the first packet, for the mean interval between packets.
+	burstMetrics burstMetrics
+	firstSeqnum  uint64
+	firstArrival time.Time
+	// rtt is the round trip time to the sender, if hasRTT is set.
+	rtt    time.Duration
+	hasRTT bool
+
+	restart *restartDetector
+	// fractionLostFilter filters the fraction lost of the reports if set.
+	fractionLostFilter *fractionLostFilter
+	fractionLost       fractionLost
+	// probation is the first packet after a jump, which is confirmed as a
+	// restart by the packet following it.
+	probation     *rtp.Header
+	probationTime time.Time
 }
 
 func newReceiverStream(ssrc uint32, clockRate uint32) *receiverStream {
@@ -51,49 +95,194 @@ func newReceiverStream(ssrc uint32, clockRate uint32) *receiverStream {
 	}
 }
 
-func (stream *receiverStream) processRTP(now time.Time, pktHeader *rtp.Header) {
+func (stream *receiverStream) clockRateFor(payloadType uint8) float64 {
+	if stream.resolveClockRate != nil {
+		if stream.lastClockRate != 0 && payloadType == stream.lastPayloadType {
+			return stream.lastClockRate
+		}
+		if rate := stream.resolveClockRate(payloadType); rate != 0 {
+			return float64(rate)
+		}
+	}
+	if stream.info == nil {
+		return stream.clockRate
+	}
+
+	return float64(stream.info.ClockRateForPayloadType(payloadType))
+}
+
+// processRTP returns true if the packet confirmed a restart of the sender, in
+// which case the stream was reset.
+func (stream *receiverStream) processRTP(now time.Time, pktHeader *rtp.Header) bool {
 	stream.m.Lock()
 	defer stream.m.Unlock()
 
+	stream.active = true
+
+	if stream.started && stream.restart != nil && stream.restart.isJump(stream, now, pktHeader) {
+		probation := stream.probation
+		if probation == nil || pktHeader.SequenceNumber != probation.SequenceNumber+1 {
+			stream.probation = &rtp.Header{
+				SequenceNumber: pktHeader.SequenceNumber,
+				Timestamp:      pktHeader.Timestamp,
+				PayloadType:    pktHeader.PayloadType,
+			}
+			stream.probationTime = now
+
+			return false
+		}
+
+		stream.reset()
+		stream.process(stream.probationTime, probation)
+		stream.process(now, pktHeader)
+
+		return true
+	}
+	stream.probation = nil
+	stream.process(now, pktHeader)
+
+	return false
+}
+
+// reset must be called with stream.m held.
+func (stream *receiverStream) reset() {
+	stream.packets = make([]uint64, stream.size)
+	stream.started = false
+	stream.seqnums.Reset()
+	stream.jitter = 0
+	stream.lastSenderReport = 0
+	stream.lastSenderReportTime = time.Time{}
+	stream.lastSenderReportNTP = 0
+	stream.lastSenderReportRTP = 0
+	stream.totalLost = 0
+	stream.packetsReceived = 0
+	stream.duplicates = 0
+	stream.jitterSummary = summary{}
+	stream.hopLimitSummary = summary{}
+	stream.burstMetrics = burstMetrics{}
+	stream.fractionLost = fractionLost{}
+	stream.probation = nil
+}
+
+// process must be called with stream.m held.
+func (stream *receiverStream) process(now time.Time, pktHeader *rtp.Header) {
+	stream.packetsReceived++
 	//nolint:nestif
 	if !stream.started { // first frame
 		stream.started = true
+		seq, _ := stream.seqnums.Unroll(pktHeader.SequenceNumber)
 		stream.setReceived(pktHeader.SequenceNumber)
-		stream.lastSeqnum = pktHeader.SequenceNumber
-		stream.lastReportSeqnum = pktHeader.SequenceNumber - 1
+		stream.lastReportSeqnum = seq - 1
+		stream.firstSeqnum = seq
+		stream.firstArrival = now
 		stream.lastRTPTimeRTP = pktHeader.Timestamp
 		stream.lastRTPTimeTime = now
+		stream.lastClockRate = stream.clockRateFor(pktHeader.PayloadType)
+		stream.lastPayloadType = pktHeader.PayloadType
 	} else { // following frames
-		stream.setReceived(pktHeader.SequenceNumber)
-
-		diff := pktHeader.SequenceNumber - stream.lastSeqnum
-		if diff > 0 && diff < (1<<15) {
-			// wrap around
-			if pktHeader.SequenceNumber < stream.lastSeqnum {
-				stream.seqnumCycles++
-			}
-
-			// set missing packets as missing
-			for i := stream.lastSeqnum + 1; i != pktHeader.SequenceNumber; i++ {
-				stream.delReceived(i)
-			}
-
-			stream.lastSeqnum = pktHeader.SequenceNumber
-		}
+		stream.markReceived(pktHeader.SequenceNumber)
 
 		// compute jitter
 		// https://tools.ietf.org/html/rfc3550#page-39
-		D := now.Sub(stream.lastRTPTimeTime).Seconds()*stream.clockRate -
-			(float64(pktHeader.Timestamp) - float64(stream.lastRTPTimeRTP))
-		if D < 0 {
-			D = -D
+		// Timestamps of payloads with different clock rates can't be compared,
+		// so a clock rate switch only updates the reference.
+		clockRate := stream.clockRateFor(pktHeader.PayloadType)
+		if clockRate == stream.lastClockRate {
+			D := now.Sub(stream.lastRTPTimeTime).Seconds()*clockRate -
+				(float64(pktHeader.Timestamp) - float64(stream.lastRTPTimeRTP))
+			if D < 0 {
+				D = -D
+			}
+			stream.jitter += (D - stream.jitter) / 16
+			stream.jitterSummary.add(D)
 		}
-		stream.jitter += (D - stream.jitter) / 16
+		stream.lastClockRate = clockRate
+		stream.lastPayloadType = pktHeader.PayloadType
 		stream.lastRTPTimeRTP = pktHeader.Timestamp
 		stream.lastRTPTimeTime = now
 	}
 }
 
+// processInvalidRTP records a packet which was marked invalid as received, so
+// it isn't reported as lost, without using it for jitter or restart detection.
+func (stream *receiverStream) processInvalidRTP(pktHeader *rtp.Header) {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	if !stream.started {
+		return
+	}
+	stream.active = true
+	stream.packetsReceived++
+	stream.markReceived(pktHeader.SequenceNumber)
+}
+
+// markReceived must be called with stream.m held.
+func (stream *receiverStream) markReceived(seqnum uint16) {
+	highest := stream.seqnums.Highest()
+	// Packets from before the first one are too old to be reported
+	seq, ok := stream.seqnums.Unroll(seqnum)
+	if !ok {
+		return
+	}
+	if seq <= highest && stream.getReceived(seqnum) {
+		stream.duplicates++
+	}
+	stream.setReceived(seqnum)
+
+	// set missing packets as missing
+	for i := highest + 1; i < seq; i++ {
+		stream.delReceived(uint16(i)) //nolint:gosec // G115
+		stream.loss = true
+	}
+}
+
+// processHopLimit records the TTL or hop limit of a received packet.
+func (stream *receiverStream) processHopLimit(hopLimit interceptor.HopLimit) {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	stream.hopLimitSummary.add(float64(hopLimit.Value))
+	stream.hopLimitIPv6 = hopLimit.IPv6
+}
+
+// takeActivity returns whether packets were received since the previous call.
+func (stream *receiverStream) takeActivity() bool {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	active := stream.active
+	stream.active = false
+
+	return active
+}
+
+// takeLoss returns whether packet loss was detected since the previous call.
+func (stream *receiverStream) takeLoss() bool {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	loss := stream.loss
+	stream.loss = false
+
+	return loss
+}
+
+func (stream *receiverStream) setRoundTripTime(rtt time.Duration) {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	stream.rtt = rtt
+	stream.hasRTT = true
+}
+
+func (stream *receiverStream) roundTripTime() (time.Duration, bool) {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	return stream.rtt, stream.hasRTT
+}
+
 func (stream *receiverStream) setReceived(seq uint16) {
 	pos := seq % (stream.size * packetsPerHistoryEntry)
 	stream.packets[pos/packetsPerHistoryEntry] |= 1 << (pos % packetsPerHistoryEntry)
@@ -110,34 +299,149 @@ func (stream *receiverStream) getReceived(seq uint16) bool {
 	return (stream.packets[pos/packetsPerHistoryEntry] & (1 << (pos % packetsPerHistoryEntry))) != 0
 }
 
-func (stream *receiverStream) processSenderReport(now time.Time, sr *rtcp.SenderReport) {
+// processSenderReport returns false if the report was ignored, because it
+// isn't newer than the previous one, or its RTP timestamp doesn't match the
+// NTP time elapsed since the previous one.
+func (stream *receiverStream) processSenderReport(now time.Time, sr *rtcp.SenderReport) bool {
 	stream.m.Lock()
 	defer stream.m.Unlock()
 
+	if stream.lastSenderReportNTP != 0 {
+		if sr.NTPTime <= stream.lastSenderReportNTP || !stream.plausibleRTPTime(sr) {
+			return false
+		}
+	}
+
 	stream.lastSenderReport = uint32(sr.NTPTime >> 16) //nolint:gosec // G115
 	stream.lastSenderReportTime = now
+	stream.lastSenderReportNTP = sr.NTPTime
+	stream.lastSenderReportRTP = sr.RTPTime
+
+	return true
+}
+
+// plausibleRTPTime returns whether the RTP time elapsed since the last sender
+// report matches the elapsed NTP time, allowing for a second of jitter and 10%
+// of clock drift. It must be called with stream.m held.
+func (stream *receiverStream) plausibleRTPTime(sr *rtcp.SenderReport) bool {
+	clockRate := stream.lastClockRate
+	if clockRate == 0 {
+		clockRate = stream.clockRate
+	}
+	if clockRate == 0 {
+		return true
+	}
+
+	ntpElapsed := ntp.ToTime(sr.NTPTime).Sub(ntp.ToTime(stream.lastSenderReportNTP))
+	// The RTP timestamps can't be compared once they could have wrapped
+	if ntpElapsed.Seconds() >= float64(1<<31)/clockRate {
+		return true
+	}
+	rtpDiff := int32(sr.RTPTime - stream.lastSenderReportRTP) //nolint:gosec // G115
+	rtpElapsed := time.Duration(float64(rtpDiff) / clockRate * float64(time.Second))
+
+	skew := rtpElapsed - ntpElapsed
+	if skew < 0 {
+		skew = -skew
+	}
+
+	return skew <= time.Second+ntpElapsed/10
 }
 
 func (stream *receiverStream) generateReport(now time.Time) *rtcp.ReceiverReport {
 	stream.m.Lock()
 	defer stream.m.Unlock()
 
-	totalSinceReport := stream.lastSeqnum - stream.lastReportSeqnum
-	totalLostSinceReport := func() uint32 {
-		if stream.lastSeqnum == stream.lastReportSeqnum {
-			return 0
-		}
+	return stream.receiverReport(now)
+}
 
-		ret := uint32(0)
-		for i := stream.lastReportSeqnum + 1; i != stream.lastSeqnum; i++ {
-			if !stream.getReceived(i) {
-				ret++
-			}
+// generateReports returns the receiver report and the XR report blocks
+// covering the same packets.
+func (stream *receiverStream) generateReports(
+	now time.Time, xr extendedReports,
+) (*rtcp.ReceiverReport, []rtcp.ReportBlock) {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	blocks := stream.extendedReportBlocks(xr)
+
+	return stream.receiverReport(now), blocks
+}
+
+// lostSinceReport returns the number of packets lost since the previous
+// report. It must be called with stream.m held.
+func (stream *receiverStream) lostSinceReport(highest uint64) uint32 {
+	if highest == stream.lastReportSeqnum {
+		return 0
+	}
+
+	lost := uint32(0)
+	for i := stream.lastReportSeqnum + 1; i != highest; i++ {
+		if !stream.getReceived(uint16(i)) { //nolint:gosec // G115
+			lost++
 		}
+	}
+
+	return lost
+}
+
+// ReceiverStats is a snapshot of the reception statistics of a remote stream,
+// as they would be sent in a receiver report generated now.
+type ReceiverStats struct {
+	// PacketsReceived is the number of packets received, including
+	// duplicates.
+	PacketsReceived uint64
+	// PacketsLost is the cumulative number of packets lost.
+	PacketsLost uint32
+	// HighestSequenceNumber is the extended highest sequence number received,
+	// whose upper 16 bits are the sequence number cycles.
+	HighestSequenceNumber uint32
+	// Cycles is the number of times the sequence numbers wrapped around.
+	Cycles uint16
+	// Jitter is the interarrival jitter in timestamp units.
+	Jitter float64
+	// LastSenderReport is the middle 32 bits of the NTP timestamp of the last
+	// sender report received at LastSenderReportTime, which is zero if none
+	// was received.
+	LastSenderReport     uint32
+	LastSenderReportTime time.Time
+}
 
-		return ret
-	}()
+func (stream *receiverStream) stats() ReceiverStats {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	highest := stream.seqnums.Highest()
+	lost := stream.totalLost
+	if stream.started {
+		lost += stream.lostSinceReport(highest)
+	}
+
+	return ReceiverStats{
+		PacketsReceived:       stream.packetsReceived,
+		PacketsLost:           lost,
+		HighestSequenceNumber: uint32(highest),       //nolint:gosec // G115
+		Cycles:                uint16(highest >> 16), //nolint:gosec // G115
+		Jitter:                stream.jitter,
+		LastSenderReport:      stream.lastSenderReport,
+		LastSenderReportTime:  stream.lastSenderReportTime,
+	}
+}
+
+// receiverReport must be called with stream.m held.
+func (stream *receiverStream) receiverReport(now time.Time) *rtcp.ReceiverReport {
+	highest := stream.seqnums.Highest()
+	totalSinceReport := highest - stream.lastReportSeqnum
+	totalLostSinceReport := stream.lostSinceReport(highest)
 	stream.totalLost += totalLostSinceReport
+	stream.addLossMetrics()
+
+	var fraction uint8
+	if stream.fractionLostFilter != nil {
+		fraction = stream.fractionLost.update(stream.fractionLostFilter, totalSinceReport, uint64(totalLostSinceReport))
+	} else {
+		fraction = uint8(float64(totalLostSinceReport*256) / float64(totalSinceReport))
+	}
 
 	// allow up to 24 bits
 	if totalLostSinceReport > 0xFFFFFF {
@@ -152,23 +456,23 @@ func (stream *receiverStream) generateReport(now time.Time) *rtcp.ReceiverReport
 		Reports: []rtcp.ReceptionReport{
 			{
 				SSRC:               stream.ssrc,
-				LastSequenceNumber: uint32(stream.seqnumCycles)<<16 | uint32(stream.lastSeqnum),
+				LastSequenceNumber: uint32(highest), //nolint:gosec // G115
 				LastSenderReport:   stream.lastSenderReport,
-				FractionLost:       uint8(float64(totalLostSinceReport*256) / float64(totalSinceReport)),
+				FractionLost:       fraction,
 				TotalLost:          stream.totalLost,
 				Delay: func() uint32 {
 					if stream.lastSenderReportTime.IsZero() {
 						return 0
 					}
 
-					return uint32(now.Sub(stream.lastSenderReportTime).Seconds() * 65536)
+					return ntp.FromDuration(now.Sub(stream.lastSenderReportTime))
 				}(),
 				Jitter: uint32(stream.jitter),
 			},
 		},
 	}
 
-	stream.lastReportSeqnum = stream.lastSeqnum
+	stream.lastReportSeqnum = highest
 
 	return receiverReport
 }
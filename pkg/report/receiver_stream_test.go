@@ -5,7 +5,12 @@ package report
 
 import (
 	"testing"
+	"time"
 
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/ntp"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,4 +33,379 @@ func TestReceiverStream(t *testing.T) {
 			require.False(t, stream.getReceived(seq), "packet with SN %v should no longer be received", seq)
 		}
 	})
+
+	t.Run("jitter with payload type clock rates", func(t *testing.T) {
+		stream := newReceiverStream(12345, 8000)
+		stream.info = &interceptor.StreamInfo{
+			ClockRate:             8000,
+			PayloadTypeClockRates: map[uint8]uint32{111: 48000},
+		}
+		now := time.Now()
+
+		// 20ms packets without network jitter
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 0, Timestamp: 0, PayloadType: 0})
+		now = now.Add(20 * time.Millisecond)
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 1, Timestamp: 160, PayloadType: 0})
+		require.InDelta(t, 0, stream.jitter, 0.001)
+
+		// Switching to the 48kHz payload must not be reported as jitter
+		now = now.Add(20 * time.Millisecond)
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 2, Timestamp: 90000, PayloadType: 111})
+		require.InDelta(t, 0, stream.jitter, 0.001)
+		now = now.Add(20 * time.Millisecond)
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 3, Timestamp: 90960, PayloadType: 111})
+		require.InDelta(t, 0, stream.jitter, 0.001)
+
+		// 10ms of delay at 48kHz
+		now = now.Add(30 * time.Millisecond)
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 4, Timestamp: 91920, PayloadType: 111})
+		require.InDelta(t, 480.0/16, stream.jitter, 0.001)
+	})
+	t.Run("jitter with a clock rate resolver", func(t *testing.T) {
+		stream := newReceiverStream(12345, 8000)
+		resolved := []uint8{}
+		stream.resolveClockRate = func(payloadType uint8) uint32 {
+			resolved = append(resolved, payloadType)
+			if payloadType == 111 {
+				return 48000
+			}
+
+			return 0
+		}
+		now := time.Now()
+
+		// Unknown payload types fall back to the clock rate of the stream
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 0, Timestamp: 0, PayloadType: 0})
+		now = now.Add(20 * time.Millisecond)
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 1, Timestamp: 160, PayloadType: 0})
+		now = now.Add(20 * time.Millisecond)
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 2, Timestamp: 90000, PayloadType: 111})
+		now = now.Add(30 * time.Millisecond)
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 3, Timestamp: 90960, PayloadType: 111})
+		require.InDelta(t, 480.0/16, stream.jitter, 0.001)
+
+		// The resolver is only consulted when the payload type changes
+		require.Equal(t, []uint8{0, 111}, resolved)
+	})
+	t.Run("restart detection", func(t *testing.T) {
+		stream := newReceiverStream(12345, 8000)
+		restart, err := newRestartDetector(3000, time.Second)
+		require.NoError(t, err)
+		stream.restart = restart
+		now := time.Now()
+
+		for seq := uint16(0); seq < 10; seq++ {
+			if seq != 5 {
+				require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: seq, Timestamp: uint32(seq) * 160}))
+			}
+			now = now.Add(20 * time.Millisecond)
+		}
+
+		// A single stray packet isn't a restart and is ignored
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 40000, Timestamp: 1600}))
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 10, Timestamp: 1600}))
+		require.Equal(t, uint64(10), stream.seqnums.Highest())
+
+		// A jump of the timestamps confirmed by the next packet is a restart
+		now = now.Add(20 * time.Millisecond)
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 11, Timestamp: 1_000_000}))
+		now = now.Add(20 * time.Millisecond)
+		require.True(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 12, Timestamp: 1_000_160}))
+		require.InDelta(t, 0, stream.jitter, 0.001)
+
+		report := stream.generateReport(now)
+		require.Equal(t, uint32(12), report.Reports[0].LastSequenceNumber)
+		require.Equal(t, uint32(0), report.Reports[0].TotalLost)
+
+		_, err = newRestartDetector(0, 0)
+		require.ErrorIs(t, err, errInvalidRestartThreshold)
+	})
+
+	t.Run("default restart detection", func(t *testing.T) {
+		stream := newReceiverStream(12345, 8000)
+		stream.restart = defaultRestartDetector()
+		now := time.Now()
+
+		for seq := uint16(5000); seq < 5010; seq++ {
+			require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: seq}))
+			now = now.Add(20 * time.Millisecond)
+		}
+
+		// Reordered packets and gaps within the thresholds aren't restarts
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 4950}))
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 7000}))
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 7001}))
+		require.Equal(t, uint64(7001), stream.seqnums.Highest())
+
+		// A large backward jump of a new random sequence base is a restart
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 60000}))
+		require.True(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 60001}))
+
+		report := stream.generateReport(now)
+		require.Equal(t, uint32(60001), report.Reports[0].LastSequenceNumber)
+		require.Equal(t, uint32(0), report.Reports[0].TotalLost)
+	})
+
+	t.Run("default restart detection with late retransmissions", func(t *testing.T) {
+		stream := newReceiverStream(12345, 8000)
+		stream.restart = defaultRestartDetector()
+		now := time.Now()
+
+		// Packet 5000 is lost and retransmitted 200 packets later
+		for seq := uint16(4999); seq < 5201; seq++ {
+			if seq != 5000 {
+				require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: seq}))
+			}
+			now = now.Add(20 * time.Millisecond)
+		}
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 5000}))
+		require.False(t, stream.processRTP(now, &rtp.Header{SequenceNumber: 5201}))
+
+		report := stream.generateReport(now)
+		require.Equal(t, uint32(5201), report.Reports[0].LastSequenceNumber)
+		require.Equal(t, uint32(0), report.Reports[0].TotalLost)
+	})
+
+	t.Run("sender report validation", func(t *testing.T) {
+		stream := newReceiverStream(12345, 90000)
+		now := time.Now()
+		report := func(elapsed, rtpElapsed time.Duration) *rtcp.SenderReport {
+			return &rtcp.SenderReport{
+				SSRC:    12345,
+				NTPTime: ntp.ToNTP(now.Add(elapsed)),
+				RTPTime: 1000 + uint32(rtpElapsed.Seconds()*90000),
+			}
+		}
+
+		require.True(t, stream.processSenderReport(now, report(0, 0)))
+		require.True(t, stream.processSenderReport(now, report(time.Second, time.Second)))
+		// Duplicates and older reports are ignored
+		require.False(t, stream.processSenderReport(now, report(time.Second, time.Second)))
+		require.False(t, stream.processSenderReport(now, report(500*time.Millisecond, 500*time.Millisecond)))
+		// as are RTP timestamps far off the elapsed NTP time
+		require.False(t, stream.processSenderReport(now, report(2*time.Second, time.Hour)))
+		require.False(t, stream.processSenderReport(now, report(5*time.Second, time.Second)))
+		require.Equal(t, uint32(ntp.ToNTP(now.Add(time.Second))>>16), stream.lastSenderReport)
+
+		// Jitter and drift within the tolerance are accepted
+		require.True(t, stream.processSenderReport(now, report(5*time.Second, 5500*time.Millisecond)))
+
+		// A restart of the sender resets the validation
+		stream.reset()
+		require.True(t, stream.processSenderReport(now, report(0, time.Hour)))
+	})
+	t.Run("extended report blocks", func(t *testing.T) {
+		stream := newReceiverStream(12345, 90000)
+		now := time.Now()
+		for seq := uint16(0); seq < 40; seq++ {
+			if seq == 3 || seq == 4 {
+				continue
+			}
+			// 10ms packets, the 10th one is 1ms late
+			arrival := now.Add(time.Duration(seq) * 10 * time.Millisecond)
+			if seq == 10 {
+				arrival = arrival.Add(time.Millisecond)
+			}
+			stream.processRTP(arrival, &rtp.Header{SequenceNumber: seq, Timestamp: uint32(seq) * 900})
+		}
+		stream.processRTP(now.Add(400*time.Millisecond), &rtp.Header{SequenceNumber: 5, Timestamp: 5 * 900})
+		stream.processHopLimit(interceptor.HopLimit{Value: 60})
+		stream.processHopLimit(interceptor.HopLimit{Value: 62})
+
+		rr, blocks := stream.generateReports(now, extendedReports{lossRLE: true, statisticsSummary: true})
+		require.Equal(t, uint32(2), rr.Reports[0].TotalLost)
+		require.Len(t, blocks, 2)
+
+		rle, ok := blocks[0].(*rtcp.LossRLEReportBlock)
+		require.True(t, ok)
+		require.Equal(t, uint16(0), rle.BeginSeq)
+		require.Equal(t, uint16(40), rle.EndSeq)
+		require.Equal(t, []rtcp.Chunk{0b1111001111111111, 1<<14 | 25}, rle.Chunks)
+
+		summary, ok := blocks[1].(*rtcp.StatisticsSummaryReportBlock)
+		require.True(t, ok)
+		require.Equal(t, uint32(2), summary.LostPackets)
+		require.Equal(t, uint32(1), summary.DupPackets)
+		require.Equal(t, uint32(0), summary.MinJitter)
+		// the duplicate arrived long after its timestamp
+		require.Equal(t, uint32(31500), summary.MaxJitter)
+		require.NotZero(t, summary.MeanJitter)
+		require.Equal(t, rtcp.TTLorHopLimitType(rtcp.ToHIPv4), summary.TTLorHopLimit)
+		require.Equal(t, uint8(60), summary.MinTTLOrHL)
+		require.Equal(t, uint8(62), summary.MaxTTLOrHL)
+		require.Equal(t, uint8(61), summary.MeanTTLOrHL)
+		require.Equal(t, uint8(1), summary.DevTTLOrHL)
+
+		// The next blocks only cover the following packets
+		stream.processRTP(now.Add(410*time.Millisecond), &rtp.Header{SequenceNumber: 41, Timestamp: 41 * 900})
+		_, blocks = stream.generateReports(now, extendedReports{lossRLE: true, statisticsSummary: true})
+		rle, ok = blocks[0].(*rtcp.LossRLEReportBlock)
+		require.True(t, ok)
+		require.Equal(t, uint16(40), rle.BeginSeq)
+		require.Equal(t, []rtcp.Chunk{1<<15 | 1<<13, 0}, rle.Chunks)
+		summary, ok = blocks[1].(*rtcp.StatisticsSummaryReportBlock)
+		require.True(t, ok)
+		require.Equal(t, uint32(0), summary.DupPackets)
+		require.Equal(t, rtcp.TTLorHopLimitType(rtcp.ToHMissing), summary.TTLorHopLimit)
+
+		_, err := newExtendedReports([]rtcp.BlockTypeType{rtcp.DLRRReportBlockType})
+		require.ErrorIs(t, err, errUnsupportedExtendedReport)
+	})
+
+	t.Run("loss metrics", func(t *testing.T) {
+		stream := newReceiverStream(12345, 8000)
+		now := time.Now()
+
+		// An isolated loss is part of the gap, the losses around 42 a burst
+		for seq := uint16(0); seq < 100; seq++ {
+			if seq != 10 && seq != 40 && seq != 43 && seq != 44 {
+				stream.processRTP(now, &rtp.Header{SequenceNumber: seq, Timestamp: uint32(seq) * 160})
+			}
+			now = now.Add(20 * time.Millisecond)
+		}
+		require.Equal(t, LossMetrics{}, stream.lossMetrics())
+
+		stream.generateReport(now)
+		metrics := stream.lossMetrics()
+		require.Equal(t, uint32(1), metrics.Bursts)
+		require.Equal(t, uint32(2), metrics.Gaps)
+		require.InDelta(t, 3.0/5, metrics.BurstDensity, 1e-9)
+		require.InDelta(t, 1.0/95, metrics.GapDensity, 1e-9)
+		require.Equal(t, 100*time.Millisecond, metrics.BurstDuration)
+		require.Equal(t, 950*time.Millisecond, metrics.GapDuration)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		stream := newReceiverStream(12345, 8000)
+		now := time.Now()
+		require.Equal(t, ReceiverStats{}, stream.stats())
+
+		for _, seq := range []uint16{65530, 65532, 65533, 65534, 65535, 0, 1, 1, 3} {
+			stream.processRTP(now, &rtp.Header{SequenceNumber: seq})
+		}
+		sr := &rtcp.SenderReport{SSRC: 12345, NTPTime: 0x1122334455667788}
+		require.True(t, stream.processSenderReport(now, sr))
+
+		expected := ReceiverStats{
+			PacketsReceived:       9,
+			PacketsLost:           2,
+			HighestSequenceNumber: 1<<16 + 3,
+			Cycles:                1,
+			Jitter:                stream.jitter,
+			LastSenderReport:      0x33445566,
+			LastSenderReportTime:  now,
+		}
+		require.Equal(t, expected, stream.stats())
+
+		// The report doesn't change the stats
+		report := stream.generateReport(now).Reports[0]
+		require.Equal(t, report.TotalLost, expected.PacketsLost)
+		require.Equal(t, expected, stream.stats())
+	})
+
+	t.Run("invalid packets", func(t *testing.T) {
+		stream := newReceiverStream(12345, 8000)
+		now := time.Now()
+
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 0, Timestamp: 0})
+		stream.processInvalidRTP(&rtp.Header{SequenceNumber: 1, Timestamp: 1 << 30})
+		stream.processRTP(now.Add(20*time.Millisecond), &rtp.Header{SequenceNumber: 2, Timestamp: 160})
+
+		report := stream.generateReport(now).Reports[0]
+		require.Equal(t, uint32(0), report.TotalLost)
+		require.Equal(t, uint8(0), report.FractionLost)
+		require.Equal(t, uint32(0), report.Jitter)
+		require.Equal(t, uint64(3), stream.stats().PacketsReceived)
+	})
+
+	t.Run("fraction lost filter", func(t *testing.T) {
+		filter, err := newFractionLostFilter(20, 0.5)
+		require.NoError(t, err)
+		stream := newReceiverStream(12345, 8000)
+		stream.fractionLostFilter = filter
+		now := time.Now()
+
+		seq := uint16(0)
+		receive := func(received, lost int) {
+			for k := 0; k < received+lost; k++ {
+				if k == 0 || k > lost {
+					stream.processRTP(now, &rtp.Header{SequenceNumber: seq})
+				}
+				seq++
+			}
+		}
+
+		// 1 of 20 packets lost, the first one is always received
+		receive(19, 1)
+		require.Equal(t, uint8(256/20), stream.generateReport(now).Reports[0].FractionLost)
+
+		// 2 of 4 packets lost, too few packets for a fraction
+		receive(2, 2)
+		report := stream.generateReport(now).Reports[0]
+		require.Equal(t, uint8(256/20), report.FractionLost)
+		require.Equal(t, uint32(3), report.TotalLost)
+
+		// 2 of 20 packets lost in the merged intervals, smoothed with 1 of 20 to 7.5%
+		receive(16, 0)
+		require.Equal(t, uint8(19), stream.generateReport(now).Reports[0].FractionLost)
+
+		_, err = newFractionLostFilter(0, 0)
+		require.ErrorIs(t, err, errInvalidFractionLostSmoothing)
+		_, err = newFractionLostFilter(0, 1.5)
+		require.ErrorIs(t, err, errInvalidFractionLostSmoothing)
+	})
+
+	t.Run("packets from before the first one", func(t *testing.T) {
+		stream := newReceiverStream(12345, 90000)
+		now := time.Now()
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 1})
+		// Reordered across the wrap, not a jump by a whole cycle
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 65535})
+		stream.processRTP(now, &rtp.Header{SequenceNumber: 2})
+
+		report := stream.generateReport(now).Reports[0]
+		require.Equal(t, uint32(2), report.LastSequenceNumber)
+		require.Equal(t, uint32(0), report.TotalLost)
+	})
+}
+
+// FuzzReceiverStreamSequence checks the extended highest sequence number and
+// the cumulative loss reported by a receiverStream for a stream with gaps,
+// duplicates and wrap arounds.
+func FuzzReceiverStreamSequence(f *testing.F) {
+	f.Add(uint16(0), []byte{0, 1, 2, 0x80})
+	f.Add(uint16(65530), []byte{0, 0, 5, 0x8f, 0x43, 0})
+	f.Add(uint16(65535), []byte{0x0f, 0x0f, 0x0f, 0xcf})
+
+	f.Fuzz(func(t *testing.T, start uint16, data []byte) {
+		stream := newReceiverStream(12345, 90000)
+		now := time.Now()
+		seq := start
+		extended := uint32(start)
+		lost := uint32(0)
+		stream.processRTP(now, &rtp.Header{SequenceNumber: seq})
+
+		check := func() {
+			report := stream.generateReport(now).Reports[0]
+			require.Equal(t, extended, report.LastSequenceNumber)
+			require.Equal(t, lost, report.TotalLost)
+		}
+		for i, b := range data {
+			delta := uint16(b&0x0f) + 1
+			seq += delta
+			extended += uint32(delta)
+			lost += uint32(delta) - 1
+			stream.processRTP(now, &rtp.Header{SequenceNumber: seq})
+			if b&0x40 != 0 {
+				// Duplicates don't change anything
+				stream.processRTP(now, &rtp.Header{SequenceNumber: seq})
+			}
+			// The history only covers the packets since the previous report
+			if b&0x80 != 0 || i%256 == 255 {
+				check()
+			}
+		}
+		check()
+	})
+
 }
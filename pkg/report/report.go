@@ -3,3 +3,42 @@
 
 // Package report provides interceptors to implement sending sender and receiver reports.
 package report
+
+import (
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+// SenderReportCallback is called with a sender report.
+type SenderReportCallback func(sr *rtcp.SenderReport)
+
+// ReceiverReportCallback is called with a receiver report.
+type ReceiverReportCallback func(rr *rtcp.ReceiverReport)
+
+// ReceptionReportCallback is called with a reception report about a local
+// stream, and its interarrival jitter converted from RTP timestamp units of
+// the stream to a duration.
+type ReceptionReportCallback func(report rtcp.ReceptionReport, jitter time.Duration)
+
+// ClockRateResolver returns the clock rate of the payload type, or 0 if it
+// isn't known.
+type ClockRateResolver func(payloadType uint8) uint32
+
+// RTTCallback is called with a round trip time measured for the stream with
+// the SSRC.
+type RTTCallback func(ssrc uint32, rtt time.Duration)
+
+// Mode is the direction media flows in for a session. Report machinery which
+// isn't needed for the direction is skipped.
+type Mode int
+
+const (
+	// ModeSendRecv sends and receives media, both sender and receiver reports
+	// are generated.
+	ModeSendRecv Mode = iota
+	// ModeRecvOnly only receives media. No sender reports are generated.
+	ModeRecvOnly
+	// ModeSendOnly only sends media. No receiver reports are generated.
+	ModeSendOnly
+)
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+var errInvalidRestartThreshold = errors.New("restart sequence number threshold must be in (0, 32768)")
+
+// RestartCallback is called with the SSRC of a remote stream whose sender
+// restarted.
+type RestartCallback func(ssrc uint32)
+
+const (
+	// maxDropout and maxMisorder are the largest forward and backward jumps
+	// of the sequence numbers which aren't considered a restart by default,
+	// as in RFC 3550 Appendix A.1. Backward jumps within the reception history
+	// of a stream are never a restart, though, see isJump.
+	maxDropout  = 3000
+	maxMisorder = 100
+)
+
+// restartDetector holds the thresholds beyond which a jump of a stream is
+// considered a restart of the sender.
+type restartDetector struct {
+	maxDropout       uint16
+	maxMisorder      uint16
+	maxTimestampJump time.Duration
+}
+
+func newRestartDetector(maxSeqJump uint16, maxTimestampJump time.Duration) (*restartDetector, error) {
+	if maxSeqJump == 0 || maxSeqJump >= 1<<15 {
+		return nil, errInvalidRestartThreshold
+	}
+
+	return &restartDetector{
+		maxDropout:       maxSeqJump,
+		maxMisorder:      maxSeqJump,
+		maxTimestampJump: maxTimestampJump,
+	}, nil
+}
+
+// defaultRestartDetector only checks the sequence numbers, with the
+// thresholds of RFC 3550.
+func defaultRestartDetector() *restartDetector {
+	return &restartDetector{
+		maxDropout:       maxDropout,
+		maxMisorder:      maxMisorder,
+		maxTimestampJump: 0,
+	}
+}
+
+// isJump must be called with stream.m held, after the first packet.
+func (d *restartDetector) isJump(stream *receiverStream, now time.Time, header *rtp.Header) bool {
+	// Packets within the reception history are late, e.g. retransmissions
+	// after a NACK, which RFC 3550 doesn't account for.
+	misorder := d.maxMisorder
+	if history := stream.size * packetsPerHistoryEntry; history > misorder {
+		misorder = history
+	}
+	diff := header.SequenceNumber - uint16(stream.seqnums.Highest()) //nolint:gosec // G115
+	if (diff < 1<<15 && diff > d.maxDropout) || (diff >= 1<<15 && -diff > misorder) {
+		return true
+	}
+
+	clockRate := stream.clockRateFor(header.PayloadType)
+	if d.maxTimestampJump <= 0 || clockRate != stream.lastClockRate || clockRate == 0 {
+		return false
+	}
+	elapsed := now.Sub(stream.lastRTPTimeTime).Seconds()
+	advanced := float64(int32(header.Timestamp-stream.lastRTPTimeRTP)) / clockRate //nolint:gosec // G115
+
+	return math.Abs(advanced-elapsed) > d.maxTimestampJump.Seconds()
+}
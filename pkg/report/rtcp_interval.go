@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+)
+
+const (
+	// rtcpBandwidthFraction is the fraction of the session bandwidth used for RTCP.
+	rtcpBandwidthFraction = 0.05
+	// senderBandwidthFraction is the share of the RTCP bandwidth used by
+	// senders, when they are at most a quarter of the members.
+	senderBandwidthFraction = 0.25
+	// receiverBandwidthFraction is the share of the RTCP bandwidth used by
+	// receivers, when senders are at most a quarter of the members.
+	receiverBandwidthFraction = 0.75
+	// udpIPOverhead is added to the size of every RTCP packet.
+	udpIPOverhead = 28
+	// compensation corrects the interval for the randomization, see RFC 3550
+	// section 6.3.1.
+	compensation = math.E - 1.5
+	// memberTimeout and senderTimeout are the number of deterministic
+	// intervals after which members and senders which weren't heard from
+	// anymore are removed, see RFC 3550 section 6.3.5.
+	memberTimeout = 5
+	senderTimeout = 2
+)
+
+// rtcpInterval computes the interval between reports following RFC 3550
+// section 6.3, from the session bandwidth and the members of the session. The
+// members are the remote SSRCs heard from and the interceptor itself, which
+// is a sender if weSent is set.
+type rtcpInterval struct {
+	m                sync.Mutex
+	sessionBandwidth float64
+	weSent           bool
+	avgRTCPSize      float64
+	random           func() float64
+
+	// members holds the time every remote member was last heard from, and
+	// senders the time every remote sender last sent media.
+	members map[uint32]time.Time
+	senders map[uint32]time.Time
+	// deterministic is the last computed interval without randomization.
+	deterministic time.Duration
+	// previous is the time the previous report was sent, tp of RFC 3550.
+	previous time.Time
+}
+
+func newRTCPInterval(sessionBandwidth float64, weSent bool) *rtcpInterval {
+	return &rtcpInterval{
+		sessionBandwidth: sessionBandwidth,
+		weSent:           weSent,
+		avgRTCPSize:      0,
+		random:           rand.Float64, // #nosec
+		members:          map[uint32]time.Time{},
+		senders:          map[uint32]time.Time{},
+	}
+}
+
+// addPacket updates the average RTCP packet size with a packet of size bytes.
+func (r *rtcpInterval) addPacket(size int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	total := float64(size + udpIPOverhead)
+	if r.avgRTCPSize == 0 {
+		r.avgRTCPSize = total
+
+		return
+	}
+	r.avgRTCPSize = total/16 + 15*r.avgRTCPSize/16
+}
+
+// addMember records that the remote SSRC was heard from at now, and sent
+// media if sender is set.
+func (r *rtcpInterval) addMember(ssrc uint32, now time.Time, sender bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.members[ssrc] = now
+	if sender {
+		r.senders[ssrc] = now
+	}
+}
+
+// removeMember removes the remote SSRC after it left the session with a BYE.
+func (r *rtcpInterval) removeMember(ssrc uint32) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.members, ssrc)
+	delete(r.senders, ssrc)
+}
+
+// processPackets adds the senders of received reports as members, and
+// removes the sources of BYE packets.
+func (r *rtcpInterval) processPackets(now time.Time, pkts []rtcp.Packet) {
+	for _, pkt := range pkts {
+		switch pkt := pkt.(type) {
+		case *rtcp.SenderReport:
+			r.addMember(pkt.SSRC, now, true)
+		case *rtcp.ReceiverReport:
+			r.addMember(pkt.SSRC, now, false)
+		case *rtcp.Goodbye:
+			for _, ssrc := range pkt.Sources {
+				r.removeMember(ssrc)
+			}
+		}
+	}
+}
+
+// next returns the randomized interval until the next report.
+func (r *rtcpInterval) next(now time.Time) time.Duration {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.timeout(now)
+
+	members := float64(len(r.members) + 1)
+	senders := float64(len(r.senders))
+	if r.weSent {
+		senders++
+	}
+	bandwidth := r.sessionBandwidth * rtcpBandwidthFraction / 8
+	participants := members
+	if senders <= members*senderBandwidthFraction {
+		if r.weSent {
+			bandwidth *= senderBandwidthFraction
+			participants = senders
+		} else {
+			bandwidth *= receiverBandwidthFraction
+			participants = members - senders
+		}
+	}
+
+	// The reduced minimum of 360 divided by the session bandwidth in kbit/s.
+	minimum := 360 / (r.sessionBandwidth / 1000)
+	interval := math.Max(minimum, r.avgRTCPSize*participants/bandwidth)
+	r.deterministic = time.Duration(interval * float64(time.Second))
+	interval *= (r.random() + 0.5) / compensation
+
+	return time.Duration(interval * float64(time.Second))
+}
+
+// timeout removes the members and senders which weren't heard from for too
+// long. It must be called with r.m held.
+func (r *rtcpInterval) timeout(now time.Time) {
+	if r.deterministic == 0 {
+		return
+	}
+	for ssrc, seen := range r.members {
+		if now.Sub(seen) > memberTimeout*r.deterministic {
+			delete(r.members, ssrc)
+		}
+	}
+	for ssrc, seen := range r.senders {
+		if now.Sub(seen) > senderTimeout*r.deterministic {
+			delete(r.senders, ssrc)
+		}
+	}
+}
+
+// reconsider returns the time until the report due at now is sent, if timer
+// reconsideration postpones it because the interval grew since it was
+// scheduled, e.g. as members joined, see RFC 3550 section 6.3.6. It must only
+// be used from the report loop.
+func (r *rtcpInterval) reconsider(now time.Time) (time.Duration, bool) {
+	if r.previous.IsZero() {
+		return 0, false
+	}
+	if due := r.previous.Add(r.next(now)); due.After(now) {
+		return due.Sub(now), true
+	}
+
+	return 0, false
+}
+
+// sent records that a report was sent at now. It must only be used from the
+// report loop.
+func (r *rtcpInterval) sent(now time.Time) {
+	r.previous = now
+}
+
+// writer returns a RTCPWriter adding the size of every written packet to the
+// average RTCP packet size.
+func (r *rtcpInterval) writer(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	return interceptor.RTCPWriterFunc(func(pkts []rtcp.Packet, attributes interceptor.Attributes) (int, error) {
+		size := 0
+		for _, pkt := range pkts {
+			size += pkt.MarshalSize()
+		}
+		r.addPacket(size)
+
+		return writer.Write(pkts, attributes)
+	})
+}
@@ -31,6 +31,7 @@ func (s *SenderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interce
 		},
 		log:   logging.NewDefaultLoggerFactory().NewLogger("sender_interceptor"),
 		close: make(chan struct{}),
+		force: make(chan struct{}, 1),
 	}
 
 	for _, opt := range s.opts {
@@ -38,6 +39,9 @@ func (s *SenderInterceptorFactory) NewInterceptor(_ string) (interceptor.Interce
 			return nil, err
 		}
 	}
+	if senderInterceptor.sessionBandwidth > 0 {
+		senderInterceptor.rtcpInterval = newRTCPInterval(senderInterceptor.sessionBandwidth, true)
+	}
 
 	return senderInterceptor, nil
 }
@@ -52,15 +56,61 @@ type SenderInterceptor struct {
 	interceptor.NoOp
 	interval  time.Duration
 	now       func() time.Time
+	ntpClock  func() time.Time
 	newTicker TickerFactory
 	streams   sync.Map
 	log       logging.LeveledLogger
 	m         sync.Mutex
 	wg        sync.WaitGroup
 	close     chan struct{}
+	force     chan struct{}
 	started   chan struct{}
 
-	useLatestPacket bool
+	useLatestPacket  bool
+	estimationWindow time.Duration
+	mode             Mode
+	sessionBandwidth float64
+	rtcpInterval     *rtcpInterval
+	adaptiveInterval *adaptiveInterval
+
+	onReport          SenderReportCallback
+	onReceivedReport  ReceiverReportCallback
+	onReceptionReport ReceptionReportCallback
+	onRTT             RTTCallback
+
+	referenceTimes *referenceTimeTracker
+
+	sourceDescription *SourceDescription
+	keepalive         time.Duration
+
+	goodbye       bool
+	goodbyeReason string
+	rtcpWriter    interceptor.RTCPWriter
+}
+
+// ntpTime returns the time of the NTP clock set with SenderNTPClock, or now.
+func (s *SenderInterceptor) ntpTime(now time.Time) time.Time {
+	if s.ntpClock == nil {
+		return now
+	}
+
+	return s.ntpClock()
+}
+
+// RTT returns the round trip time of the local stream with the SSRC, computed
+// from the last reception report referring to one of its sender reports, and
+// whether such a report was received.
+func (s *SenderInterceptor) RTT(ssrc uint32) (time.Duration, bool) {
+	value, ok := s.streams.Load(ssrc)
+	if !ok {
+		return 0, false
+	}
+	stream, ok := value.(*senderStream)
+	if !ok {
+		return 0, false
+	}
+
+	return stream.roundTripTime()
 }
 
 func (s *SenderInterceptor) isClosed() bool {
@@ -91,13 +141,18 @@ func (s *SenderInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interc
 	s.m.Lock()
 	defer s.m.Unlock()
 
-	if s.isClosed() {
+	if s.isClosed() || s.mode == ModeRecvOnly {
 		return writer
 	}
 
+	rtcpWriter := writer
+	if s.rtcpInterval != nil {
+		rtcpWriter = s.rtcpInterval.writer(rtcpWriter)
+	}
+	s.rtcpWriter = rtcpWriter
 	s.wg.Add(1)
 
-	go s.loop(writer)
+	go s.loop(rtcpWriter)
 
 	return writer
 }
@@ -106,7 +161,9 @@ func (s *SenderInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 	defer s.wg.Done()
 
 	ticker := s.newTicker(s.interval)
-	defer ticker.Stop()
+	defer func() {
+		ticker.Stop()
+	}()
 	if s.started != nil {
 		// This lets us synchronize in tests to know whether the loop has begun or not.
 		// It only happens if started was initialized, which should not occur in non-tests.
@@ -116,17 +173,26 @@ func (s *SenderInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 		select {
 		case <-ticker.Ch():
 			now := s.now()
-			s.streams.Range(func(_, value interface{}) bool {
-				if stream, ok := value.(*senderStream); !ok {
-					s.log.Warnf("failed to cast SenderInterceptor stream")
-				} else if _, err := rtcpWriter.Write(
-					[]rtcp.Packet{stream.generateReport(now)}, interceptor.Attributes{},
-				); err != nil {
-					s.log.Warnf("failed sending: %+v", err)
+			// The Ticker interface can't be reset, so new ones are created
+			if s.rtcpInterval != nil {
+				if wait, ok := s.rtcpInterval.reconsider(now); ok {
+					ticker.Stop()
+					ticker = s.newTicker(wait)
+
+					continue
 				}
+			}
+			active := s.writeReports(rtcpWriter, now, true)
+			if s.rtcpInterval != nil {
+				s.rtcpInterval.sent(now)
+			}
+			if next, ok := s.nextInterval(now, active); ok {
+				ticker.Stop()
+				ticker = s.newTicker(next)
+			}
 
-				return true
-			})
+		case <-s.force:
+			s.writeReports(rtcpWriter, s.now(), false)
 
 		case <-s.close:
 			return
@@ -134,12 +200,180 @@ func (s *SenderInterceptor) loop(rtcpWriter interceptor.RTCPWriter) {
 	}
 }
 
+// writeReports writes a sender report for every stream and returns whether
+// any of them was active. Forced reports don't take the activity, which only
+// affects the regular interval.
+func (s *SenderInterceptor) writeReports(rtcpWriter interceptor.RTCPWriter, now time.Time, regular bool) bool {
+	active := false
+	s.streams.Range(func(_, value interface{}) bool {
+		stream, ok := value.(*senderStream)
+		if !ok {
+			s.log.Warnf("failed to cast SenderInterceptor stream")
+
+			return true
+		}
+		if regular && stream.takeActivity() {
+			active = true
+		}
+		if regular && s.keepalive > 0 {
+			if paused, due := stream.keepalive(now, s.keepalive); paused {
+				if due {
+					s.writeKeepalive(rtcpWriter, stream.ssrc)
+				}
+
+				return true
+			}
+		}
+		sr := stream.generateReport(now, s.ntpTime(now))
+		if s.onReport != nil {
+			s.onReport(sr)
+		}
+		pkts := []rtcp.Packet{sr}
+		if s.sourceDescription != nil {
+			pkts = append(pkts, s.sourceDescription.packet(stream.ssrc))
+		}
+		// The pending reference times are all answered with the first report
+		if s.referenceTimes != nil {
+			if dlrr := s.referenceTimes.generateDLRR(now); dlrr != nil {
+				pkts = append(pkts, &rtcp.ExtendedReport{
+					SenderSSRC: stream.ssrc,
+					Reports:    []rtcp.ReportBlock{dlrr},
+				})
+			}
+		}
+		if _, err := rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
+			s.log.Warnf("failed sending: %+v", err)
+		}
+
+		return true
+	})
+
+	return active
+}
+
+// writeKeepalive writes an empty receiver report from the SSRC, with the SDES
+// packet if there is one, as the smallest valid compound packet.
+func (s *SenderInterceptor) writeKeepalive(rtcpWriter interceptor.RTCPWriter, ssrc uint32) {
+	pkts := []rtcp.Packet{&rtcp.ReceiverReport{SSRC: ssrc}}
+	if s.sourceDescription != nil {
+		pkts = append(pkts, s.sourceDescription.packet(ssrc))
+	}
+	if _, err := rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
+		s.log.Warnf("failed sending keepalive: %+v", err)
+	}
+}
+
+// ForceReport sends a sender report for every local stream right away,
+// outside of the regular interval, e.g. to let the remote resynchronize audio
+// and video after a renegotiation or a switch of streams. Requests made while
+// the previous one is still pending are merged.
+func (s *SenderInterceptor) ForceReport() {
+	select {
+	case s.force <- struct{}{}:
+	default:
+	}
+}
+
+// nextInterval returns the interval until the next report, if it differs from
+// the configured interval.
+func (s *SenderInterceptor) nextInterval(now time.Time, active bool) (time.Duration, bool) {
+	next := time.Duration(0)
+	if s.adaptiveInterval != nil {
+		next = s.adaptiveInterval.next(active)
+	}
+	if s.rtcpInterval != nil {
+		if computed := s.rtcpInterval.next(now); computed > next {
+			next = computed
+		}
+	}
+
+	return next, next > 0
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (s *SenderInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		pkts, err := attr.GetRTCPPackets(b[:i])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		now := s.now()
+		if s.rtcpInterval != nil {
+			s.rtcpInterval.processPackets(now, pkts)
+		}
+		for _, pkt := range pkts {
+			switch pkt := pkt.(type) {
+			case *rtcp.ReceiverReport:
+				s.processReceptionReports(now, pkt.Reports, attr)
+				if s.onReceivedReport != nil {
+					s.onReceivedReport(pkt)
+				}
+			case *rtcp.SenderReport:
+				s.processReceptionReports(now, pkt.Reports, attr)
+			case *rtcp.ExtendedReport:
+				if s.referenceTimes != nil {
+					s.referenceTimes.processExtendedReport(now, pkt)
+				}
+			}
+		}
+
+		return i, attr, nil
+	})
+}
+
+// processReceptionReports stores the RTT of the reports in attr, for the
+// interceptors reading the RTCP packets after this one.
+func (s *SenderInterceptor) processReceptionReports(
+	now time.Time, reports []rtcp.ReceptionReport, attr interceptor.Attributes,
+) {
+	for _, report := range reports {
+		value, ok := s.streams.Load(report.SSRC)
+		if !ok {
+			continue
+		}
+		stream, ok := value.(*senderStream)
+		if !ok {
+			s.log.Warnf("failed to cast SenderInterceptor stream")
+
+			continue
+		}
+		if s.onReceptionReport != nil {
+			s.onReceptionReport(report, stream.jitter(report))
+		}
+		rtt, ok := stream.processReceptionReport(s.ntpTime(now), report)
+		if !ok {
+			continue
+		}
+		attr.SetRTT(rtt)
+		if s.onRTT != nil {
+			s.onRTT(report.SSRC, rtt)
+		}
+	}
+}
+
 // BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
 // will be called once per rtp packet.
 func (s *SenderInterceptor) BindLocalStream(
 	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
 ) interceptor.RTPWriter {
+	if s.mode == ModeRecvOnly {
+		return writer
+	}
+
 	stream := newSenderStream(info.SSRC, info.ClockRate, s.useLatestPacket)
+	stream.info = info
+	stream.estimationWindow = s.estimationWindow
+	stream.lastActivity = s.now()
 	s.streams.Store(info.SSRC, stream)
 
 	return interceptor.RTPWriterFunc(func(header *rtp.Header, payload []byte, a interceptor.Attributes) (int, error) {
@@ -151,5 +385,33 @@ func (s *SenderInterceptor) BindLocalStream(
 
 // UnbindLocalStream is called when the Stream is removed. It can be used to clean up any data related to that track.
 func (s *SenderInterceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
-	s.streams.Delete(info.SSRC)
+	value, ok := s.streams.LoadAndDelete(info.SSRC)
+	if !ok || !s.goodbye {
+		return
+	}
+	if stream, ok := value.(*senderStream); ok {
+		s.writeGoodbye(stream)
+	}
+}
+
+// writeGoodbye writes a final sender report of the removed stream followed by
+// a BYE packet, if the interceptor wasn't closed yet.
+func (s *SenderInterceptor) writeGoodbye(stream *senderStream) {
+	s.m.Lock()
+	rtcpWriter := s.rtcpWriter
+	closed := s.isClosed()
+	s.m.Unlock()
+	if rtcpWriter == nil || closed {
+		return
+	}
+
+	now := s.now()
+	pkts := []rtcp.Packet{stream.generateReport(now, s.ntpTime(now))}
+	if s.sourceDescription != nil {
+		pkts = append(pkts, s.sourceDescription.packet(stream.ssrc))
+	}
+	pkts = append(pkts, &rtcp.Goodbye{Sources: []uint32{stream.ssrc}, Reason: s.goodbyeReason})
+	if _, err := rtcpWriter.Write(pkts, interceptor.Attributes{}); err != nil {
+		s.log.Warnf("failed sending goodbye: %+v", err)
+	}
 }
@@ -8,8 +8,8 @@ import (
 	"time"
 
 	"github.com/pion/interceptor"
-	"github.com/pion/interceptor/internal/ntp"
 	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
@@ -154,6 +154,84 @@ func TestSenderInterceptor(t *testing.T) {
 		}, sr)
 	})
 
+	t.Run("payload type clock rates", func(t *testing.T) {
+		mt := &test.MockTime{}
+		f, err := NewSenderInterceptor(
+			SenderInterval(time.Millisecond*50),
+			SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+			SenderNow(mt.Now),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:                  123456,
+			ClockRate:             8000,
+			PayloadTypeClockRates: map[uint8]uint32{111: 48000},
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		start := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+		mt.SetNow(start)
+		assert.NoError(t, stream.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: 1, Timestamp: 1000, PayloadType: 111},
+			Payload: []byte("\x00\x00"),
+		}))
+
+		mt.SetNow(start.Add(time.Second))
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, len(pkts), 1)
+		sr, ok := pkts[0].(*rtcp.SenderReport)
+		assert.True(t, ok)
+		assert.Equal(t, uint32(1000+48000), sr.RTPTime)
+	})
+
+	t.Run("estimate RTP time of forwarded packets", func(t *testing.T) {
+		mt := &test.MockTime{}
+		f, err := NewSenderInterceptor(
+			SenderInterval(time.Millisecond*50),
+			SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+			SenderNow(mt.Now),
+			SenderEstimateRTPTime(time.Second),
+		)
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:      123456,
+			ClockRate: 90000,
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		// The second packet was captured 10ms after the first one, but is
+		// forwarded with 10ms of additional delay.
+		start := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+		mt.SetNow(start)
+		assert.NoError(t, stream.WriteRTP(&rtp.Packet{
+			Header: rtp.Header{SequenceNumber: 1, Timestamp: 0},
+		}))
+		mt.SetNow(start.Add(20 * time.Millisecond))
+		assert.NoError(t, stream.WriteRTP(&rtp.Packet{
+			Header: rtp.Header{SequenceNumber: 2, Timestamp: 900},
+		}))
+
+		mt.SetNow(start.Add(100 * time.Millisecond))
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, len(pkts), 1)
+		sr, ok := pkts[0].(*rtcp.SenderReport)
+		assert.True(t, ok)
+		// Extrapolating the second packet would result in 8100
+		assert.Equal(t, uint32(9000), sr.RTPTime)
+	})
+
 	t.Run("out of order RTP packets with SenderUseLatestPacket", func(t *testing.T) {
 		mt := &test.MockTime{}
 		f, err := NewSenderInterceptor(
@@ -254,3 +332,574 @@ func TestSenderInterceptor(t *testing.T) {
 		}
 	})
 }
+
+func TestSenderInterceptor_RecvOnly(t *testing.T) {
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Millisecond*10),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderMode(ModeRecvOnly),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456}}))
+
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.FailNow(t, "unexpected sender report", "%v", pkts)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSenderInterceptor_AdaptiveInterval(t *testing.T) {
+	_, err := NewSenderInterceptor(SenderAdaptiveInterval(0, time.Second))
+	assert.NoError(t, err)
+
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Millisecond*10),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderAdaptiveInterval(0, time.Second),
+	)
+	assert.NoError(t, err)
+	_, err = f.NewInterceptor("")
+	assert.ErrorIs(t, err, errInvalidAdaptiveInterval)
+
+	f, err = NewSenderInterceptor(
+		SenderInterval(time.Millisecond*10),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderAdaptiveInterval(time.Millisecond*10, time.Hour),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456}}))
+
+	for i := 0; i < 2; i++ {
+		pkts := <-stream.WrittenRTCP()
+		_, ok := pkts[0].(*rtcp.SenderReport)
+		assert.True(t, ok)
+	}
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.FailNow(t, "unexpected sender report", "%v", pkts)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSenderInterceptor_SessionBandwidth(t *testing.T) {
+	mNow := &test.MockTime{}
+	mTick := &test.MockTicker{
+		C: make(chan time.Time),
+	}
+	intervals := make(chan time.Duration, 10)
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Millisecond*10),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderNow(mNow.Now),
+		SenderTicker(func(d time.Duration) Ticker {
+			intervals <- d
+
+			return mTick
+		}),
+		SenderSessionBandwidth(64000),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	senderInterceptor, ok := i.(*SenderInterceptor)
+	assert.True(t, ok)
+	senderInterceptor.rtcpInterval.random = func() float64 { return 0.5 }
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// The first report is sent after the configured interval, the next one
+	// after the reduced minimum, as the 56 bytes of a report are far below the
+	// bandwidth share.
+	assert.Equal(t, 10*time.Millisecond, <-intervals)
+	start := time.Now()
+	mNow.SetNow(start)
+	mTick.Tick(start)
+	_, ok = (<-stream.WrittenRTCP())[0].(*rtcp.SenderReport)
+	assert.True(t, ok)
+	assert.InDelta(t, 5.625/compensation, (<-intervals).Seconds(), 1e-6)
+
+	// 49 more senders share the bandwidth, so the next report is postponed.
+	pkts := make([]rtcp.Packet, 49)
+	for i := range pkts {
+		pkts[i] = &rtcp.SenderReport{SSRC: uint32(i + 1)}
+	}
+	stream.ReceiveRTCP(pkts)
+	assert.NoError(t, (<-stream.ReadRTCP()).Err)
+
+	mNow.SetNow(start.Add(2 * time.Second))
+	mTick.Tick(mNow.Now())
+	assert.InDelta(t, 56*50/400.0/compensation-2, (<-intervals).Seconds(), 1e-6)
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.FailNow(t, "unexpected sender report", "%v", pkts)
+	default:
+	}
+
+	mNow.SetNow(start.Add(20 * time.Second))
+	mTick.Tick(mNow.Now())
+	_, ok = (<-stream.WrittenRTCP())[0].(*rtcp.SenderReport)
+	assert.True(t, ok)
+}
+
+func TestSenderInterceptor_ForceReport(t *testing.T) {
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Hour),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	senderInterceptor, ok := i.(*SenderInterceptor)
+	assert.True(t, ok)
+	for k := 0; k < 2; k++ {
+		senderInterceptor.ForceReport()
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			sr, ok := pkts[0].(*rtcp.SenderReport)
+			assert.True(t, ok)
+			assert.Equal(t, uint32(123456), sr.SSRC)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "no forced sender report")
+		}
+	}
+}
+
+func TestSenderInterceptor_Goodbye(t *testing.T) {
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Hour),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderSourceDescription(SourceDescription{CNAME: "cname"}),
+		SenderGoodbye("removed"),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	info := &interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}
+	stream := test.NewMockStream(info, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	i.UnbindLocalStream(info)
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.Len(t, pkts, 3)
+		sr, ok := pkts[0].(*rtcp.SenderReport)
+		assert.True(t, ok)
+		assert.Equal(t, uint32(123456), sr.SSRC)
+		_, ok = pkts[1].(*rtcp.SourceDescription)
+		assert.True(t, ok)
+		assert.Equal(t, &rtcp.Goodbye{Sources: []uint32{123456}, Reason: "removed"}, pkts[2])
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no goodbye")
+	}
+
+	// The state of the stream is removed with it
+	_, ok := i.(*SenderInterceptor).streams.Load(uint32(123456))
+	assert.False(t, ok)
+	i.UnbindLocalStream(info)
+	select {
+	case pkts := <-stream.WrittenRTCP():
+		assert.FailNow(t, "unexpected packets", pkts)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSenderInterceptor_SourceDescription(t *testing.T) {
+	f, err := NewSenderInterceptor(SenderSourceDescription(SourceDescription{}))
+	assert.NoError(t, err)
+	_, err = f.NewInterceptor("")
+	assert.ErrorIs(t, err, errMissingCNAME)
+
+	f, err = NewSenderInterceptor(
+		SenderInterval(time.Millisecond*10),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderSourceDescription(SourceDescription{CNAME: "cname", Name: "name"}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	pkts := <-stream.WrittenRTCP()
+	assert.Len(t, pkts, 2)
+	_, ok := pkts[0].(*rtcp.SenderReport)
+	assert.True(t, ok)
+	assert.Equal(t, &rtcp.SourceDescription{Chunks: []rtcp.SourceDescriptionChunk{{
+		Source: 123456,
+		Items: []rtcp.SourceDescriptionItem{
+			{Type: rtcp.SDESCNAME, Text: "cname"},
+			{Type: rtcp.SDESName, Text: "name"},
+		},
+	}}}, pkts[1])
+}
+
+func TestSenderInterceptor_Keepalive(t *testing.T) {
+	mNow := &test.MockTime{}
+	mTick := &test.MockTicker{
+		C: make(chan time.Time),
+	}
+	start := time.Now()
+	mNow.SetNow(start)
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Millisecond*500),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderNow(mNow.Now),
+		SenderTicker(func(time.Duration) Ticker { return mTick }),
+		SenderKeepalive(time.Second),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	tick := func(elapsed time.Duration) {
+		mNow.SetNow(start.Add(elapsed))
+		mTick.Tick(mNow.Now())
+	}
+	readReport := func() rtcp.Packet {
+		pkts := <-stream.WrittenRTCP()
+		assert.Len(t, pkts, 1)
+
+		return pkts[0]
+	}
+	keepalive := &rtcp.ReceiverReport{SSRC: 123456}
+
+	assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456}}))
+	<-stream.WrittenRTP()
+	tick(500 * time.Millisecond)
+	_, ok := readReport().(*rtcp.SenderReport)
+	assert.True(t, ok)
+
+	// Paused for a second, a keepalive is sent every second
+	tick(time.Second)
+	assert.Equal(t, keepalive, readReport())
+	tick(2 * time.Second)
+	assert.Equal(t, keepalive, readReport())
+	// No keepalive is due yet, so the forced report is written next
+	tick(2500 * time.Millisecond)
+	senderInterceptor, ok := i.(*SenderInterceptor)
+	assert.True(t, ok)
+	senderInterceptor.ForceReport()
+	_, ok = readReport().(*rtcp.SenderReport)
+	assert.True(t, ok)
+
+	assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456, SequenceNumber: 1}}))
+	<-stream.WrittenRTP()
+	tick(3 * time.Second)
+	sr, ok := readReport().(*rtcp.SenderReport)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(2), sr.PacketCount)
+}
+
+func TestSenderInterceptor_ReportCallbacks(t *testing.T) {
+	generated := make(chan *rtcp.SenderReport, 10)
+	received := make(chan *rtcp.ReceiverReport, 10)
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Millisecond*10),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderOnReport(func(sr *rtcp.SenderReport) {
+			generated <- sr
+		}),
+		SenderOnReceivedReport(func(rr *rtcp.ReceiverReport) {
+			received <- rr
+		}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	pkts := <-stream.WrittenRTCP()
+	assert.Equal(t, pkts[0], <-generated)
+
+	rr := &rtcp.ReceiverReport{SSRC: 654321, Reports: []rtcp.ReceptionReport{{SSRC: 123456}}}
+	stream.ReceiveRTCP([]rtcp.Packet{rr})
+	assert.NoError(t, (<-stream.ReadRTCP()).Err)
+	assert.Equal(t, rr.Reports, (<-received).Reports)
+}
+
+func TestSenderInterceptor_ReceptionReportCallback(t *testing.T) {
+	type receptionReport struct {
+		report rtcp.ReceptionReport
+		jitter time.Duration
+	}
+	reports := make(chan receptionReport, 10)
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Hour),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderOnReceptionReport(func(report rtcp.ReceptionReport, jitter time.Duration) {
+			reports <- receptionReport{report, jitter}
+		}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// Reports about unknown streams are skipped
+	ours := rtcp.ReceptionReport{SSRC: 123456, FractionLost: 64, Jitter: 900}
+	stream.ReceiveRTCP([]rtcp.Packet{
+		&rtcp.ReceiverReport{SSRC: 654321, Reports: []rtcp.ReceptionReport{{SSRC: 1}, ours}},
+		&rtcp.SenderReport{SSRC: 654321, Reports: []rtcp.ReceptionReport{ours}},
+	})
+	assert.NoError(t, (<-stream.ReadRTCP()).Err)
+	for k := 0; k < 2; k++ {
+		assert.Equal(t, receptionReport{ours, 10 * time.Millisecond}, <-reports)
+	}
+	assert.Empty(t, reports)
+}
+
+// rttProbe records the RTTs stored in the attributes of the RTCP packets read
+// by the interceptors before it.
+type rttProbe struct {
+	interceptor.NoOp
+	rtts chan time.Duration
+}
+
+func (p *rttProbe) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if rtt, ok := attr.GetRTT(); ok {
+			p.rtts <- rtt
+		}
+
+		return i, attr, err
+	})
+}
+
+func TestSenderInterceptor_RTT(t *testing.T) {
+	mt := &test.MockTime{}
+	sent := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	mt.SetNow(sent)
+	rtts := make(chan time.Duration, 10)
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Millisecond*10),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderNow(mt.Now),
+		SenderOnRTT(func(ssrc uint32, rtt time.Duration) {
+			assert.Equal(t, uint32(123456), ssrc)
+			rtts <- rtt
+		}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	sender, ok := i.(*SenderInterceptor)
+	assert.True(t, ok)
+	probe := &rttProbe{rtts: make(chan time.Duration, 10)}
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, interceptor.NewChain([]interceptor.Interceptor{i, probe}))
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	pkts := <-stream.WrittenRTCP()
+	sr, ok := pkts[0].(*rtcp.SenderReport)
+	assert.True(t, ok)
+	_, ok = sender.RTT(123456)
+	assert.False(t, ok)
+
+	mt.SetNow(sent.Add(150 * time.Millisecond))
+	stream.ReceiveRTCP([]rtcp.Packet{
+		// Reports not referring to a sent report are ignored
+		&rtcp.ReceiverReport{SSRC: 654321, Reports: []rtcp.ReceptionReport{{
+			SSRC: 123456, LastSenderReport: uint32(sr.NTPTime>>16) + 1, Delay: 1,
+		}}},
+		&rtcp.SenderReport{SSRC: 654321, Reports: []rtcp.ReceptionReport{{
+			SSRC: 123456, LastSenderReport: uint32(sr.NTPTime >> 16), Delay: ntp.FromDuration(50 * time.Millisecond),
+		}}},
+	})
+	assert.NoError(t, (<-stream.ReadRTCP()).Err)
+
+	// The LSR and DLSR have a resolution of 1/65536 seconds
+	assert.InDelta(t, 100*time.Millisecond, <-rtts, float64(time.Second/65536)*2)
+	assert.Len(t, rtts, 0)
+	rtt, ok := sender.RTT(123456)
+	assert.True(t, ok)
+	assert.InDelta(t, 100*time.Millisecond, rtt, float64(time.Second/65536)*2)
+
+	// The RTT is passed on to the following interceptors
+	assert.Equal(t, rtt, <-probe.rtts)
+}
+
+func TestSenderInterceptor_NTPClock(t *testing.T) {
+	mt := &test.MockTime{}
+	sent := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	mt.SetNow(sent)
+	skew := 90 * time.Minute
+	rtts := make(chan time.Duration, 10)
+	f, err := NewSenderInterceptor(
+		SenderInterval(time.Millisecond*10),
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderNow(mt.Now),
+		SenderNTPClock(func() time.Time {
+			return mt.Now().Add(skew)
+		}),
+		SenderOnRTT(func(_ uint32, rtt time.Duration) {
+			rtts <- rtt
+		}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	pkts := <-stream.WrittenRTCP()
+	sr, ok := pkts[0].(*rtcp.SenderReport)
+	assert.True(t, ok)
+	assert.Equal(t, ntp.ToNTP(sent.Add(skew)), sr.NTPTime)
+
+	// The round trip time is measured with the same clock
+	mt.SetNow(sent.Add(150 * time.Millisecond))
+	stream.ReceiveRTCP([]rtcp.Packet{
+		&rtcp.ReceiverReport{SSRC: 654321, Reports: []rtcp.ReceptionReport{{
+			SSRC: 123456, LastSenderReport: uint32(sr.NTPTime >> 16), Delay: ntp.FromDuration(50 * time.Millisecond),
+		}}},
+	})
+	assert.NoError(t, (<-stream.ReadRTCP()).Err)
+	assert.InDelta(t, 100*time.Millisecond, <-rtts, float64(time.Second/65536)*2)
+}
+
+func TestSenderInterceptor_ReferenceTime(t *testing.T) {
+	mt := &test.MockTime{}
+	received := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	mt.SetNow(received)
+	mTick := &test.MockTicker{
+		C: make(chan time.Time),
+	}
+	f, err := NewSenderInterceptor(
+		SenderLog(logging.NewDefaultLoggerFactory().NewLogger("test")),
+		SenderNow(mt.Now),
+		SenderTicker(func(time.Duration) Ticker { return mTick }),
+		SenderReferenceTime(),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{
+		SSRC:      123456,
+		ClockRate: 90000,
+	}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ExtendedReport{
+		SenderSSRC: 654321,
+		Reports: []rtcp.ReportBlock{
+			&rtcp.ReceiverReferenceTimeReportBlock{NTPTimestamp: ntp.ToNTP(received)},
+		},
+	}})
+	assert.NoError(t, (<-stream.ReadRTCP()).Err)
+
+	mt.SetNow(received.Add(time.Second))
+	mTick.Tick(mt.Now())
+	pkts := <-stream.WrittenRTCP()
+	assert.Len(t, pkts, 2)
+	xr, ok := pkts[1].(*rtcp.ExtendedReport)
+	assert.True(t, ok)
+	assert.Equal(t, &rtcp.ExtendedReport{
+		SenderSSRC: 123456,
+		Reports: []rtcp.ReportBlock{&rtcp.DLRRReportBlock{
+			Reports: []rtcp.DLRRReport{{SSRC: 654321, LastRR: ntp.ToNTP32(received), DLRR: 65536}},
+		}},
+	}, xr)
+
+	// Each reference time is only answered once
+	mTick.Tick(mt.Now())
+	assert.Len(t, <-stream.WrittenRTCP(), 1)
+}
@@ -39,6 +39,20 @@ func SenderNow(f func() time.Time) SenderOption {
 	}
 }
 
+// SenderNTPClock sets a clock returning the current time, e.g. one
+// synchronized by an NTP client, which the NTP timestamps of sender reports
+// are taken from instead of the wall clock. Receivers synchronizing streams
+// of several machines by the NTP timestamps then stay in sync even if the
+// clocks of the machines are skewed. The RTP timestamps of the reports and the
+// round trip times are unaffected.
+func SenderNTPClock(clock func() time.Time) SenderOption {
+	return func(r *SenderInterceptor) error {
+		r.ntpClock = clock
+
+		return nil
+	}
+}
+
 // SenderTicker sets an alternative for the time.NewTicker function.
 func SenderTicker(f TickerFactory) SenderOption {
 	return func(r *SenderInterceptor) error {
@@ -58,6 +72,18 @@ func SenderUseLatestPacket() SenderOption {
 	}
 }
 
+// SenderEstimateRTPTime estimates the RTP time of reports from all packets sent
+// within window instead of only the last one. This improves the accuracy for
+// forwarded streams, whose packets are sent with the jitter they were received
+// with, as the capture clock isn't known.
+func SenderEstimateRTPTime(window time.Duration) SenderOption {
+	return func(r *SenderInterceptor) error {
+		r.estimationWindow = window
+
+		return nil
+	}
+}
+
 // enableStartTracking is used by tests to synchronize whether the loop() has begun
 // and it's safe to start sending ticks to the ticker.
 func enableStartTracking(startedCh chan struct{}) SenderOption {
@@ -67,3 +93,144 @@ func enableStartTracking(startedCh chan struct{}) SenderOption {
 		return nil
 	}
 }
+
+// SenderMode sets the direction of the session. In ModeRecvOnly the
+// interceptor never sends sender reports.
+func SenderMode(mode Mode) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.mode = mode
+
+		return nil
+	}
+}
+
+// SenderSessionBandwidth sets the session bandwidth in bits per second. The
+// interval between sender reports is then computed from the RTCP bandwidth
+// share of senders and the members of the session heard from, as described in
+// RFC 3550 section 6.3, instead of the one set with SenderInterval. The first
+// report is still sent after SenderInterval.
+func SenderSessionBandwidth(bitsPerSecond float64) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.sessionBandwidth = bitsPerSecond
+
+		return nil
+	}
+}
+
+// SenderAdaptiveInterval sends sender reports every active interval while
+// packets are sent and every idle interval during silence. It replaces the
+// interval set with SenderInterval after the first report. With
+// SenderSessionBandwidth the interval computed from the bandwidth is still a
+// lower bound.
+func SenderAdaptiveInterval(active, idle time.Duration) SenderOption {
+	return func(s *SenderInterceptor) error {
+		adaptive, err := newAdaptiveInterval(active, idle)
+		if err != nil {
+			return err
+		}
+		s.adaptiveInterval = adaptive
+
+		return nil
+	}
+}
+
+// SenderOnReport sets a callback which is called with every generated sender
+// report before it is written. It is called from the report loop and must not
+// modify the report.
+func SenderOnReport(cb SenderReportCallback) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.onReport = cb
+
+		return nil
+	}
+}
+
+// SenderOnReceivedReport sets a callback which is called with every received
+// receiver report.
+func SenderOnReceivedReport(cb ReceiverReportCallback) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.onReceivedReport = cb
+
+		return nil
+	}
+}
+
+// SenderOnReceptionReport sets a callback which is called with every received
+// reception report about a local stream, from receiver reports as well as
+// from sender reports of remote endpoints which also send, so applications
+// can record the fraction lost and the jitter of each interval. The round trip
+// time computed from the same report is passed to the callback of SenderOnRTT.
+func SenderOnReceptionReport(cb ReceptionReportCallback) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.onReceptionReport = cb
+
+		return nil
+	}
+}
+
+// SenderOnRTT sets a callback which is called with the round trip time
+// computed from every received reception report referring to a sender report
+// of a local stream, using its LSR and DLSR fields as described in RFC 3550
+// section 6.4.1. The last round trip time of a stream is also returned by
+// SenderInterceptor.RTT.
+func SenderOnRTT(cb RTTCallback) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.onRTT = cb
+
+		return nil
+	}
+}
+
+// SenderReferenceTime enables answering RTCP XR Receiver Reference Time report
+// blocks received from remote receivers with DLRR report blocks, sent with
+// the next sender reports, so receive-only endpoints can measure their round
+// trip time as described in RFC 3611 section 4.5. It isn't needed if the
+// ReceiverInterceptor with ReceiverReferenceTime handles the same RTCP, which
+// answers them already.
+func SenderReferenceTime() SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.referenceTimes = newReferenceTimeTracker()
+
+		return nil
+	}
+}
+
+// SenderSourceDescription bundles a SDES packet describing the SSRC of the
+// local stream with every sender report. The description is shared by all
+// streams.
+func SenderSourceDescription(desc SourceDescription) SenderOption {
+	return func(s *SenderInterceptor) error {
+		if desc.CNAME == "" {
+			return errMissingCNAME
+		}
+		s.sourceDescription = &desc
+
+		return nil
+	}
+}
+
+// SenderKeepalive replaces the sender reports of local streams which didn't
+// send packets for idle, e.g. paused ones, with an empty receiver report sent
+// every idle as a keepalive, with the SDES packet of SenderSourceDescription
+// if set. It keeps NAT bindings alive and the remote from timing out the
+// stream, without claiming to still send media. Reports forced with ForceReport are always sender reports.
+func SenderKeepalive(idle time.Duration) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.keepalive = idle
+
+		return nil
+	}
+}
+
+// SenderGoodbye sends a RTCP BYE packet for every local stream when it is
+// unbound, so the remote can release the state of the stream right away
+// instead of timing it out. The BYE follows a final sender report and the SDES
+// packet of SenderSourceDescription if set, and carries reason if not empty.
+func SenderGoodbye(reason string) SenderOption {
+	return func(s *SenderInterceptor) error {
+		s.goodbye = true
+		s.goodbyeReason = reason
+
+		return nil
+	}
+}
@@ -7,7 +7,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
@@ -17,20 +18,54 @@ type senderStream struct {
 	clockRate float64
 	m         sync.Mutex
 
+	// info overrides clockRate for specific payload types, see
+	// interceptor.StreamInfo.ClockRateForPayloadType.
+	info *interceptor.StreamInfo
+
 	useLatestPacket bool
 
 	// data from rtp packets
 	lastRTPTimeRTP  uint32
 	lastRTPTimeTime time.Time
+	lastClockRate   float64
 	lastRTPSN       uint16
 	packetCount     uint32
 	octetCount      uint32
+	// active is set when packets were sent since takeActivity was called.
+	active bool
+	// lastActivity is the time the last packet was sent, or the stream was
+	// bound, and lastKeepalive the time the last keepalive was sent.
+	lastActivity  time.Time
+	lastKeepalive time.Time
+
+	// estimationWindow enables estimating the RTP time of reports from the
+	// packets sent within the window, see SenderEstimateRTPTime.
+	estimationWindow time.Duration
+	samples          []rtpTimeSample
+
+	// sentReports holds the LSR values of the last sent reports, to match the
+	// reception reports of receivers to them.
+	sentReports    [sentReportsHistory]uint32
+	nextSentReport int
+	rtt            time.Duration
+	hasRTT         bool
+}
+
+// sentReportsHistory is the number of sent reports reception reports are
+// matched to, receivers may report based on an older one than the last.
+const sentReportsHistory = 4
+
+// rtpTimeSample is the RTP timestamp of a packet and the time it was sent.
+type rtpTimeSample struct {
+	time    time.Time
+	rtpTime uint32
 }
 
 func newSenderStream(ssrc uint32, clockRate uint32, useLatestPacket bool) *senderStream {
 	return &senderStream{
 		ssrc:            ssrc,
 		clockRate:       float64(clockRate),
+		lastClockRate:   float64(clockRate),
 		useLatestPacket: useLatestPacket,
 	}
 }
@@ -39,27 +74,164 @@ func (stream *senderStream) processRTP(now time.Time, header *rtp.Header, payloa
 	stream.m.Lock()
 	defer stream.m.Unlock()
 
+	stream.active = true
+	stream.lastActivity = now
+
 	diff := header.SequenceNumber - stream.lastRTPSN
 	if stream.useLatestPacket || stream.packetCount == 0 || (diff > 0 && diff < (1<<15)) {
 		// Told to consider every packet, or this was the first packet, or it's in-order
 		stream.lastRTPSN = header.SequenceNumber
 		stream.lastRTPTimeRTP = header.Timestamp
 		stream.lastRTPTimeTime = now
+		previousClockRate := stream.lastClockRate
+		stream.lastClockRate = stream.clockRate
+		if stream.info != nil {
+			stream.lastClockRate = float64(stream.info.ClockRateForPayloadType(header.PayloadType))
+		}
+		if stream.estimationWindow > 0 {
+			stream.addSample(now, header.Timestamp, previousClockRate)
+		}
 	}
 
 	stream.packetCount++
 	stream.octetCount += uint32(len(payload)) //nolint:gosec // G115
 }
 
-func (stream *senderStream) generateReport(now time.Time) *rtcp.SenderReport {
+// takeActivity returns whether packets were sent since the previous call.
+func (stream *senderStream) takeActivity() bool {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	active := stream.active
+	stream.active = false
+
+	return active
+}
+
+// keepalive returns whether the stream is paused, as no packets were sent for
+// idle, and whether a keepalive is due, which is every idle while paused.
+func (stream *senderStream) keepalive(now time.Time, idle time.Duration) (paused bool, due bool) {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	if now.Sub(stream.lastActivity) < idle {
+		return false, false
+	}
+	if now.Sub(stream.lastKeepalive) < idle {
+		return true, false
+	}
+	stream.lastKeepalive = now
+
+	return true, true
+}
+
+// generateReport returns the sender report at now, with ntpTime as its NTP
+// timestamp, which is now unless an NTP clock is used.
+func (stream *senderStream) generateReport(now, ntpTime time.Time) *rtcp.SenderReport {
 	stream.m.Lock()
 	defer stream.m.Unlock()
 
+	stream.sentReports[stream.nextSentReport] = ntp.ToNTP32(ntpTime)
+	stream.nextSentReport = (stream.nextSentReport + 1) % sentReportsHistory
+
 	return &rtcp.SenderReport{
 		SSRC:        stream.ssrc,
-		NTPTime:     ntp.ToNTP(now),
-		RTPTime:     stream.lastRTPTimeRTP + uint32(now.Sub(stream.lastRTPTimeTime).Seconds()*stream.clockRate),
+		NTPTime:     ntp.ToNTP(ntpTime),
+		RTPTime:     stream.rtpTime(now),
 		PacketCount: stream.packetCount,
 		OctetCount:  stream.octetCount,
 	}
 }
+
+// jitter returns the interarrival jitter of the reception report as a
+// duration, using the clock rate of the last sent packet.
+func (stream *senderStream) jitter(report rtcp.ReceptionReport) time.Duration {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	if stream.lastClockRate == 0 {
+		return 0
+	}
+
+	return time.Duration(float64(report.Jitter) / stream.lastClockRate * float64(time.Second))
+}
+
+// processReceptionReport computes the round trip time from the LSR and DLSR
+// fields of a reception report received at now, in the time of the clock the
+// NTP timestamps of the sender reports were taken from. It returns false if
+// the report doesn't refer to one of the last sent reports.
+func (stream *senderStream) processReceptionReport(now time.Time, report rtcp.ReceptionReport) (time.Duration, bool) {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	if report.LastSenderReport == 0 || !stream.sentReport(report.LastSenderReport) {
+		return 0, false
+	}
+	// In units of 1/65536 seconds, a negative RTT wraps around
+	rtt := ntp.ToNTP32(now) - report.LastSenderReport - report.Delay
+	if rtt >= 1<<31 {
+		return 0, false
+	}
+	stream.rtt = ntp.ToDuration(rtt)
+	stream.hasRTT = true
+
+	return stream.rtt, true
+}
+
+// sentReport must be called with stream.m held.
+func (stream *senderStream) sentReport(lsr uint32) bool {
+	for _, sent := range stream.sentReports {
+		if sent == lsr {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (stream *senderStream) roundTripTime() (time.Duration, bool) {
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	return stream.rtt, stream.hasRTT
+}
+
+// addSample records the RTP timestamp of a packet sent at now. Samples of a
+// previous clock rate can't be compared and are dropped.
+func (stream *senderStream) addSample(now time.Time, rtpTime uint32, previousClockRate float64) {
+	if previousClockRate != stream.lastClockRate {
+		stream.samples = stream.samples[:0]
+	}
+	stream.samples = append(stream.samples, rtpTimeSample{time: now, rtpTime: rtpTime})
+
+	expired := 0
+	for expired < len(stream.samples)-1 && now.Sub(stream.samples[expired].time) > stream.estimationWindow {
+		expired++
+	}
+	stream.samples = append(stream.samples[:0], stream.samples[expired:]...)
+}
+
+// rtpTime returns the RTP time corresponding to now. It is extrapolated from
+// the last sent packet, or with an estimation window from the packet which
+// was delayed the least, since forwarded packets are sent with the jitter of
+// their arrival.
+func (stream *senderStream) rtpTime(now time.Time) uint32 {
+	extrapolate := func(rtpTime uint32, sent time.Time) uint32 {
+		return rtpTime + uint32(now.Sub(sent).Seconds()*stream.lastClockRate)
+	}
+
+	estimate := extrapolate(stream.lastRTPTimeRTP, stream.lastRTPTimeTime)
+	if len(stream.samples) == 0 {
+		return estimate
+	}
+
+	estimate = extrapolate(stream.samples[0].rtpTime, stream.samples[0].time)
+	for _, sample := range stream.samples[1:] {
+		// Samples delayed less result in later RTP times
+		if candidate := extrapolate(sample.rtpTime, sample.time); int32(candidate-estimate) > 0 { //nolint:gosec // G115
+			estimate = candidate
+		}
+	}
+
+	return estimate
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"errors"
+
+	"github.com/pion/rtcp"
+)
+
+var errMissingCNAME = errors.New("source description requires a CNAME")
+
+// SourceDescription is the SDES information bundled with every report, so
+// the written RTCP packets are compound packets as required by RFC 3550
+// section 6.1. The CNAME should be the same for all streams of an endpoint,
+// so receivers can synchronize them. Name and Tool are optional.
+type SourceDescription struct {
+	CNAME string
+	Name  string
+	Tool  string
+}
+
+// packet returns a SDES packet with a chunk describing the SSRC.
+func (d *SourceDescription) packet(ssrc uint32) *rtcp.SourceDescription {
+	items := []rtcp.SourceDescriptionItem{{Type: rtcp.SDESCNAME, Text: d.CNAME}}
+	if d.Name != "" {
+		items = append(items, rtcp.SourceDescriptionItem{Type: rtcp.SDESName, Text: d.Name})
+	}
+	if d.Tool != "" {
+		items = append(items, rtcp.SourceDescriptionItem{Type: rtcp.SDESTool, Text: d.Tool})
+	}
+
+	return &rtcp.SourceDescription{
+		Chunks: []rtcp.SourceDescriptionChunk{{Source: ssrc, Items: items}},
+	}
+}
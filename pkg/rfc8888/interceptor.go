@@ -7,6 +7,7 @@ package rfc8888
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
@@ -67,6 +68,10 @@ type SenderInterceptor struct {
 	newTicker     TickerFactory
 	now           func() time.Time
 	close         chan struct{}
+
+	// requireCompound is set once a stream requiring compound RTCP is bound,
+	// as the reports cover all streams.
+	requireCompound atomic.Bool
 }
 
 type packet struct {
@@ -96,8 +101,12 @@ func (s *SenderInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interc
 // It is called once for per RemoteStream. The returned method
 // will be called once per rtp packet..
 func (s *SenderInterceptor) BindRemoteStream(
-	_ *interceptor.StreamInfo, reader interceptor.RTPReader,
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
 ) interceptor.RTPReader {
+	if info.RequireCompoundRTCP {
+		s.requireCompound.Store(true)
+	}
+
 	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
 		i, attr, err := reader.Read(b, a)
 		if err != nil {
@@ -182,7 +191,11 @@ func (s *SenderInterceptor) loop(writer interceptor.RTCPWriter) {
 				continue
 			}
 			s.log.Tracef("got report: %v", pkts)
-			if _, err := writer.Write([]rtcp.Packet{pkts}, nil); err != nil {
+			report := []rtcp.Packet{pkts}
+			if s.requireCompound.Load() {
+				report = interceptor.CompoundRTCP(report, pkts.SenderSSRC)
+			}
+			if _, err := writer.Write(report, nil); err != nil {
 				s.log.Error(err.Error())
 			}
 		}
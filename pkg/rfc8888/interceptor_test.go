@@ -81,6 +81,32 @@ func TestInterceptor(t *testing.T) {
 		assert.Equal(t, 10, len(fb.ReportBlocks[0].MetricBlocks))
 	})
 
+	t.Run("compound RTCP required", func(t *testing.T) {
+		f, err := NewSenderInterceptor()
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC:                123456,
+			RequireCompoundRTCP: true,
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123456}})
+
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, len(pkts), 2)
+		rr, ok := pkts[0].(*rtcp.ReceiverReport)
+		assert.True(t, ok)
+		fb, ok := pkts[1].(*rtcp.CCFeedbackReport)
+		assert.True(t, ok)
+		assert.Equal(t, fb.SenderSSRC, rr.SSRC)
+	})
+
 	t.Run("different delays between RTP packets", func(t *testing.T) {
 		mNow := &test.MockTime{}
 		mTick := &test.MockTicker{
@@ -6,7 +6,7 @@ package rfc8888
 import (
 	"time"
 
-	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/rtcp"
 )
 
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package rtcpaggregator provides an interceptor that coalesces RTCP packets
+// written by different interceptors within a short window into a single
+// compound write.
+package rtcpaggregator
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+const (
+	defaultWindow  = 5 * time.Millisecond
+	defaultMaxSize = 1200
+)
+
+// InterceptorFactory is a interceptor.Factory for an aggregator Interceptor.
+type InterceptorFactory struct {
+	opts []Option
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{opts}, nil
+}
+
+// NewInterceptor constructs a new aggregator Interceptor.
+func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	aggregator := &Interceptor{
+		window:     defaultWindow,
+		maxSize:    defaultMaxSize,
+		log:        logging.NewDefaultLoggerFactory().NewLogger("rtcp_aggregator"),
+		reportSSRC: rand.Uint32(), // #nosec
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(aggregator); err != nil {
+			return nil, err
+		}
+	}
+
+	return aggregator, nil
+}
+
+// Interceptor batches outgoing RTCP packets. It only sees the packets written
+// by interceptors registered after it, so it should be the first interceptor
+// of the chain.
+type Interceptor struct {
+	interceptor.NoOp
+	window  time.Duration
+	maxSize int
+	log     logging.LeveledLogger
+
+	requireCompound bool
+	reportSSRC      uint32
+
+	m       sync.Mutex
+	batches []*batch
+	closed  bool
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (i *Interceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if i.closed {
+		return writer
+	}
+
+	b := &batch{
+		writer:          writer,
+		window:          i.window,
+		maxSize:         i.maxSize,
+		log:             i.log,
+		requireCompound: i.requireCompound,
+		reportSSRC:      i.reportSSRC,
+	}
+	i.batches = append(i.batches, b)
+
+	return b
+}
+
+// Close flushes all pending packets and closes the interceptor.
+func (i *Interceptor) Close() error {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	i.closed = true
+	for _, b := range i.batches {
+		b.close()
+	}
+	i.batches = nil
+
+	return nil
+}
+
+// batch collects the packets written to a single RTCPWriter.
+type batch struct {
+	writer  interceptor.RTCPWriter
+	window  time.Duration
+	maxSize int
+	log     logging.LeveledLogger
+
+	requireCompound bool
+	reportSSRC      uint32
+
+	m          sync.Mutex
+	pkts       []rtcp.Packet
+	size       int
+	attributes interceptor.Attributes
+	timer      *time.Timer
+	generation uint64
+	closed     bool
+}
+
+// Write queues pkts to be sent with the next flush of the batch.
+func (b *batch) Write(pkts []rtcp.Packet, attributes interceptor.Attributes) (int, error) {
+	size := 0
+	for _, pkt := range pkts {
+		size += pkt.MarshalSize()
+	}
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.closed || b.window <= 0 {
+		return b.writer.Write(b.compound(pkts), attributes)
+	}
+
+	if len(b.pkts) > 0 && b.size+size > b.maxSize {
+		b.flushLocked()
+	}
+
+	if len(b.pkts) == 0 {
+		b.attributes = attributes
+		generation := b.generation
+		b.timer = time.AfterFunc(b.window, func() {
+			b.flush(generation)
+		})
+	}
+	b.pkts = append(b.pkts, pkts...)
+	b.size += size
+
+	return size, nil
+}
+
+func (b *batch) flush(generation uint64) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	// The batch was already flushed by a later write or close.
+	if generation != b.generation {
+		return
+	}
+	b.flushLocked()
+}
+
+func (b *batch) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pkts) == 0 {
+		return
+	}
+
+	b.generation++
+	pkts := b.compound(interceptor.OrderReportsFirst(b.pkts))
+	attributes := b.attributes
+	b.pkts = nil
+	b.size = 0
+	b.attributes = nil
+
+	if _, err := b.writer.Write(pkts, attributes); err != nil {
+		b.log.Warnf("failed sending: %+v", err)
+	}
+}
+
+func (b *batch) close() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.flushLocked()
+	b.closed = true
+}
+
+// compound makes pkts start with a report if compound packets are required,
+// see interceptor.CompoundRTCP.
+func (b *batch) compound(pkts []rtcp.Packet) []rtcp.Packet {
+	if !b.requireCompound {
+		return pkts
+	}
+
+	return interceptor.CompoundRTCP(pkts, b.reportSSRC)
+}
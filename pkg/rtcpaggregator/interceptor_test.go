@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcpaggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestInterceptor(t *testing.T, opts ...Option) interceptor.Interceptor {
+	t.Helper()
+
+	opts = append([]Option{Log(logging.NewDefaultLoggerFactory().NewLogger("test"))}, opts...)
+	f, err := NewInterceptor(opts...)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	return i
+}
+
+func TestInterceptor(t *testing.T) {
+	t.Run("coalesces writes within window", func(t *testing.T) {
+		i := newTestInterceptor(t, Window(20*time.Millisecond))
+		stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123456}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		pli := &rtcp.PictureLossIndication{MediaSSRC: 123456}
+		rr := &rtcp.ReceiverReport{SSRC: 1}
+		assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{pli}))
+		assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{rr}))
+
+		select {
+		case <-stream.WrittenRTCP():
+			assert.FailNow(t, "batch should not be flushed before the window elapsed")
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, []rtcp.Packet{rr, pli}, pkts)
+	})
+
+	t.Run("flushes when exceeding max size", func(t *testing.T) {
+		i := newTestInterceptor(t, Window(time.Hour), MaxSize(20))
+		stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123456}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		first := &rtcp.PictureLossIndication{MediaSSRC: 1}
+		second := &rtcp.PictureLossIndication{MediaSSRC: 2}
+		assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{first}))
+		assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{second}))
+
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, []rtcp.Packet{first}, pkts)
+	})
+
+	t.Run("flushes on close", func(t *testing.T) {
+		i := newTestInterceptor(t, Window(time.Hour))
+		stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123456}, i)
+
+		pli := &rtcp.PictureLossIndication{MediaSSRC: 1}
+		assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{pli}))
+		assert.NoError(t, stream.Close())
+
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, []rtcp.Packet{pli}, pkts)
+	})
+
+	t.Run("passes through without window", func(t *testing.T) {
+		i := newTestInterceptor(t, Window(0))
+		stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123456}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		pli := &rtcp.PictureLossIndication{MediaSSRC: 1}
+		assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{pli}))
+
+		select {
+		case pkts := <-stream.WrittenRTCP():
+			assert.Equal(t, []rtcp.Packet{pli}, pkts)
+		default:
+			assert.FailNow(t, "packet should be written immediately")
+		}
+	})
+
+	t.Run("prepends receiver report when compound is required", func(t *testing.T) {
+		i := newTestInterceptor(t, Window(0), RequireCompound())
+		stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123456}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		pli := &rtcp.PictureLossIndication{MediaSSRC: 1}
+		assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{pli}))
+
+		pkts := <-stream.WrittenRTCP()
+		assert.Len(t, pkts, 2)
+		assert.IsType(t, &rtcp.ReceiverReport{}, pkts[0])
+		assert.Equal(t, pli, pkts[1])
+
+		sr := &rtcp.SenderReport{SSRC: 1}
+		assert.NoError(t, stream.WriteRTCP([]rtcp.Packet{pli, sr}))
+		assert.Equal(t, []rtcp.Packet{sr, pli}, <-stream.WrittenRTCP())
+	})
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcpaggregator
+
+import (
+	"time"
+
+	"github.com/pion/logging"
+)
+
+// Option can be used to configure the aggregator Interceptor.
+type Option func(i *Interceptor) error
+
+// Log sets a logger for the interceptor.
+func Log(log logging.LeveledLogger) Option {
+	return func(i *Interceptor) error {
+		i.log = log
+
+		return nil
+	}
+}
+
+// Window sets how long packets are collected after the first packet of a
+// batch was written, before the batch is passed on as one compound write.
+func Window(window time.Duration) Option {
+	return func(i *Interceptor) error {
+		i.window = window
+
+		return nil
+	}
+}
+
+// MaxSize sets the maximum size in bytes of an aggregated batch. A write
+// which would exceed it causes the pending batch to be flushed first.
+func MaxSize(size int) Option {
+	return func(i *Interceptor) error {
+		i.maxSize = size
+
+		return nil
+	}
+}
+
+// RequireCompound makes every write start with a report, as required by peers
+// not supporting reduced-size RTCP (RFC 5506). Batches without a sender or
+// receiver report get an empty receiver report prepended. The writes are only
+// compound packets as defined by RFC 3550 if the batches contain a SDES CNAME,
+// see interceptor.CompoundRTCP.
+func RequireCompound() Option {
+	return func(i *Interceptor) error {
+		i.requireCompound = true
+
+		return nil
+	}
+}
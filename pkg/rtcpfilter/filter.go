@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcpfilter
+
+import (
+	"github.com/pion/rtcp"
+)
+
+// Type is the type of a RTCP packet.
+type Type int
+
+// The types of RTCP packets.
+const (
+	TypeUnknown Type = iota
+	TypeSenderReport
+	TypeReceiverReport
+	TypeSourceDescription
+	TypeGoodbye
+	TypeApplicationDefined
+	TypeExtendedReport
+	TypeNACK
+	TypeRapidResynchronizationRequest
+	TypeTransportCC
+	TypeCCFeedback
+	TypePLI
+	TypeSLI
+	TypeFIR
+	TypeREMB
+)
+
+// TypeOf returns the type of pkt, TypeUnknown for packets the rtcp package
+// couldn't parse.
+func TypeOf(pkt rtcp.Packet) Type { //nolint:cyclop
+	switch pkt.(type) {
+	case *rtcp.SenderReport:
+		return TypeSenderReport
+	case *rtcp.ReceiverReport:
+		return TypeReceiverReport
+	case *rtcp.SourceDescription:
+		return TypeSourceDescription
+	case *rtcp.Goodbye:
+		return TypeGoodbye
+	case *rtcp.ApplicationDefined:
+		return TypeApplicationDefined
+	case *rtcp.ExtendedReport:
+		return TypeExtendedReport
+	case *rtcp.TransportLayerNack:
+		return TypeNACK
+	case *rtcp.RapidResynchronizationRequest:
+		return TypeRapidResynchronizationRequest
+	case *rtcp.TransportLayerCC:
+		return TypeTransportCC
+	case *rtcp.CCFeedbackReport:
+		return TypeCCFeedback
+	case *rtcp.PictureLossIndication:
+		return TypePLI
+	case *rtcp.SliceLossIndication:
+		return TypeSLI
+	case *rtcp.FullIntraRequest:
+		return TypeFIR
+	case *rtcp.ReceiverEstimatedMaximumBitrate:
+		return TypeREMB
+	default:
+		return TypeUnknown
+	}
+}
+
+// Filter returns whether a packet is forwarded.
+type Filter func(pkt rtcp.Packet) bool
+
+// Allow returns a Filter forwarding only packets of the given types.
+func Allow(types ...Type) Filter {
+	allowed := typeSet(types)
+
+	return func(pkt rtcp.Packet) bool {
+		_, ok := allowed[TypeOf(pkt)]
+
+		return ok
+	}
+}
+
+// Deny returns a Filter forwarding all packets except those of the given
+// types.
+func Deny(types ...Type) Filter {
+	denied := typeSet(types)
+
+	return func(pkt rtcp.Packet) bool {
+		_, ok := denied[TypeOf(pkt)]
+
+		return !ok
+	}
+}
+
+func typeSet(types []Type) map[Type]struct{} {
+	set := make(map[Type]struct{}, len(types))
+	for _, t := range types {
+		set[t] = struct{}{}
+	}
+
+	return set
+}
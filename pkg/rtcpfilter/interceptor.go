@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package rtcpfilter provides an interceptor which forwards only selected
+// types of RTCP packets, separately for each direction. Relays like SFUs use
+// it to terminate RTCP on a leg, e.g. passing PLI and NACK on to the
+// publisher while stripping REMB and extended reports meant for the relay
+// itself.
+package rtcpfilter
+
+import (
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+)
+
+// InterceptorFactory is a interceptor.Factory for a filter Interceptor.
+type InterceptorFactory struct {
+	opts []Option
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{opts}, nil
+}
+
+// NewInterceptor constructs a new filter Interceptor.
+func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	i := &Interceptor{
+		NoOp:       interceptor.NoOp{},
+		log:        logging.NewDefaultLoggerFactory().NewLogger("rtcpfilter"),
+		incoming:   nil,
+		outgoing:   nil,
+		rawHandler: nil,
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// Interceptor removes the RTCP packets rejected by its filters from received
+// and sent batches, and hands the received packets which couldn't be parsed to
+// its raw handler. Batches without any forwarded packet are dropped
+// entirely. It only filters received packets for the interceptors registered
+// before it, and sent packets written by the interceptors registered after
+// it.
+type Interceptor struct {
+	interceptor.NoOp
+	log        logging.LeveledLogger
+	incoming   Filter
+	outgoing   Filter
+	rawHandler RawPacketHandler
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (i *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	if i.incoming == nil && i.rawHandler == nil {
+		return reader
+	}
+
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		for {
+			n, attr, err := reader.Read(b, a)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			if attr == nil {
+				attr = make(interceptor.Attributes)
+			}
+			pkts, err := attr.GetRTCPPackets(b[:n])
+			if err != nil {
+				return 0, nil, err
+			}
+
+			if i.rawHandler != nil {
+				for _, pkt := range pkts {
+					if raw, ok := pkt.(*rtcp.RawPacket); ok {
+						i.rawHandler(*raw)
+					}
+				}
+			}
+			if i.incoming == nil {
+				return n, attr, nil
+			}
+
+			forwarded := i.filter(i.incoming, pkts)
+			switch len(forwarded) {
+			case len(pkts):
+				return n, attr, nil
+			case 0:
+				// The attributes of the dropped batch must not leak into the next one
+				a = interceptor.Attributes{}
+
+				continue
+			}
+
+			raw, err := rtcp.Marshal(forwarded)
+			if err != nil {
+				return 0, nil, err
+			}
+			attr.SetRTCPPackets(forwarded)
+
+			return copy(b, raw), attr, nil
+		}
+	})
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (i *Interceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	if i.outgoing == nil {
+		return writer
+	}
+
+	return interceptor.RTCPWriterFunc(func(pkts []rtcp.Packet, attributes interceptor.Attributes) (int, error) {
+		forwarded := i.filter(i.outgoing, pkts)
+		if len(forwarded) == 0 {
+			return 0, nil
+		}
+
+		return writer.Write(forwarded, attributes)
+	})
+}
+
+func (i *Interceptor) filter(filter Filter, pkts []rtcp.Packet) []rtcp.Packet {
+	forwarded := make([]rtcp.Packet, 0, len(pkts))
+	for _, pkt := range pkts {
+		if filter(pkt) {
+			forwarded = append(forwarded, pkt)
+
+			continue
+		}
+		i.log.Debugf("not forwarding %T", pkt)
+	}
+
+	return forwarded
+}
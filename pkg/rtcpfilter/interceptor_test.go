@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcpfilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptor(t *testing.T) {
+	factory, err := NewInterceptor(
+		Incoming(Allow(TypePLI, TypeFIR, TypeNACK)),
+		Outgoing(Deny(TypeREMB, TypeExtendedReport)),
+	)
+	require.NoError(t, err)
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	t.Run("incoming", func(t *testing.T) {
+		pli := &rtcp.PictureLossIndication{SenderSSRC: 456, MediaSSRC: 123}
+		nack := &rtcp.TransportLayerNack{SenderSSRC: 456, MediaSSRC: 123, Nacks: []rtcp.NackPair{{PacketID: 1}}}
+		stream.ReceiveRTCP([]rtcp.Packet{
+			&rtcp.ReceiverReport{SSRC: 456},
+			pli,
+			&rtcp.ReceiverEstimatedMaximumBitrate{SenderSSRC: 456, Bitrate: 1000, SSRCs: []uint32{123}},
+		})
+		// A batch of only filtered packets is dropped
+		stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ReceiverReport{SSRC: 456}})
+		stream.ReceiveRTCP([]rtcp.Packet{nack})
+
+		for _, expected := range [][]rtcp.Packet{{pli}, {nack}} {
+			select {
+			case r := <-stream.ReadRTCP():
+				require.NoError(t, r.Err)
+				assert.Equal(t, expected, r.Packets)
+			case <-time.After(time.Second):
+				assert.FailNow(t, "receiver rtcp packets not found")
+			}
+		}
+	})
+
+	t.Run("outgoing", func(t *testing.T) {
+		rr := &rtcp.ReceiverReport{SSRC: 456}
+		require.NoError(t, stream.WriteRTCP([]rtcp.Packet{
+			rr,
+			&rtcp.ReceiverEstimatedMaximumBitrate{SenderSSRC: 456, Bitrate: 1000, SSRCs: []uint32{123}},
+			&rtcp.ExtendedReport{SenderSSRC: 456},
+		}))
+		require.NoError(t, stream.WriteRTCP([]rtcp.Packet{&rtcp.ExtendedReport{SenderSSRC: 456}}))
+		pli := &rtcp.PictureLossIndication{SenderSSRC: 456, MediaSSRC: 123}
+		require.NoError(t, stream.WriteRTCP([]rtcp.Packet{pli}))
+
+		for _, expected := range [][]rtcp.Packet{{rr}, {pli}} {
+			select {
+			case pkts := <-stream.WrittenRTCP():
+				assert.Equal(t, expected, pkts)
+			case <-time.After(time.Second):
+				assert.FailNow(t, "written rtcp packets not found")
+			}
+		}
+	})
+}
+
+func TestTypeOf(t *testing.T) {
+	assert.Equal(t, TypeSenderReport, TypeOf(&rtcp.SenderReport{}))
+	assert.Equal(t, TypeTransportCC, TypeOf(&rtcp.TransportLayerCC{}))
+	assert.Equal(t, TypeREMB, TypeOf(&rtcp.ReceiverEstimatedMaximumBitrate{}))
+	assert.Equal(t, TypeUnknown, TypeOf(&rtcp.RawPacket{}))
+
+	assert.True(t, Allow(TypePLI)(&rtcp.PictureLossIndication{}))
+	assert.False(t, Allow(TypePLI)(&rtcp.FullIntraRequest{}))
+	assert.False(t, Deny(TypePLI)(&rtcp.PictureLossIndication{}))
+	assert.True(t, Deny(TypePLI)(&rtcp.FullIntraRequest{}))
+}
+
+func TestInterceptor_RawHandler(t *testing.T) {
+	raw := make(chan []byte, 1)
+	factory, err := NewInterceptor(RawHandler(func(b []byte) {
+		raw <- append([]byte{}, b...)
+	}))
+	require.NoError(t, err)
+	i, err := factory.NewInterceptor("")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	// A PLI without media SSRC, which the rtcp package fails to parse
+	truncated := rtcp.RawPacket{0x81, 0xce, 0x00, 0x01, 0x00, 0x00, 0x01, 0xc8}
+	pli := &rtcp.PictureLossIndication{SenderSSRC: 456, MediaSSRC: 123}
+	stream.ReceiveRTCP([]rtcp.Packet{&rtcp.ReceiverReport{SSRC: 456}, &truncated, pli})
+
+	select {
+	case b := <-raw:
+		assert.Equal(t, []byte(truncated), b)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "raw packet not handled")
+	}
+
+	// The packets after the unparsable one are still read
+	select {
+	case r := <-stream.ReadRTCP():
+		require.NoError(t, r.Err)
+		require.Len(t, r.Packets, 3)
+		assert.Equal(t, []rtcp.Packet{&truncated, pli}, r.Packets[1:])
+	case <-time.After(time.Second):
+		assert.FailNow(t, "receiver rtcp packets not found")
+	}
+}
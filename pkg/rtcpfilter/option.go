@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtcpfilter
+
+import (
+	"github.com/pion/logging"
+)
+
+// Option can be used to configure the filter Interceptor.
+type Option func(i *Interceptor) error
+
+// Log sets a logger for the interceptor.
+func Log(log logging.LeveledLogger) Option {
+	return func(i *Interceptor) error {
+		i.log = log
+
+		return nil
+	}
+}
+
+// Incoming sets the filter of received RTCP packets. By default all packets
+// are forwarded.
+func Incoming(filter Filter) Option {
+	return func(i *Interceptor) error {
+		i.incoming = filter
+
+		return nil
+	}
+}
+
+// Outgoing sets the filter of sent RTCP packets, including those written by
+// interceptors registered after this one. By default all packets are
+// forwarded.
+func Outgoing(filter Filter) Option {
+	return func(i *Interceptor) error {
+		i.outgoing = filter
+
+		return nil
+	}
+}
+
+// RawPacketHandler handles the raw bytes of a received RTCP packet.
+type RawPacketHandler func(raw []byte)
+
+// RawHandler sets a handler which is called with each received packet the
+// rtcp package couldn't parse, e.g. of experimental types, before the packets
+// are filtered.
+func RawHandler(handler RawPacketHandler) Option {
+	return func(i *Interceptor) error {
+		i.rawHandler = handler
+
+		return nil
+	}
+}
@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package speaker provides an interceptor which selects the dominant speaker
+// of the remote audio streams from their audio level header extensions (RFC
+// 6464), e.g. for SFUs switching the forwarded video to the active speaker.
+package speaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultInterval = 100 * time.Millisecond
+
+	// The time scales the activity of speakers is compared on, in intervals.
+	immediateIntervals = 3
+	mediumIntervals    = 10
+	longIntervals      = 30
+
+	// minActivity is the medium activity a speaker needs to become dominant.
+	minActivity = 0.05
+
+	// noiseLevel is the audio level in -dBov at which packets don't count as
+	// activity, the activity of louder packets grows linearly in dB.
+	noiseLevel = 70
+)
+
+var errInvalidInterval = errors.New("speaker detection interval must be positive")
+
+// ChangeCallback is called with the SSRC of the new dominant speaker.
+type ChangeCallback func(ssrc uint32)
+
+// DetectorOption configures a Detector.
+type DetectorOption func(*Detector) error
+
+// DetectorInterval sets the interval the activity of the speakers is measured
+// in and the dominant speaker is reevaluated. The default is 100ms.
+func DetectorInterval(interval time.Duration) DetectorOption {
+	return func(d *Detector) error {
+		if interval <= 0 {
+			return errInvalidInterval
+		}
+		d.interval = interval
+
+		return nil
+	}
+}
+
+// DetectorOnChange sets a callback which is called whenever the dominant
+// speaker changes. It is called from the detection loop and must return
+// quickly.
+func DetectorOnChange(cb ChangeCallback) DetectorOption {
+	return func(d *Detector) error {
+		d.onChange = cb
+
+		return nil
+	}
+}
+
+// Detector selects the dominant speaker of all streams reported by the
+// interceptors created with it, similar to the Dominant Speaker
+// Identification of Volfin and Cohen. The activity of every speaker is
+// measured on three time scales, the last 3, 10 and 30 intervals. Another
+// speaker only becomes dominant if it was more active than the dominant
+// speaker on all of them, so short noises and interjections don't cause a
+// switch, while an actual change of speakers is followed within a second.
+type Detector struct {
+	interval time.Duration
+	onChange ChangeCallback
+
+	m           sync.Mutex
+	speakers    map[uint32]*speaker
+	dominant    uint32
+	hasDominant bool
+
+	wg    sync.WaitGroup
+	close chan struct{}
+}
+
+// NewDetector returns a new Detector, which is shared by the interceptors
+// created with NewInterceptor.
+func NewDetector(opts ...DetectorOption) (*Detector, error) {
+	detector := &Detector{
+		interval:    defaultInterval,
+		onChange:    nil,
+		m:           sync.Mutex{},
+		speakers:    map[uint32]*speaker{},
+		dominant:    0,
+		hasDominant: false,
+		wg:          sync.WaitGroup{},
+		close:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(detector); err != nil {
+			return nil, err
+		}
+	}
+
+	detector.wg.Add(1)
+	go detector.loop()
+
+	return detector, nil
+}
+
+// DominantSpeaker returns the SSRC of the dominant speaker, and false if no
+// speaker was active enough yet.
+func (d *Detector) DominantSpeaker() (uint32, bool) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	return d.dominant, d.hasDominant
+}
+
+// Close stops the detection.
+func (d *Detector) Close() error {
+	d.m.Lock()
+	select {
+	case <-d.close:
+	default:
+		close(d.close)
+	}
+	d.m.Unlock()
+	d.wg.Wait()
+
+	return nil
+}
+
+func (d *Detector) loop() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.update()
+		case <-d.close:
+			return
+		}
+	}
+}
+
+// addLevel records a packet of the speaker with the audio level in -dBov.
+func (d *Detector) addLevel(ssrc uint32, level uint8) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	s, ok := d.speakers[ssrc]
+	if !ok {
+		s = &speaker{}
+		d.speakers[ssrc] = s
+	}
+	s.add(level)
+}
+
+func (d *Detector) remove(ssrc uint32) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	delete(d.speakers, ssrc)
+	if d.hasDominant && d.dominant == ssrc {
+		d.hasDominant = false
+	}
+}
+
+// update ends the current interval of all speakers and calls the callback if
+// the dominant speaker changed.
+func (d *Detector) update() {
+	d.m.Lock()
+	for _, s := range d.speakers {
+		s.endInterval()
+	}
+	changed := d.selectDominant()
+	dominant, onChange := d.dominant, d.onChange
+	d.m.Unlock()
+
+	if changed && onChange != nil {
+		onChange(dominant)
+	}
+}
+
+// selectDominant must be called with d.m held. It returns whether the
+// dominant speaker changed.
+func (d *Detector) selectDominant() bool {
+	var current *speaker
+	if d.hasDominant {
+		current = d.speakers[d.dominant]
+	}
+
+	var best *speaker
+	bestSSRC := uint32(0)
+	for ssrc, s := range d.speakers {
+		if s == current || s.activity(mediumIntervals) < minActivity {
+			continue
+		}
+		if current != nil && !s.dominates(current) {
+			continue
+		}
+		if best == nil || s.activity(mediumIntervals) > best.activity(mediumIntervals) {
+			best, bestSSRC = s, ssrc
+		}
+	}
+	if best == nil {
+		return false
+	}
+
+	d.dominant = bestSSRC
+	d.hasDominant = true
+
+	return true
+}
+
+// speaker holds the activity of a stream in the last intervals.
+type speaker struct {
+	// sum and packets are the activity of the packets of the current interval.
+	sum     float64
+	packets int
+	// history holds the mean activity of the last intervals, next is the
+	// index of the next one to write.
+	history [longIntervals]float64
+	next    int
+}
+
+func (s *speaker) add(level uint8) {
+	s.packets++
+	if level < noiseLevel {
+		s.sum += float64(noiseLevel-level) / noiseLevel
+	}
+}
+
+func (s *speaker) endInterval() {
+	activity := 0.0
+	if s.packets > 0 {
+		activity = s.sum / float64(s.packets)
+	}
+	s.history[s.next] = activity
+	s.next = (s.next + 1) % longIntervals
+	s.sum = 0
+	s.packets = 0
+}
+
+// activity returns the mean activity of the last intervals.
+func (s *speaker) activity(intervals int) float64 {
+	sum := 0.0
+	for k := 1; k <= intervals; k++ {
+		sum += s.history[(s.next-k+longIntervals)%longIntervals]
+	}
+
+	return sum / float64(intervals)
+}
+
+// dominates returns whether s was more active than other on all time scales.
+func (s *speaker) dominates(other *speaker) bool {
+	for _, intervals := range []int{immediateIntervals, mediumIntervals, longIntervals} {
+		if s.activity(intervals) <= other.activity(intervals) {
+			return false
+		}
+	}
+
+	return true
+}
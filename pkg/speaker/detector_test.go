@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package speaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetector(t *testing.T) {
+	changes := []uint32{}
+	// The intervals are ended manually by calling update.
+	detector, err := NewDetector(
+		DetectorInterval(time.Hour),
+		DetectorOnChange(func(ssrc uint32) {
+			changes = append(changes, ssrc)
+		}),
+	)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, detector.Close())
+	}()
+
+	interval := func(levels map[uint32]uint8) {
+		for ssrc, level := range levels {
+			for k := 0; k < 5; k++ {
+				detector.addLevel(ssrc, level)
+			}
+		}
+		detector.update()
+	}
+
+	_, ok := detector.DominantSpeaker()
+	assert.False(t, ok)
+
+	t.Run("silence", func(t *testing.T) {
+		for k := 0; k < longIntervals; k++ {
+			interval(map[uint32]uint8{1: 127, 2: noiseLevel})
+		}
+		_, ok := detector.DominantSpeaker()
+		assert.False(t, ok)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("first speaker", func(t *testing.T) {
+		interval(map[uint32]uint8{1: 20, 2: 127})
+		ssrc, ok := detector.DominantSpeaker()
+		assert.True(t, ok)
+		assert.Equal(t, uint32(1), ssrc)
+		assert.Equal(t, []uint32{1}, changes)
+
+		for k := 0; k < longIntervals; k++ {
+			interval(map[uint32]uint8{1: 20, 2: 127})
+		}
+		assert.Equal(t, []uint32{1}, changes)
+	})
+
+	t.Run("interjection", func(t *testing.T) {
+		for k := 0; k < 2; k++ {
+			interval(map[uint32]uint8{1: 20, 2: 0})
+		}
+		ssrc, _ := detector.DominantSpeaker()
+		assert.Equal(t, uint32(1), ssrc)
+		assert.Equal(t, []uint32{1}, changes)
+	})
+
+	t.Run("speaker change", func(t *testing.T) {
+		intervals := 0
+		for ; intervals < longIntervals; intervals++ {
+			if ssrc, _ := detector.DominantSpeaker(); ssrc == 2 {
+				break
+			}
+			interval(map[uint32]uint8{1: 127, 2: 20})
+		}
+		assert.Greater(t, intervals, mediumIntervals/2)
+		assert.Less(t, intervals, longIntervals)
+		assert.Equal(t, []uint32{1, 2}, changes)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		detector.remove(2)
+		_, ok := detector.DominantSpeaker()
+		assert.False(t, ok)
+
+		interval(map[uint32]uint8{1: 20})
+		ssrc, ok := detector.DominantSpeaker()
+		assert.True(t, ok)
+		assert.Equal(t, uint32(1), ssrc)
+		assert.Equal(t, []uint32{1, 2, 1}, changes)
+	})
+}
+
+func TestDetector_InvalidInterval(t *testing.T) {
+	_, err := NewDetector(DetectorInterval(0))
+	assert.ErrorIs(t, err, errInvalidInterval)
+}
+
+func TestDetector_Loop(t *testing.T) {
+	changes := make(chan uint32, 1)
+	detector, err := NewDetector(
+		DetectorInterval(time.Millisecond*10),
+		DetectorOnChange(func(ssrc uint32) {
+			changes <- ssrc
+		}),
+	)
+	assert.NoError(t, err)
+
+	detector.addLevel(1, 0)
+	select {
+	case ssrc := <-changes:
+		assert.Equal(t, uint32(1), ssrc)
+	case <-time.After(time.Second):
+		t.Fatal("speaker change not found")
+	}
+	assert.NoError(t, detector.Close())
+	assert.NoError(t, detector.Close())
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package speaker
+
+import (
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+const audioLevelURI = "urn:ietf:params:rtp-hdrext:ssrc-audio-level"
+
+// Option can be used to configure the speaker Interceptor.
+type Option func(i *Interceptor) error
+
+// Log sets a logger for the interceptor.
+func Log(log logging.LeveledLogger) Option {
+	return func(i *Interceptor) error {
+		i.log = log
+
+		return nil
+	}
+}
+
+// InterceptorFactory is a interceptor.Factory for a speaker Interceptor.
+type InterceptorFactory struct {
+	detector *Detector
+	opts     []Option
+}
+
+// NewInterceptor returns a new InterceptorFactory, whose interceptors report
+// the audio levels of the remote streams to detector.
+func NewInterceptor(detector *Detector, opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{detector: detector, opts: opts}, nil
+}
+
+// NewInterceptor constructs a new speaker Interceptor.
+func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	i := &Interceptor{
+		NoOp:     interceptor.NoOp{},
+		log:      logging.NewDefaultLoggerFactory().NewLogger("speaker"),
+		detector: f.detector,
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// Interceptor reports the audio levels of the packets of remote streams which
+// negotiated the audio level header extension to its Detector.
+type Interceptor struct {
+	interceptor.NoOp
+	log      logging.LeveledLogger
+	detector *Detector
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets.
+// It is called once for per RemoteStream. The returned method
+// will be called once per rtp packet.
+func (i *Interceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	var extID uint8
+	for _, ext := range info.RTPHeaderExtensions {
+		if ext.URI == audioLevelURI {
+			extID = uint8(ext.ID) //nolint:gosec // G115
+
+			break
+		}
+	}
+	if extID == 0 {
+		return reader
+	}
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		header, err := attr.GetRTPHeader(b[:n])
+		if err != nil {
+			return 0, nil, err
+		}
+		if ext := header.GetExtension(extID); ext != nil {
+			var level rtp.AudioLevelExtension
+			if err := level.Unmarshal(ext); err != nil {
+				i.log.Debugf("invalid audio level extension of ssrc %d: %v", info.SSRC, err)
+			} else {
+				i.detector.addLevel(info.SSRC, level.Level)
+			}
+		}
+
+		return n, attr, nil
+	})
+}
+
+// UnbindRemoteStream is called when the Stream is removed. It can be used to clean up any data related to that track.
+func (i *Interceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	i.detector.remove(info.SSRC)
+}
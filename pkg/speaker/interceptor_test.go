@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package speaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptor(t *testing.T) {
+	detector, err := NewDetector(DetectorInterval(time.Hour))
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, detector.Close())
+	}()
+
+	f, err := NewInterceptor(detector)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+
+	info := &interceptor.StreamInfo{
+		SSRC:                1,
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{{URI: audioLevelURI, ID: 3}},
+	}
+	stream := test.NewMockStream(info, i)
+	withoutLevels := test.NewMockStream(&interceptor.StreamInfo{SSRC: 2}, i)
+	defer func() {
+		assert.NoError(t, withoutLevels.Close())
+	}()
+
+	ext, err := (&rtp.AudioLevelExtension{Level: 10, Voice: true}).Marshal()
+	assert.NoError(t, err)
+	for seq := uint16(0); seq < 5; seq++ {
+		pkt := &rtp.Packet{Header: rtp.Header{SequenceNumber: seq}}
+		assert.NoError(t, pkt.Header.SetExtension(3, ext))
+		stream.ReceiveRTP(pkt)
+		withoutLevels.ReceiveRTP(pkt)
+
+		for _, s := range []*test.MockStream{stream, withoutLevels} {
+			select {
+			case r := <-s.ReadRTP():
+				assert.NoError(t, r.Err)
+				assert.Equal(t, seq, r.Packet.SequenceNumber)
+			case <-time.After(time.Second):
+				t.Fatal("receiver rtp packet not found")
+			}
+		}
+	}
+
+	detector.update()
+	ssrc, ok := detector.DominantSpeaker()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(1), ssrc)
+
+	detector.m.Lock()
+	assert.Len(t, detector.speakers, 1)
+	detector.m.Unlock()
+
+	assert.NoError(t, stream.Close())
+	i.UnbindRemoteStream(info)
+	_, ok = detector.DominantSpeaker()
+	assert.False(t, ok)
+}
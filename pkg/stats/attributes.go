@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import "github.com/pion/interceptor"
+
+type attributesKey int
+
+const (
+	// RetransmissionAttributesKey marks an incoming RTP packet as a
+	// retransmission, e.g. after it was decapsulated from an RTX stream. The
+	// value must be a bool.
+	RetransmissionAttributesKey attributesKey = iota
+)
+
+func getBool(attr interceptor.Attributes, key interface{}) bool {
+	if attr == nil {
+		return false
+	}
+	val, ok := attr.Get(key).(bool)
+
+	return ok && val
+}
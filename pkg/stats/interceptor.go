@@ -6,6 +6,7 @@ package stats
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
@@ -36,9 +37,69 @@ func SetNowFunc(now func() time.Time) Option {
 	}
 }
 
-// Getter returns the most recent stats of a stream.
+// SetMaxStreams limits the number of streams for which stats are tracked. When
+// the limit is reached, the least recently active stream is evicted to make
+// room for a new one. An evicted stream which is still bound is tracked again,
+// starting with new stats, once it becomes active again. A value <= 0 means no
+// limit.
+func SetMaxStreams(n int) Option {
+	return func(i *Interceptor) error {
+		i.maxStreams = n
+
+		return nil
+	}
+}
+
+// SetOnStreamEnded sets a callback which is called with the final stats of a
+// stream when it is unbound.
+func SetOnStreamEnded(cb StreamEndedCallback) Option {
+	return func(i *Interceptor) error {
+		i.onStreamEnded = cb
+
+		return nil
+	}
+}
+
+// SetBandwidthEstimate sets a function returning the current bandwidth
+// estimate in bits per second, e.g. the GetTargetBitrate method of a cc
+// BandwidthEstimator, which is reported as the AvailableOutgoingBitrate of
+// the TransportStats.
+func SetBandwidthEstimate(f func() int) Option {
+	return func(i *Interceptor) error {
+		i.transport.bandwidthFunc = f
+
+		return nil
+	}
+}
+
+// SetIncomingBandwidthEstimate sets a function returning the current estimate
+// of the bandwidth available for the received streams in bits per second,
+// e.g. the Estimate method of a remb ReceiverInterceptor, which is reported as
+// the AvailableIncomingBitrate of the TransportStats.
+func SetIncomingBandwidthEstimate(f func() int) Option {
+	return func(i *Interceptor) error {
+		i.transport.incomingBandwidthFunc = f
+
+		return nil
+	}
+}
+
+// StreamSummary is the final stats snapshot of a stream.
+type StreamSummary struct {
+	SSRC uint32
+	Stats
+	// Duration is the time between the stream being bound and unbound.
+	Duration time.Duration
+}
+
+// StreamEndedCallback receives the StreamSummary of a stream which was
+// unbound from the PeerConnection with the given id.
+type StreamEndedCallback func(id string, summary StreamSummary)
+
+// Getter returns the most recent stats of a stream and of the transport.
 type Getter interface {
 	Get(ssrc uint32) *Stats
+	GetTransport() TransportStats
 }
 
 // NewPeerConnectionCallback receives a new StatsGetter for a newly created
@@ -69,13 +130,16 @@ func (r *InterceptorFactory) OnNewPeerConnection(cb NewPeerConnectionCallback) {
 func (r *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
 	interceptor := &Interceptor{
 		NoOp: interceptor.NoOp{},
+		id:   id,
 		now:  time.Now,
 		lock: sync.Mutex{},
 		RecorderFactory: func(ssrc uint32, clockRate float64) Recorder {
 			return newRecorder(ssrc, clockRate)
 		},
-		recorders: map[uint32]Recorder{},
-		wg:        sync.WaitGroup{},
+		recorders:     map[uint32]*trackedRecorder{},
+		wg:            sync.WaitGroup{},
+		onStreamEnded: nil,
+		transport:     &transportRecorder{},
 	}
 	for _, opt := range r.opts {
 		if err := opt(interceptor); err != nil {
@@ -107,11 +171,41 @@ type RecorderFactory func(ssrc uint32, clockRate float64) Recorder
 // Interceptor is the interceptor that collects stream stats.
 type Interceptor struct {
 	interceptor.NoOp
+	id              string
 	now             func() time.Time
 	lock            sync.Mutex
 	RecorderFactory RecorderFactory
-	recorders       map[uint32]Recorder
+	recorders       map[uint32]*trackedRecorder
 	wg              sync.WaitGroup
+
+	maxStreams int
+	evictions  uint64
+
+	onStreamEnded StreamEndedCallback
+
+	transport *transportRecorder
+
+	// unsubscribe removes the subscription to the event bus, nil without one.
+	unsubscribe func()
+}
+
+// trackedRecorder is a Recorder with the time of the last packet it saw.
+type trackedRecorder struct {
+	Recorder
+	lastActive int64 // UnixNano, accessed atomically
+	bound      time.Time
+	labels     interceptor.Labels
+}
+
+func (t *trackedRecorder) stats() Stats {
+	stats := t.GetStats()
+	stats.Labels = t.labels
+
+	return stats
+}
+
+func (t *trackedRecorder) touch(now time.Time) {
+	atomic.StoreInt64(&t.lastActive, now.UnixNano())
 }
 
 // Get returns the statistics for the stream with ssrc.
@@ -119,7 +213,7 @@ func (r *Interceptor) Get(ssrc uint32) *Stats {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	if rec, ok := r.recorders[ssrc]; ok {
-		stats := rec.GetStats()
+		stats := rec.stats()
 
 		return &stats
 	}
@@ -127,13 +221,89 @@ func (r *Interceptor) Get(ssrc uint32) *Stats {
 	return nil
 }
 
-func (r *Interceptor) getRecorder(ssrc uint32, clockRate float64) Recorder {
+// GetTransport returns the statistics of all streams sent and received
+// through the interceptor.
+func (r *Interceptor) GetTransport() TransportStats {
+	return r.transport.get(r.now())
+}
+
+// RecommendedMaxPayloadSize returns the largest payload size in bytes a
+// packetizer should produce to keep the sent RTP packets within mtu bytes,
+// taking into account the largest header sent so far, including header
+// extensions like the transport-wide sequence number or the MID added by the
+// interceptors writing before the stats interceptor.
+func (r *Interceptor) RecommendedMaxPayloadSize(mtu int) int {
+	return r.transport.maxPayloadSize(mtu)
+}
+
+// RemoveStream stops tracking the stream with ssrc and releases its stats.
+func (r *Interceptor) RemoveStream(ssrc uint32) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if rec, ok := r.recorders[ssrc]; ok {
+		rec.Stop()
+		delete(r.recorders, ssrc)
+	}
+}
+
+// Evictions returns the number of streams that were evicted because the
+// limit set by SetMaxStreams was reached.
+func (r *Interceptor) Evictions() uint64 {
+	return atomic.LoadUint64(&r.evictions)
+}
+
+// streamInfoRecorder is implemented by recorders which support streams with
+// per payload type clock rates.
+type streamInfoRecorder interface {
+	setStreamInfo(info *interceptor.StreamInfo)
+}
+
+// discardRecorder is implemented by recorders which count the received
+// packets discarded by a later stage.
+type discardRecorder interface {
+	recordDiscarded()
+}
+
+// audioRecorder is implemented by recorders which support audio stats.
+type audioRecorder interface {
+	setAudio(channels uint16, audioLevelID uint8)
+}
+
+const audioLevelURI = "urn:ietf:params:rtp-hdrext:ssrc-audio-level"
+
+func audioLevelID(info *interceptor.StreamInfo) uint8 {
+	for _, ext := range info.RTPHeaderExtensions {
+		if ext.URI == audioLevelURI {
+			return uint8(ext.ID) //nolint:gosec // G115
+		}
+	}
+
+	return 0
+}
+
+func (r *Interceptor) getRecorder(info *interceptor.StreamInfo) *trackedRecorder {
+	ssrc := info.SSRC
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	if rec, ok := r.recorders[ssrc]; ok {
 		return rec
 	}
-	rec := r.RecorderFactory(ssrc, clockRate)
+	if r.maxStreams > 0 && len(r.recorders) >= r.maxStreams {
+		r.evictLeastRecentlyActive()
+	}
+	now := r.now()
+	rec := &trackedRecorder{
+		Recorder: r.RecorderFactory(ssrc, float64(info.ClockRate)),
+		bound:    now,
+		labels:   info.Attributes.GetLabels(),
+	}
+	if sr, ok := rec.Recorder.(streamInfoRecorder); ok {
+		sr.setStreamInfo(info)
+	}
+	if ar, ok := rec.Recorder.(audioRecorder); ok {
+		ar.setAudio(info.Channels, audioLevelID(info))
+	}
+	rec.touch(now)
 	r.wg.Add(1)
 	go func() {
 		defer r.wg.Done()
@@ -144,6 +314,59 @@ func (r *Interceptor) getRecorder(ssrc uint32, clockRate float64) Recorder {
 	return rec
 }
 
+// evictLeastRecentlyActive must be called with r.lock held.
+func (r *Interceptor) evictLeastRecentlyActive() {
+	var (
+		oldestSSRC uint32
+		oldest     int64
+		found      bool
+	)
+	for ssrc, rec := range r.recorders {
+		lastActive := atomic.LoadInt64(&rec.lastActive)
+		if !found || lastActive < oldest {
+			oldestSSRC, oldest, found = ssrc, lastActive, true
+		}
+	}
+	if !found {
+		return
+	}
+	r.recorders[oldestSSRC].Stop()
+	delete(r.recorders, oldestSSRC)
+	atomic.AddUint64(&r.evictions, 1)
+}
+
+// SetEventBus subscribes to the interceptor.PacketDiscarded events published
+// on bus, e.g. by a jitter buffer, which are counted as PacketsDiscarded of
+// the received stream.
+func (r *Interceptor) SetEventBus(bus *interceptor.EventBus) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.unsubscribe != nil {
+		r.unsubscribe()
+		r.unsubscribe = nil
+	}
+	if bus != nil {
+		r.unsubscribe = bus.Subscribe(r.handleEvent)
+	}
+}
+
+func (r *Interceptor) handleEvent(event interceptor.Event) {
+	discarded, ok := event.(interceptor.PacketDiscarded)
+	if !ok {
+		return
+	}
+	r.lock.Lock()
+	rec, ok := r.recorders[discarded.SSRC]
+	r.lock.Unlock()
+	if !ok {
+		return
+	}
+	if dr, ok := rec.Recorder.(discardRecorder); ok {
+		dr.recordDiscarded()
+	}
+}
+
 // Close closes the interceptor and associated stats recorders.
 func (r *Interceptor) Close() error {
 	defer r.wg.Wait()
@@ -151,6 +374,11 @@ func (r *Interceptor) Close() error {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
+	if r.unsubscribe != nil {
+		r.unsubscribe()
+		r.unsubscribe = nil
+	}
+
 	for _, r := range r.recorders {
 		r.Stop()
 	}
@@ -167,6 +395,7 @@ func (r *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.
 			if err != nil {
 				return 0, attattributes, err
 			}
+			r.transport.recordReceived(r.now(), n, rtcpPacketCount(bytes[:n]))
 			r.lock.Lock()
 			for _, recorder := range r.recorders {
 				recorder.QueueIncomingRTCP(r.now(), bytes[:n], attributes)
@@ -182,6 +411,11 @@ func (r *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.
 // will be called once per packet batch.
 func (r *Interceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
 	return interceptor.RTCPWriterFunc(func(pkts []rtcp.Packet, attributes interceptor.Attributes) (int, error) {
+		size := 0
+		for _, pkt := range pkts {
+			size += pkt.MarshalSize()
+		}
+		r.transport.recordSent(r.now(), size, len(pkts))
 		r.lock.Lock()
 		for _, recorder := range r.recorders {
 			recorder.QueueOutgoingRTCP(r.now(), pkts, attributes)
@@ -197,23 +431,35 @@ func (r *Interceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.
 func (r *Interceptor) BindLocalStream(
 	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
 ) interceptor.RTPWriter {
-	recorder := r.getRecorder(info.SSRC, float64(info.ClockRate))
+	r.getRecorder(info)
 
 	return interceptor.RTPWriterFunc(
 		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
-			recorder.QueueOutgoingRTP(r.now(), header, payload, attributes)
+			now := r.now()
+			// The recorder is looked up for every packet, as it may have been evicted
+			recorder := r.getRecorder(info)
+			recorder.touch(now)
+			recorder.QueueOutgoingRTP(now, header, payload, attributes)
+			r.transport.recordSentRTP(now, header.MarshalSize(), len(payload))
 
 			return writer.Write(header, payload, attributes)
 		},
 	)
 }
 
+// UnbindLocalStream is called when the Stream is removed. It reports the final
+// stats of the stream to the callback set by SetOnStreamEnded and releases
+// them.
+func (r *Interceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	r.streamEnded(info.SSRC)
+}
+
 // BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
 // The returned method will be called once per rtp packet.
 func (r *Interceptor) BindRemoteStream(
 	info *interceptor.StreamInfo, reader interceptor.RTPReader,
 ) interceptor.RTPReader {
-	recorder := r.getRecorder(info.SSRC, float64(info.ClockRate))
+	r.getRecorder(info)
 
 	return interceptor.RTPReaderFunc(
 		func(bytes []byte, attributes interceptor.Attributes) (int, interceptor.Attributes, error) {
@@ -221,9 +467,60 @@ func (r *Interceptor) BindRemoteStream(
 			if err != nil {
 				return 0, nil, err
 			}
-			recorder.QueueIncomingRTP(r.now(), bytes[:n], attributes)
+			now := r.now()
+			// The recorder is looked up for every packet, as it may have been evicted
+			recorder := r.getRecorder(info)
+			recorder.touch(now)
+			recorder.QueueIncomingRTP(now, bytes[:n], attributes)
+			r.transport.recordReceived(now, n, 0)
 
 			return n, attributes, nil
 		},
 	)
 }
+
+// UnbindRemoteStream is called when the Stream is removed. It reports the final
+// stats of the stream to the callback set by SetOnStreamEnded and releases
+// them.
+func (r *Interceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	r.streamEnded(info.SSRC)
+}
+
+func (r *Interceptor) streamEnded(ssrc uint32) {
+	r.lock.Lock()
+	rec, ok := r.recorders[ssrc]
+	delete(r.recorders, ssrc)
+	r.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	summary := StreamSummary{
+		SSRC:     ssrc,
+		Stats:    rec.stats(),
+		Duration: r.now().Sub(rec.bound),
+	}
+	rec.Stop()
+	if r.onStreamEnded != nil {
+		r.onStreamEnded(r.id, summary)
+	}
+}
+
+// rtcpPacketCount returns the number of RTCP packets in a compound packet,
+// counting from the headers, or 1 if it is malformed.
+func rtcpPacketCount(raw []byte) int {
+	count := 0
+	for len(raw) >= 4 {
+		length := ((int(raw[2])<<8 | int(raw[3])) + 1) * 4
+		if length > len(raw) {
+			break
+		}
+		raw = raw[length:]
+		count++
+	}
+	if count == 0 {
+		return 1
+	}
+
+	return count
+}
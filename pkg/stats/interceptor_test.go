@@ -4,6 +4,7 @@
 package stats
 
 import (
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -150,6 +151,184 @@ func TestInterceptor(t *testing.T) {
 	})
 }
 
+func TestInterceptor_MaxStreams(t *testing.T) {
+	now := time.Now()
+	f, err := NewInterceptor(
+		SetMaxStreams(2),
+		SetNowFunc(func() time.Time {
+			return now
+		}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	statsInterceptor, ok := i.(*Interceptor)
+	assert.True(t, ok)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	readers := map[uint32]interceptor.RTPReader{}
+	for _, ssrc := range []uint32{1, 2} {
+		buf, err := (&rtp.Packet{Header: rtp.Header{SSRC: ssrc}}).Marshal()
+		assert.NoError(t, err)
+		readers[ssrc] = i.BindRemoteStream(&interceptor.StreamInfo{SSRC: ssrc, ClockRate: 90000}, interceptor.RTPReaderFunc(
+			func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+				return copy(b, buf), a, nil
+			},
+		))
+		now = now.Add(time.Second)
+	}
+
+	// Stream 1 was bound first, but had a packet more recently
+	_, _, err = readers[1].Read(make([]byte, 1500), interceptor.Attributes{})
+	assert.NoError(t, err)
+	now = now.Add(time.Second)
+
+	i.BindRemoteStream(&interceptor.StreamInfo{SSRC: 3, ClockRate: 90000}, nil)
+	assert.NotNil(t, statsInterceptor.Get(1))
+	assert.Nil(t, statsInterceptor.Get(2))
+	assert.NotNil(t, statsInterceptor.Get(3))
+	assert.Equal(t, uint64(1), statsInterceptor.Evictions())
+
+	// The evicted stream is tracked again once it becomes active, evicting the
+	// least recently active one
+	_, _, err = readers[2].Read(make([]byte, 1500), interceptor.Attributes{})
+	assert.NoError(t, err)
+	assert.Nil(t, statsInterceptor.Get(1))
+	assert.NotNil(t, statsInterceptor.Get(2))
+	assert.Equal(t, uint64(2), statsInterceptor.Evictions())
+
+	statsInterceptor.RemoveStream(3)
+	assert.Nil(t, statsInterceptor.Get(3))
+	assert.Equal(t, uint64(2), statsInterceptor.Evictions())
+}
+
+func TestInterceptor_OnStreamEnded(t *testing.T) {
+	now := time.Now()
+	var summaries []StreamSummary
+	f, err := NewInterceptor(
+		SetNowFunc(func() time.Time {
+			return now
+		}),
+		SetOnStreamEnded(func(id string, summary StreamSummary) {
+			assert.Equal(t, "pc", id)
+			summaries = append(summaries, summary)
+		}),
+	)
+	assert.NoError(t, err)
+
+	i, err := f.NewInterceptor("pc")
+	assert.NoError(t, err)
+	statsInterceptor, ok := i.(*Interceptor)
+	assert.True(t, ok)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	info := &interceptor.StreamInfo{SSRC: 1, ClockRate: 90000, Attributes: interceptor.Attributes{}}
+	info.Attributes.SetLabels(interceptor.Labels{"conference": "42"})
+	seq := uint16(0)
+	reader := i.BindRemoteStream(info, interceptor.RTPReaderFunc(
+		func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			seq++
+			buf, err := (&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: seq}}).Marshal()
+			assert.NoError(t, err)
+
+			return copy(b, buf), a, nil
+		},
+	))
+
+	// The recorder is started asynchronously, read until it counts packets
+	assert.Eventually(t, func() bool {
+		_, _, err := reader.Read(make([]byte, 1500), interceptor.Attributes{})
+		assert.NoError(t, err)
+
+		return statsInterceptor.Get(1).InboundRTPStreamStats.PacketsReceived > 0
+	}, time.Second, time.Millisecond)
+
+	now = now.Add(3 * time.Second)
+	final := *statsInterceptor.Get(1)
+	i.UnbindRemoteStream(info)
+	i.UnbindRemoteStream(&interceptor.StreamInfo{SSRC: 2})
+
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, uint32(1), summaries[0].SSRC)
+	assert.Equal(t, 3*time.Second, summaries[0].Duration)
+	assert.Equal(t, final, summaries[0].Stats)
+	assert.Equal(t, interceptor.Labels{"conference": "42"}, summaries[0].Labels)
+
+	// The stats of the ended stream are released
+	assert.Nil(t, statsInterceptor.Get(1))
+}
+
+func TestInterceptor_UnbindReleasesStats(t *testing.T) {
+	mockRecorder := newMockRecorder()
+	f, err := NewInterceptor(SetRecorderFactory(func(uint32, float64) Recorder {
+		return mockRecorder
+	}))
+	assert.NoError(t, err)
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	statsInterceptor, ok := i.(*Interceptor)
+	assert.True(t, ok)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	// Without a callback, the recorder is still stopped and released
+	info := &interceptor.StreamInfo{SSRC: 1}
+	i.BindLocalStream(info, nil)
+	assert.NotNil(t, statsInterceptor.Get(1))
+	i.UnbindLocalStream(info)
+	assert.Nil(t, statsInterceptor.Get(1))
+	assert.True(t, mockRecorder.stopped())
+}
+
+func TestInterceptor_PacketDiscarded(t *testing.T) {
+	f, err := NewInterceptor()
+	assert.NoError(t, err)
+	i, err := f.NewInterceptor("")
+	assert.NoError(t, err)
+	statsInterceptor, ok := i.(*Interceptor)
+	assert.True(t, ok)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	bus := interceptor.NewEventBus()
+	statsInterceptor.SetEventBus(bus)
+
+	seq := uint16(0)
+	reader := i.BindRemoteStream(&interceptor.StreamInfo{SSRC: 1, ClockRate: 90000}, interceptor.RTPReaderFunc(
+		func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			seq++
+			buf, err := (&rtp.Packet{Header: rtp.Header{SSRC: 1, SequenceNumber: seq}}).Marshal()
+			assert.NoError(t, err)
+
+			return copy(b, buf), a, nil
+		},
+	))
+
+	// The recorder is started asynchronously, read until it counts packets
+	assert.Eventually(t, func() bool {
+		_, _, err := reader.Read(make([]byte, 1500), interceptor.Attributes{})
+		assert.NoError(t, err)
+
+		return statsInterceptor.Get(1).InboundRTPStreamStats.PacketsReceived > 0
+	}, time.Second, time.Millisecond)
+
+	bus.Publish(interceptor.PacketDiscarded{SSRC: 1, SequenceNumber: 1})
+	bus.Publish(interceptor.PacketDiscarded{SSRC: 2, SequenceNumber: 1})
+	assert.Equal(t, uint64(1), statsInterceptor.Get(1).InboundRTPStreamStats.PacketsDiscarded)
+
+	// Events published after the interceptor was closed aren't counted
+	assert.NoError(t, i.Close())
+	bus.Publish(interceptor.PacketDiscarded{SSRC: 1, SequenceNumber: 2})
+	assert.Equal(t, uint64(1), statsInterceptor.Get(1).InboundRTPStreamStats.PacketsDiscarded)
+}
+
 type recordedOutgoingRTP struct {
 	ts      time.Time
 	header  *rtp.Header
@@ -180,6 +359,7 @@ type mockRecorder struct {
 	incomingRTCPQueue chan recordedIncomingRTCP
 	outgoingRTPQueue  chan recordedOutgoingRTP
 	outgoingRTCPQueue chan recordedOutgoingRTCP
+	stops             atomic.Int32
 }
 
 func newMockRecorder() *mockRecorder {
@@ -230,4 +410,10 @@ func (r *mockRecorder) GetStats() Stats {
 
 func (r *mockRecorder) Start() {}
 
-func (r *mockRecorder) Stop() {}
+func (r *mockRecorder) Stop() {
+	r.stops.Add(1)
+}
+
+func (r *mockRecorder) stopped() bool {
+	return r.stops.Load() > 0
+}
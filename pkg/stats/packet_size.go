@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"fmt"
+)
+
+// PacketSizeBucketWidth is the width in bytes of the buckets of a
+// PacketSizeHistogram.
+const PacketSizeBucketWidth = 100
+
+// packetSizeBuckets is the number of buckets of a PacketSizeHistogram, the
+// last one counts all packets of 1500 bytes or larger.
+const packetSizeBuckets = 16
+
+// minHeaderSize is the size in bytes of a RTP header without CSRCs and
+// extensions.
+const minHeaderSize = 12
+
+// PacketSizeHistogram is the distribution of the sizes of sent RTP packets,
+// including the header extensions added by the interceptors which wrote the
+// packets before the stats interceptor.
+type PacketSizeHistogram struct {
+	// Buckets[i] is the number of packets with a size between
+	// i*PacketSizeBucketWidth and (i+1)*PacketSizeBucketWidth-1 bytes. The
+	// last bucket also counts all larger packets.
+	Buckets [packetSizeBuckets]uint64
+	// MaxPacketSize is the size in bytes of the largest packet.
+	MaxPacketSize int
+	// MaxHeaderSize is the size in bytes of the largest RTP header, including
+	// CSRCs and header extensions.
+	MaxHeaderSize int
+}
+
+// String returns a string representation of PacketSizeHistogram.
+func (h PacketSizeHistogram) String() string {
+	return fmt.Sprintf("%v (max packet %v, max header %v)", h.Buckets, h.MaxPacketSize, h.MaxHeaderSize)
+}
+
+func (h *PacketSizeHistogram) add(headerSize, payloadSize int) {
+	size := headerSize + payloadSize
+	bucket := size / PacketSizeBucketWidth
+	if bucket >= packetSizeBuckets {
+		bucket = packetSizeBuckets - 1
+	}
+	h.Buckets[bucket]++
+	if size > h.MaxPacketSize {
+		h.MaxPacketSize = size
+	}
+	if headerSize > h.MaxHeaderSize {
+		h.MaxHeaderSize = headerSize
+	}
+}
+
+// MaxPayloadSize returns the largest payload size in bytes which keeps RTP
+// packets within mtu bytes, the maximum size of a RTP packet on the path,
+// given the largest header sent so far. Before any packet was sent it
+// assumes a header without CSRCs and extensions. The result is not
+// negative.
+func (h PacketSizeHistogram) MaxPayloadSize(mtu int) int {
+	headerSize := h.MaxHeaderSize
+	if headerSize == 0 {
+		headerSize = minHeaderSize
+	}
+	if mtu <= headerSize {
+		return 0
+	}
+
+	return mtu - headerSize
+}
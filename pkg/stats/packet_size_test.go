@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacketSizeHistogram(t *testing.T) {
+	histogram := PacketSizeHistogram{}
+	assert.Equal(t, 1188, histogram.MaxPayloadSize(1200))
+
+	histogram.add(12, 50)
+	histogram.add(20, 1180)
+	histogram.add(24, 1600)
+
+	assert.Equal(t, uint64(1), histogram.Buckets[0])
+	assert.Equal(t, uint64(1), histogram.Buckets[12])
+	assert.Equal(t, uint64(1), histogram.Buckets[packetSizeBuckets-1])
+	assert.Equal(t, 1624, histogram.MaxPacketSize)
+	assert.Equal(t, 24, histogram.MaxHeaderSize)
+	assert.Equal(t, 1176, histogram.MaxPayloadSize(1200))
+	assert.Equal(t, 0, histogram.MaxPayloadSize(20))
+}
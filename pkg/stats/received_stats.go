@@ -34,6 +34,39 @@ type InboundRTPStreamStats struct {
 	FIRCount                    uint32
 	PLICount                    uint32
 	NACKCount                   uint32
+
+	// RetransmittedPacketsReceived and RetransmittedBytesReceived count the
+	// packets received more than once or marked with
+	// RetransmissionAttributesKey.
+	RetransmittedPacketsReceived uint64
+	RetransmittedBytesReceived   uint64
+	// PacketsDiscarded counts the interceptor.PacketDiscarded events of the
+	// stream, see Interceptor.SetEventBus.
+	PacketsDiscarded uint64
+	// PacketsInvalid counts packets marked invalid, see
+	// interceptor.Attributes.MarkInvalid. It isn't part of webrtc-stats.
+	PacketsInvalid uint64
+	// ClockDrift is the estimated drift of the sender's RTP clock relative to
+	// the local clock in parts per million, positive if the sender's clock
+	// runs faster. It isn't part of webrtc-stats.
+	ClockDrift float64
+
+	// Channels is the number of audio channels of the stream. It is a codec
+	// stat in webrtc-stats.
+	Channels uint16
+	// AudioLevel is the level of the last packet between 0 and 1, linear in
+	// the sound pressure, and TotalAudioEnergy the sum of the squared levels
+	// weighted by the durations of the packets, whose sum is
+	// TotalSamplesDuration in seconds. They are read from the audio level
+	// header extension (RFC 6464) if negotiated. The average level of an
+	// interval is the square root of the difference of the energies divided
+	// by the difference of the durations.
+	AudioLevel           float64
+	TotalAudioEnergy     float64
+	TotalSamplesDuration float64
+	// VoiceActivityPackets counts the packets whose audio level extension
+	// has the voice activity flag set. It isn't part of webrtc-stats.
+	VoiceActivityPackets uint64
 }
 
 // String returns a string representation of InboundRTPStreamStats.
@@ -46,6 +79,16 @@ func (s InboundRTPStreamStats) String() string {
 	out += fmt.Sprintf("\tFIRCount: %v\n", s.FIRCount)
 	out += fmt.Sprintf("\tPLICount: %v\n", s.PLICount)
 	out += fmt.Sprintf("\tNACKCount: %v\n", s.NACKCount)
+	out += fmt.Sprintf("\tRetransmittedPacketsReceived: %v\n", s.RetransmittedPacketsReceived)
+	out += fmt.Sprintf("\tRetransmittedBytesReceived: %v\n", s.RetransmittedBytesReceived)
+	out += fmt.Sprintf("\tPacketsDiscarded: %v\n", s.PacketsDiscarded)
+	out += fmt.Sprintf("\tPacketsInvalid: %v\n", s.PacketsInvalid)
+	out += fmt.Sprintf("\tClockDrift: %v\n", s.ClockDrift)
+	out += fmt.Sprintf("\tChannels: %v\n", s.Channels)
+	out += fmt.Sprintf("\tAudioLevel: %v\n", s.AudioLevel)
+	out += fmt.Sprintf("\tTotalAudioEnergy: %v\n", s.TotalAudioEnergy)
+	out += fmt.Sprintf("\tTotalSamplesDuration: %v\n", s.TotalSamplesDuration)
+	out += fmt.Sprintf("\tVoiceActivityPackets: %v\n", s.VoiceActivityPackets)
 
 	return out
 }
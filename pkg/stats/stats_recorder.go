@@ -4,13 +4,15 @@
 package stats
 
 import (
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
-	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/internal/clockdrift"
 	"github.com/pion/interceptor/internal/sequencenumber"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
@@ -22,6 +24,9 @@ type Stats struct {
 	OutboundRTPStreamStats
 	RemoteInboundRTPStreamStats
 	RemoteOutboundRTPStreamStats
+
+	// Labels are the labels of the stream, see interceptor.Labels.
+	Labels interceptor.Labels
 }
 
 type internalStats struct {
@@ -29,10 +34,20 @@ type internalStats struct {
 	inboundSequenceNumberInitialized bool
 	inboundFirstSequenceNumber       int64
 	inboundHighestSequenceNumber     int64
+	// inboundReceivedWindow is a bitmask of the packets received in the
+	// window ending at inboundHighestSequenceNumber, bit 0 being the highest.
+	inboundReceivedWindow uint64
 
 	inboundLastArrivalInitialized bool
 	inboundLastArrival            time.Time
 	inboundLastTransit            int
+	inboundLastClockRate          float64
+	inboundClockDrift             *clockdrift.Estimator
+
+	inboundAudioTimestampInitialized bool
+	inboundLastAudioTimestamp        uint32
+
+	outboundLastClockRate float64
 
 	remoteInboundFirstSequenceNumberInitialized bool
 	remoteInboundFirstSequenceNumber            int64
@@ -79,6 +94,12 @@ type recorder struct {
 
 	ssrc      uint32
 	clockRate float64
+	// info overrides clockRate for specific payload types, see
+	// interceptor.StreamInfo.ClockRateForPayloadType.
+	info *interceptor.StreamInfo
+	// audioLevelID is the ID of the audio level header extension, 0 if it
+	// wasn't negotiated.
+	audioLevelID uint8
 
 	maxLastSenderReports          int
 	maxLastReceiverReferenceTimes int
@@ -100,6 +121,26 @@ func newRecorder(ssrc uint32, clockRate float64) *recorder {
 	}
 }
 
+func (r *recorder) setStreamInfo(info *interceptor.StreamInfo) {
+	r.info = info
+}
+
+func (r *recorder) setAudio(channels uint16, audioLevelID uint8) {
+	r.ms.Lock()
+	defer r.ms.Unlock()
+
+	r.latestStats.Channels = channels
+	r.audioLevelID = audioLevelID
+}
+
+func (r *recorder) clockRateFor(payloadType uint8) float64 {
+	if r.info == nil {
+		return r.clockRate
+	}
+
+	return float64(r.info.ClockRateForPayloadType(payloadType))
+}
+
 func (r *recorder) Stop() {
 	atomic.StoreUint32(&r.running, 0)
 }
@@ -121,12 +162,14 @@ func (r *recorder) recordIncomingRTP(latestStats internalStats, incoming *incomi
 		return latestStats
 	}
 	sequenceNumber := latestStats.inboundSequencerNumber.Unwrap(incoming.header.SequenceNumber)
+	duplicate := false
 	if !latestStats.inboundSequenceNumberInitialized {
 		latestStats.inboundFirstSequenceNumber = sequenceNumber
-		latestStats.inboundSequenceNumberInitialized = true
-	}
-	if sequenceNumber > latestStats.inboundHighestSequenceNumber {
 		latestStats.inboundHighestSequenceNumber = sequenceNumber
+		latestStats.inboundReceivedWindow = 1
+		latestStats.inboundSequenceNumberInitialized = true
+	} else {
+		duplicate = latestStats.markReceived(sequenceNumber)
 	}
 
 	latestStats.InboundRTPStreamStats.PacketsReceived++
@@ -135,20 +178,48 @@ func (r *recorder) recordIncomingRTP(latestStats internalStats, incoming *incomi
 	latestStats.InboundRTPStreamStats.PacketsLost = expectedPackets -
 		int64(latestStats.InboundRTPStreamStats.PacketsReceived)
 
+	// Invalid packets are received, but would distort jitter and the byte counts
+	if incoming.attr.IsInvalid() {
+		latestStats.PacketsInvalid++
+
+		return latestStats
+	}
+	// A packet received twice or decapsulated from RTX was retransmitted, e.g.
+	// after a NACK
+	if duplicate || getBool(incoming.attr, RetransmissionAttributesKey) {
+		latestStats.RetransmittedPacketsReceived++
+		//nolint:gosec // G115
+		latestStats.RetransmittedBytesReceived += uint64(incoming.header.MarshalSize() + incoming.payloadLen)
+	}
+
+	clockRate := r.clockRateFor(incoming.header.PayloadType)
+	changedClockRate := clockRate != latestStats.inboundLastClockRate
 	if !latestStats.inboundLastArrivalInitialized {
 		latestStats.inboundLastArrival = incoming.ts
 		latestStats.inboundLastArrivalInitialized = true
 	} else {
-		arrival := int(incoming.ts.Sub(latestStats.inboundLastArrival).Seconds() * r.clockRate)
+		arrival := int(incoming.ts.Sub(latestStats.inboundLastArrival).Seconds() * clockRate)
 		transit := arrival - int(incoming.header.Timestamp)
 		d := transit - latestStats.inboundLastTransit
 		latestStats.inboundLastTransit = transit
 		if d < 0 {
 			d = -d
 		}
-		latestStats.InboundRTPStreamStats.Jitter += (1.0 / 16.0) * (float64(d) - latestStats.InboundRTPStreamStats.Jitter)
+		// Transit times of payloads with different clock rates can't be compared
+		if !changedClockRate {
+			latestStats.InboundRTPStreamStats.Jitter += (1.0 / 16.0) * (float64(d) - latestStats.InboundRTPStreamStats.Jitter)
+		}
 		latestStats.inboundLastArrival = incoming.ts
 	}
+	latestStats.inboundLastClockRate = clockRate
+	if latestStats.inboundClockDrift == nil || changedClockRate {
+		latestStats.inboundClockDrift = clockdrift.NewEstimator(uint32(clockRate))
+	}
+	latestStats.inboundClockDrift.Update(incoming.ts, incoming.header.Timestamp)
+	latestStats.ClockDrift = latestStats.inboundClockDrift.Drift()
+	if r.audioLevelID != 0 {
+		latestStats.recordAudioLevel(&incoming.header, r.audioLevelID, clockRate, changedClockRate)
+	}
 
 	latestStats.LastPacketReceivedTimestamp = incoming.ts
 	latestStats.HeaderBytesReceived += uint64(incoming.header.MarshalSize())                 //nolint:gosec // G115
@@ -222,6 +293,7 @@ func (r *recorder) recordOutgoingRTP(latestStats internalStats, v *outgoingRTP)
 	latestStats.OutboundRTPStreamStats.PacketsSent++
 	latestStats.OutboundRTPStreamStats.BytesSent += uint64(headerSize + v.payloadLen) //nolint:gosec // G115
 	latestStats.HeaderBytesSent += uint64(headerSize)                                 //nolint:gosec // G115
+	latestStats.outboundLastClockRate = r.clockRateFor(v.header.PayloadType)
 	if !latestStats.remoteInboundFirstSequenceNumberInitialized {
 		latestStats.remoteInboundFirstSequenceNumber = int64(v.header.SequenceNumber)
 		latestStats.remoteInboundFirstSequenceNumberInitialized = true
@@ -241,13 +313,17 @@ func (r *recorder) recordIncomingRR(latestStats internalStats, pkt *rtcp.Receive
 				uint64(latestStats.remoteInboundFirstSequenceNumber) + 1
 		}
 		latestStats.RemoteInboundRTPStreamStats.PacketsLost = int64(report.TotalLost)
-		latestStats.RemoteInboundRTPStreamStats.Jitter = float64(report.Jitter) / r.clockRate
+		clockRate := latestStats.outboundLastClockRate
+		if clockRate == 0 {
+			clockRate = r.clockRate
+		}
+		latestStats.RemoteInboundRTPStreamStats.Jitter = float64(report.Jitter) / clockRate
 
 		if report.Delay != 0 && report.LastSenderReport != 0 {
 			for i := minInt(r.maxLastSenderReports, len(latestStats.lastSenderReports)) - 1; i >= 0; i-- {
 				lastReport := latestStats.lastSenderReports[i]
 				if (lastReport&0x0000FFFFFFFF0000)>>16 == uint64(report.LastSenderReport) {
-					dlsr := time.Duration(float64(report.Delay) / 65536.0 * float64(time.Second))
+					dlsr := ntp.ToDuration(report.Delay)
 					latestStats.RemoteInboundRTPStreamStats.RoundTripTime = (ts.Add(-dlsr)).Sub(ntp.ToTime(lastReport))
 					latestStats.RemoteInboundRTPStreamStats.TotalRoundTripTime += latestStats.RemoteInboundRTPStreamStats.RoundTripTime
 					latestStats.RemoteInboundRTPStreamStats.RoundTripTimeMeasurements++
@@ -270,7 +346,7 @@ func (r *recorder) recordIncomingXR(latestStats internalStats, pkt *rtcp.Extende
 					for i := minInt(r.maxLastReceiverReferenceTimes, len(latestStats.lastReceiverReferenceTimes)) - 1; i >= 0; i-- {
 						lastRR := latestStats.lastReceiverReferenceTimes[i]
 						if (lastRR&0x0000FFFFFFFF0000)>>16 == uint64(xrReport.LastRR) {
-							dlrr := time.Duration(float64(xrReport.DLRR) / 65536.0 * float64(time.Second))
+							dlrr := ntp.ToDuration(xrReport.DLRR)
 							latestStats.RemoteOutboundRTPStreamStats.RoundTripTime = (ts.Add(-dlrr)).Sub(ntp.ToTime(lastRR))
 							//nolint:lll
 							latestStats.RemoteOutboundRTPStreamStats.TotalRoundTripTime += latestStats.RemoteOutboundRTPStreamStats.RoundTripTime
@@ -285,6 +361,72 @@ func (r *recorder) recordIncomingXR(latestStats internalStats, pkt *rtcp.Extende
 	return latestStats
 }
 
+// maxAudioPacketDuration caps the duration attributed to an audio packet, so
+// the gaps of discontinuous transmission don't count as samples.
+const maxAudioPacketDuration = 0.12
+
+// recordAudioLevel records the audio level of a packet, whose duration is
+// taken from the RTP timestamps since the previous one.
+func (s *internalStats) recordAudioLevel(header *rtp.Header, id uint8, clockRate float64, changedClockRate bool) {
+	ext := header.GetExtension(id)
+	if ext == nil {
+		return
+	}
+	var audioLevel rtp.AudioLevelExtension
+	if err := audioLevel.Unmarshal(ext); err != nil {
+		return
+	}
+
+	// The level is in -dBov
+	level := math.Pow(10, -float64(audioLevel.Level)/20)
+	s.AudioLevel = level
+	if audioLevel.Voice {
+		s.VoiceActivityPackets++
+	}
+
+	if s.inboundAudioTimestampInitialized && !changedClockRate && clockRate > 0 {
+		if diff := int32(header.Timestamp - s.inboundLastAudioTimestamp); diff > 0 { //nolint:gosec // G115
+			duration := math.Min(float64(diff)/clockRate, maxAudioPacketDuration)
+			s.TotalAudioEnergy += level * level * duration
+			s.TotalSamplesDuration += duration
+		}
+	}
+	s.inboundAudioTimestampInitialized = true
+	s.inboundLastAudioTimestamp = header.Timestamp
+}
+
+// markReceived records sequenceNumber in the receive window and reports
+// whether it was already received before.
+func (s *internalStats) markReceived(sequenceNumber int64) bool {
+	const windowSize = 64
+
+	if sequenceNumber > s.inboundHighestSequenceNumber {
+		shift := sequenceNumber - s.inboundHighestSequenceNumber
+		if shift >= windowSize {
+			s.inboundReceivedWindow = 0
+		} else {
+			s.inboundReceivedWindow <<= uint64(shift)
+		}
+		s.inboundReceivedWindow |= 1
+		s.inboundHighestSequenceNumber = sequenceNumber
+
+		return false
+	}
+
+	offset := s.inboundHighestSequenceNumber - sequenceNumber
+	if offset >= windowSize {
+		// Too old to tell, assume it is a late packet.
+		return false
+	}
+	bit := uint64(1) << uint64(offset)
+	if s.inboundReceivedWindow&bit != 0 {
+		return true
+	}
+	s.inboundReceivedWindow |= bit
+
+	return false
+}
+
 func contains(ls []uint32, e uint32) bool {
 	for _, x := range ls {
 		if x == e {
@@ -375,6 +517,17 @@ func (r *recorder) QueueIncomingRTCP(ts time.Time, buf []byte, attr interceptor.
 	r.ms.Unlock()
 }
 
+// recordDiscarded counts a received packet which was discarded by a later
+// stage, e.g. a jitter buffer.
+func (r *recorder) recordDiscarded() {
+	if atomic.LoadUint32(&r.running) == 0 {
+		return
+	}
+	r.ms.Lock()
+	r.latestStats.PacketsDiscarded++
+	r.ms.Unlock()
+}
+
 func (r *recorder) QueueOutgoingRTP(ts time.Time, header *rtp.Header, payload []byte, attr interceptor.Attributes) {
 	if atomic.LoadUint32(&r.running) == 0 {
 		return
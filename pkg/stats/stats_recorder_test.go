@@ -11,7 +11,7 @@ import (
 	"time"
 
 	"github.com/pion/interceptor"
-	"github.com/pion/interceptor/internal/ntp"
+	"github.com/pion/interceptor/pkg/ntp"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/assert"
@@ -102,6 +102,89 @@ func TestStatsRecorder(t *testing.T) {
 				BytesReceived:               36,
 			},
 		},
+		{
+			name: "retransmittedIncomingRTP",
+			records: []record{
+				{
+					ts: now,
+					content: incomingRTP{
+						header: rtp.Header{SequenceNumber: 7},
+					},
+				},
+				{
+					ts: now,
+					content: incomingRTP{
+						header: rtp.Header{SequenceNumber: 9},
+					},
+				},
+				{
+					// The RTX packet filling the gap is a retransmission as well
+					ts: now,
+					content: incomingRTP{
+						header: rtp.Header{SequenceNumber: 8},
+						attr:   interceptor.Attributes{RetransmissionAttributesKey: true},
+					},
+				},
+				{
+					ts: now,
+					content: incomingRTP{
+						header: rtp.Header{SequenceNumber: 9},
+					},
+				},
+				{
+					ts: now,
+					content: incomingRTP{
+						header: rtp.Header{SequenceNumber: 10},
+					},
+				},
+			},
+			expectedInboundRTPStreamStats: InboundRTPStreamStats{
+				ReceivedRTPStreamStats: ReceivedRTPStreamStats{
+					PacketsReceived: 5,
+					PacketsLost:     -1,
+				},
+				LastPacketReceivedTimestamp:  now,
+				HeaderBytesReceived:          60,
+				BytesReceived:                60,
+				RetransmittedPacketsReceived: 2,
+				RetransmittedBytesReceived:   24,
+			},
+		},
+		{
+			name: "invalidIncomingRTP",
+			records: []record{
+				{
+					ts: now,
+					content: incomingRTP{
+						header: rtp.Header{SequenceNumber: 1},
+					},
+				},
+				{
+					ts: now,
+					content: incomingRTP{
+						header: rtp.Header{SequenceNumber: 2},
+						attr:   invalidAttributes(),
+					},
+				},
+				{
+					ts: now,
+					content: incomingRTP{
+						header: rtp.Header{SequenceNumber: 3},
+					},
+				},
+			},
+			// The invalid packet is not lost, but not part of the byte counts
+			expectedInboundRTPStreamStats: InboundRTPStreamStats{
+				ReceivedRTPStreamStats: ReceivedRTPStreamStats{
+					PacketsReceived: 3,
+					PacketsLost:     0,
+				},
+				LastPacketReceivedTimestamp: now,
+				HeaderBytesReceived:         24,
+				BytesReceived:               24,
+				PacketsInvalid:              1,
+			},
+		},
 		{
 			name: "basicOutgoingRTP",
 			records: []record{
@@ -825,6 +908,90 @@ func TestStatsRecorder(t *testing.T) {
 	}
 }
 
+func TestStatsRecorder_PayloadClockRates(t *testing.T) {
+	recorder := newRecorder(0, 8000)
+	recorder.setStreamInfo(&interceptor.StreamInfo{
+		ClockRate:             8000,
+		PayloadTypeClockRates: map[uint8]uint32{111: 48000},
+	})
+	recorder.Start()
+
+	now := time.Date(2022, time.July, 18, 0, 0, 0, 0, time.Local)
+	for i, pkt := range []struct {
+		offset      time.Duration
+		timestamp   uint32
+		payloadType uint8
+	}{
+		{0, 0, 0},
+		{20 * time.Millisecond, 160, 0},
+		// The timestamps of the 48kHz payload are not comparable to the
+		// previous ones and must not be accounted as jitter.
+		{40 * time.Millisecond, 50000, 111},
+	} {
+		recorder.QueueIncomingRTP(now.Add(pkt.offset), mustMarshalRTP(t, rtp.Packet{Header: rtp.Header{
+			SequenceNumber: uint16(i), //nolint:gosec // G115
+			Timestamp:      pkt.timestamp,
+			PayloadType:    pkt.payloadType,
+		}}), nil)
+	}
+	assert.Equal(t, 0.0, recorder.GetStats().InboundRTPStreamStats.Jitter)
+
+	recorder.QueueOutgoingRTP(now, &rtp.Header{PayloadType: 111}, nil, nil)
+	recorder.QueueIncomingRTCP(now, mustMarshalRTCPs(t, &rtcp.ReceiverReport{
+		Reports: []rtcp.ReceptionReport{{SSRC: 0, Jitter: 4800}},
+	}), nil)
+	assert.Equal(t, 0.1, recorder.GetStats().RemoteInboundRTPStreamStats.Jitter)
+}
+
+func TestStatsRecorder_ClockDrift(t *testing.T) {
+	recorder := newRecorder(0, 90_000)
+	recorder.Start()
+
+	// The sender's clock runs 100ppm fast, 30 packets per second for a minute
+	now := time.Date(2022, time.July, 18, 0, 0, 0, 0, time.Local)
+	for i := 0; i < 1800; i++ {
+		arrival := time.Duration(float64(i) * float64(time.Second) / 30 / (1 + 100e-6))
+		recorder.QueueIncomingRTP(now.Add(arrival), mustMarshalRTP(t, rtp.Packet{Header: rtp.Header{
+			SequenceNumber: uint16(i),        //nolint:gosec // G115
+			Timestamp:      uint32(i * 3000), //nolint:gosec // G115
+		}}), nil)
+	}
+	assert.InDelta(t, 100, recorder.GetStats().InboundRTPStreamStats.ClockDrift, 1)
+}
+
+func TestStatsRecorder_AudioLevel(t *testing.T) {
+	recorder := newRecorder(0, 48000)
+	recorder.setAudio(2, 1)
+	recorder.Start()
+
+	now := time.Date(2022, time.July, 18, 0, 0, 0, 0, time.Local)
+	timestamp := uint32(0)
+	for i := 0; i < 21; i++ {
+		// Voice at 0 dBov and silence at -20 dBov, after a gap of one second
+		level := rtp.AudioLevelExtension{Level: 0, Voice: true}
+		if i > 10 {
+			level = rtp.AudioLevelExtension{Level: 20, Voice: false}
+		}
+		if i == 11 {
+			timestamp += 48000
+		}
+		ext, err := level.Marshal()
+		assert.NoError(t, err)
+		header := rtp.Header{SequenceNumber: uint16(i), Timestamp: timestamp} //nolint:gosec // G115
+		assert.NoError(t, header.SetExtension(1, ext))
+		recorder.QueueIncomingRTP(now, mustMarshalRTP(t, rtp.Packet{Header: header}), nil)
+		timestamp += 960
+	}
+
+	stats := recorder.GetStats().InboundRTPStreamStats
+	assert.Equal(t, uint16(2), stats.Channels)
+	assert.InDelta(t, 0.1, stats.AudioLevel, 1e-9)
+	assert.Equal(t, uint64(11), stats.VoiceActivityPackets)
+	// The packet after the gap counts for at most 120ms
+	assert.InDelta(t, 10*0.02+0.12+9*0.02, stats.TotalSamplesDuration, 1e-9)
+	assert.InDelta(t, 10*0.02+(0.12+9*0.02)*0.01, stats.TotalAudioEnergy, 1e-9)
+}
+
 func TestStatsRecorder_DLRR_Precision(t *testing.T) {
 	recorder := newRecorder(0, 90_000)
 
@@ -937,3 +1104,10 @@ func TestContains(t *testing.T) {
 		})
 	}
 }
+
+func invalidAttributes() interceptor.Attributes {
+	attr := interceptor.Attributes{}
+	attr.MarkInvalid()
+
+	return attr
+}
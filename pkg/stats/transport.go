@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// transportRateWindow is the window over which the bitrates of the transport
+// are measured.
+const transportRateWindow = time.Second
+
+// TransportStats contains stats of all streams sent and received through an
+// interceptor, similar to the transport stats of webrtc-stats. Bytes include
+// the RTP headers.
+type TransportStats struct {
+	PacketsSent     uint64
+	PacketsReceived uint64
+	BytesSent       uint64
+	BytesReceived   uint64
+
+	RTCPPacketsSent     uint64
+	RTCPPacketsReceived uint64
+	RTCPBytesSent       uint64
+	RTCPBytesReceived   uint64
+
+	// SendBitrate and ReceiveBitrate are the bitrates of RTP and RTCP in bits
+	// per second during the last second.
+	SendBitrate    float64
+	ReceiveBitrate float64
+
+	// AvailableOutgoingBitrate is the current bandwidth estimate in bits per
+	// second, if one was set with SetBandwidthEstimate.
+	AvailableOutgoingBitrate int
+	// AvailableIncomingBitrate is the current estimate of the bandwidth
+	// available for the received streams in bits per second, if one was set
+	// with SetIncomingBandwidthEstimate.
+	AvailableIncomingBitrate int
+
+	// SentPacketSizes is the distribution of the sizes of sent RTP packets.
+	SentPacketSizes PacketSizeHistogram
+}
+
+// String returns a string representation of TransportStats.
+func (s TransportStats) String() string {
+	out := "TransportStats:\n"
+	out += fmt.Sprintf("\tPacketsSent: %v\n", s.PacketsSent)
+	out += fmt.Sprintf("\tPacketsReceived: %v\n", s.PacketsReceived)
+	out += fmt.Sprintf("\tBytesSent: %v\n", s.BytesSent)
+	out += fmt.Sprintf("\tBytesReceived: %v\n", s.BytesReceived)
+	out += fmt.Sprintf("\tRTCPPacketsSent: %v\n", s.RTCPPacketsSent)
+	out += fmt.Sprintf("\tRTCPPacketsReceived: %v\n", s.RTCPPacketsReceived)
+	out += fmt.Sprintf("\tRTCPBytesSent: %v\n", s.RTCPBytesSent)
+	out += fmt.Sprintf("\tRTCPBytesReceived: %v\n", s.RTCPBytesReceived)
+	out += fmt.Sprintf("\tSendBitrate: %v\n", s.SendBitrate)
+	out += fmt.Sprintf("\tReceiveBitrate: %v\n", s.ReceiveBitrate)
+	out += fmt.Sprintf("\tAvailableOutgoingBitrate: %v\n", s.AvailableOutgoingBitrate)
+	out += fmt.Sprintf("\tAvailableIncomingBitrate: %v\n", s.AvailableIncomingBitrate)
+	out += fmt.Sprintf("\tSentPacketSizes: %v\n", s.SentPacketSizes)
+
+	return out
+}
+
+// rateMeter measures a bitrate over consecutive windows.
+type rateMeter struct {
+	start time.Time
+	bytes uint64
+	rate  float64
+}
+
+func (m *rateMeter) add(now time.Time, bytes int) {
+	m.roll(now)
+	m.bytes += uint64(bytes) //nolint:gosec // G115
+}
+
+// bitrate returns the bitrate of the previous window.
+func (m *rateMeter) bitrate(now time.Time) float64 {
+	m.roll(now)
+
+	return m.rate
+}
+
+func (m *rateMeter) roll(now time.Time) {
+	elapsed := now.Sub(m.start)
+	switch {
+	case m.start.IsZero():
+		m.start = now
+	case elapsed >= 2*transportRateWindow:
+		// Nothing was sent in the previous window
+		m.start, m.bytes, m.rate = now, 0, 0
+	case elapsed >= transportRateWindow:
+		m.rate = float64(m.bytes*8) / elapsed.Seconds()
+		m.start, m.bytes = now, 0
+	}
+}
+
+// transportRecorder records the TransportStats of an interceptor.
+type transportRecorder struct {
+	m             sync.Mutex
+	stats         TransportStats
+	sendRate      rateMeter
+	receiveRate   rateMeter
+	bandwidthFunc func() int
+	// incomingBandwidthFunc returns the receive side estimate.
+	incomingBandwidthFunc func() int
+}
+
+func (t *transportRecorder) recordSent(now time.Time, bytes int, rtcpPackets int) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if rtcpPackets > 0 {
+		t.stats.RTCPPacketsSent += uint64(rtcpPackets) //nolint:gosec // G115
+		t.stats.RTCPBytesSent += uint64(bytes)         //nolint:gosec // G115
+	} else {
+		t.stats.PacketsSent++
+		t.stats.BytesSent += uint64(bytes) //nolint:gosec // G115
+	}
+	t.sendRate.add(now, bytes)
+}
+
+func (t *transportRecorder) recordSentRTP(now time.Time, headerSize, payloadSize int) {
+	t.m.Lock()
+	t.stats.SentPacketSizes.add(headerSize, payloadSize)
+	t.m.Unlock()
+
+	t.recordSent(now, headerSize+payloadSize, 0)
+}
+
+func (t *transportRecorder) maxPayloadSize(mtu int) int {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	return t.stats.SentPacketSizes.MaxPayloadSize(mtu)
+}
+
+func (t *transportRecorder) recordReceived(now time.Time, bytes int, rtcpPackets int) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if rtcpPackets > 0 {
+		t.stats.RTCPPacketsReceived += uint64(rtcpPackets) //nolint:gosec // G115
+		t.stats.RTCPBytesReceived += uint64(bytes)         //nolint:gosec // G115
+	} else {
+		t.stats.PacketsReceived++
+		t.stats.BytesReceived += uint64(bytes) //nolint:gosec // G115
+	}
+	t.receiveRate.add(now, bytes)
+}
+
+func (t *transportRecorder) get(now time.Time) TransportStats {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	stats := t.stats
+	stats.SendBitrate = t.sendRate.bitrate(now)
+	stats.ReceiveBitrate = t.receiveRate.bitrate(now)
+	if t.bandwidthFunc != nil {
+		stats.AvailableOutgoingBitrate = t.bandwidthFunc()
+	}
+	if t.incomingBandwidthFunc != nil {
+		stats.AvailableIncomingBitrate = t.incomingBandwidthFunc()
+	}
+
+	return stats
+}
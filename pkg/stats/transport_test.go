@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateMeter(t *testing.T) {
+	now := time.Now()
+	meter := &rateMeter{}
+	meter.add(now, 1000)
+	meter.add(now.Add(500*time.Millisecond), 1500)
+	assert.Equal(t, 0.0, meter.bitrate(now.Add(900*time.Millisecond)))
+	assert.Equal(t, 20000.0, meter.bitrate(now.Add(time.Second)))
+	assert.Equal(t, 20000.0, meter.bitrate(now.Add(1900*time.Millisecond)))
+	assert.Equal(t, 0.0, meter.bitrate(now.Add(3*time.Second)))
+}
+
+func TestInterceptor_GetTransport(t *testing.T) {
+	clock := &test.MockTime{}
+	start := time.Now()
+	clock.SetNow(start)
+	f, err := NewInterceptor(
+		SetNowFunc(clock.Now),
+		SetBandwidthEstimate(func() int {
+			return 1_000_000
+		}),
+		SetIncomingBandwidthEstimate(func() int {
+			return 2_000_000
+		}),
+	)
+	require.NoError(t, err)
+
+	var getter Getter
+	f.OnNewPeerConnection(func(_ string, g Getter) {
+		getter = g
+	})
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	streams := []*test.MockStream{
+		test.NewMockStream(&interceptor.StreamInfo{SSRC: 1}, i),
+		test.NewMockStream(&interceptor.StreamInfo{SSRC: 2}, i),
+	}
+	for _, stream := range streams {
+		defer func(stream *test.MockStream) {
+			assert.NoError(t, stream.Close())
+		}(stream)
+	}
+
+	for ssrc, stream := range streams {
+		pkt := &rtp.Packet{Header: rtp.Header{SSRC: uint32(ssrc + 1)}, Payload: make([]byte, 88)} //nolint:gosec // G115
+		require.NoError(t, stream.WriteRTP(pkt))
+		stream.ReceiveRTP(pkt)
+		<-stream.ReadRTP()
+	}
+	require.NoError(t, streams[0].WriteRTCP([]rtcp.Packet{
+		&rtcp.PictureLossIndication{MediaSSRC: 1},
+		&rtcp.PictureLossIndication{MediaSSRC: 2},
+	}))
+	streams[0].ReceiveRTCP([]rtcp.Packet{&rtcp.ReceiverReport{SSRC: 3}})
+	<-streams[0].ReadRTCP()
+
+	clock.SetNow(start.Add(time.Second))
+	transport := getter.GetTransport()
+	assert.Equal(t, uint64(2), transport.PacketsSent)
+	assert.Equal(t, uint64(2), transport.PacketsReceived)
+	assert.Equal(t, uint64(200), transport.BytesSent)
+	assert.Equal(t, uint64(200), transport.BytesReceived)
+	assert.Equal(t, uint64(2), transport.RTCPPacketsSent)
+	assert.Equal(t, uint64(24), transport.RTCPBytesSent)
+	assert.Equal(t, uint64(1), transport.RTCPPacketsReceived)
+	assert.Equal(t, uint64(8), transport.RTCPBytesReceived)
+	assert.Equal(t, 224.0*8, transport.SendBitrate)
+	assert.Equal(t, 208.0*8, transport.ReceiveBitrate)
+	assert.Equal(t, 1_000_000, transport.AvailableOutgoingBitrate)
+	assert.Equal(t, 2_000_000, transport.AvailableIncomingBitrate)
+	assert.Equal(t, uint64(2), transport.SentPacketSizes.Buckets[1])
+	assert.Equal(t, 100, transport.SentPacketSizes.MaxPacketSize)
+	assert.Equal(t, 12, transport.SentPacketSizes.MaxHeaderSize)
+
+	// A header extension added to the packets reduces the recommended payload size
+	statsInterceptor, ok := i.(*Interceptor)
+	require.True(t, ok)
+	assert.Equal(t, 1188, statsInterceptor.RecommendedMaxPayloadSize(1200))
+	pkt := &rtp.Packet{Header: rtp.Header{SSRC: 1}, Payload: make([]byte, 88)}
+	require.NoError(t, pkt.SetExtension(1, []byte{0x00, 0x01}))
+	require.NoError(t, streams[0].WriteRTP(pkt))
+	assert.Equal(t, 1180, statsInterceptor.RecommendedMaxPayloadSize(1200))
+}
@@ -0,0 +1,280 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package timeseries provides an interceptor sampling per stream metrics at a
+// fixed cadence, e.g. to analyze long running soak tests offline.
+package timeseries
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+var errInvalidSampleInterval = errors.New("sample interval must be positive")
+
+// InterceptorFactory is a interceptor.Factory for a timeseries Interceptor.
+type InterceptorFactory struct {
+	opts []Option
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{
+		opts: opts,
+	}, nil
+}
+
+// NewInterceptor constructs a new Interceptor.
+func (f *InterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	i := &Interceptor{
+		NoOp:     interceptor.NoOp{},
+		id:       id,
+		log:      logging.NewDefaultLoggerFactory().NewLogger("timeseries"),
+		interval: time.Second,
+		writer:   nil,
+		now:      time.Now,
+		streams:  map[streamKey]*stream{},
+		close:    make(chan struct{}),
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	if i.writer == nil {
+		writer, err := NewCSVWriter(os.Stdout)
+		if err != nil {
+			return nil, err
+		}
+		i.writer = writer
+	}
+
+	i.wg.Add(1)
+	go i.loop()
+
+	return i, nil
+}
+
+// Interceptor samples the packet and byte counts, bitrate and loss of every
+// stream and writes them to a RecordWriter.
+type Interceptor struct {
+	interceptor.NoOp
+	id       string
+	log      logging.LeveledLogger
+	interval time.Duration
+	writer   RecordWriter
+	now      func() time.Time
+
+	m       sync.Mutex
+	streams map[streamKey]*stream
+
+	wg    sync.WaitGroup
+	close chan struct{}
+}
+
+type streamKey struct {
+	direction Direction
+	ssrc      uint32
+}
+
+type stream struct {
+	labels  interceptor.Labels
+	packets uint64
+	bytes   uint64
+
+	started   bool
+	baseSeq   uint64
+	maxSeq    uint64
+	lastTime  time.Time
+	lastBytes uint64
+}
+
+func (s *stream) add(header *rtp.Header, size int) {
+	s.packets++
+	s.bytes += uint64(size) //nolint:gosec // G115
+
+	if !s.started {
+		s.started = true
+		s.baseSeq = uint64(header.SequenceNumber)
+		s.maxSeq = s.baseSeq
+
+		return
+	}
+	if diff := int16(header.SequenceNumber - uint16(s.maxSeq)); diff > 0 { //nolint:gosec // G115
+		s.maxSeq += uint64(diff)
+	}
+}
+
+func (s *stream) sample(now time.Time, key streamKey, id string) Record {
+	record := Record{
+		Time:      now,
+		ID:        id,
+		Direction: key.direction,
+		SSRC:      key.ssrc,
+		Packets:   s.packets,
+		Bytes:     s.bytes,
+		Labels:    s.labels,
+	}
+	if elapsed := now.Sub(s.lastTime).Seconds(); elapsed > 0 {
+		record.Bitrate = float64(s.bytes-s.lastBytes) * 8 / elapsed
+	}
+	if key.direction == DirectionInbound && s.started {
+		record.Lost = int64(s.maxSeq-s.baseSeq+1) - int64(s.packets) //nolint:gosec // G115
+	}
+	s.lastTime = now
+	s.lastBytes = s.bytes
+
+	return record
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
+// will be called once per rtp packet.
+func (i *Interceptor) BindLocalStream(
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	stream := i.addStream(streamKey{direction: DirectionOutbound, ssrc: info.SSRC}, info.Attributes.GetLabels())
+
+	return interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			i.m.Lock()
+			stream.add(header, header.MarshalSize()+len(payload))
+			i.m.Unlock()
+
+			return writer.Write(header, payload, attributes)
+		},
+	)
+}
+
+// UnbindLocalStream writes a last sample of the stream.
+func (i *Interceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	i.removeStream(streamKey{direction: DirectionOutbound, ssrc: info.SSRC})
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream. The returned method
+// will be called once per rtp packet.
+func (i *Interceptor) BindRemoteStream(
+	info *interceptor.StreamInfo, reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	stream := i.addStream(streamKey{direction: DirectionInbound, ssrc: info.SSRC}, info.Attributes.GetLabels())
+
+	return interceptor.RTPReaderFunc(
+		func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			n, attr, err := reader.Read(b, a)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			if attr == nil {
+				attr = make(interceptor.Attributes)
+			}
+			header, err := attr.GetRTPHeader(b[:n])
+			if err != nil {
+				return 0, nil, err
+			}
+
+			i.m.Lock()
+			stream.add(header, n)
+			i.m.Unlock()
+
+			return n, attr, nil
+		},
+	)
+}
+
+// UnbindRemoteStream writes a last sample of the stream.
+func (i *Interceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	i.removeStream(streamKey{direction: DirectionInbound, ssrc: info.SSRC})
+}
+
+// Close stops sampling. The RecordWriter is not closed, since it may be
+// shared with other interceptors.
+func (i *Interceptor) Close() error {
+	defer i.wg.Wait()
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if !i.isClosed() {
+		close(i.close)
+	}
+
+	return nil
+}
+
+func (i *Interceptor) isClosed() bool {
+	select {
+	case <-i.close:
+		return true
+	default:
+		return false
+	}
+}
+
+func (i *Interceptor) addStream(key streamKey, labels interceptor.Labels) *stream {
+	now := i.now()
+	s := &stream{labels: labels, lastTime: now}
+
+	i.m.Lock()
+	i.streams[key] = s
+	i.m.Unlock()
+
+	return s
+}
+
+func (i *Interceptor) removeStream(key streamKey) {
+	i.m.Lock()
+	s, ok := i.streams[key]
+	if !ok {
+		i.m.Unlock()
+
+		return
+	}
+	delete(i.streams, key)
+	record := s.sample(i.now(), key, i.id)
+	i.m.Unlock()
+
+	i.writeRecords([]Record{record})
+}
+
+func (i *Interceptor) loop() {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			i.sample()
+		case <-i.close:
+			return
+		}
+	}
+}
+
+func (i *Interceptor) sample() {
+	i.m.Lock()
+	now := i.now()
+	records := make([]Record, 0, len(i.streams))
+	for key, s := range i.streams {
+		records = append(records, s.sample(now, key, i.id))
+	}
+	i.m.Unlock()
+
+	i.writeRecords(records)
+}
+
+func (i *Interceptor) writeRecords(records []Record) {
+	for _, record := range records {
+		if err := i.writer.WriteRecord(record); err != nil {
+			i.log.Warnf("failed writing sample: %+v", err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package timeseries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordChan chan Record
+
+func (c recordChan) WriteRecord(r Record) error {
+	c <- r
+
+	return nil
+}
+
+func (c recordChan) Close() error {
+	return nil
+}
+
+func TestInterceptor(t *testing.T) {
+	records := make(recordChan, 10)
+	factory, err := NewInterceptor(SetRecordWriter(records), SampleInterval(20*time.Millisecond))
+	require.NoError(t, err)
+
+	i, err := factory.NewInterceptor("pc")
+	require.NoError(t, err)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{SSRC: 123}, i)
+
+	for _, seq := range []uint16{65534, 65535, 1} {
+		stream.ReceiveRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123, SequenceNumber: seq}, Payload: make([]byte, 88)})
+		select {
+		case r := <-stream.ReadRTP():
+			assert.NoError(t, r.Err)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "receiver rtp packet not found")
+		}
+	}
+	assert.NoError(t, stream.WriteRTP(&rtp.Packet{Header: rtp.Header{SSRC: 123}, Payload: make([]byte, 88)}))
+
+	seen := map[Direction]Record{}
+	deadline := time.After(time.Second)
+	for len(seen) < 2 {
+		select {
+		case record := <-records:
+			if record.Packets > 0 {
+				seen[record.Direction] = record
+			}
+		case <-deadline:
+			assert.FailNow(t, "no samples written")
+		}
+	}
+
+	inbound := seen[DirectionInbound]
+	assert.Equal(t, "pc", inbound.ID)
+	assert.Equal(t, uint32(123), inbound.SSRC)
+	assert.Equal(t, uint64(3), inbound.Packets)
+	assert.Equal(t, uint64(300), inbound.Bytes)
+	assert.Equal(t, int64(1), inbound.Lost)
+
+	outbound := seen[DirectionOutbound]
+	assert.Equal(t, uint64(1), outbound.Packets)
+	assert.Equal(t, uint64(100), outbound.Bytes)
+	assert.Zero(t, outbound.Lost)
+
+	assert.NoError(t, stream.Close())
+}
+
+func TestInterceptor_InvalidInterval(t *testing.T) {
+	factory, err := NewInterceptor(SampleInterval(0))
+	require.NoError(t, err)
+
+	_, err = factory.NewInterceptor("")
+	assert.ErrorIs(t, err, errInvalidSampleInterval)
+}
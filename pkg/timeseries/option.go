@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package timeseries
+
+import (
+	"time"
+
+	"github.com/pion/logging"
+)
+
+// Option can be used to configure the Interceptor.
+type Option func(*Interceptor) error
+
+// Log sets a logger for the interceptor.
+func Log(log logging.LeveledLogger) Option {
+	return func(i *Interceptor) error {
+		i.log = log
+
+		return nil
+	}
+}
+
+// SampleInterval sets how often the metrics of every stream are sampled.
+func SampleInterval(interval time.Duration) Option {
+	return func(i *Interceptor) error {
+		if interval <= 0 {
+			return errInvalidSampleInterval
+		}
+		i.interval = interval
+
+		return nil
+	}
+}
+
+// SetRecordWriter sets the RecordWriter samples are written to. The same
+// RecordWriter can be shared by the interceptors of many PeerConnections.
+func SetRecordWriter(w RecordWriter) Option {
+	return func(i *Interceptor) error {
+		i.writer = w
+
+		return nil
+	}
+}
+
+// SetNowFunc sets the function used to timestamp samples.
+func SetNowFunc(now func() time.Time) Option {
+	return func(i *Interceptor) error {
+		i.now = now
+
+		return nil
+	}
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package timeseries
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+)
+
+var errInvalidMaxFiles = errors.New("max files must be at least 1")
+
+// Direction is the direction of a sampled stream.
+type Direction string
+
+const (
+	// DirectionOutbound is a stream sent to the remote peer.
+	DirectionOutbound Direction = "outbound"
+	// DirectionInbound is a stream received from the remote peer.
+	DirectionInbound Direction = "inbound"
+)
+
+// Record is a single sample of the metrics of a stream.
+type Record struct {
+	Time      time.Time
+	ID        string
+	Direction Direction
+	SSRC      uint32
+
+	// Packets and Bytes are the totals since the stream was bound.
+	Packets uint64
+	Bytes   uint64
+	// Bitrate is the bitrate in bits per second since the previous sample.
+	Bitrate float64
+	// Lost is the number of packets lost, derived from the sequence numbers.
+	// It's only set for inbound streams and can be negative if packets were
+	// duplicated.
+	Lost int64
+	// Labels are the labels of the stream, see interceptor.Labels.
+	Labels interceptor.Labels
+}
+
+// RecordWriter writes samples.
+type RecordWriter interface {
+	WriteRecord(Record) error
+	Close() error
+}
+
+func csvHeader() []string {
+	return []string{"time", "id", "direction", "ssrc", "packets", "bytes", "bitrate", "lost", "labels"}
+}
+
+// CSVWriter is a RecordWriter writing one CSV line per Record.
+type CSVWriter struct {
+	m sync.Mutex
+
+	out    io.Writer
+	closer io.Closer
+
+	// The rotation is only used by NewRotatingCSVWriter.
+	path        string
+	maxFileSize int64
+	maxFiles    int
+	size        int64
+}
+
+// NewCSVWriter returns a CSVWriter writing to w, starting with a header line.
+func NewCSVWriter(w io.Writer) (*CSVWriter, error) {
+	writer := &CSVWriter{
+		out: w,
+	}
+	if err := writer.write(csvHeader()); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// NewRotatingCSVWriter returns a CSVWriter writing to the file at path. Once
+// the file grows beyond maxFileSize bytes, it is renamed to path.1, older
+// files are shifted to path.2 and so on, and a new file is started. At most
+// maxFiles files are kept, the oldest one is removed. A maxFileSize <= 0
+// disables rotation.
+func NewRotatingCSVWriter(path string, maxFileSize int64, maxFiles int) (*CSVWriter, error) {
+	if maxFiles < 1 {
+		return nil, errInvalidMaxFiles
+	}
+
+	writer := &CSVWriter{
+		path:        path,
+		maxFileSize: maxFileSize,
+		maxFiles:    maxFiles,
+	}
+	if err := writer.open(); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// WriteRecord writes a Record as a CSV line.
+func (w *CSVWriter) WriteRecord(r Record) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.path != "" && w.maxFileSize > 0 && w.size >= w.maxFileSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	return w.write([]string{
+		r.Time.UTC().Format(time.RFC3339Nano),
+		r.ID,
+		string(r.Direction),
+		strconv.FormatUint(uint64(r.SSRC), 10),
+		strconv.FormatUint(r.Packets, 10),
+		strconv.FormatUint(r.Bytes, 10),
+		strconv.FormatFloat(r.Bitrate, 'f', 0, 64),
+		strconv.FormatInt(r.Lost, 10),
+		r.Labels.String(),
+	})
+}
+
+// Close closes the underlying file, if the CSVWriter opened one.
+func (w *CSVWriter) Close() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.closer == nil {
+		return nil
+	}
+	err := w.closer.Close()
+	w.closer = nil
+
+	return err
+}
+
+func (w *CSVWriter) write(record []string) error {
+	if w.out == nil {
+		return os.ErrClosed
+	}
+
+	counter := &countingWriter{w: w.out}
+	csvWriter := csv.NewWriter(counter)
+	if err := csvWriter.Write(record); err != nil {
+		return err
+	}
+	csvWriter.Flush()
+	w.size += counter.n
+
+	return csvWriter.Error()
+}
+
+func (w *CSVWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w.out = file
+	w.closer = file
+	w.size = 0
+
+	return w.write(csvHeader())
+}
+
+func (w *CSVWriter) rotate() error {
+	if err := w.closer.Close(); err != nil {
+		return err
+	}
+	w.out, w.closer = nil, nil
+
+	if err := os.Remove(w.rotatedPath(w.maxFiles - 1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := w.maxFiles - 2; i >= 0; i-- {
+		if err := os.Rename(w.rotatedPath(i), w.rotatedPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return w.open()
+}
+
+// rotatedPath returns the path of the file rotated n times, the current file
+// for n == 0.
+func (w *CSVWriter) rotatedPath(n int) string {
+	if n == 0 {
+		return w.path
+	}
+
+	return fmt.Sprintf("%s.%d", w.path, n)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package timeseries
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVWriter(t *testing.T) {
+	buf := bytes.Buffer{}
+	writer, err := NewCSVWriter(&buf)
+	require.NoError(t, err)
+
+	assert.NoError(t, writer.WriteRecord(Record{
+		Time:      time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		ID:        "pc",
+		Direction: DirectionInbound,
+		SSRC:      123,
+		Packets:   10,
+		Bytes:     1000,
+		Bitrate:   8000,
+		Lost:      2,
+		Labels:    interceptor.Labels{"user": "bob", "conference": "42"},
+	}))
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t,
+		"time,id,direction,ssrc,packets,bytes,bitrate,lost,labels\n"+
+			"2023-01-02T03:04:05Z,pc,inbound,123,10,1000,8000,2,conference=42 user=bob\n",
+		buf.String(),
+	)
+}
+
+func TestRotatingCSVWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.csv")
+	writer, err := NewRotatingCSVWriter(path, 100, 3)
+	require.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, writer.WriteRecord(Record{ID: "pc", SSRC: uint32(i)}))
+	}
+	assert.NoError(t, writer.Close())
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		content, err := os.ReadFile(name) //nolint:gosec // test file
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), "time,id,"), name)
+		// A file is rotated as soon as it exceeds the size cap.
+		assert.Less(t, len(content), 200, name)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = NewRotatingCSVWriter(path, 100, 0)
+	assert.ErrorIs(t, err, errInvalidMaxFiles)
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package twcc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/cc"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// PacketResult is the feedback for a single sent packet, parsed from a
+// TransportLayerCC packet.
+type PacketResult struct {
+	// SequenceNumber is the transport wide sequence number of the packet.
+	SequenceNumber uint16
+	// Size is the size of the packet including the RTP header.
+	Size      int
+	Departure time.Time
+	// Arrival is the zero time if the packet was reported as lost. It is
+	// relative to the reference time of the remote and can only be compared
+	// to the arrival times of other packets.
+	Arrival time.Time
+}
+
+// Lost returns true if the packet was reported as not received.
+func (r PacketResult) Lost() bool {
+	return r.Arrival.IsZero()
+}
+
+// FeedbackCallback receives the results of all packets reported in a single
+// TransportLayerCC packet.
+type FeedbackCallback func(feedback *rtcp.TransportLayerCC, results []PacketResult)
+
+// FeedbackOption can be used to configure a FeedbackInterceptor.
+type FeedbackOption func(*FeedbackInterceptor) error
+
+// FeedbackLog sets a logger for the interceptor.
+func FeedbackLog(log logging.LeveledLogger) FeedbackOption {
+	return func(f *FeedbackInterceptor) error {
+		f.log = log
+
+		return nil
+	}
+}
+
+// FeedbackNow sets an alternative for the time.Now function.
+func FeedbackNow(now func() time.Time) FeedbackOption {
+	return func(f *FeedbackInterceptor) error {
+		f.now = now
+
+		return nil
+	}
+}
+
+// NewPeerConnectionCallback receives the FeedbackInterceptor created for a
+// new PeerConnection.
+type NewPeerConnectionCallback func(id string, feedback *FeedbackInterceptor)
+
+// FeedbackInterceptorFactory is a interceptor.Factory for a FeedbackInterceptor.
+type FeedbackInterceptorFactory struct {
+	opts              []FeedbackOption
+	addPeerConnection NewPeerConnectionCallback
+}
+
+// NewFeedbackInterceptor returns a new FeedbackInterceptorFactory configured
+// with the given options.
+func NewFeedbackInterceptor(opts ...FeedbackOption) (*FeedbackInterceptorFactory, error) {
+	return &FeedbackInterceptorFactory{
+		opts:              opts,
+		addPeerConnection: nil,
+	}, nil
+}
+
+// OnNewPeerConnection sets the callback that is called when a new
+// FeedbackInterceptor is created.
+func (f *FeedbackInterceptorFactory) OnNewPeerConnection(cb NewPeerConnectionCallback) {
+	f.addPeerConnection = cb
+}
+
+// NewInterceptor constructs a new FeedbackInterceptor.
+func (f *FeedbackInterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	feedbackInterceptor := &FeedbackInterceptor{
+		NoOp:        interceptor.NoOp{},
+		log:         logging.NewDefaultLoggerFactory().NewLogger("twcc_feedback_interceptor"),
+		now:         time.Now,
+		adapter:     cc.NewFeedbackAdapter(),
+		subscribers: nil,
+	}
+
+	for _, opt := range f.opts {
+		if err := opt(feedbackInterceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.addPeerConnection != nil {
+		f.addPeerConnection(id, feedbackInterceptor)
+	}
+
+	return feedbackInterceptor, nil
+}
+
+// FeedbackInterceptor is the sender side counterpart of the SenderInterceptor.
+// It records when packets carrying a transport wide sequence number are sent,
+// parses incoming TWCC feedback and delivers the results to its subscribers.
+//
+// The transport wide sequence numbers must already be set when a packet
+// passes the interceptor, i.e. it has to be added to the registry before the
+// HeaderExtensionInterceptor.
+type FeedbackInterceptor struct {
+	interceptor.NoOp
+	log     logging.LeveledLogger
+	now     func() time.Time
+	adapter *cc.FeedbackAdapter
+
+	lock        sync.Mutex
+	subscribers []*feedbackSubscriber
+}
+
+// feedbackSubscriber identifies a subscription, callbacks can't be compared.
+type feedbackSubscriber struct {
+	cb FeedbackCallback
+}
+
+// Subscribe adds a callback which is called for every received feedback
+// packet. The callbacks are called in the order they subscribed. The returned
+// function removes the subscription.
+func (f *FeedbackInterceptor) Subscribe(cb FeedbackCallback) func() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	subscriber := &feedbackSubscriber{cb: cb}
+	f.subscribers = append(f.subscribers, subscriber)
+
+	return func() {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+
+		for k, s := range f.subscribers {
+			if s == subscriber {
+				f.subscribers = append(f.subscribers[:k:k], f.subscribers[k+1:]...)
+
+				break
+			}
+		}
+	}
+}
+
+// BindLocalStream records the departure of every outgoing packet which carries
+// a transport wide sequence number.
+func (f *FeedbackInterceptor) BindLocalStream(
+	info *interceptor.StreamInfo, writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	var hdrExtID uint8
+	for _, e := range info.RTPHeaderExtensions {
+		if e.URI == transportCCURI {
+			hdrExtID = uint8(e.ID) //nolint:gosec // G115
+
+			break
+		}
+	}
+	if hdrExtID == 0 {
+		return writer
+	}
+
+	return interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			if attributes == nil {
+				attributes = make(interceptor.Attributes)
+			}
+			attributes.Set(cc.TwccExtensionAttributesKey, hdrExtID)
+			if err := f.adapter.OnSent(f.now(), header, len(payload), attributes); err != nil {
+				f.log.Warnf("failed to record sent packet: %v", err)
+			}
+
+			return writer.Write(header, payload, attributes)
+		},
+	)
+}
+
+// BindRTCPReader parses incoming TWCC feedback and passes the results to the
+// subscribers.
+func (f *FeedbackInterceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		i, attr, err := reader.Read(b, a)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		pkts, err := attr.GetRTCPPackets(b[:i])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		for _, pkt := range pkts {
+			if feedback, ok := pkt.(*rtcp.TransportLayerCC); ok {
+				f.onFeedback(feedback)
+			}
+		}
+
+		return i, attr, nil
+	})
+}
+
+func (f *FeedbackInterceptor) onFeedback(feedback *rtcp.TransportLayerCC) {
+	// The slice is never modified in place
+	f.lock.Lock()
+	subscribers := f.subscribers
+	f.lock.Unlock()
+	if len(subscribers) == 0 {
+		return
+	}
+
+	acks, err := f.adapter.OnTransportCCFeedback(f.now(), feedback)
+	if err != nil {
+		f.log.Warnf("failed to parse feedback: %v", err)
+
+		return
+	}
+
+	results := make([]PacketResult, 0, len(acks))
+	for _, ack := range acks {
+		// Acknowledgments of packets we don't know about carry no information.
+		if ack.Departure.IsZero() {
+			continue
+		}
+		results = append(results, PacketResult{
+			SequenceNumber: ack.SequenceNumber,
+			Size:           ack.Size,
+			Departure:      ack.Departure,
+			Arrival:        ack.Arrival,
+		})
+	}
+
+	for _, s := range subscribers {
+		s.cb(feedback, results)
+	}
+}
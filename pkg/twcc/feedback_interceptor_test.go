@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package twcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/internal/test"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedbackInterceptor(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	feedbackFactory, err := NewFeedbackInterceptor(FeedbackNow(func() time.Time {
+		return now
+	}))
+	require.NoError(t, err)
+
+	var feedbackInterceptor *FeedbackInterceptor
+	feedbackFactory.OnNewPeerConnection(func(id string, f *FeedbackInterceptor) {
+		assert.Equal(t, "pc", id)
+		feedbackInterceptor = f
+	})
+
+	headerExtensionFactory, err := NewHeaderExtensionInterceptor()
+	require.NoError(t, err)
+
+	registry := &interceptor.Registry{}
+	registry.Add(feedbackFactory)
+	registry.Add(headerExtensionFactory)
+	chain, err := registry.Build("pc")
+	require.NoError(t, err)
+	require.NotNil(t, feedbackInterceptor)
+
+	stream := test.NewMockStream(&interceptor.StreamInfo{RTPHeaderExtensions: []interceptor.RTPHeaderExtension{
+		{
+			URI: transportCCURI,
+			ID:  1,
+		},
+	}}, chain)
+	defer func() {
+		assert.NoError(t, stream.Close())
+	}()
+
+	type received struct {
+		feedback *rtcp.TransportLayerCC
+		results  []PacketResult
+	}
+	resultChan := make(chan received, 1)
+	unsubscribe := feedbackInterceptor.Subscribe(func(feedback *rtcp.TransportLayerCC, results []PacketResult) {
+		resultChan <- received{feedback, results}
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, stream.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: uint16(i)}, //nolint:gosec // G115
+			Payload: make([]byte, 100),
+		}))
+		<-stream.WrittenRTP()
+		now = now.Add(10 * time.Millisecond)
+	}
+
+	feedback := &rtcp.TransportLayerCC{
+		Header: rtcp.Header{
+			Count:  rtcp.FormatTCC,
+			Type:   rtcp.TypeTransportSpecificFeedback,
+			Length: 5,
+		},
+		BaseSequenceNumber: 0,
+		PacketStatusCount:  3,
+		PacketChunks: []rtcp.PacketStatusChunk{
+			&rtcp.StatusVectorChunk{
+				Type:       rtcp.TypeTCCStatusVectorChunk,
+				SymbolSize: rtcp.TypeTCCSymbolSizeTwoBit,
+				SymbolList: []uint16{
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketNotReceived,
+				},
+			},
+		},
+		RecvDeltas: []*rtcp.RecvDelta{
+			{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 1000},
+			{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 20000},
+		},
+	}
+	stream.ReceiveRTCP([]rtcp.Packet{feedback})
+
+	select {
+	case r := <-resultChan:
+		assert.Equal(t, feedback, r.feedback)
+		require.Len(t, r.results, 3)
+		for i, result := range r.results {
+			assert.Equal(t, uint16(i), result.SequenceNumber) //nolint:gosec // G115
+			assert.Equal(t, now.Add(time.Duration(i-3)*10*time.Millisecond), result.Departure)
+			assert.Equal(t, 120, result.Size)
+		}
+		assert.False(t, r.results[0].Lost())
+		assert.True(t, r.results[1].Lost())
+		assert.False(t, r.results[2].Lost())
+		assert.Equal(t, 20*time.Millisecond, r.results[2].Arrival.Sub(r.results[0].Arrival))
+	case <-time.After(time.Second):
+		assert.Fail(t, "no feedback delivered")
+	}
+	r := <-stream.ReadRTCP()
+	assert.NoError(t, r.Err)
+
+	unsubscribe()
+	stream.ReceiveRTCP([]rtcp.Packet{feedback})
+	<-stream.ReadRTCP()
+	select {
+	case <-resultChan:
+		assert.Fail(t, "feedback delivered after unsubscribe")
+	default:
+	}
+}
+
+func TestFeedbackInterceptor_SubscriberOrder(t *testing.T) {
+	f, err := NewFeedbackInterceptor()
+	require.NoError(t, err)
+	i, err := f.NewInterceptor("")
+	require.NoError(t, err)
+	feedbackInterceptor, ok := i.(*FeedbackInterceptor)
+	require.True(t, ok)
+
+	called := []int{}
+	unsubscribes := []func(){}
+	for k := 0; k < 5; k++ {
+		k := k
+		unsubscribes = append(unsubscribes, feedbackInterceptor.Subscribe(func(*rtcp.TransportLayerCC, []PacketResult) {
+			called = append(called, k)
+		}))
+	}
+	unsubscribes[2]()
+
+	feedbackInterceptor.onFeedback(&rtcp.TransportLayerCC{
+		PacketStatusCount: 1,
+		PacketChunks: []rtcp.PacketStatusChunk{&rtcp.RunLengthChunk{
+			PacketStatusSymbol: rtcp.TypeTCCPacketNotReceived,
+			RunLength:          1,
+		}},
+	})
+	assert.Equal(t, []int{0, 1, 3, 4}, called)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
@@ -62,6 +63,10 @@ type SenderInterceptor struct {
 
 	recorder   *Recorder
 	packetChan chan packet
+
+	// requireCompound is set once a stream requiring compound RTCP is bound,
+	// as the feedback covers all streams.
+	requireCompound atomic.Bool
 }
 
 // An Option is a function that can be used to configure a SenderInterceptor.
@@ -122,6 +127,9 @@ func (s *SenderInterceptor) BindRemoteStream(
 	if hdrExtID == 0 { // Don't try to read header extension if ID is 0, because 0 is an invalid extension ID
 		return reader
 	}
+	if info.RequireCompoundRTCP {
+		s.requireCompound.Store(true)
+	}
 
 	return interceptor.RTPReaderFunc(
 		func(buf []byte, attributes interceptor.Attributes) (int, interceptor.Attributes, error) {
@@ -210,6 +218,9 @@ func (s *SenderInterceptor) loop(writer interceptor.RTCPWriter) {
 			if len(pkts) == 0 {
 				continue
 			}
+			if s.requireCompound.Load() {
+				pkts = interceptor.CompoundRTCP(pkts, s.recorder.senderSSRC)
+			}
 			if _, err := writer.Write(pkts, nil); err != nil {
 				s.log.Error(err.Error())
 			}
@@ -83,6 +83,42 @@ func TestSenderInterceptor(t *testing.T) {
 		}, cc.PacketChunks)
 	})
 
+	t.Run("compound RTCP required", func(t *testing.T) {
+		f, err := NewSenderInterceptor()
+		assert.NoError(t, err)
+
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+
+		stream := test.NewMockStream(&interceptor.StreamInfo{
+			SSRC: 1,
+			RTPHeaderExtensions: []interceptor.RTPHeaderExtension{
+				{
+					URI: transportCCURI,
+					ID:  1,
+				},
+			},
+			RequireCompoundRTCP: true,
+		}, i)
+		defer func() {
+			assert.NoError(t, stream.Close())
+		}()
+
+		hdr := rtp.Header{}
+		tcc, err := (&rtp.TransportCCExtension{TransportSequence: 0}).Marshal()
+		assert.NoError(t, err)
+		assert.NoError(t, hdr.SetExtension(1, tcc))
+		stream.ReceiveRTP(&rtp.Packet{Header: hdr})
+
+		pkts := <-stream.WrittenRTCP()
+		assert.Equal(t, 2, len(pkts))
+		rr, ok := pkts[0].(*rtcp.ReceiverReport)
+		assert.True(t, ok)
+		cc, ok := pkts[1].(*rtcp.TransportLayerCC)
+		assert.True(t, ok)
+		assert.Equal(t, cc.SenderSSRC, rr.SSRC)
+	})
+
 	t.Run("different delays between RTP packets", func(t *testing.T) {
 		f, err := NewSenderInterceptor(SendInterval(500 * time.Millisecond))
 		assert.NoError(t, err)
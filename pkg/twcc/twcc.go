@@ -22,7 +22,7 @@ const (
 type Recorder struct {
 	arrivalTimeMap packetArrivalTimeMap
 
-	sequenceUnwrapper sequencenumber.Unwrapper
+	sequenceUnroller sequencenumber.Unroller
 
 	// startSequenceNumber is the first sequence number that will be included in the the
 	// next feedback packet.
@@ -47,9 +47,14 @@ func NewRecorder(senderSSRC uint32) *Recorder {
 func (r *Recorder) Record(mediaSSRC uint32, sequenceNumber uint16, arrivalTime int64) {
 	r.mediaSSRC = mediaSSRC
 
-	// "Unwrap" the sequence number to get a monotonically increasing sequence number that
-	// won't wrap around after math.MaxUint16.
-	unwrappedSN := r.sequenceUnwrapper.Unwrap(sequenceNumber)
+	// "Unroll" the sequence number to get a monotonically increasing sequence number that
+	// won't wrap around after math.MaxUint16. Packets from before the first one are
+	// too old to be reported.
+	extendedSN, ok := r.sequenceUnroller.Unroll(sequenceNumber)
+	if !ok {
+		return
+	}
+	unwrappedSN := int64(extendedSN) //nolint:gosec // G115
 	r.maybeCullOldPackets(unwrappedSN, arrivalTime)
 	if r.startSequenceNumber == nil || unwrappedSN < *r.startSequenceNumber {
 		r.startSequenceNumber = &unwrappedSN
@@ -712,6 +712,21 @@ func TestDuplicatePackets(t *testing.T) {
 	marshalAll(t, rtcpPackets)
 }
 
+func TestPacketsBeforeFirst(t *testing.T) {
+	r := NewRecorder(5000)
+
+	// A packet reordered before the first one across the wrap is no jump by a
+	// whole cycle
+	arrivalTime := int64(scaleFactorReferenceTime)
+	addRun(t, r, []uint16{1, 65535, 2}, []int64{arrivalTime, arrivalTime, arrivalTime})
+	assert.Equal(t, 2, r.PacketsHeld())
+
+	pkts := rtcpToTwcc(t, r.BuildFeedbackPacket())
+	assert.Len(t, pkts, 1)
+	assert.Equal(t, uint16(1), pkts[0].BaseSequenceNumber)
+	assert.Equal(t, uint16(2), pkts[0].PacketStatusCount)
+}
+
 func TestShortDeltas(t *testing.T) {
 	t.Run("SplitsOneBitDeltas", func(t *testing.T) {
 		recorder := NewRecorder(5000)
@@ -924,3 +939,88 @@ func TestPacketsHheld(t *testing.T) {
 	recorder.BuildFeedbackPacket()
 	assert.Zero(t, recorder.PacketsHeld())
 }
+
+// FuzzRecorder checks that the feedback built by a Recorder round trips
+// through marshaling and reports exactly the recorded packets with their
+// arrival times.
+func FuzzRecorder(f *testing.F) {
+	f.Add(uint16(0), []byte{0, 0, 1, 20, 7, 0, 2, 255})
+	f.Add(uint16(65530), []byte{0, 4, 0, 4, 0, 4, 0, 4, 0, 4, 0, 4, 5, 4, 0, 4})
+	f.Add(uint16(100), []byte{3, 255, 3, 255, 0, 0, 0, 0, 0, 0, 6, 1})
+
+	f.Fuzz(func(t *testing.T, start uint16, data []byte) {
+		recorder := NewRecorder(5000)
+		recorded := map[uint16]int64{}
+		reported := map[uint16]int64{}
+		collect := func() {
+			for _, pkt := range recorder.BuildFeedbackPacket() {
+				raw, err := pkt.Marshal()
+				require.NoError(t, err)
+				pkts, err := rtcp.Unmarshal(raw)
+				require.NoError(t, err)
+				require.Len(t, pkts, 1)
+				feedback, ok := pkts[0].(*rtcp.TransportLayerCC)
+				require.True(t, ok)
+				for seq, arrival := range receivedPackets(t, feedback) {
+					_, duplicate := reported[seq]
+					require.False(t, duplicate, "packet %d reported twice", seq)
+					reported[seq] = arrival
+				}
+			}
+		}
+
+		seq := start - 1
+		arrival := int64(0)
+		for i := 0; i+1 < len(data); i += 2 {
+			seq += uint16(data[i]%8) + 1
+			arrival += int64(data[i+1]) * 1000
+			recorder.Record(1, seq, arrival)
+			recorded[seq] = arrival
+			if data[i]&0x80 != 0 {
+				collect()
+			}
+		}
+		collect()
+
+		require.Len(t, reported, len(recorded))
+		for seq, arrival := range recorded {
+			require.InDelta(t, arrival, reported[seq], rtcp.TypeTCCDeltaScaleFactor, "packet %d", seq)
+		}
+	})
+}
+
+// receivedPackets returns the arrival times of the packets reported as
+// received by feedback.
+func receivedPackets(t *testing.T, feedback *rtcp.TransportLayerCC) map[uint16]int64 {
+	t.Helper()
+
+	symbols := []uint16{}
+	for _, c := range feedback.PacketChunks {
+		switch c := c.(type) {
+		case *rtcp.RunLengthChunk:
+			for i := uint16(0); i < c.RunLength; i++ {
+				symbols = append(symbols, c.PacketStatusSymbol)
+			}
+		case *rtcp.StatusVectorChunk:
+			symbols = append(symbols, c.SymbolList...)
+		}
+	}
+	require.GreaterOrEqual(t, len(symbols), int(feedback.PacketStatusCount))
+
+	received := map[uint16]int64{}
+	arrival := int64(feedback.ReferenceTime) * 64000
+	deltas := feedback.RecvDeltas
+	for i, symbol := range symbols[:feedback.PacketStatusCount] {
+		if symbol == rtcp.TypeTCCPacketNotReceived {
+			continue
+		}
+		require.NotEmpty(t, deltas)
+		require.Equal(t, symbol, deltas[0].Type)
+		arrival += deltas[0].Delta
+		deltas = deltas[1:]
+		received[feedback.BaseSequenceNumber+uint16(i)] = arrival //nolint:gosec // G115
+	}
+	require.Empty(t, deltas)
+
+	return received
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+// PreBinder is implemented by interceptors which can construct the state of a
+// stream, like its buffers, before the stream is bound. Servers expecting many
+// streams at once can pre-bind them, so the allocations don't delay the first
+// packets. The pre-bound state is used by the next BindLocalStream or
+// BindRemoteStream with the same SSRC, and dropped by the matching unbind
+// call if the stream is never bound.
+type PreBinder interface {
+	// PreBindLocalStream constructs the state of a LocalStream, which is expected
+	// to be bound with info soon.
+	PreBindLocalStream(info *StreamInfo)
+
+	// PreBindRemoteStream constructs the state of a RemoteStream, which is
+	// expected to be bound with info soon.
+	PreBindRemoteStream(info *StreamInfo)
+}
+
+// PreBindLocalStream pre-binds a LocalStream on all interceptors of the chain
+// implementing PreBinder.
+func (i *Chain) PreBindLocalStream(info *StreamInfo) {
+	for _, interceptor := range i.interceptors {
+		if binder, ok := interceptor.(PreBinder); ok {
+			binder.PreBindLocalStream(info)
+		}
+	}
+}
+
+// PreBindRemoteStream pre-binds a RemoteStream on all interceptors of the
+// chain implementing PreBinder.
+func (i *Chain) PreBindRemoteStream(info *StreamInfo) {
+	for _, interceptor := range i.interceptors {
+		if binder, ok := interceptor.(PreBinder); ok {
+			binder.PreBindRemoteStream(info)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type preBindRecorder struct {
+	NoOp
+	local, remote []uint32
+}
+
+func (p *preBindRecorder) PreBindLocalStream(info *StreamInfo) {
+	p.local = append(p.local, info.SSRC)
+}
+
+func (p *preBindRecorder) PreBindRemoteStream(info *StreamInfo) {
+	p.remote = append(p.remote, info.SSRC)
+}
+
+func TestChain_PreBind(t *testing.T) {
+	recorder := &preBindRecorder{}
+	chain := NewChain([]Interceptor{&NoOp{}, recorder})
+
+	var binder PreBinder = chain
+	binder.PreBindLocalStream(&StreamInfo{SSRC: 1})
+	binder.PreBindRemoteStream(&StreamInfo{SSRC: 2})
+
+	assert.Equal(t, []uint32{1}, recorder.local)
+	assert.Equal(t, []uint32{2}, recorder.remote)
+}
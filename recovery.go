@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"errors"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// ErrInterceptorPanic is returned for packets dropped by a RecoveryChain, as
+// an interceptor panicked while processing them.
+var ErrInterceptorPanic = errors.New("interceptor panicked, packet dropped")
+
+// maxReadRetries is the number of times a read is retried after the
+// interceptor panicked, before ErrInterceptorPanic is returned, so an
+// interceptor panicking before it reads doesn't read in an endless loop.
+const maxReadRetries = 1
+
+// PanicCallback is called with the interceptor which panicked and the value
+// it panicked with.
+type PanicCallback func(interceptor Interceptor, recovered interface{})
+
+// RecoveryOption configures a RecoveryChain.
+type RecoveryOption func(*RecoveryChain) error
+
+// RecoveryLog sets the logger panics are logged to, with their stack trace.
+func RecoveryLog(log logging.LeveledLogger) RecoveryOption {
+	return func(c *RecoveryChain) error {
+		c.log = log
+
+		return nil
+	}
+}
+
+// RecoveryOnPanic sets a callback which is called for every recovered panic,
+// e.g. to report it to an error tracker.
+func RecoveryOnPanic(cb PanicCallback) RecoveryOption {
+	return func(c *RecoveryChain) error {
+		c.onPanic = cb
+
+		return nil
+	}
+}
+
+// RecoveryChain is a Chain isolating the interceptors from each other: when
+// an interceptor panics while reading or writing a packet, the panic is
+// recovered, logged and counted, and only that packet is dropped, instead of
+// crashing the whole process. A dropped written packet fails with
+// ErrInterceptorPanic, a dropped read packet is skipped and the next one is
+// read, as read loops usually stop on the first error. If the interceptor
+// panics on the next packet as well, the read fails with ErrInterceptorPanic.
+// The state of the interceptor may be inconsistent afterwards, so the panics
+// should still be fixed. Binding, unbinding and closing are not guarded.
+type RecoveryChain struct {
+	*Chain
+	log     logging.LeveledLogger
+	onPanic PanicCallback
+	panics  []atomic.Uint64
+}
+
+// NewRecoveryChain returns a new RecoveryChain of interceptors.
+func NewRecoveryChain(interceptors []Interceptor, opts ...RecoveryOption) (*RecoveryChain, error) {
+	chain := &RecoveryChain{
+		Chain:   NewChain(interceptors),
+		log:     logging.NewDefaultLoggerFactory().NewLogger("interceptor_recovery"),
+		onPanic: nil,
+		panics:  make([]atomic.Uint64, len(interceptors)),
+	}
+	for _, opt := range opts {
+		if err := opt(chain); err != nil {
+			return nil, err
+		}
+	}
+
+	return chain, nil
+}
+
+// Panics returns the number of panics recovered for every interceptor, in the
+// order they were passed to NewRecoveryChain.
+func (i *RecoveryChain) Panics() []uint64 {
+	panics := make([]uint64, len(i.panics))
+	for k := range i.panics {
+		panics[k] = i.panics[k].Load()
+	}
+
+	return panics
+}
+
+// recoverPanic must be deferred by the writers of the interceptor k. It sets
+// err to ErrInterceptorPanic if the interceptor panicked.
+func (i *RecoveryChain) recoverPanic(k int, err *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	i.handlePanic(k, recovered)
+	*err = ErrInterceptorPanic
+}
+
+// recoverRead must be deferred by the readers of the interceptor k. It sets
+// panicked if the interceptor panicked.
+func (i *RecoveryChain) recoverRead(k int, panicked *bool) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	i.handlePanic(k, recovered)
+	*panicked = true
+}
+
+func (i *RecoveryChain) handlePanic(k int, recovered interface{}) {
+	i.panics[k].Add(1)
+	i.log.Errorf("interceptor %T panicked: %v\n%s", i.interceptors[k], recovered, debug.Stack())
+	if i.onPanic != nil {
+		i.onPanic(i.interceptors[k], recovered)
+	}
+}
+
+// read calls read until the interceptor k doesn't panic, dropping the packets
+// it panicked on, at most maxReadRetries times.
+func (i *RecoveryChain) read(k int, read func() (int, Attributes, error)) (n int, attr Attributes, err error) {
+	for retries := 0; ; retries++ {
+		panicked := false
+		func() {
+			defer i.recoverRead(k, &panicked)
+			n, attr, err = read()
+		}()
+		if !panicked {
+			return n, attr, err
+		}
+		if retries == maxReadRetries {
+			return 0, nil, ErrInterceptorPanic
+		}
+	}
+}
+
+// BindRTCPReader lets you modify any incoming RTCP packets. It is called once per sender/receiver, however this might
+// change in the future. The returned method will be called once per packet batch.
+func (i *RecoveryChain) BindRTCPReader(reader RTCPReader) RTCPReader {
+	for k, interceptor := range i.interceptors {
+		k, next := k, interceptor.BindRTCPReader(reader)
+		reader = RTCPReaderFunc(func(b []byte, a Attributes) (int, Attributes, error) {
+			return i.read(k, func() (int, Attributes, error) {
+				return next.Read(b, a)
+			})
+		})
+	}
+
+	return reader
+}
+
+// BindRTCPWriter lets you modify any outgoing RTCP packets. It is called once per PeerConnection. The returned method
+// will be called once per packet batch.
+func (i *RecoveryChain) BindRTCPWriter(writer RTCPWriter) RTCPWriter {
+	for k, interceptor := range i.interceptors {
+		k, next := k, interceptor.BindRTCPWriter(writer)
+		writer = RTCPWriterFunc(func(pkts []rtcp.Packet, attributes Attributes) (n int, err error) {
+			defer i.recoverPanic(k, &err)
+
+			return next.Write(pkts, attributes)
+		})
+	}
+
+	return writer
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream. The returned method
+// will be called once per rtp packet.
+func (i *RecoveryChain) BindLocalStream(ctx *StreamInfo, writer RTPWriter) RTPWriter {
+	i.bindStream(i.localStreams, ctx, true)
+	for k, interceptor := range i.interceptors {
+		k, next := k, interceptor.BindLocalStream(ctx, writer)
+		writer = RTPWriterFunc(func(header *rtp.Header, payload []byte, attributes Attributes) (n int, err error) {
+			defer i.recoverPanic(k, &err)
+
+			return next.Write(header, payload, attributes)
+		})
+	}
+
+	return writer
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets.
+// It is called once for per RemoteStream. The returned method
+// will be called once per rtp packet.
+func (i *RecoveryChain) BindRemoteStream(ctx *StreamInfo, reader RTPReader) RTPReader {
+	i.bindStream(i.remoteStreams, ctx, true)
+	reader = annotateCodec(ctx, reader)
+	for k, interceptor := range i.interceptors {
+		k, next := k, interceptor.BindRemoteStream(ctx, reader)
+		reader = RTPReaderFunc(func(b []byte, a Attributes) (int, Attributes, error) {
+			return i.read(k, func() (int, Attributes, error) {
+				return next.Read(b, a)
+			})
+		})
+	}
+
+	return reader
+}
@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicInterceptor struct {
+	NoOp
+}
+
+func (p *panicInterceptor) BindLocalStream(_ *StreamInfo, writer RTPWriter) RTPWriter {
+	return RTPWriterFunc(func(header *rtp.Header, payload []byte, attributes Attributes) (int, error) {
+		if header.SequenceNumber == 1 {
+			panic("edge case")
+		}
+
+		return writer.Write(header, payload, attributes)
+	})
+}
+
+func (p *panicInterceptor) BindRTCPReader(reader RTCPReader) RTCPReader {
+	return RTCPReaderFunc(func(b []byte, a Attributes) (int, Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if b[0] == 1 {
+			var pkts []rtcp.Packet
+			_ = pkts[n]
+		}
+
+		return n, attr, err
+	})
+}
+
+func (p *panicInterceptor) BindRemoteStream(_ *StreamInfo, reader RTPReader) RTPReader {
+	return RTPReaderFunc(func(b []byte, a Attributes) (int, Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if b[0] == 1 {
+			panic("edge case")
+		}
+
+		return n, attr, err
+	})
+}
+
+// brokenReaderInterceptor panics before reading any packet.
+type brokenReaderInterceptor struct {
+	NoOp
+}
+
+func (p *brokenReaderInterceptor) BindRTCPReader(RTCPReader) RTCPReader {
+	return RTCPReaderFunc(func([]byte, Attributes) (int, Attributes, error) {
+		panic("broken")
+	})
+}
+
+func TestRecoveryChain(t *testing.T) {
+	recovered := []interface{}{}
+	faulty := &panicInterceptor{}
+	chain, err := NewRecoveryChain([]Interceptor{&NoOp{}, faulty}, RecoveryOnPanic(
+		func(interceptor Interceptor, value interface{}) {
+			assert.Equal(t, faulty, interceptor)
+			recovered = append(recovered, value)
+		},
+	))
+	require.NoError(t, err)
+
+	written := []uint16{}
+	writer := chain.BindLocalStream(&StreamInfo{SSRC: 1}, RTPWriterFunc(
+		func(header *rtp.Header, _ []byte, _ Attributes) (int, error) {
+			written = append(written, header.SequenceNumber)
+
+			return 0, nil
+		},
+	))
+	for seq := uint16(0); seq < 3; seq++ {
+		_, err := writer.Write(&rtp.Header{SequenceNumber: seq}, nil, nil)
+		if seq == 1 {
+			assert.ErrorIs(t, err, ErrInterceptorPanic)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+	// Only the packet the interceptor panicked on is dropped
+	assert.Equal(t, []uint16{0, 2}, written)
+
+	// Read packets the interceptor panicked on are skipped
+	next := byte(0)
+	source := func(b []byte, a Attributes) (int, Attributes, error) {
+		b[0] = next
+		next++
+
+		return 1, a, nil
+	}
+	reader := chain.BindRTCPReader(RTCPReaderFunc(source))
+	buf := make([]byte, 10)
+	for _, expected := range []byte{0, 2} {
+		n, _, err := reader.Read(buf, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, expected, buf[0])
+	}
+
+	next = 0
+	remoteReader := chain.BindRemoteStream(&StreamInfo{SSRC: 2}, RTPReaderFunc(source))
+	for _, expected := range []byte{0, 2} {
+		n, _, err := remoteReader.Read(buf, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, expected, buf[0])
+	}
+
+	assert.Equal(t, []uint64{0, 3}, chain.Panics())
+	assert.Len(t, recovered, 3)
+	assert.Equal(t, "edge case", recovered[0])
+	assert.Equal(t, []StreamInfo{{SSRC: 1}}, chain.LocalStreams())
+}
+
+func TestRecoveryChain_ReadRetries(t *testing.T) {
+	chain, err := NewRecoveryChain([]Interceptor{&brokenReaderInterceptor{}})
+	require.NoError(t, err)
+
+	// The read is retried once, then the panic is returned
+	reader := chain.BindRTCPReader(RTCPReaderFunc(func(b []byte, a Attributes) (int, Attributes, error) {
+		return len(b), a, nil
+	}))
+	_, _, err = reader.Read(make([]byte, 10), nil)
+	assert.ErrorIs(t, err, ErrInterceptorPanic)
+	assert.Equal(t, []uint64{2}, chain.Panics())
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/pion/rtcp"
+)
+
+var errNoRTCPRoute = errors.New("no RTCP writer for the destination of the packets")
+
+// RTCPRouter is a RTCPWriter for sessions without RTCP multiplexing, where
+// the RTCP of every stream is sent on a transport of its own. It's passed to
+// BindRTCPWriter in place of the single RTCPWriter of a PeerConnection, and
+// routes the packets written by the interceptors to the RTCPWriter bound to
+// the stream they refer to with BindStreams.
+//
+// Incoming RTCP needs no routing, since BindRTCPReader can be called for every
+// transport.
+type RTCPRouter struct {
+	mu       sync.RWMutex
+	fallback RTCPWriter
+	streams  map[uint32]*rtcpRoute
+}
+
+// rtcpRoute is shared by the SSRCs of the streams bound together, RTCPWriters
+// can't be compared since they may be functions.
+type rtcpRoute struct {
+	writer RTCPWriter
+}
+
+// NewRTCPRouter returns a new RTCPRouter. Packets not referring to a bound
+// stream are written to fallback, which may be nil to fail writing them.
+func NewRTCPRouter(fallback RTCPWriter) *RTCPRouter {
+	return &RTCPRouter{
+		fallback: fallback,
+		streams:  map[uint32]*rtcpRoute{},
+	}
+}
+
+// BindStreams routes the packets referring to the SSRCs of the streams,
+// including those of their retransmission and FEC streams, to writer. All
+// streams sharing a transport, e.g. the local and the remote stream of a
+// bidirectional audio session, must be bound in a single call, so the packets
+// referring to several of them are written as one batch.
+func (r *RTCPRouter) BindStreams(writer RTCPWriter, infos ...*StreamInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	route := &rtcpRoute{writer: writer}
+	for _, info := range infos {
+		for _, ssrc := range streamSSRCs(info) {
+			r.streams[ssrc] = route
+		}
+	}
+}
+
+// UnbindStreams removes the routes of the streams.
+func (r *RTCPRouter) UnbindStreams(infos ...*StreamInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, info := range infos {
+		for _, ssrc := range streamSSRCs(info) {
+			delete(r.streams, ssrc)
+		}
+	}
+}
+
+// Write routes every packet to the writer of the first bound stream it refers
+// to. Packets referring to no bound stream, like RTCP XR Receiver Reference
+// Time reports, stay with the rest of the batch, so compound packets are kept
+// together.
+func (r *RTCPRouter) Write(pkts []rtcp.Packet, attributes Attributes) (int, error) {
+	r.mu.RLock()
+	routes := []*rtcpRoute{}
+	batches := [][]rtcp.Packet{}
+	unrouted := []rtcp.Packet{}
+	for _, pkt := range pkts {
+		route := r.route(pkt)
+		if route == nil {
+			unrouted = append(unrouted, pkt)
+
+			continue
+		}
+		found := false
+		for i := range routes {
+			if routes[i] == route {
+				batches[i] = append(batches[i], pkt)
+				found = true
+
+				break
+			}
+		}
+		if !found {
+			routes = append(routes, route)
+			batches = append(batches, []rtcp.Packet{pkt})
+		}
+	}
+	r.mu.RUnlock()
+
+	if len(unrouted) > 0 {
+		switch {
+		case len(routes) > 0:
+			batches[0] = append(batches[0], unrouted...)
+		case r.fallback != nil:
+			routes = append(routes, &rtcpRoute{writer: r.fallback})
+			batches = append(batches, unrouted)
+		default:
+			return 0, errNoRTCPRoute
+		}
+	}
+
+	total := 0
+	var errs []error
+	for i, route := range routes {
+		n, err := route.writer.Write(batches[i], attributes)
+		total += n
+		errs = append(errs, err)
+	}
+
+	return total, flattenErrs(errs)
+}
+
+// route must be called with r.mu held.
+func (r *RTCPRouter) route(pkt rtcp.Packet) *rtcpRoute {
+	for _, ssrc := range pkt.DestinationSSRC() {
+		if route, ok := r.streams[ssrc]; ok {
+			return route
+		}
+	}
+
+	return nil
+}
+
+func streamSSRCs(info *StreamInfo) []uint32 {
+	ssrcs := []uint32{info.SSRC}
+	if info.SSRCRetransmission != 0 {
+		ssrcs = append(ssrcs, info.SSRCRetransmission)
+	}
+	if info.SSRCForwardErrorCorrection != 0 {
+		ssrcs = append(ssrcs, info.SSRCForwardErrorCorrection)
+	}
+
+	return ssrcs
+}
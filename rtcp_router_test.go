@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+type rtcpRecorder struct {
+	batches [][]rtcp.Packet
+}
+
+func (r *rtcpRecorder) Write(pkts []rtcp.Packet, _ Attributes) (int, error) {
+	r.batches = append(r.batches, pkts)
+
+	return len(pkts), nil
+}
+
+func TestRTCPRouter(t *testing.T) {
+	audio, video, fallback := &rtcpRecorder{}, &rtcpRecorder{}, &rtcpRecorder{}
+	router := NewRTCPRouter(fallback)
+	// The local and the remote audio stream share a transport
+	router.BindStreams(audio, &StreamInfo{SSRC: 1}, &StreamInfo{SSRC: 4})
+	router.BindStreams(video, &StreamInfo{SSRC: 2, SSRCRetransmission: 3})
+
+	rr := &rtcp.ReceiverReport{SSRC: 10, Reports: []rtcp.ReceptionReport{{SSRC: 1}}}
+	rrtr := &rtcp.ExtendedReport{SenderSSRC: 10, Reports: []rtcp.ReportBlock{&rtcp.ReceiverReferenceTimeReportBlock{}}}
+	n, err := router.Write([]rtcp.Packet{rr, rrtr}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, [][]rtcp.Packet{{rr, rrtr}}, audio.batches)
+
+	nack := &rtcp.TransportLayerNack{MediaSSRC: 3}
+	pli := &rtcp.PictureLossIndication{MediaSSRC: 1}
+	n, err = router.Write([]rtcp.Packet{nack, pli}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, [][]rtcp.Packet{{nack}}, video.batches)
+	assert.Equal(t, [][]rtcp.Packet{{rr, rrtr}, {pli}}, audio.batches)
+
+	// Packets of streams sharing a transport are written as one batch
+	remotePLI := &rtcp.PictureLossIndication{MediaSSRC: 4}
+	n, err = router.Write([]rtcp.Packet{pli, nack, remotePLI}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, [][]rtcp.Packet{{rr, rrtr}, {pli}, {pli, remotePLI}}, audio.batches)
+
+	router.UnbindStreams(&StreamInfo{SSRC: 1}, &StreamInfo{SSRC: 4})
+	_, err = router.Write([]rtcp.Packet{pli}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]rtcp.Packet{{pli}}, fallback.batches)
+
+	_, err = NewRTCPRouter(nil).Write([]rtcp.Packet{pli}, nil)
+	assert.ErrorIs(t, err, errNoRTCPRoute)
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import "time"
+
+// SetRTT stores a round trip time measured from the RTCP packets the
+// attributes belong to. It is set in the RTCPReader of interceptors measuring
+// the RTT, e.g. the report interceptors or a congestion controller processing
+// TWCC feedback, and read by the interceptors whose RTCPReaders run later,
+// i.e. which were added to the Chain after them.
+func (a Attributes) SetRTT(rtt time.Duration) {
+	a[rttKey] = rtt
+}
+
+// GetRTT returns the round trip time stored with SetRTT, and whether one was
+// stored.
+func (a Attributes) GetRTT() (time.Duration, bool) {
+	rtt, ok := a[rttKey].(time.Duration)
+
+	return rtt, ok
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributesRTT(t *testing.T) {
+	attributes := Attributes{}
+	_, ok := attributes.GetRTT()
+	assert.False(t, ok)
+
+	attributes.SetRTT(40 * time.Millisecond)
+	rtt, ok := attributes.GetRTT()
+	assert.True(t, ok)
+	assert.Equal(t, 40*time.Millisecond, rtt)
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import "sort"
+
+// StreamLister is implemented by interceptors which can enumerate the streams
+// currently bound to them, e.g. for admin endpoints or debugging tools.
+type StreamLister interface {
+	// LocalStreams returns the bound local streams, ordered by SSRC.
+	LocalStreams() []StreamInfo
+	// RemoteStreams returns the bound remote streams, ordered by SSRC.
+	RemoteStreams() []StreamInfo
+}
+
+// LocalStreams returns the local streams currently bound to the Chain, ordered
+// by SSRC. The StreamInfos are copies, but share their slices and maps with
+// the bound ones, which must not be modified.
+func (i *Chain) LocalStreams() []StreamInfo {
+	return i.listStreams(i.localStreams)
+}
+
+// RemoteStreams returns the remote streams currently bound to the Chain,
+// ordered by SSRC, like LocalStreams.
+func (i *Chain) RemoteStreams() []StreamInfo {
+	return i.listStreams(i.remoteStreams)
+}
+
+func (i *Chain) listStreams(streams map[uint32]*StreamInfo) []StreamInfo {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	infos := make([]StreamInfo, 0, len(streams))
+	for _, info := range streams {
+		infos = append(infos, *info)
+	}
+	sort.Slice(infos, func(a, b int) bool { return infos[a].SSRC < infos[b].SSRC })
+
+	return infos
+}
+
+// bindStream records info as bound in streams, or as unbound if bound is false.
+func (i *Chain) bindStream(streams map[uint32]*StreamInfo, info *StreamInfo, bound bool) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if bound {
+		streams[info.SSRC] = info
+	} else {
+		delete(streams, info.SSRC)
+	}
+}
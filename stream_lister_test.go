@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain_StreamLister(t *testing.T) {
+	chain := NewChain([]Interceptor{&NoOp{}})
+	var lister StreamLister = chain
+
+	chain.BindLocalStream(&StreamInfo{SSRC: 3, MimeType: "video/VP8"}, nil)
+	chain.BindLocalStream(&StreamInfo{SSRC: 1, MimeType: "audio/opus"}, nil)
+	chain.BindRemoteStream(&StreamInfo{SSRC: 2}, nil)
+	assert.Equal(t, []StreamInfo{{SSRC: 1, MimeType: "audio/opus"}, {SSRC: 3, MimeType: "video/VP8"}},
+		lister.LocalStreams())
+	assert.Equal(t, []StreamInfo{{SSRC: 2}}, lister.RemoteStreams())
+
+	chain.UnbindLocalStream(&StreamInfo{SSRC: 1})
+	chain.UnbindRemoteStream(&StreamInfo{SSRC: 2})
+	assert.Equal(t, []StreamInfo{{SSRC: 3, MimeType: "video/VP8"}}, lister.LocalStreams())
+	assert.Empty(t, lister.RemoteStreams())
+
+	// The LatencyChain records the local streams it binds itself
+	latency := NewLatencyChain([]Interceptor{&NoOp{}})
+	latency.BindLocalStream(&StreamInfo{SSRC: 4}, nil)
+	assert.Equal(t, []StreamInfo{{SSRC: 4}}, latency.LocalStreams())
+}
@@ -25,6 +25,34 @@ type StreamInfo struct {
 	Channels                          uint16
 	SDPFmtpLine                       string
 	RTCPFeedback                      []RTCPFeedback
+
+	// PayloadTypeClockRates holds the clock rates of payload types which don't
+	// use ClockRate, e.g. for audio streams switching between codecs.
+	PayloadTypeClockRates map[uint8]uint32
+
+	// Codecs holds the codecs of all payload types negotiated for the stream,
+	// see CodecForPayloadType.
+	Codecs []Codec
+
+	// RequireCompoundRTCP is set if the remote didn't negotiate reduced-size
+	// RTCP (RFC 5506, rtcp-rsize). The interceptors sending feedback for the
+	// stream then send packets starting with a report, see CompoundRTCP,
+	// instead of feedback packets on their own.
+	RequireCompoundRTCP bool
+}
+
+// ClockRateForPayloadType returns the clock rate of packets with the given
+// payload type, from PayloadTypeClockRates or Codecs, falling back to
+// ClockRate.
+func (s *StreamInfo) ClockRateForPayloadType(payloadType uint8) uint32 {
+	if rate, ok := s.PayloadTypeClockRates[payloadType]; ok {
+		return rate
+	}
+	if codec, ok := s.codec(payloadType); ok && codec.ClockRate != 0 {
+		return codec.ClockRate
+	}
+
+	return s.ClockRate
 }
 
 // RTCPFeedback signals the connection to use additional RTCP packet types.